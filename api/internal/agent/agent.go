@@ -23,7 +23,7 @@ func NewAgent(openaiClient *openai.OpenAIClient, toolExecutor *ToolExecutor) *Ag
 
 // ProcessMessage processes a user message and returns the assistant's response
 // It handles tool calling loops until a final response is generated
-func (a *Agent) ProcessMessage(ctx context.Context, userID string, systemPrompt string, messages []openai.ChatMessage) (result *openai.ChatMessage, err error) {
+func (a *Agent) ProcessMessage(ctx context.Context, userID, workspaceID string, systemPrompt string, messages []openai.ChatMessage) (result *openai.ChatMessage, err error) {
 	tools := GetToolDefinitions()
 	maxIterations := 10
 	iteration := 0
@@ -63,13 +63,17 @@ func (a *Agent) ProcessMessage(ctx context.Context, userID string, systemPrompt
 			// Execute the appropriate tool
 			switch toolName {
 			case "search_notes":
-				toolResult, toolErr = a.toolExecutor.SearchNotesTool(ctx, userID, arguments)
+				toolResult, toolErr = a.toolExecutor.SearchNotesTool(ctx, userID, workspaceID, arguments)
 			case "search_tasks":
-				toolResult, toolErr = a.toolExecutor.SearchTasksTool(ctx, userID, arguments)
+				toolResult, toolErr = a.toolExecutor.SearchTasksTool(ctx, userID, workspaceID, arguments)
 			case "list_collections":
-				toolResult, toolErr = a.toolExecutor.ListCollectionsTool(ctx, userID)
+				toolResult, toolErr = a.toolExecutor.ListCollectionsTool(ctx, userID, workspaceID)
 			case "list_projects":
-				toolResult, toolErr = a.toolExecutor.ListProjectsTool(ctx, userID)
+				toolResult, toolErr = a.toolExecutor.ListProjectsTool(ctx, userID, workspaceID)
+			case "get_comments":
+				toolResult, toolErr = a.toolExecutor.GetCommentsTool(ctx, userID, workspaceID, arguments)
+			case "list_tags":
+				toolResult, toolErr = a.toolExecutor.ListTagsTool(ctx, userID)
 			default:
 				toolErr = fmt.Errorf("unknown tool: %s", toolName)
 			}
@@ -103,7 +107,7 @@ func (a *Agent) ProcessMessage(ctx context.Context, userID string, systemPrompt
 
 // ProcessMessageStream processes a user message with streaming support
 // It executes tool calling loops first (non-streaming), then streams only the final text response
-func (a *Agent) ProcessMessageStream(ctx context.Context, userID string, systemPrompt string, messages []openai.ChatMessage, onChunk openai.StreamChunkCallback) (result *openai.ChatMessage, err error) {
+func (a *Agent) ProcessMessageStream(ctx context.Context, userID, workspaceID string, systemPrompt string, messages []openai.ChatMessage, onChunk openai.StreamChunkCallback) (result *openai.ChatMessage, err error) {
 	tools := GetToolDefinitions()
 	maxIterations := 10
 	iteration := 0
@@ -150,13 +154,17 @@ func (a *Agent) ProcessMessageStream(ctx context.Context, userID string, systemP
 			// Execute the appropriate tool
 			switch toolName {
 			case "search_notes":
-				toolResult, toolErr = a.toolExecutor.SearchNotesTool(ctx, userID, arguments)
+				toolResult, toolErr = a.toolExecutor.SearchNotesTool(ctx, userID, workspaceID, arguments)
 			case "search_tasks":
-				toolResult, toolErr = a.toolExecutor.SearchTasksTool(ctx, userID, arguments)
+				toolResult, toolErr = a.toolExecutor.SearchTasksTool(ctx, userID, workspaceID, arguments)
 			case "list_collections":
-				toolResult, toolErr = a.toolExecutor.ListCollectionsTool(ctx, userID)
+				toolResult, toolErr = a.toolExecutor.ListCollectionsTool(ctx, userID, workspaceID)
 			case "list_projects":
-				toolResult, toolErr = a.toolExecutor.ListProjectsTool(ctx, userID)
+				toolResult, toolErr = a.toolExecutor.ListProjectsTool(ctx, userID, workspaceID)
+			case "get_comments":
+				toolResult, toolErr = a.toolExecutor.GetCommentsTool(ctx, userID, workspaceID, arguments)
+			case "list_tags":
+				toolResult, toolErr = a.toolExecutor.ListTagsTool(ctx, userID)
 			default:
 				toolErr = fmt.Errorf("unknown tool: %s", toolName)
 			}
@@ -2,8 +2,11 @@ package agent
 
 import (
 	"app/internal/model"
+	"app/internal/repository"
 	"app/pkg/logger"
 	"context"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/pgvector/pgvector-go"
@@ -22,16 +25,17 @@ func NewAgentRepository(db *gorm.DB) *AgentRepository {
 // NoteSearchFilters represents filters for note search
 type NoteSearchFilters struct {
 	CollectionID   *string   `json:"collection_id"`
+	Tags           []string  `json:"tags"`
 	QueryEmbedding []float32 `json:"query_embedding"`
 	Limit          int       `json:"limit"`
 }
 
-// SearchNotes performs vector similarity search on notes
-func (r *AgentRepository) SearchNotes(ctx context.Context, userID string, filters NoteSearchFilters) ([]model.Note, error) {
+// SearchNotes performs vector similarity search on the notes the user sees in the active workspace
+func (r *AgentRepository) SearchNotes(ctx context.Context, userID, workspaceID string, filters NoteSearchFilters) ([]model.Note, error) {
 	var notes []model.Note
 
-	baseQuery := "SELECT id, user_id, collection_id, title, content, embedding, created_at, updated_at, deleted_at FROM notes WHERE user_id = ? AND deleted_at IS NULL AND embedding IS NOT NULL"
-	args := []interface{}{userID}
+	scope, args := r.scopeItems("collection_id", userID, workspaceID)
+	baseQuery := "SELECT id, user_id, collection_id, title, content, embedding, created_at, updated_at, deleted_at FROM notes WHERE " + scope + " AND deleted_at IS NULL AND embedding IS NOT NULL"
 
 	// Add collection_id filter if present
 	if filters.CollectionID != nil && *filters.CollectionID != "" {
@@ -39,6 +43,16 @@ func (r *AgentRepository) SearchNotes(ctx context.Context, userID string, filter
 		args = append(args, *filters.CollectionID)
 	}
 
+	// Add tag filter if present; a note must carry every requested tag
+	if len(filters.Tags) > 0 {
+		baseQuery += ` AND id IN (
+			SELECT nt.note_id FROM note_tags nt
+			JOIN tags t ON t.id = nt.tag_id
+			WHERE t.user_id = ? AND t.deleted_at IS NULL AND LOWER(t.name) IN ?
+			GROUP BY nt.note_id HAVING COUNT(DISTINCT t.id) = ?)`
+		args = append(args, userID, filters.Tags, len(filters.Tags))
+	}
+
 	// Only add similarity ordering if QueryEmbedding is not empty
 	if len(filters.QueryEmbedding) > 0 {
 		queryVector := pgvector.NewVector(filters.QueryEmbedding)
@@ -73,20 +87,68 @@ func (r *AgentRepository) SearchNotes(ctx context.Context, userID string, filter
 		}
 	}
 
+	if err := r.loadNoteTags(ctx, userID, notes); err != nil {
+		logger.Log.Error("Failed to load note tags", zap.Error(err), zap.String("userID", userID))
+		return nil, err
+	}
+
 	return notes, nil
 }
 
-// SearchTasks performs filtered search on tasks
-func (r *AgentRepository) SearchTasks(ctx context.Context, userID string, filters TaskSearchFilters) ([]model.Task, error) {
+// SearchTasks performs filtered search on the tasks the user sees in the active workspace
+func (r *AgentRepository) SearchTasks(ctx context.Context, userID, workspaceID string, filters TaskSearchFilters) ([]model.Task, error) {
 	var tasks []model.Task
 
+	scope, scopeArgs := r.scopeItems("project_id", userID, workspaceID)
 	query := r.db.WithContext(ctx).
-		Where("user_id = ? AND deleted_at IS NULL", userID)
+		Where(scope+" AND deleted_at IS NULL", scopeArgs...)
 
 	if filters.Status != nil {
 		query = query.Where("status = ?", *filters.Status)
 	}
 
+	// A custom status name matches that status in any project. A category or
+	// built-in status name matches every task in that category.
+	if filters.StatusName != nil {
+		name := strings.ToLower(strings.TrimSpace(*filters.StatusName))
+		condition := "status_id IN (SELECT id FROM project_statuses WHERE project_id IN (?) AND deleted_at IS NULL AND LOWER(name) = ?)"
+		projectIDs := r.scopeContainerIDs("project_id", userID, workspaceID)
+		if code, ok := statusCodeByName(name); ok {
+			query = query.Where("("+condition+" OR status = ?)", projectIDs, name, code)
+		} else {
+			query = query.Where(condition, projectIDs, name)
+		}
+	}
+
+	if filters.RecurringOnly {
+		query = query.Where("recurrence_rule IS NOT NULL AND status NOT IN ?", []int{model.TaskStatusCompleted, model.TaskStatusCancelled})
+	}
+
+	if filters.OpenOnly {
+		query = query.Where("status NOT IN ?", []int{model.TaskStatusCompleted, model.TaskStatusCancelled})
+	}
+
+	if filters.ActionableOnly {
+		query = query.Where("NOT blocked AND status NOT IN ?", []int{model.TaskStatusCompleted, model.TaskStatusCancelled})
+	}
+
+	if filters.MinPriority != nil {
+		query = query.Where("priority >= ?", *filters.MinPriority)
+	}
+
+	// Tasks without an estimate can't be known to fit, so they are left out
+	if filters.MaxEstimateMinutes != nil {
+		query = query.Where("estimate_minutes <= ?", *filters.MaxEstimateMinutes)
+	}
+
+	if filters.StartFrom != nil {
+		query = query.Where("start_date >= ?", *filters.StartFrom)
+	}
+
+	if filters.StartTo != nil {
+		query = query.Where("start_date <= ?", *filters.StartTo)
+	}
+
 	if filters.DueFrom != nil {
 		query = query.Where("due_date >= ?", *filters.DueFrom)
 	}
@@ -99,6 +161,12 @@ func (r *AgentRepository) SearchTasks(ctx context.Context, userID string, filter
 		query = query.Where("project_id = ?", *filters.ProjectID)
 	}
 
+	if filters.ParentTaskID != nil {
+		query = query.Where("parent_task_id = ?", *filters.ParentTaskID)
+	} else if filters.TopLevelOnly {
+		query = query.Where("parent_task_id IS NULL")
+	}
+
 	if filters.Search != nil && *filters.Search != "" {
 		searchPattern := "%" + *filters.Search + "%"
 		query = query.Where("title ILIKE ? OR description ILIKE ?", searchPattern, searchPattern)
@@ -108,11 +176,33 @@ func (r *AgentRepository) SearchTasks(ctx context.Context, userID string, filter
 		query = query.Where("collection_id = ?", *filters.CollectionID)
 	}
 
+	// A task must carry every requested tag
+	if len(filters.Tags) > 0 {
+		query = query.Where(`id IN (
+			SELECT tt.task_id FROM task_tags tt
+			JOIN tags t ON t.id = tt.tag_id
+			WHERE t.user_id = ? AND t.deleted_at IS NULL AND LOWER(t.name) IN ?
+			GROUP BY tt.task_id HAVING COUNT(DISTINCT t.id) = ?)`, userID, filters.Tags, len(filters.Tags))
+	}
+
 	query = query.Limit(filters.Limit)
 
+	if column, ok := taskSortColumns[filters.SortBy]; ok {
+		direction := "ASC"
+		if filters.SortDesc {
+			direction = "DESC"
+		}
+		query = query.Order(column + " " + direction + " NULLS LAST")
+	}
 	query = query.Order("due_date ASC NULLS LAST")
 
-	err := query.Preload("Project").Find(&tasks).Error
+	// Tags are personal, so tasks of shared projects show only the user's own
+	err := query.Preload("Project").
+		Preload("WorkflowStatus").
+		Preload("Tags", "deleted_at IS NULL AND user_id = ?", userID).
+		Preload("Subtasks", "deleted_at IS NULL").
+		Preload("BlockedBy").
+		Find(&tasks).Error
 	if err != nil {
 		logger.Log.Error("Failed to search tasks", zap.Error(err), zap.String("userID", userID))
 		return nil, err
@@ -130,15 +220,60 @@ type TaskSearchFilters struct {
 	DueTo        *time.Time `json:"due_to"`
 	ProjectID    *string    `json:"project_id"`
 	Search       *string    `json:"search"`
+	Tags         []string   `json:"tags"`
+	// StatusName is a custom status name, a category or a built-in status name
+	StatusName *string `json:"status_name"`
+	// ParentTaskID limits results to the direct subtasks of a task
+	ParentTaskID *string `json:"parent_task_id"`
+	// TopLevelOnly leaves subtasks out, which are included by default
+	TopLevelOnly bool `json:"top_level_only"`
+	// RecurringOnly limits results to open tasks that carry a recurrence rule
+	RecurringOnly bool `json:"recurring_only"`
+	// OpenOnly leaves out completed and cancelled tasks
+	OpenOnly bool `json:"open_only"`
+	// ActionableOnly leaves out completed, cancelled and blocked tasks
+	ActionableOnly     bool       `json:"actionable_only"`
+	MinPriority        *int       `json:"min_priority"`
+	MaxEstimateMinutes *int       `json:"max_estimate_minutes"`
+	StartFrom          *time.Time `json:"start_from"`
+	StartTo            *time.Time `json:"start_to"`
+	// SortBy is a key of taskSortColumns; results fall back to due date order
+	SortBy   string `json:"sort_by"`
+	SortDesc bool   `json:"sort_desc"`
 }
 
-// List projects
-func (r *AgentRepository) ListProjects(ctx context.Context, userID string) ([]model.Project, error) {
+// taskSortColumns maps the sort keys accepted by search_tasks to columns
+var taskSortColumns = map[string]string{
+	"due_date":   "due_date",
+	"start_date": "start_date",
+	"priority":   "priority",
+	"estimate":   "estimate_minutes",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+	// The user's own order within each list
+	"manual": `sort_order COLLATE "C"`,
+}
+
+// statusCodeByName resolves a lowercased category or built-in status name to its Task.Status code
+func statusCodeByName(name string) (int, bool) {
+	for _, category := range []string{model.StatusCategoryTodo, model.StatusCategoryDoing, model.StatusCategoryDone, model.StatusCategoryCancelled} {
+		if name == category || name == strings.ToLower(model.DefaultStatusName(category)) {
+			return model.StatusCategoryCode(category)
+		}
+	}
+	return 0, false
+}
+
+// List the projects the user sees in the active workspace
+func (r *AgentRepository) ListProjects(ctx context.Context, userID, workspaceID string) ([]model.Project, error) {
 	var projects []model.Project
 
 	err := r.db.WithContext(ctx).
-		Where("user_id = ? AND deleted_at IS NULL", userID).
+		Where("id IN (?) AND deleted_at IS NULL", r.scopeContainerIDs("project_id", userID, workspaceID)).
 		Preload("Tasks").
+		Preload("Statuses", func(db *gorm.DB) *gorm.DB {
+			return db.Where("deleted_at IS NULL").Order("sort_order ASC NULLS LAST, created_at ASC")
+		}).
 		Find(&projects).Error
 
 	if err != nil {
@@ -149,12 +284,12 @@ func (r *AgentRepository) ListProjects(ctx context.Context, userID string) ([]mo
 	return projects, nil
 }
 
-// List collections
-func (r *AgentRepository) ListCollections(ctx context.Context, userID string) ([]model.Collection, error) {
+// List the collections the user sees in the active workspace
+func (r *AgentRepository) ListCollections(ctx context.Context, userID, workspaceID string) ([]model.Collection, error) {
 	var collections []model.Collection
 
 	err := r.db.WithContext(ctx).
-		Where("user_id = ? AND deleted_at IS NULL", userID).
+		Where("id IN (?) AND deleted_at IS NULL", r.scopeContainerIDs("collection_id", userID, workspaceID)).
 		Preload("Notes").
 		Find(&collections).Error
 	if err != nil {
@@ -164,3 +299,98 @@ func (r *AgentRepository) ListCollections(ctx context.Context, userID string) ([
 
 	return collections, nil
 }
+
+// List tags
+func (r *AgentRepository) ListTags(ctx context.Context, userID string) ([]model.Tag, error) {
+	var tags []model.Tag
+
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND deleted_at IS NULL", userID).
+		Order("name ASC").
+		Find(&tags).Error
+	if err != nil {
+		logger.Log.Error("Failed to list tags", zap.Error(err), zap.String("userID", userID))
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// ListComments returns the latest live comments, oldest first and with their authors, on a task or,
+// when taskID is empty, a note the user sees in the active workspace
+func (r *AgentRepository) ListComments(ctx context.Context, userID, workspaceID, taskID, noteID string, limit int) ([]model.Comment, error) {
+	var comments []model.Comment
+
+	table, column, parentColumn, parentID := "tasks", "project_id", "task_id", taskID
+	if taskID == "" {
+		table, column, parentColumn, parentID = "notes", "collection_id", "note_id", noteID
+	}
+	scope, scopeArgs := r.scopeItems(column, userID, workspaceID)
+	parents := r.db.Table(table).Select("id").Where(scope+" AND deleted_at IS NULL", scopeArgs...)
+
+	err := r.db.WithContext(ctx).
+		Preload("User", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id, name")
+		}).
+		Where(parentColumn+" = ? AND "+parentColumn+" IN (?) AND deleted_at IS NULL", parentID, parents).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&comments).Error
+	if err != nil {
+		logger.Log.Error("Failed to list comments", zap.Error(err), zap.String("userID", userID))
+		return nil, err
+	}
+
+	slices.Reverse(comments)
+	return comments, nil
+}
+
+// scopeContainerIDs selects the projects or collections the user sees in a scope, see
+// repository.ScopeContainerIDsSQL. column is project_id or collection_id.
+func (r *AgentRepository) scopeContainerIDs(column, userID, workspaceID string) *gorm.DB {
+	return r.db.Raw(repository.ScopeContainerIDsSQL(column, workspaceID), map[string]any{"user_id": userID, "workspace_id": workspaceID})
+}
+
+// scopeItems returns the condition matching the tasks or notes the user sees in a scope, where
+// column ties them to their project or collection. The personal space also has the user's items in neither.
+func (r *AgentRepository) scopeItems(column, userID, workspaceID string) (string, []interface{}) {
+	condition := "((? AND user_id = ? AND " + column + " IS NULL) OR " + column + " IN (?))"
+	return condition, []interface{}{workspaceID == "", userID, r.scopeContainerIDs(column, userID, workspaceID)}
+}
+
+// loadNoteTags attaches the user's non-deleted tags to notes fetched through a raw query.
+// Tags are personal, so notes of shared collections show only the user's own.
+func (r *AgentRepository) loadNoteTags(ctx context.Context, userID string, notes []model.Note) error {
+	if len(notes) == 0 {
+		return nil
+	}
+
+	noteIDs := make([]string, 0, len(notes))
+	for _, note := range notes {
+		noteIDs = append(noteIDs, note.ID)
+	}
+
+	var rows []struct {
+		NoteID string
+		model.Tag
+	}
+	err := r.db.WithContext(ctx).
+		Table("note_tags").
+		Select("note_tags.note_id, tags.*").
+		Joins("JOIN tags ON tags.id = note_tags.tag_id").
+		Where("note_tags.note_id IN ? AND tags.user_id = ? AND tags.deleted_at IS NULL", noteIDs, userID).
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		for i := range notes {
+			if notes[i].ID == row.NoteID {
+				notes[i].Tags = append(notes[i].Tags, row.Tag)
+			}
+		}
+	}
+
+	return nil
+}
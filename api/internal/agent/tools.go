@@ -1,8 +1,11 @@
 package agent
 
 import (
+	"app/internal/model"
 	"app/pkg/logger"
 	"app/pkg/openai"
+	"app/pkg/recurrence"
+	"app/pkg/util"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -11,6 +14,13 @@ import (
 	"go.uber.org/zap"
 )
 
+const (
+	// recurringTasksLimit caps how many recurring tasks are considered when expanding occurrences
+	recurringTasksLimit = 50
+	// commentsLimit caps how many of the latest comments get_comments returns
+	commentsLimit = 50
+)
+
 type ToolExecutor struct {
 	openaiClient *openai.OpenAIClient
 	repo         *AgentRepository
@@ -24,7 +34,7 @@ func NewToolExecutor(openaiClient *openai.OpenAIClient, repo *AgentRepository) *
 }
 
 // SearchNotesTool executes the search_notes tool
-func (e *ToolExecutor) SearchNotesTool(ctx context.Context, userID string, arguments map[string]interface{}) (string, error) {
+func (e *ToolExecutor) SearchNotesTool(ctx context.Context, userID, workspaceID string, arguments map[string]interface{}) (string, error) {
 	filters := NoteSearchFilters{}
 
 	// query can be empty or omitted
@@ -47,6 +57,9 @@ func (e *ToolExecutor) SearchNotesTool(ctx context.Context, userID string, argum
 		filters.CollectionID = &collectionID
 	}
 
+	// Parse tags
+	filters.Tags = parseTagsArgument(arguments)
+
 	// Only generate embedding if query is NOT empty. If empty, set no QueryEmbedding (broad search)
 	var embedding []float32
 	var err error
@@ -62,7 +75,7 @@ func (e *ToolExecutor) SearchNotesTool(ctx context.Context, userID string, argum
 	}
 
 	// Search notes
-	notes, err := e.repo.SearchNotes(ctx, userID, filters)
+	notes, err := e.repo.SearchNotes(ctx, userID, workspaceID, filters)
 	if err != nil {
 		logger.Log.Error("Failed to search notes", zap.Error(err))
 		return "[]", fmt.Errorf("failed to search notes: %w", err)
@@ -81,6 +94,7 @@ func (e *ToolExecutor) SearchNotesTool(ctx context.Context, userID string, argum
 			"content":          note.Content,
 			"collection_id":    note.CollectionID,
 			"collection_title": collectionTitle,
+			"tags":             tagNames(note.Tags),
 		}
 		results = append(results, result)
 	}
@@ -94,11 +108,25 @@ func (e *ToolExecutor) SearchNotesTool(ctx context.Context, userID string, argum
 }
 
 // SearchTasksTool executes the search_tasks tool
-func (e *ToolExecutor) SearchTasksTool(ctx context.Context, userID string, arguments map[string]interface{}) (string, error) {
+func (e *ToolExecutor) SearchTasksTool(ctx context.Context, userID, workspaceID string, arguments map[string]interface{}) (string, error) {
 	filters := TaskSearchFilters{}
 
+	// Parse limit
+	limit := 10
+	if v, ok := arguments["limit"].(float64); ok {
+		limit = int(v)
+	}
+	if limit > 10 {
+		limit = 10
+	} else if limit < 1 {
+		limit = 1
+	}
+	filters.Limit = limit
+
 	// Parse status
-	if statusVal, ok := arguments["status"].(float64); ok {
+	if statusName, ok := arguments["status"].(string); ok && statusName != "" {
+		filters.StatusName = &statusName
+	} else if statusVal, ok := arguments["status"].(float64); ok {
 		status := int(statusVal)
 		filters.Status = &status
 	}
@@ -123,6 +151,56 @@ func (e *ToolExecutor) SearchTasksTool(ctx context.Context, userID string, argum
 		}
 	}
 
+	// Parse start_from
+	if startFromStr, ok := arguments["start_from"].(string); ok && startFromStr != "" {
+		startFrom, err := time.Parse(time.RFC3339, startFromStr)
+		if err != nil {
+			logger.Log.Warn("Failed to parse start_from", zap.Error(err), zap.String("start_from", startFromStr))
+		} else {
+			filters.StartFrom = &startFrom
+		}
+	}
+
+	// Parse start_to
+	if startToStr, ok := arguments["start_to"].(string); ok && startToStr != "" {
+		startTo, err := time.Parse(time.RFC3339, startToStr)
+		if err != nil {
+			logger.Log.Warn("Failed to parse start_to", zap.Error(err), zap.String("start_to", startToStr))
+		} else {
+			filters.StartTo = &startTo
+		}
+	}
+
+	// Parse open_only
+	if openOnly, ok := arguments["open_only"].(bool); ok {
+		filters.OpenOnly = openOnly
+	}
+
+	// Parse actionable_only
+	if actionableOnly, ok := arguments["actionable_only"].(bool); ok {
+		filters.ActionableOnly = actionableOnly
+	}
+
+	// Parse min_priority
+	if minPriority, ok := arguments["min_priority"].(float64); ok {
+		priority := int(minPriority)
+		filters.MinPriority = &priority
+	}
+
+	// Parse max_estimate_minutes
+	if maxEstimate, ok := arguments["max_estimate_minutes"].(float64); ok {
+		estimate := int(maxEstimate)
+		filters.MaxEstimateMinutes = &estimate
+	}
+
+	// Parse sort_by and sort_order
+	if sortBy, ok := arguments["sort_by"].(string); ok {
+		filters.SortBy = sortBy
+	}
+	if sortOrder, ok := arguments["sort_order"].(string); ok {
+		filters.SortDesc = sortOrder == "desc"
+	}
+
 	// Parse project_id
 	if projectID, ok := arguments["project_id"].(string); ok && projectID != "" {
 		filters.ProjectID = &projectID
@@ -133,45 +211,44 @@ func (e *ToolExecutor) SearchTasksTool(ctx context.Context, userID string, argum
 		filters.Search = &search
 	}
 
+	// Parse tags
+	filters.Tags = parseTagsArgument(arguments)
+
+	// Parse parent_task_id
+	if parentTaskID, ok := arguments["parent_task_id"].(string); ok && parentTaskID != "" {
+		filters.ParentTaskID = &parentTaskID
+	}
+
+	// Parse include_subtasks
+	if includeSubtasks, ok := arguments["include_subtasks"].(bool); ok {
+		filters.TopLevelOnly = !includeSubtasks
+	}
+
 	// Search tasks
-	tasks, err := e.repo.SearchTasks(ctx, userID, filters)
+	tasks, err := e.repo.SearchTasks(ctx, userID, workspaceID, filters)
 	if err != nil {
 		logger.Log.Error("Failed to search tasks", zap.Error(err))
 		return "[]", fmt.Errorf("failed to search tasks: %w", err)
 	}
 
-	// Parse limit
-	limit := 10
-	if v, ok := arguments["limit"].(float64); ok {
-		limit = int(v)
-	}
-	if limit > 10 {
-		limit = 10
-	} else if limit < 1 {
-		limit = 1
-	}
-	filters.Limit = limit
-
 	// Convert to JSON array
 	results := make([]map[string]interface{}, 0, len(tasks))
 	for _, task := range tasks {
-		var projectTitle string
-		if task.Project != nil {
-			projectTitle = *task.Project.Title
-		}
-		result := map[string]interface{}{
-			"id":            task.ID,
-			"title":         task.Title,
-			"description":   task.Description,
-			"status":        task.Status,
-			"due_date":      nil,
-			"project_id":    task.ProjectID,
-			"project_title": projectTitle,
-		}
-		if task.DueDate != nil {
-			result["due_date"] = task.DueDate.Format(time.RFC3339)
+		results = append(results, taskResult(task))
+	}
+
+	// Expand future occurrences of recurring tasks into the requested due window
+	includeRecurrences := true
+	if v, ok := arguments["include_recurrences"].(bool); ok {
+		includeRecurrences = v
+	}
+	if includeRecurrences && (filters.DueFrom != nil || filters.DueTo != nil) {
+		occurrences, err := e.expandRecurringTasks(ctx, userID, workspaceID, filters)
+		if err != nil {
+			logger.Log.Warn("Failed to expand recurring tasks", zap.Error(err))
+		} else {
+			results = append(results, occurrences...)
 		}
-		results = append(results, result)
 	}
 
 	jsonBytes, err := json.Marshal(results)
@@ -182,9 +259,92 @@ func (e *ToolExecutor) SearchTasksTool(ctx context.Context, userID string, argum
 	return string(jsonBytes), nil
 }
 
+// expandRecurringTasks returns the upcoming occurrences of recurring tasks that fall
+// within the filter's due window, excluding each task's current due date
+func (e *ToolExecutor) expandRecurringTasks(ctx context.Context, userID, workspaceID string, filters TaskSearchFilters) ([]map[string]interface{}, error) {
+	from := time.Now()
+	if filters.DueFrom != nil {
+		from = *filters.DueFrom
+	}
+	to := from.AddDate(0, 1, 0)
+	if filters.DueTo != nil {
+		to = *filters.DueTo
+	}
+
+	recurringFilters := filters
+	recurringFilters.RecurringOnly = true
+	recurringFilters.DueFrom = nil
+	recurringFilters.Limit = recurringTasksLimit
+	tasks, err := e.repo.SearchTasks(ctx, userID, workspaceID, recurringFilters)
+	if err != nil {
+		return nil, err
+	}
+
+	results := []map[string]interface{}{}
+	for _, task := range tasks {
+		occurrences, err := recurrence.Between(*task.RecurrenceRule, task.RecurrenceAnchor(), from, to)
+		if err != nil {
+			logger.Log.Warn("Failed to expand recurrence rule", zap.Error(err), zap.String("taskID", task.ID))
+			continue
+		}
+
+		for _, occurrence := range occurrences {
+			// The current occurrence is the task itself
+			if task.DueDate != nil && !occurrence.After(*task.DueDate) {
+				continue
+			}
+			result := taskResult(task)
+			result["due_date"] = occurrence.Format(time.RFC3339)
+			result["status"] = model.DefaultStatusName(model.StatusCategoryTodo)
+			result["status_category"] = model.StatusCategoryTodo
+			result["is_future_occurrence"] = true
+			results = append(results, result)
+			if len(results) >= filters.Limit {
+				return results, nil
+			}
+		}
+	}
+
+	return results, nil
+}
+
+func taskResult(task model.Task) map[string]interface{} {
+	var projectTitle string
+	if task.Project != nil {
+		projectTitle = util.ToValue(task.Project.Title)
+	}
+	result := map[string]interface{}{
+		"id":                 task.ID,
+		"title":              task.Title,
+		"description":        task.Description,
+		"status":             task.StatusName(),
+		"status_category":    model.StatusCategoryOf(task.Status),
+		"priority":           task.Priority,
+		"estimate_minutes":   task.EstimateMinutes,
+		"start_date":         nil,
+		"due_date":           nil,
+		"project_id":         task.ProjectID,
+		"project_title":      projectTitle,
+		"tags":               tagNames(task.Tags),
+		"parent_task_id":     task.ParentTaskID,
+		"subtasks_total":     len(task.Subtasks),
+		"subtasks_completed": countCompletedTasks(task.Subtasks),
+		"recurrence_rule":    task.RecurrenceRule,
+		"blocked":            task.Blocked,
+		"blocked_by":         blockedByTaskIDs(task.BlockedBy),
+	}
+	if task.StartDate != nil {
+		result["start_date"] = task.StartDate.Format(time.RFC3339)
+	}
+	if task.DueDate != nil {
+		result["due_date"] = task.DueDate.Format(time.RFC3339)
+	}
+	return result
+}
+
 // ListCollectionsTool executes the list_collections tool
-func (e *ToolExecutor) ListCollectionsTool(ctx context.Context, userID string) (string, error) {
-	collections, err := e.repo.ListCollections(ctx, userID)
+func (e *ToolExecutor) ListCollectionsTool(ctx context.Context, userID, workspaceID string) (string, error) {
+	collections, err := e.repo.ListCollections(ctx, userID, workspaceID)
 	if err != nil {
 		logger.Log.Error("Failed to list collections", zap.Error(err))
 		return "[]", fmt.Errorf("failed to list collections: %w", err)
@@ -195,6 +355,7 @@ func (e *ToolExecutor) ListCollectionsTool(ctx context.Context, userID string) (
 			"id":          collection.ID,
 			"title":       collection.Title,
 			"notes_count": len(collection.Notes),
+			"shared":      collection.UserID != userID,
 		}
 		results = append(results, result)
 	}
@@ -206,8 +367,8 @@ func (e *ToolExecutor) ListCollectionsTool(ctx context.Context, userID string) (
 }
 
 // ListProjectsTool executes the list_projects tool
-func (e *ToolExecutor) ListProjectsTool(ctx context.Context, userID string) (string, error) {
-	projects, err := e.repo.ListProjects(ctx, userID)
+func (e *ToolExecutor) ListProjectsTool(ctx context.Context, userID, workspaceID string) (string, error) {
+	projects, err := e.repo.ListProjects(ctx, userID, workspaceID)
 	if err != nil {
 		logger.Log.Error("Failed to list projects", zap.Error(err))
 		return "[]", fmt.Errorf("failed to list projects: %w", err)
@@ -218,6 +379,65 @@ func (e *ToolExecutor) ListProjectsTool(ctx context.Context, userID string) (str
 			"id":          project.ID,
 			"title":       project.Title,
 			"tasks_count": len(project.Tasks),
+			"statuses":    statusResults(project.Statuses),
+			"shared":      project.UserID != userID,
+		}
+		results = append(results, result)
+	}
+	jsonBytes, err := json.Marshal(results)
+	if err != nil {
+		return "[]", fmt.Errorf("failed to marshal results: %w", err)
+	}
+	return string(jsonBytes), nil
+}
+
+// GetCommentsTool executes the get_comments tool
+func (e *ToolExecutor) GetCommentsTool(ctx context.Context, userID, workspaceID string, arguments map[string]interface{}) (string, error) {
+	taskID, _ := arguments["task_id"].(string)
+	noteID, _ := arguments["note_id"].(string)
+	if (taskID == "") == (noteID == "") {
+		return "[]", fmt.Errorf("either task_id or note_id is required")
+	}
+
+	comments, err := e.repo.ListComments(ctx, userID, workspaceID, taskID, noteID, commentsLimit)
+	if err != nil {
+		logger.Log.Error("Failed to list comments", zap.Error(err))
+		return "[]", fmt.Errorf("failed to list comments: %w", err)
+	}
+	results := make([]map[string]interface{}, 0, len(comments))
+	for _, comment := range comments {
+		var authorName string
+		if comment.User != nil {
+			authorName = comment.User.Name
+		}
+		results = append(results, map[string]interface{}{
+			"id":         comment.ID,
+			"author":     authorName,
+			"by_me":      comment.UserID == userID,
+			"content":    comment.Content,
+			"created_at": comment.CreatedAt.Format(time.RFC3339),
+			"edited":     comment.EditedAt != nil,
+		})
+	}
+	jsonBytes, err := json.Marshal(results)
+	if err != nil {
+		return "[]", fmt.Errorf("failed to marshal results: %w", err)
+	}
+	return string(jsonBytes), nil
+}
+
+// ListTagsTool executes the list_tags tool
+func (e *ToolExecutor) ListTagsTool(ctx context.Context, userID string) (string, error) {
+	tags, err := e.repo.ListTags(ctx, userID)
+	if err != nil {
+		logger.Log.Error("Failed to list tags", zap.Error(err))
+		return "[]", fmt.Errorf("failed to list tags: %w", err)
+	}
+	results := make([]map[string]interface{}, 0, len(tags))
+	for _, tag := range tags {
+		result := map[string]interface{}{
+			"id":   tag.ID,
+			"name": tag.Name,
 		}
 		results = append(results, result)
 	}
@@ -228,6 +448,62 @@ func (e *ToolExecutor) ListProjectsTool(ctx context.Context, userID string) (str
 	return string(jsonBytes), nil
 }
 
+// parseTagsArgument reads the optional "tags" array argument as normalized tag names
+func parseTagsArgument(arguments map[string]interface{}) []string {
+	rawTags, ok := arguments["tags"].([]interface{})
+	if !ok {
+		return nil
+	}
+	names := make([]string, 0, len(rawTags))
+	for _, rawTag := range rawTags {
+		if name, ok := rawTag.(string); ok {
+			names = append(names, name)
+		}
+	}
+	return util.NormalizeTagNames(names)
+}
+
+func countCompletedTasks(tasks []model.Task) int {
+	count := 0
+	for _, task := range tasks {
+		if task.Status == model.TaskStatusCompleted {
+			count++
+		}
+	}
+	return count
+}
+
+func tagNames(tags []model.Tag) []string {
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	return names
+}
+
+// statusResults lists a project's custom statuses. Projects without any use
+// the built-in "To do", "In progress", "Done" and "Cancelled".
+func statusResults(statuses []model.ProjectStatus) []map[string]interface{} {
+	results := make([]map[string]interface{}, 0, len(statuses))
+	for _, status := range statuses {
+		results = append(results, map[string]interface{}{
+			"id":       status.ID,
+			"name":     status.Name,
+			"category": status.Category,
+			"color":    status.Color,
+		})
+	}
+	return results
+}
+
+func blockedByTaskIDs(dependencies []model.TaskDependency) []string {
+	ids := make([]string, 0, len(dependencies))
+	for _, dependency := range dependencies {
+		ids = append(ids, dependency.BlockedByTaskID)
+	}
+	return ids
+}
+
 // GetToolDefinitions returns the tool definitions for OpenAI
 func GetToolDefinitions() []openai.ChatTool {
 	return []openai.ChatTool{
@@ -253,6 +529,11 @@ func GetToolDefinitions() []openai.ChatTool {
 							"type":        "string",
 							"description": "Filter notes by collection ID (UUID). Only use if user ask for notes in a specific collection.",
 						},
+						"tags": map[string]interface{}{
+							"type":        "array",
+							"items":       map[string]interface{}{"type": "string"},
+							"description": "Only return notes carrying all of these tag names (case-insensitive, leading # optional)",
+						},
 					},
 					"required": []string{"query"},
 				},
@@ -262,13 +543,40 @@ func GetToolDefinitions() []openai.ChatTool {
 			Type: "function",
 			Function: openai.ChatToolFunction{
 				Name:        "search_tasks",
-				Description: "Search and filter tasks by status, due date, project, or keyword search.",
+				Description: "Search and filter tasks by status, priority, estimate, start and due date, project, tags, parent task, or keyword search.",
 				Parameters: map[string]interface{}{
 					"type": "object",
 					"properties": map[string]interface{}{
 						"status": map[string]interface{}{
+							"type":        "string",
+							"description": "Filter by status name, e.g. \"Review\" (see list_projects for each project's statuses), or by category: todo, doing, done, cancelled",
+						},
+						"open_only": map[string]interface{}{
+							"type":        "boolean",
+							"description": "Only return tasks that are neither completed nor cancelled",
+						},
+						"actionable_only": map[string]interface{}{
+							"type":        "boolean",
+							"description": "Only return tasks that can be worked on now: not completed, not cancelled and not blocked by another open task",
+						},
+						"min_priority": map[string]interface{}{
 							"type":        "integer",
-							"description": "Filter by task status (0=pending, 1=in_progress, 2=completed, -1=cancelled)",
+							"description": "Only return tasks with at least this priority (0=none, 1=low, 2=medium, 3=high, 4=urgent)",
+							"minimum":     0,
+							"maximum":     4,
+						},
+						"max_estimate_minutes": map[string]interface{}{
+							"type":        "integer",
+							"description": "Only return tasks estimated to take at most this many minutes. Tasks without an estimate are excluded.",
+							"minimum":     1,
+						},
+						"start_from": map[string]interface{}{
+							"type":        "string",
+							"description": "Filter tasks scheduled to start from this date (RFC3339 format)",
+						},
+						"start_to": map[string]interface{}{
+							"type":        "string",
+							"description": "Filter tasks scheduled to start up to this date (RFC3339 format)",
 						},
 						"due_from": map[string]interface{}{
 							"type":        "string",
@@ -292,6 +600,33 @@ func GetToolDefinitions() []openai.ChatTool {
 							"type":        "string",
 							"description": "Keyword search in task title and description",
 						},
+						"tags": map[string]interface{}{
+							"type":        "array",
+							"items":       map[string]interface{}{"type": "string"},
+							"description": "Only return tasks carrying all of these tag names (case-insensitive, leading # optional)",
+						},
+						"parent_task_id": map[string]interface{}{
+							"type":        "string",
+							"description": "Only return the direct subtasks of this task ID (UUID)",
+						},
+						"include_recurrences": map[string]interface{}{
+							"type":        "boolean",
+							"description": "When due_from or due_to is set, also return future occurrences of recurring tasks in that window, marked with is_future_occurrence (default: true)",
+						},
+						"sort_by": map[string]interface{}{
+							"type":        "string",
+							"enum":        []string{"due_date", "start_date", "priority", "estimate", "created_at", "updated_at", "manual"},
+							"description": "Field to sort by (default: due_date). manual is the order the user arranged tasks in. Ties are broken by due date.",
+						},
+						"sort_order": map[string]interface{}{
+							"type":        "string",
+							"enum":        []string{"asc", "desc"},
+							"description": "Sort direction (default: asc). Use desc with priority for most important first.",
+						},
+						"include_subtasks": map[string]interface{}{
+							"type":        "boolean",
+							"description": "Whether subtasks are returned alongside top-level tasks (default: true). Set false to list top-level tasks only.",
+						},
 					},
 				},
 			},
@@ -300,14 +635,41 @@ func GetToolDefinitions() []openai.ChatTool {
 			Type: "function",
 			Function: openai.ChatToolFunction{
 				Name:        "list_collections",
-				Description: "List all note collections for the user.",
+				Description: "List all note collections for the user, including those shared with them (shared: true).",
 			},
 		},
 		{
 			Type: "function",
 			Function: openai.ChatToolFunction{
 				Name:        "list_projects",
-				Description: "List all task projects for the user.",
+				Description: "List all task projects for the user, including those shared with them (shared: true), with each project's custom workflow statuses.",
+			},
+		},
+		{
+			Type: "function",
+			Function: openai.ChatToolFunction{
+				Name:        "get_comments",
+				Description: "Get the discussion on a task or note, oldest first. Use it when summarising a task or note, or when asked what was said about it.",
+				Parameters: map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"task_id": map[string]interface{}{
+							"type":        "string",
+							"description": "ID (UUID) of the task to get the comments of",
+						},
+						"note_id": map[string]interface{}{
+							"type":        "string",
+							"description": "ID (UUID) of the note to get the comments of. Use instead of task_id.",
+						},
+					},
+				},
+			},
+		},
+		{
+			Type: "function",
+			Function: openai.ChatToolFunction{
+				Name:        "list_tags",
+				Description: "List all tags the user labels notes and tasks with.",
 			},
 		},
 	}
@@ -65,6 +65,9 @@ func setupFiberApp() *fiber.App {
 
 	// Middleware setup
 	app.Use("/v1/auth", middleware.LimiterConfig())
+	// Slows down guessing share link tokens and passwords
+	app.Use("/v1/shared", middleware.LimiterConfig())
+	app.Use(middleware.BodyLimitConfig())
 	app.Use(middleware.LoggerConfig())
 	app.Use(helmet.New())
 	app.Use(compress.New())
@@ -3,6 +3,7 @@ package app
 import (
 	"app/internal/agent"
 	"app/internal/config"
+	"app/internal/cron"
 	"app/internal/handler"
 	"app/internal/repository"
 	"app/internal/usecase"
@@ -49,6 +50,133 @@ func InjectHTTPHandlers(ctx context.Context, app *fiber.App, db *gorm.DB) {
 	syncHandler := handler.NewSyncHandler(syncUsecase)
 	syncHandler.RegisterRoutes(app)
 
+	// Task setup
+	taskRepo := repository.NewTaskRepository(db)
+	taskUsecase := usecase.NewTaskUsecase(taskRepo)
+	taskHandler := handler.NewTaskHandler(taskUsecase)
+	taskHandler.RegisterRoutes(app)
+	_ = cron.NewRecurrenceCron(ctx, taskRepo)
+	_ = cron.NewSortOrderCron(ctx, taskRepo)
+
+	// Member setup
+	membershipRepo := repository.NewMembershipRepository(db)
+	var invitationEmailUsecase *usecase.EmailUsecase
+	if config.Env.SMTPGoogle.Host != "" {
+		invitationEmailUsecase = usecase.NewEmailUsecase()
+	} else {
+		logger.Log.Warn("SMTP not configured, invitation emails disabled")
+	}
+	memberUsecase := usecase.NewMemberUsecase(membershipRepo, userRepo, invitationEmailUsecase)
+	memberHandler := handler.NewMemberHandler(memberUsecase)
+	memberHandler.RegisterRoutes(app)
+
+	// Workspace setup
+	workspaceRepo := repository.NewWorkspaceRepository(db)
+	workspaceUsecase := usecase.NewWorkspaceUsecase(workspaceRepo, membershipRepo, tokenUsecase)
+	workspaceHandler := handler.NewWorkspaceHandler(workspaceUsecase)
+	workspaceHandler.RegisterRoutes(app)
+
+	// Activity setup
+	activityRepo := repository.NewActivityRepository(db)
+	activityUsecase := usecase.NewActivityUsecase(activityRepo, membershipRepo)
+	activityHandler := handler.NewActivityHandler(activityUsecase)
+	activityHandler.RegisterRoutes(app)
+
+	// Webhook setup
+	webhookRepo := repository.NewWebhookRepository(db)
+	webhookUsecase := usecase.NewWebhookUsecase(webhookRepo, membershipRepo)
+	webhookHandler := handler.NewWebhookHandler(webhookUsecase)
+	webhookHandler.RegisterRoutes(app)
+	_ = cron.NewWebhookCron(ctx, webhookUsecase)
+
+	// Rule setup
+	ruleRepo := repository.NewRuleRepository(db)
+	ruleUsecase := usecase.NewRuleUsecase(ruleRepo, syncRepo)
+	ruleHandler := handler.NewRuleHandler(ruleUsecase)
+	ruleHandler.RegisterRoutes(app)
+	_ = cron.NewRuleCron(ctx, ruleUsecase)
+
+	// Board setup
+	boardUsecase := usecase.NewBoardUsecase(taskRepo, syncRepo, membershipRepo)
+	boardHandler := handler.NewBoardHandler(boardUsecase)
+	boardHandler.RegisterRoutes(app)
+
+	// Calendar setup
+	calendarRepo := repository.NewCalendarRepository(db)
+	calendarUsecase := usecase.NewCalendarUsecase(userRepo, calendarRepo)
+	calendarHandler := handler.NewCalendarHandler(calendarUsecase)
+	calendarHandler.RegisterRoutes(app)
+
+	// Insights setup
+	insightsRepo := repository.NewInsightsRepository(db)
+	insightsUsecase := usecase.NewInsightsUsecase(userRepo, insightsRepo)
+	insightsHandler := handler.NewInsightsHandler(insightsUsecase)
+	insightsHandler.RegisterRoutes(app)
+
+	// Reminder setup
+	reminderRepo := repository.NewReminderRepository(db)
+	reminderChannels := []usecase.ReminderChannel{}
+	if config.Env.SMTPGoogle.Host != "" {
+		reminderChannels = append(reminderChannels, usecase.NewEmailReminderChannel(usecase.NewEmailUsecase()))
+	} else {
+		logger.Log.Warn("SMTP not configured, email reminders disabled")
+	}
+	if firebaseUsecase != nil {
+		reminderChannels = append(reminderChannels, usecase.NewPushReminderChannel(firebaseUsecase, reminderRepo))
+	} else {
+		logger.Log.Warn("Firebase not configured, push reminders disabled")
+	}
+	reminderUsecase := usecase.NewReminderUsecase(userRepo, reminderRepo, reminderChannels...)
+	reminderHandler := handler.NewReminderHandler(reminderUsecase)
+	reminderHandler.RegisterRoutes(app)
+	_ = cron.NewReminderCron(ctx, reminderUsecase)
+
+	// Comment setup
+	commentRepo := repository.NewCommentRepository(db)
+	mentionChannels := []usecase.MentionChannel{}
+	if config.Env.SMTPGoogle.Host != "" {
+		mentionChannels = append(mentionChannels, usecase.NewEmailMentionChannel(usecase.NewEmailUsecase()))
+	} else {
+		logger.Log.Warn("SMTP not configured, email mention notifications disabled")
+	}
+	if firebaseUsecase != nil {
+		mentionChannels = append(mentionChannels, usecase.NewPushMentionChannel(firebaseUsecase, reminderRepo))
+	} else {
+		logger.Log.Warn("Firebase not configured, push mention notifications disabled")
+	}
+	mentionUsecase := usecase.NewMentionUsecase(userRepo, commentRepo, mentionChannels...)
+	_ = cron.NewMentionCron(ctx, mentionUsecase)
+
+	// Search setup
+	searchRepo := repository.NewSearchRepository(db)
+	searchUsecase := usecase.NewSearchUsecase(searchRepo, openaiClient)
+	searchHandler := handler.NewSearchHandler(searchUsecase)
+	searchHandler.RegisterRoutes(app)
+
+	// Note setup
+	noteRepo := repository.NewNoteRepository(db)
+	noteUsecase := usecase.NewNoteUsecase(noteRepo)
+	noteHandler := handler.NewNoteHandler(noteUsecase)
+	noteHandler.RegisterRoutes(app)
+
+	// Import setup
+	importUsecase := usecase.NewImportUsecase(syncRepo, userRepo)
+	importHandler := handler.NewImportHandler(importUsecase)
+	importHandler.RegisterRoutes(app)
+
+	// Share setup
+	shareRepo := repository.NewShareRepository(db)
+	shareUsecase := usecase.NewShareUsecase(shareRepo)
+	shareHandler := handler.NewShareHandler(shareUsecase)
+	shareHandler.RegisterRoutes(app)
+
+	// Export setup
+	exportRepo := repository.NewExportRepository(db)
+	exportUsecase := usecase.NewExportUsecase(exportRepo)
+	exportHandler := handler.NewExportHandler(exportUsecase)
+	exportHandler.RegisterRoutes(app)
+	_ = cron.NewExportCron(ctx, exportUsecase)
+
 	// Chat setup
 	chatRepo := repository.NewChatRepository(db)
 	agentRepo := agent.NewAgentRepository(db)
@@ -16,6 +16,8 @@ type Environment struct {
 	Firebase    Firebase
 	OpenAI      OpenAI
 	GoogleOAuth GoogleOAuth
+	Webhook     Webhook
+	Export      Export
 }
 
 type App struct {
@@ -76,6 +78,16 @@ type GoogleOAuth struct {
 	ClientCallbackURI string `env:"GOOGLE_OAUTH_CLIENT_CALLBACK_URI"`
 }
 
+type Webhook struct {
+	// Lets webhooks post to loopback and private addresses, for local development
+	AllowPrivateNetworks bool `env:"WEBHOOK_ALLOW_PRIVATE_NETWORKS"`
+}
+
+type Export struct {
+	// Where finished export archives are kept until they expire. Every server instance must see the same directory.
+	Directory string `env:"EXPORT_DIRECTORY" envDefault:"exports"`
+}
+
 var Env Environment
 
 func init() {
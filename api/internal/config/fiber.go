@@ -7,6 +7,18 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
+const (
+	// DefaultBodyLimit caps request bodies on every route but the ones in RouteBodyLimits
+	DefaultBodyLimit = fiber.DefaultBodyLimit
+	// ImportBodyLimit caps imports, which are uploaded as a single file
+	ImportBodyLimit = 32 << 20
+)
+
+// RouteBodyLimits raises the body limit of the routes under each path prefix
+var RouteBodyLimits = map[string]int{
+	"/v1/import/": ImportBodyLimit,
+}
+
 func FiberConfig() fiber.Config {
 	return fiber.Config{
 		CaseSensitive: true,
@@ -15,5 +27,10 @@ func FiberConfig() fiber.Config {
 		ErrorHandler:  util.ErrorHandler,
 		JSONEncoder:   json.Marshal,
 		JSONDecoder:   json.Unmarshal,
+		// Bodies over the limit are streamed rather than refused, so routes in RouteBodyLimits can
+		// accept more. middleware.BodyLimitConfig caps what is streamed.
+		BodyLimit:                    DefaultBodyLimit,
+		StreamRequestBody:            true,
+		DisablePreParseMultipartForm: true,
 	}
 }
@@ -0,0 +1,36 @@
+package contract
+
+type ActivityReq struct {
+	Page  int `query:"page"`
+	Limit int `query:"limit"`
+}
+
+type ActivityRes struct {
+	ID string `json:"id"`
+	// task, project, note, collection, tag, status or comment
+	EntityType string `json:"entityType"`
+	EntityID   string `json:"entityId"`
+	// create, update, delete or restore
+	Action string `json:"action"`
+	// sync, board, import, rule or system
+	Source    string  `json:"source"`
+	DeviceID  *string `json:"deviceId"`
+	ActorID   string  `json:"actorId"`
+	ActorName string  `json:"actorName"`
+	// The user whose item changed, who differs from the actor on shared projects and collections
+	OwnerID string `json:"ownerId"`
+	// Each column that changed by name, with its value before and after. A create lists every column that is set.
+	// Tasks and notes also list tag_ids, and tasks blocked_by_task_ids and completions, the number of
+	// times the task was completed. A note's content is given as {"sha256", "length"} rather than the text.
+	Changes      map[string]ActivityChangeRes `json:"changes"`
+	ProjectID    *string                      `json:"projectId"`
+	CollectionID *string                      `json:"collectionId"`
+	TaskID       *string                      `json:"taskId"`
+	NoteID       *string                      `json:"noteId"`
+	CreatedAt    string                       `json:"createdAt"`
+}
+
+type ActivityChangeRes struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
@@ -6,10 +6,16 @@ type UserRes struct {
 	Name        string `json:"name"`
 	GoogleImage string `json:"googleImage"`
 	IsVerified  bool   `json:"isVerified"`
+	Timezone    string `json:"timezone"`
 	CreatedAt   string `json:"createdAt"`
 	UpdatedAt   string `json:"updatedAt"`
 }
 
+type UpdateUserReq struct {
+	// IANA time zone name, e.g. "Europe/Berlin"
+	Timezone *string `json:"timezone" validate:"omitempty,timezone"`
+}
+
 type TokenRes struct {
 	AccessToken           string `json:"accessToken"`
 	AccessTokenExpiresAt  string `json:"accessTokenExpiresAt"`
@@ -0,0 +1,55 @@
+package contract
+
+import "app/pkg/util"
+
+type BoardReq struct {
+	// Only return this column: a custom status, or with category alone the built-in status of that category
+	StatusID *string `query:"status_id" validate:"omitempty,uuid"`
+	Category *string `query:"category" validate:"omitempty,oneof=todo doing done cancelled"`
+	// Page of every returned column
+	Page  int `query:"page"`
+	Limit int `query:"limit"`
+}
+
+type BoardRes struct {
+	ProjectID string           `json:"projectId"`
+	Columns   []BoardColumnRes `json:"columns"`
+}
+
+type BoardColumnRes struct {
+	// Null for the built-in status of the category
+	StatusID *string `json:"statusId"`
+	Category string  `json:"category"`
+	Name     string  `json:"name"`
+	Color    *string `json:"color"`
+	// Items are BoardTaskRes in sort order
+	Tasks util.PaginatedData `json:"tasks"`
+}
+
+type BoardTaskRes struct {
+	ID               string   `json:"id"`
+	Title            *string  `json:"title"`
+	Description      *string  `json:"description"`
+	Status           int      `json:"status"`
+	StatusID         *string  `json:"statusId"`
+	SortOrder        *string  `json:"sortOrder"`
+	DueDate          *string  `json:"dueDate"`
+	StartDate        *string  `json:"startDate"`
+	Priority         int      `json:"priority"`
+	EstimateMinutes  *int     `json:"estimateMinutes"`
+	Blocked          bool     `json:"blocked"`
+	BlockedByTaskIDs []string `json:"blockedByTaskIds"`
+	TagIDs           []string `json:"tagIds"`
+	UpdatedAt        string   `json:"updatedAt"`
+}
+
+type MoveBoardTaskReq struct {
+	TaskID string `json:"taskId" validate:"required,uuid"`
+	// Target column: a custom status, or with category alone the built-in status of that category
+	StatusID *string `json:"statusId" validate:"required_without=Category,omitempty,uuid"`
+	Category *string `json:"category" validate:"required_without=StatusID,omitempty,oneof=todo doing done cancelled"`
+	// Place the task right after this task. Omit both neighbours to move the task to the end of the column.
+	AfterTaskID *string `json:"afterTaskId" validate:"omitempty,uuid"`
+	// Place the task right before this task
+	BeforeTaskID *string `json:"beforeTaskId" validate:"omitempty,uuid"`
+}
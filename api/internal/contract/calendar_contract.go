@@ -0,0 +1,66 @@
+package contract
+
+type CalendarReq struct {
+	// First and last day of the range, inclusive, as YYYY-MM-DD
+	From string `query:"from" validate:"required,datetime=2006-01-02"`
+	To   string `query:"to" validate:"required,datetime=2006-01-02"`
+	// IANA time zone the days are in. Defaults to the user's time zone.
+	Timezone         *string `query:"timezone" validate:"omitempty,timezone"`
+	ProjectID        *string `query:"project_id" validate:"omitempty,uuid"`
+	IncludeCompleted bool    `query:"include_completed"`
+}
+
+type CalendarRes struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Timezone string `json:"timezone"`
+	// Open tasks due before the range, or before now if that is earlier. Empty when the range is in the past.
+	Overdue      []CalendarTaskRes `json:"overdue"`
+	OverdueTotal int64             `json:"overdueTotal"`
+	// Every day of the range, including empty ones
+	Days []CalendarDayRes `json:"days"`
+}
+
+type CalendarDayRes struct {
+	Date  string            `json:"date"`
+	Tasks []CalendarTaskRes `json:"tasks"`
+}
+
+type CalendarTaskRes struct {
+	ID              string   `json:"id"`
+	Title           *string  `json:"title"`
+	ProjectID       *string  `json:"projectId"`
+	ParentTaskID    *string  `json:"parentTaskId"`
+	Status          int      `json:"status"`
+	StatusID        *string  `json:"statusId"`
+	StatusName      string   `json:"statusName"`
+	DueDate         string   `json:"dueDate"`
+	StartDate       *string  `json:"startDate"`
+	Priority        int      `json:"priority"`
+	EstimateMinutes *int     `json:"estimateMinutes"`
+	Blocked         bool     `json:"blocked"`
+	TagIDs          []string `json:"tagIds"`
+	Recurring       bool     `json:"recurring"`
+	// Open and due before now
+	Overdue bool `json:"overdue"`
+	// A later occurrence of a recurring task, which doesn't exist as a task yet. ID is the recurring task's.
+	Occurrence bool `json:"occurrence"`
+}
+
+// CalendarFeedRes is the user's feed of the personal space, with the projects shared with them.
+// Workspace tasks aren't in it, whichever workspace is active when it is turned on.
+type CalendarFeedRes struct {
+	Enabled bool `json:"enabled"`
+	// Only returned when the feed is turned on or its token rotated; the server keeps no copy of the token.
+	// Append ?project_id= to export a single project, and kind=todo for VTODO entries instead of events.
+	URL            *string `json:"url,omitempty"`
+	CreatedAt      *string `json:"createdAt"`
+	RotatedAt      *string `json:"rotatedAt"`
+	LastAccessedAt *string `json:"lastAccessedAt"`
+}
+
+type CalendarFeedQuery struct {
+	ProjectID *string `query:"project_id" validate:"omitempty,uuid"`
+	// event (default) for calendars, todo for task and reminder apps
+	Kind string `query:"kind" validate:"omitempty,oneof=event todo"`
+}
@@ -0,0 +1,98 @@
+package contract
+
+import "encoding/json"
+
+type ExportJobRes struct {
+	ID string `json:"id"`
+	// pending, running, completed or failed
+	Status    string  `json:"status"`
+	Error     *string `json:"error,omitempty"`
+	SizeBytes *int64  `json:"sizeBytes,omitempty"`
+	// Path to download the archive from, once it is completed and until it expires
+	DownloadURL *string `json:"downloadUrl,omitempty"`
+	CreatedAt   string  `json:"createdAt"`
+	CompletedAt *string `json:"completedAt,omitempty"`
+	ExpiresAt   *string `json:"expiresAt,omitempty"`
+}
+
+// The types below are the JSON files of an export archive
+
+type ExportTask struct {
+	ID                 string   `json:"id"`
+	ProjectID          *string  `json:"projectId"`
+	ParentTaskID       *string  `json:"parentTaskId"`
+	Title              *string  `json:"title"`
+	Description        *string  `json:"description"`
+	Status             string   `json:"status"`
+	StatusID           *string  `json:"statusId"`
+	SortOrder          *string  `json:"sortOrder"`
+	DueDate            *string  `json:"dueDate"`
+	StartDate          *string  `json:"startDate"`
+	Priority           int      `json:"priority"`
+	EstimateMinutes    *int     `json:"estimateMinutes"`
+	CompletedAt        *string  `json:"completedAt"`
+	RecurrenceRule     *string  `json:"recurrenceRule"`
+	RecurrenceMode     string   `json:"recurrenceMode"`
+	RecurrenceSeriesID *string  `json:"recurrenceSeriesId"`
+	ReminderOffsets    *[]int   `json:"reminderOffsets"`
+	TagIDs             []string `json:"tagIds"`
+	BlockedByTaskIDs   []string `json:"blockedByTaskIds"`
+	CreatedAt          string   `json:"createdAt"`
+	UpdatedAt          string   `json:"updatedAt"`
+}
+
+type ExportProject struct {
+	ID          string                `json:"id"`
+	Title       *string               `json:"title"`
+	Description *string               `json:"description"`
+	Color       *string               `json:"color"`
+	Statuses    []ExportProjectStatus `json:"statuses"`
+	CreatedAt   string                `json:"createdAt"`
+	UpdatedAt   string                `json:"updatedAt"`
+}
+
+type ExportProjectStatus struct {
+	ID        string  `json:"id"`
+	Name      string  `json:"name"`
+	Category  string  `json:"category"`
+	Color     *string `json:"color"`
+	SortOrder *string `json:"sortOrder"`
+}
+
+type ExportCollection struct {
+	ID          string  `json:"id"`
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	Color       *string `json:"color"`
+	// Folder of the collection's notes in the archive
+	Path      string `json:"path"`
+	CreatedAt string `json:"createdAt"`
+	UpdatedAt string `json:"updatedAt"`
+}
+
+type ExportTag struct {
+	ID    string  `json:"id"`
+	Name  string  `json:"name"`
+	Color *string `json:"color"`
+}
+
+type ExportChat struct {
+	ID        string          `json:"id"`
+	CreatedAt string          `json:"createdAt"`
+	Messages  []ExportMessage `json:"messages"`
+}
+
+type ExportMessage struct {
+	ID        string           `json:"id"`
+	Role      string           `json:"role"`
+	Content   *string          `json:"content"`
+	ToolCalls []ExportToolCall `json:"toolCalls"`
+	CreatedAt string           `json:"createdAt"`
+}
+
+type ExportToolCall struct {
+	ID        string          `json:"id"`
+	Name      *string         `json:"name"`
+	Arguments json.RawMessage `json:"arguments"`
+	CreatedAt string          `json:"createdAt"`
+}
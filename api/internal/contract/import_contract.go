@@ -0,0 +1,81 @@
+package contract
+
+type ImportMarkdownRes struct {
+	Created int `json:"created"`
+	Skipped int `json:"skipped"`
+	Failed  int `json:"failed"`
+	// Links between imported notes that were resolved by file name
+	LinksResolved int `json:"linksResolved"`
+	// One entry per file in the archive, in path order
+	Files []ImportFileRes `json:"files"`
+}
+
+type ImportFileRes struct {
+	Path string `json:"path"`
+	// created, skipped or failed
+	Status          string   `json:"status"`
+	NoteID          *string  `json:"noteId,omitempty"`
+	Title           *string  `json:"title,omitempty"`
+	CollectionTitle *string  `json:"collectionTitle,omitempty"`
+	Tags            []string `json:"tags,omitempty"`
+	// Why the file was skipped or failed, or what was imported differently than expected
+	Message *string `json:"message,omitempty"`
+}
+
+// ImportTasksReq holds the form fields sent with a task import file.
+//
+// A JSON file is an array of tasks, or an object with a "tasks" array. Each task is an object with:
+//   - title (required)
+//   - description
+//   - project: the project's title. Projects that don't exist yet are created.
+//   - status: a custom status of the project by name, or a common status such as "todo",
+//     "in progress", "done" or "cancelled"
+//   - priority: none, low, medium, high or urgent, 0 to 4, or p1 (urgent) to p4 (low)
+//   - dueDate, startDate: ISO 8601 dates or times. Those without a UTC offset are in the import's time zone.
+//   - estimateMinutes: minutes, or a duration such as "1h30m"
+//   - tags: an array of names, or a comma separated string
+//
+// CSV rows are read into the same fields through the mapping.
+type ImportTasksReq struct {
+	// csv or json. Defaults to the file's extension.
+	Format string `form:"format" validate:"omitempty,oneof=csv json"`
+	// JSON object mapping task fields to CSV column headers, e.g. {"title":"Task name","dueDate":"Deadline"}.
+	// Columns named like a field, or like the usual headers of other tools' exports, need no mapping.
+	Mapping string `form:"mapping"`
+	// How numeric dates such as 03/04/2025 are read: mdy, dmy or ymd. Defaults to mdy.
+	DateOrder string `form:"date_order" validate:"omitempty,oneof=mdy dmy ymd"`
+	// IANA time zone of dates without a UTC offset. Defaults to the user's time zone.
+	Timezone *string `form:"timezone" validate:"omitempty,timezone"`
+	// Preview the import without saving anything
+	DryRun bool `form:"dry_run"`
+}
+
+type ImportTasksRes struct {
+	DryRun bool `json:"dryRun"`
+	// Tasks created, or that would be created by a dry run
+	Created int `json:"created"`
+	Failed  int `json:"failed"`
+	// Titles of the projects the import created
+	ProjectsCreated []string `json:"projectsCreated"`
+	// One entry per task in the file, in file order
+	Rows []ImportTaskRowRes `json:"rows"`
+}
+
+type ImportTaskRowRes struct {
+	// The CSV line the row starts on, or the 1-based position in the JSON array
+	Row int `json:"row"`
+	// created or failed
+	Status string `json:"status"`
+	// Not set in dry runs
+	TaskID          *string  `json:"taskId,omitempty"`
+	Title           *string  `json:"title,omitempty"`
+	Project         *string  `json:"project,omitempty"`
+	StatusName      *string  `json:"statusName,omitempty"`
+	Priority        *int     `json:"priority,omitempty"`
+	DueDate         *string  `json:"dueDate,omitempty"`
+	StartDate       *string  `json:"startDate,omitempty"`
+	EstimateMinutes *int     `json:"estimateMinutes,omitempty"`
+	Tags            []string `json:"tags,omitempty"`
+	// Why the row failed, or what was imported differently than written
+	Messages []string `json:"messages,omitempty"`
+}
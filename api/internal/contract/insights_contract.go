@@ -0,0 +1,48 @@
+package contract
+
+type InsightsReq struct {
+	// First and last day of the range, inclusive, as YYYY-MM-DD
+	From string `query:"from" validate:"required,datetime=2006-01-02"`
+	To   string `query:"to" validate:"required,datetime=2006-01-02"`
+	// IANA time zone the days are in. Defaults to the user's time zone.
+	Timezone *string `query:"timezone" validate:"omitempty,timezone"`
+}
+
+type InsightsRes struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Timezone string `json:"timezone"`
+	// Every day of the range, including days without completions
+	CompletedPerDay []InsightsDayRes `json:"completedPerDay"`
+	Completed       int64            `json:"completed"`
+	Created         int64            `json:"created"`
+	// Completions in the range after the due date the task had then
+	CompletedLate int64 `json:"completedLate"`
+	// Open tasks that are overdue right now
+	Overdue int64 `json:"overdue"`
+	// Mean hours to each completion in the range, from the task's creation or its previous completion,
+	// null if there were none
+	AverageCompletionHours *float64                `json:"averageCompletionHours"`
+	BusiestProjects        []InsightsProjectRes    `json:"busiestProjects"`
+	NotesPerCollection     []InsightsCollectionRes `json:"notesPerCollection"`
+}
+
+type InsightsDayRes struct {
+	Date  string `json:"date"`
+	Count int64  `json:"count"`
+}
+
+type InsightsProjectRes struct {
+	ProjectID string  `json:"projectId"`
+	Title     *string `json:"title"`
+	Color     *string `json:"color"`
+	Completed int64   `json:"completed"`
+}
+
+type InsightsCollectionRes struct {
+	// Null for notes outside any collection
+	CollectionID *string `json:"collectionId"`
+	Title        *string `json:"title"`
+	Color        *string `json:"color"`
+	Notes        int64   `json:"notes"`
+}
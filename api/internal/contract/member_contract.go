@@ -0,0 +1,69 @@
+package contract
+
+type InviteMemberReq struct {
+	Email string `json:"email" validate:"required,email,max=255"`
+	// viewer reads, editor also writes, owner also manages members and may delete
+	Role string `json:"role" validate:"required,oneof=viewer editor owner"`
+}
+
+type UpdateMemberReq struct {
+	Role string `json:"role" validate:"required,oneof=viewer editor owner"`
+}
+
+type AcceptInvitationReq struct {
+	// Token from the invitation email
+	Token string `json:"token" validate:"required,max=255"`
+}
+
+type MemberRes struct {
+	UserID      string  `json:"userId"`
+	Name        string  `json:"name"`
+	Email       string  `json:"email"`
+	GoogleImage *string `json:"googleImage"`
+	// viewer, editor or owner
+	Role string `json:"role"`
+	// Set for the user the project, collection or workspace belongs to, who can't be removed or change role
+	Creator  bool   `json:"creator"`
+	JoinedAt string `json:"joinedAt"`
+}
+
+type MembersRes struct {
+	// Role of the user asking
+	Role    string      `json:"role"`
+	Members []MemberRes `json:"members"`
+	// Pending invitations, only listed for owners
+	Invitations []InvitationRes `json:"invitations"`
+}
+
+type InvitationRes struct {
+	ID string `json:"id"`
+	// project, collection or workspace
+	Type         string  `json:"type"`
+	ProjectID    *string `json:"projectId"`
+	CollectionID *string `json:"collectionId"`
+	WorkspaceID  *string `json:"workspaceId"`
+	// Title of the project, collection or workspace
+	Title       *string `json:"title"`
+	Email       string  `json:"email"`
+	Role        string  `json:"role"`
+	InviterName string  `json:"inviterName"`
+	// Only returned when the invitation is created. The invitee also finds it in their pending
+	// invitations, so it isn't lost when the email couldn't be sent.
+	EmailSent *bool  `json:"emailSent,omitempty"`
+	ExpiresAt string `json:"expiresAt"`
+	CreatedAt string `json:"createdAt"`
+}
+
+type InvitationsRes struct {
+	Items []InvitationRes `json:"items"`
+}
+
+type MembershipRes struct {
+	// project, collection or workspace
+	Type         string  `json:"type"`
+	ProjectID    *string `json:"projectId"`
+	CollectionID *string `json:"collectionId"`
+	WorkspaceID  *string `json:"workspaceId"`
+	Role         string  `json:"role"`
+	JoinedAt     string  `json:"joinedAt"`
+}
@@ -0,0 +1,61 @@
+package contract
+
+type RelatedNotesReq struct {
+	CollectionID *string `query:"collection_id" validate:"omitempty,uuid"`
+	MinScore     float64 `query:"min_score" validate:"gte=0,lte=1"`
+	Limit        int     `query:"limit" validate:"gte=1,lte=50"`
+}
+
+type RelatedNoteRes struct {
+	ID           string  `json:"id"`
+	CollectionID *string `json:"collectionId"`
+	Title        *string `json:"title"`
+	Score        float64 `json:"score"`
+	UpdatedAt    string  `json:"updatedAt"`
+}
+
+type RelatedNotesRes struct {
+	Items []RelatedNoteRes `json:"items"`
+}
+
+type NoteSummaryRes struct {
+	ID           string  `json:"id"`
+	CollectionID *string `json:"collectionId"`
+	Title        *string `json:"title"`
+	UpdatedAt    string  `json:"updatedAt"`
+}
+
+type BacklinksRes struct {
+	Items []NoteSummaryRes `json:"items"`
+}
+
+type DanglingLinkRes struct {
+	SourceNoteID string  `json:"sourceNoteId"`
+	SourceTitle  *string `json:"sourceTitle"`
+	TargetTitle  string  `json:"targetTitle"`
+}
+
+type DanglingLinksRes struct {
+	Items []DanglingLinkRes `json:"items"`
+}
+
+type ResolveLinksRes struct {
+	Resolved int64 `json:"resolved"`
+}
+
+type NoteGraphNodeRes struct {
+	ID           string  `json:"id"`
+	CollectionID *string `json:"collectionId"`
+	Title        *string `json:"title"`
+	LinkCount    int     `json:"linkCount"`
+}
+
+type NoteGraphEdgeRes struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+type NoteGraphRes struct {
+	Nodes []NoteGraphNodeRes `json:"nodes"`
+	Edges []NoteGraphEdgeRes `json:"edges"`
+}
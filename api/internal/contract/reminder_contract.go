@@ -0,0 +1,40 @@
+package contract
+
+type ReminderSettingsReq struct {
+	// Minutes before a task's due date, used for tasks without their own reminder offsets
+	Offsets []int `json:"offsets" validate:"max=10,dive,gte=0,lte=40320"`
+}
+
+type ReminderSettingsRes struct {
+	Offsets  []int    `json:"offsets"`
+	Channels []string `json:"channels"`
+}
+
+type RegisterDeviceReq struct {
+	Token    string `json:"token" validate:"required,max=4096"`
+	Platform string `json:"platform" validate:"required,oneof=ios android web"`
+}
+
+type UnregisterDeviceReq struct {
+	Token string `json:"token" validate:"required,max=4096"`
+}
+
+type ReminderDeliveriesReq struct {
+	TaskID *string `query:"task_id" validate:"omitempty,uuid"`
+	Status *string `query:"status" validate:"omitempty,oneof=pending sending sent failed skipped cancelled"`
+	Page   int     `query:"page"`
+	Limit  int     `query:"limit"`
+}
+
+type ReminderDeliveryRes struct {
+	ID            string  `json:"id"`
+	TaskID        string  `json:"taskId"`
+	DueDate       string  `json:"dueDate"`
+	OffsetMinutes int     `json:"offsetMinutes"`
+	Channel       string  `json:"channel"`
+	Status        string  `json:"status"`
+	Attempts      int     `json:"attempts"`
+	LastError     *string `json:"lastError"`
+	RemindAt      string  `json:"remindAt"`
+	SentAt        *string `json:"sentAt"`
+}
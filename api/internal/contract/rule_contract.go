@@ -0,0 +1,86 @@
+package contract
+
+import "encoding/json"
+
+// RuleCondition holds when a field of the task or note meets the operator. The field is a column,
+// e.g. title, content, priority, project_id or due_date, or tag_ids for the entity's tags.
+type RuleCondition struct {
+	Field string `json:"field" validate:"required,max=255"`
+	// eq, neq, contains, empty, not_empty, changed, or changed_to. changed and changed_to look at the
+	// change that fired the rule, so they never hold for task.overdue.
+	Op string `json:"op" validate:"required,oneof=eq neq contains empty not_empty changed changed_to"`
+	// Needed by eq, neq, contains and changed_to
+	Value any `json:"value,omitempty"`
+}
+
+// RuleAction is a change a rule makes to the task or note it fired for
+type RuleAction struct {
+	// move_to_project, set_status and set_priority apply to tasks, move_to_collection and
+	// extract_action_items to notes, and add_tag to both
+	Type string `json:"type" validate:"required,oneof=move_to_project move_to_collection add_tag set_status set_priority extract_action_items"`
+	// move_to_project, and extract_action_items to put the new tasks in a project
+	ProjectID *string `json:"projectId,omitempty" validate:"omitempty,uuid"`
+	// move_to_collection
+	CollectionID *string `json:"collectionId,omitempty" validate:"omitempty,uuid"`
+	// add_tag
+	TagID *string `json:"tagId,omitempty" validate:"omitempty,uuid"`
+	// set_status: todo, doing, done or cancelled
+	Status *string `json:"status,omitempty" validate:"omitempty,oneof=todo doing done cancelled"`
+	// set_priority: 0=none, 1=low, 2=medium, 3=high, 4=urgent
+	Priority *int `json:"priority,omitempty" validate:"omitempty,gte=0,lte=4"`
+}
+
+type CreateRuleReq struct {
+	Name string `json:"name" validate:"required,max=255"`
+	// task.created, task.updated, task.completed, task.restored, task.overdue, note.created,
+	// note.updated or note.restored
+	Trigger string `json:"trigger" validate:"required,max=255"`
+	// All must hold for the rule to act
+	Conditions []RuleCondition `json:"conditions" validate:"max=20,dive"`
+	// Applied in order, as one batch of sync changes
+	Actions []RuleAction `json:"actions" validate:"required,min=1,max=10,dive"`
+	// Log the changes the rule would make without making them
+	DryRun bool `json:"dryRun"`
+}
+
+type UpdateRuleReq struct {
+	Name       *string          `json:"name" validate:"omitempty,max=255"`
+	Conditions *[]RuleCondition `json:"conditions" validate:"omitempty,max=20,dive"`
+	Actions    *[]RuleAction    `json:"actions" validate:"omitempty,min=1,max=10,dive"`
+	Active     *bool            `json:"active"`
+	DryRun     *bool            `json:"dryRun"`
+}
+
+type RuleRes struct {
+	ID          string          `json:"id"`
+	Name        string          `json:"name"`
+	Trigger     string          `json:"trigger"`
+	Conditions  []RuleCondition `json:"conditions"`
+	Actions     []RuleAction    `json:"actions"`
+	WorkspaceID *string         `json:"workspaceId"`
+	Active      bool            `json:"active"`
+	DryRun      bool            `json:"dryRun"`
+	CreatedAt   string          `json:"createdAt"`
+	UpdatedAt   string          `json:"updatedAt"`
+}
+
+type RuleExecutionsReq struct {
+	Status *string `query:"status" validate:"omitempty,oneof=pending applied dry_run skipped failed"`
+	Page   int     `query:"page"`
+	Limit  int     `query:"limit"`
+}
+
+type RuleExecutionRes struct {
+	ID         string  `json:"id"`
+	Trigger    string  `json:"trigger"`
+	EntityType string  `json:"entityType"`
+	EntityID   string  `json:"entityId"`
+	ActivityID *string `json:"activityId"`
+	// pending, applied, dry_run, skipped or failed
+	Status string `json:"status"`
+	DryRun bool   `json:"dryRun"`
+	// The sync changes the rule made, or would have made on a dry run
+	Changes   json.RawMessage `json:"changes" swaggertype:"array,object"`
+	Error     *string         `json:"error"`
+	CreatedAt string          `json:"createdAt"`
+}
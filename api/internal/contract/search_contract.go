@@ -0,0 +1,27 @@
+package contract
+
+type SearchReq struct {
+	Query string `query:"q" validate:"required,max=200"`
+	Types string `query:"types"`
+	Tags  string `query:"tags"`
+	Page  int    `query:"page"`
+	Limit int    `query:"limit"`
+}
+
+type SearchItemRes struct {
+	// note, task or comment
+	Type     string `json:"type"`
+	EntityID string `json:"entityId"`
+	// Title of a comment is that of its task or note
+	Title     *string `json:"title"`
+	Snippet   string  `json:"snippet"`
+	Score     float64 `json:"score"`
+	UpdatedAt string  `json:"updatedAt"`
+	// Comment-only: the task or note it is on
+	TaskID *string `json:"taskId,omitempty"`
+	NoteID *string `json:"noteId,omitempty"`
+}
+
+type SearchRes struct {
+	Items []SearchItemRes `json:"items"`
+}
@@ -0,0 +1,73 @@
+package contract
+
+type CreateShareLinkReq struct {
+	// Exactly one of noteId and collectionId
+	NoteID       *string `json:"noteId" validate:"required_without=CollectionID,excluded_with=CollectionID,omitempty,uuid"`
+	CollectionID *string `json:"collectionId" validate:"required_without=NoteID,omitempty,uuid"`
+	// RFC3339 time after which the link stops working. Omit for a link that doesn't expire.
+	ExpiresAt *string `json:"expiresAt" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	// Viewers have to enter this password. Omit for a link anyone with the URL can open.
+	Password *string `json:"password" validate:"omitempty,min=4,max=72"`
+}
+
+type ListShareLinksReq struct {
+	NoteID       *string `query:"note_id" validate:"omitempty,uuid"`
+	CollectionID *string `query:"collection_id" validate:"omitempty,uuid"`
+}
+
+type ShareLinkRes struct {
+	ID string `json:"id"`
+	// note or collection
+	Type         string  `json:"type"`
+	NoteID       *string `json:"noteId"`
+	CollectionID *string `json:"collectionId"`
+	// Title of the shared note or collection
+	Title *string `json:"title"`
+	// Only returned when the link is created; the server keeps no copy of the token.
+	// Append ?format=json for the content as JSON instead of a web page.
+	URL          *string `json:"url,omitempty"`
+	HasPassword  bool    `json:"hasPassword"`
+	ExpiresAt    *string `json:"expiresAt"`
+	Expired      bool    `json:"expired"`
+	ViewCount    int64   `json:"viewCount"`
+	LastViewedAt *string `json:"lastViewedAt"`
+	CreatedAt    string  `json:"createdAt"`
+}
+
+type ShareLinksRes struct {
+	Items []ShareLinkRes `json:"items"`
+}
+
+type SharedContentReq struct {
+	// html (default) for a web page, json for the content
+	Format string `query:"format" validate:"omitempty,oneof=html json"`
+}
+
+type SharedPasswordReq struct {
+	Password string `json:"password" form:"password"`
+}
+
+type SharedContentRes struct {
+	// note or collection
+	Type       string               `json:"type"`
+	Note       *SharedNoteRes       `json:"note,omitempty"`
+	Collection *SharedCollectionRes `json:"collection,omitempty"`
+	ExpiresAt  *string              `json:"expiresAt"`
+}
+
+type SharedNoteRes struct {
+	Title *string `json:"title"`
+	// Markdown source
+	Content *string `json:"content"`
+	// Sanitized HTML rendering of the content
+	HTML      string   `json:"html"`
+	Tags      []string `json:"tags"`
+	CreatedAt string   `json:"createdAt"`
+	UpdatedAt string   `json:"updatedAt"`
+}
+
+type SharedCollectionRes struct {
+	Title       string          `json:"title"`
+	Description string          `json:"description"`
+	Notes       []SharedNoteRes `json:"notes"`
+}
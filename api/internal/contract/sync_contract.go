@@ -3,32 +3,107 @@ package contract
 type SyncReq struct {
 	Changes      []Change `json:"changes" validate:"dive"`
 	LastSyncTime string   `json:"lastSyncTime" validate:"required"`
+	// Identifies the client's device in the activity log
+	DeviceID *string `json:"deviceId,omitempty" validate:"omitempty,max=255"`
 }
 
 type SyncRes struct {
 	Changes      []Change `json:"changes"`
 	LastSyncTime string   `json:"lastSyncTime"`
+	// Changes of the batch that weren't applied because the user may not make them, such as edits
+	// by a viewer. The rest of the batch is applied.
+	Rejected []RejectedChange `json:"rejected"`
+}
+
+// RejectedChange is a change of a sync batch that wasn't applied, and why
+type RejectedChange struct {
+	Type     string `json:"type"`
+	EntityID string `json:"entityId"`
+	Reason   string `json:"reason"`
 }
 
 type Change struct {
-	Type        string  `json:"type" validate:"required,oneof=task project note collection"`
+	Type        string  `json:"type" validate:"required,oneof=task project note collection tag status comment"`
 	EntityID    string  `json:"entityId" validate:"required,uuid"`
 	Title       *string `json:"title,omitempty"`
 	Description *string `json:"description,omitempty"`
 
 	// Task-only
 	ProjectID *string `json:"projectId,omitempty" validate:"omitempty,uuid"`
+	// Fractional index key (base-62, compared byte by byte) among the tasks of the same project
+	// and parent. Send "" to clear. A key that clashes with a sibling's is moved just past it.
+	// A key that isn't a fractional index key is ignored.
 	SortOrder *string `json:"sortOrder,omitempty"`
 	DueDate   *string `json:"dueDate,omitempty"`
-	Status    *int    `json:"status,omitempty"`
-	Content   *string `json:"content,omitempty"`
+	// Status category code: 0=todo, 1=doing, 2=done, -1=cancelled. Derived from statusId when that is sent.
+	Status *int `json:"status,omitempty"`
+	// Custom status of the task's project. Send "" to fall back to the built-in status for the category.
+	StatusID *string `json:"statusId,omitempty" validate:"omitempty,uuid"`
+	// Scheduled date in RFC3339. Send "" to unschedule.
+	StartDate *string `json:"startDate,omitempty"`
+	// 0=none, 1=low, 2=medium, 3=high, 4=urgent
+	Priority *int `json:"priority,omitempty" validate:"omitempty,gte=0,lte=4"`
+	// Send 0 to clear the estimate
+	EstimateMinutes *int    `json:"estimateMinutes,omitempty" validate:"omitempty,gte=0,lte=100000"`
+	Content         *string `json:"content,omitempty"`
+	// Omit to keep the current parent, send "" to detach from it. The server sends "" for top-level tasks.
+	ParentTaskID *string `json:"parentTaskId,omitempty" validate:"omitempty,uuid"`
+	// When completing a task, also complete its subtasks that aren't cancelled
+	CompleteSubtasks *bool `json:"completeSubtasks,omitempty"`
+	// RFC 5545 RRULE, e.g. "FREQ=WEEKLY;BYDAY=MO". Send "" to stop recurring.
+	RecurrenceRule *string `json:"recurrenceRule,omitempty"`
+	RecurrenceMode *string `json:"recurrenceMode,omitempty" validate:"omitempty,oneof=roll spawn"`
+	// Set by the server: the task that started the series, shared by every instance it spawns. Ignored when sent.
+	RecurrenceSeriesID *string `json:"recurrenceSeriesId,omitempty"`
+	// Tasks that must be done before this one. When present, replaces the full set.
+	BlockedByTaskIDs *[]string `json:"blockedByTaskIds,omitempty" validate:"omitempty,max=50,dive,uuid"`
+	// Computed by the server: whether any blocking task is still open. A recurring blocker stops
+	// blocking once an occurrence is completed. Ignored when sent.
+	Blocked *bool `json:"blocked,omitempty"`
+	// Set by the server when the task is completed, cleared when it is reopened. Ignored when sent.
+	CompletedAt *string `json:"completedAt,omitempty"`
+	// Minutes before the due date to send reminders. Omit to keep the current offsets,
+	// send [] to turn reminders off. Tasks that never set offsets use the user's default.
+	ReminderOffsets *[]int `json:"reminderOffsets,omitempty" validate:"omitempty,max=10,dive,gte=0,lte=40320"`
 
-	// Project and collection-only
+	// Project, collection, tag and status-only
 	Color *string `json:"color,omitempty"`
 
+	// Project and collection-only. Set by the server on those shared with the user or in the
+	// active workspace: viewer, editor or owner. Their items come with the project or collection
+	// and carry no tagIds when another user holds them, since tags are personal. Ignored when sent.
+	Role *string `json:"role,omitempty"`
+	// Project and collection-only. Set by the server on those of a workspace. New ones join the
+	// workspace active when they are created and never leave it. They and their items can only be
+	// changed while that workspace is active. Ignored when sent.
+	WorkspaceID *string `json:"workspaceId,omitempty"`
+
+	// Task and note-only. When present, replaces the full set of tags on the entity.
+	TagIDs *[]string `json:"tagIds,omitempty" validate:"omitempty,dive,uuid"`
+
+	// Tag and status-only
+	Name *string `json:"name,omitempty" validate:"omitempty,max=255"`
+
+	// Status-only. Statuses also use projectId and sortOrder.
+	Category *string `json:"category,omitempty" validate:"omitempty,oneof=todo doing done cancelled"`
+
 	// Note-only
 	CollectionID *string `json:"collectionId,omitempty" validate:"omitempty,uuid"`
 
+	// Comment-only, along with content. A new comment is on exactly one task or note and stays on
+	// it. Anyone who sees the task or note may comment, only the author edits a comment, and the
+	// author or an owner of the project or collection may delete it.
+	TaskID *string `json:"taskId,omitempty" validate:"omitempty,uuid"`
+	NoteID *string `json:"noteId,omitempty" validate:"omitempty,uuid"`
+	// Comment-only. Set by the server. Ignored when sent.
+	AuthorID *string `json:"authorId,omitempty"`
+	// Comment-only. Set by the server when the content changes after the comment was created. Ignored when sent.
+	EditedAt *string `json:"editedAt,omitempty"`
+	// Comment-only. Set by the server from the @mentions in the content that name someone who sees
+	// the task or note, by email address, its part before the @, or name without spaces. They are
+	// notified once by email or push. Ignored when sent.
+	MentionedUserIDs *[]string `json:"mentionedUserIds,omitempty"`
+
 	UpdatedAt string  `json:"updatedAt"`
 	CreatedAt string  `json:"createdAt"`
 	DeletedAt *string `json:"deletedAt,omitempty"`
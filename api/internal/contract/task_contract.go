@@ -0,0 +1,13 @@
+package contract
+
+type MoveTaskReq struct {
+	// Place the task right after this sibling. Omit both neighbours to move the task to the end of its list.
+	AfterTaskID *string `json:"afterTaskId" validate:"omitempty,uuid"`
+	// Place the task right before this sibling
+	BeforeTaskID *string `json:"beforeTaskId" validate:"omitempty,uuid"`
+}
+
+type MoveTaskRes struct {
+	TaskID    string `json:"taskId"`
+	SortOrder string `json:"sortOrder"`
+}
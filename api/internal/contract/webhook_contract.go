@@ -0,0 +1,91 @@
+package contract
+
+import "encoding/json"
+
+type CreateWebhookReq struct {
+	// http or https URL the events are posted to
+	URL         string  `json:"url" validate:"required,url,max=2048"`
+	Description *string `json:"description" validate:"omitempty,max=255"`
+	// e.g. task.created, task.updated, task.deleted, task.restored, task.completed, note.updated.
+	// Every entity type synced has created, updated, deleted and restored events. task.completed is
+	// raised for every completion, also of a recurring task that moves on to its next occurrence.
+	// Instances of recurring tasks the server creates raise task.created.
+	EventTypes []string `json:"eventTypes" validate:"required,min=1,max=50,dive,required,max=255"`
+	// Only changes in this project, which may be shared with the user. Without it, the webhook gets
+	// changes to the user's own items and changes the user makes.
+	ProjectID *string `json:"projectId" validate:"omitempty,uuid"`
+}
+
+type UpdateWebhookReq struct {
+	URL         *string   `json:"url" validate:"omitempty,url,max=2048"`
+	Description *string   `json:"description" validate:"omitempty,max=255"`
+	EventTypes  *[]string `json:"eventTypes" validate:"omitempty,min=1,max=50,dive,required,max=255"`
+	// Turned off webhooks get no new events, and their pending deliveries are cancelled
+	Active *bool `json:"active"`
+}
+
+type WebhookRes struct {
+	ID          string   `json:"id"`
+	URL         string   `json:"url"`
+	Description *string  `json:"description"`
+	EventTypes  []string `json:"eventTypes"`
+	ProjectID   *string  `json:"projectId"`
+	Active      bool     `json:"active"`
+	// Key of the X-Memr-Signature header, only returned when the webhook is created
+	Secret    *string `json:"secret,omitempty"`
+	CreatedAt string  `json:"createdAt"`
+	UpdatedAt string  `json:"updatedAt"`
+}
+
+type WebhookDeliveriesReq struct {
+	Status *string `query:"status" validate:"omitempty,oneof=pending sending sent failed cancelled"`
+	Page   int     `query:"page"`
+	Limit  int     `query:"limit"`
+}
+
+type WebhookDeliveryRes struct {
+	ID        string `json:"id"`
+	EventType string `json:"eventType"`
+	// pending, sending, sent, failed or cancelled
+	Status    string  `json:"status"`
+	Attempts  int     `json:"attempts"`
+	LastError *string `json:"lastError"`
+	// HTTP status and start of the body of the endpoint's last response
+	ResponseStatus *int    `json:"responseStatus"`
+	ResponseBody   *string `json:"responseBody"`
+	DurationMs     *int    `json:"durationMs"`
+	// The delivery this one sends again
+	RedeliveryOf  *string         `json:"redeliveryOf"`
+	Payload       json.RawMessage `json:"payload" swaggertype:"object"`
+	NextAttemptAt string          `json:"nextAttemptAt"`
+	SentAt        *string         `json:"sentAt"`
+	CreatedAt     string          `json:"createdAt"`
+}
+
+// WebhookEvent is the JSON body posted to a webhook. It is signed with the webhook's secret: the
+// X-Memr-Signature header is "sha256=" and the hex HMAC-SHA256 of the X-Memr-Timestamp header,
+// a dot and the body.
+type WebhookEvent struct {
+	// Stays the same when the event is redelivered
+	ID string `json:"id"`
+	// e.g. task.completed
+	Type       string `json:"type"`
+	CreatedAt  string `json:"createdAt"`
+	EntityType string `json:"entityType"`
+	EntityID   string `json:"entityId"`
+	// create, update, delete or restore
+	Action string `json:"action"`
+	// sync, board, import, rule or system
+	Source   string  `json:"source"`
+	ActorID  string  `json:"actorId"`
+	OwnerID  string  `json:"ownerId"`
+	DeviceID *string `json:"deviceId"`
+	// Each column that changed by name, with its value before and after
+	Changes      map[string]ActivityChangeRes `json:"changes"`
+	ProjectID    *string                      `json:"projectId"`
+	CollectionID *string                      `json:"collectionId"`
+	TaskID       *string                      `json:"taskId"`
+	NoteID       *string                      `json:"noteId"`
+	// Every column of the entity after the change
+	Entity map[string]any `json:"entity"`
+}
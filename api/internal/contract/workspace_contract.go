@@ -0,0 +1,36 @@
+package contract
+
+type CreateWorkspaceReq struct {
+	Title string `json:"title" validate:"required,max=255"`
+}
+
+type UpdateWorkspaceReq struct {
+	Title string `json:"title" validate:"required,max=255"`
+}
+
+type SwitchWorkspaceReq struct {
+	// Workspace to make active. Omit or send "" for the personal space.
+	WorkspaceID string `json:"workspaceId" validate:"omitempty,uuid"`
+}
+
+type WorkspaceRes struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	// viewer, editor or owner
+	Role string `json:"role"`
+	// Set for the user who created the workspace, under whose account its projects and collections are stored
+	Creator   bool   `json:"creator"`
+	CreatedAt string `json:"createdAt"`
+}
+
+type WorkspacesRes struct {
+	// Active workspace from the token, or empty for the personal space
+	ActiveWorkspaceID string         `json:"activeWorkspaceId"`
+	Items             []WorkspaceRes `json:"items"`
+}
+
+type SwitchWorkspaceRes struct {
+	TokenRes
+	// Active workspace, or empty for the personal space. Sync from scratch after switching.
+	WorkspaceID string `json:"workspaceId"`
+}
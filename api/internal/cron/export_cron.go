@@ -0,0 +1,65 @@
+package cron
+
+import (
+	"app/internal/usecase"
+	"app/pkg/logger"
+	"context"
+
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+const (
+	// EXPORT_CRON_INTERVAL defines how often queued account exports are built and expired ones removed.
+	// Exports normally start right away; this catches the ones that didn't, e.g. after a restart.
+	// "30 * * * * *" means every minute at second 30
+	EXPORT_CRON_INTERVAL = "30 * * * * *"
+)
+
+type ExportCron struct {
+	cron          *cron.Cron
+	ctx           context.Context
+	exportUsecase *usecase.ExportUsecase
+}
+
+func NewExportCron(ctx context.Context, exportUsecase *usecase.ExportUsecase) *ExportCron {
+	// A slow run is skipped rather than overlapped by the next tick
+	c := cron.New(cron.WithSeconds(), cron.WithChain(cron.SkipIfStillRunning(cron.DiscardLogger)))
+
+	exportCron := &ExportCron{
+		cron:          c,
+		ctx:           ctx,
+		exportUsecase: exportUsecase,
+	}
+
+	_, err := c.AddFunc(EXPORT_CRON_INTERVAL, exportCron.process)
+	if err != nil {
+		logger.Log.Error("Failed to schedule export cron job", zap.Error(err))
+		return exportCron
+	}
+
+	// Start cron in a goroutine
+	go func() {
+		c.Start()
+		logger.Log.Info("Export cron job started - will build queued exports every minute")
+
+		// Wait for context cancellation
+		<-ctx.Done()
+		c.Stop()
+		logger.Log.Info("Export cron job stopped")
+	}()
+
+	return exportCron
+}
+
+func (e *ExportCron) process() {
+	processed, err := e.exportUsecase.ProcessPendingExports(e.ctx)
+	if err != nil {
+		logger.Log.Error("Failed to process exports", zap.Error(err))
+		return
+	}
+
+	if processed > 0 {
+		logger.Log.Info("Processed exports", zap.Int("processed", processed))
+	}
+}
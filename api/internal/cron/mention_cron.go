@@ -0,0 +1,64 @@
+package cron
+
+import (
+	"app/internal/usecase"
+	"app/pkg/logger"
+	"context"
+
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+const (
+	// MENTION_CRON_INTERVAL defines how often pending @mention notifications are delivered
+	// "*/15 * * * * *" means every 15 seconds
+	MENTION_CRON_INTERVAL = "*/15 * * * * *"
+)
+
+type MentionCron struct {
+	cron           *cron.Cron
+	ctx            context.Context
+	mentionUsecase *usecase.MentionUsecase
+}
+
+func NewMentionCron(ctx context.Context, mentionUsecase *usecase.MentionUsecase) *MentionCron {
+	// A slow run is skipped rather than overlapped by the next tick
+	c := cron.New(cron.WithSeconds(), cron.WithChain(cron.SkipIfStillRunning(cron.DiscardLogger)))
+
+	mentionCron := &MentionCron{
+		cron:           c,
+		ctx:            ctx,
+		mentionUsecase: mentionUsecase,
+	}
+
+	_, err := c.AddFunc(MENTION_CRON_INTERVAL, mentionCron.dispatch)
+	if err != nil {
+		logger.Log.Error("Failed to schedule mention cron job", zap.Error(err))
+		return mentionCron
+	}
+
+	// Start cron in a goroutine
+	go func() {
+		c.Start()
+		logger.Log.Info("Mention cron job started - will send mention notifications every 15 seconds")
+
+		// Wait for context cancellation
+		<-ctx.Done()
+		c.Stop()
+		logger.Log.Info("Mention cron job stopped")
+	}()
+
+	return mentionCron
+}
+
+func (m *MentionCron) dispatch() {
+	sent, err := m.mentionUsecase.DispatchMentions(m.ctx)
+	if err != nil {
+		logger.Log.Error("Failed to dispatch mention notifications", zap.Error(err))
+		return
+	}
+
+	if sent > 0 {
+		logger.Log.Info("Sent mention notifications", zap.Int("sent", sent))
+	}
+}
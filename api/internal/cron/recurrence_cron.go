@@ -0,0 +1,68 @@
+package cron
+
+import (
+	"app/internal/repository"
+	"app/pkg/logger"
+	"context"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+const (
+	// RECURRENCE_CRON_INTERVAL defines how often upcoming recurring task instances are materialized
+	// "0 5 * * * *" means every hour at minute 5
+	RECURRENCE_CRON_INTERVAL = "0 5 * * * *"
+
+	// RECURRENCE_HORIZON is how far ahead spawn-mode series get their instances created
+	RECURRENCE_HORIZON = 14 * 24 * time.Hour
+
+	// RECURRENCE_MAX_PER_SERIES caps the instances a single series gets per run
+	RECURRENCE_MAX_PER_SERIES = 20
+)
+
+type RecurrenceCron struct {
+	cron     *cron.Cron
+	ctx      context.Context
+	taskRepo *repository.TaskRepository
+}
+
+func NewRecurrenceCron(ctx context.Context, taskRepo *repository.TaskRepository) *RecurrenceCron {
+	c := cron.New(cron.WithSeconds())
+
+	recurrenceCron := &RecurrenceCron{
+		cron:     c,
+		ctx:      ctx,
+		taskRepo: taskRepo,
+	}
+
+	_, err := c.AddFunc(RECURRENCE_CRON_INTERVAL, recurrenceCron.materialize)
+	if err != nil {
+		logger.Log.Error("Failed to schedule recurrence cron job", zap.Error(err))
+		return recurrenceCron
+	}
+
+	// Start cron in a goroutine
+	go func() {
+		c.Start()
+		logger.Log.Info("Recurrence cron job started - will materialize recurring tasks every hour")
+
+		// Wait for context cancellation
+		<-ctx.Done()
+		c.Stop()
+		logger.Log.Info("Recurrence cron job stopped")
+	}()
+
+	return recurrenceCron
+}
+
+func (r *RecurrenceCron) materialize() {
+	created, err := r.taskRepo.MaterializeRecurringTasks(r.ctx, RECURRENCE_HORIZON, RECURRENCE_MAX_PER_SERIES)
+	if err != nil {
+		logger.Log.Error("Failed to materialize recurring tasks", zap.Error(err))
+		return
+	}
+
+	logger.Log.Info("Materialized recurring tasks", zap.Int("created", created))
+}
@@ -0,0 +1,64 @@
+package cron
+
+import (
+	"app/internal/usecase"
+	"app/pkg/logger"
+	"context"
+
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+const (
+	// REMINDER_CRON_INTERVAL defines how often due reminders are enqueued and delivered
+	// "0 * * * * *" means every minute at second 0
+	REMINDER_CRON_INTERVAL = "0 * * * * *"
+)
+
+type ReminderCron struct {
+	cron            *cron.Cron
+	ctx             context.Context
+	reminderUsecase *usecase.ReminderUsecase
+}
+
+func NewReminderCron(ctx context.Context, reminderUsecase *usecase.ReminderUsecase) *ReminderCron {
+	// A slow run is skipped rather than overlapped by the next tick
+	c := cron.New(cron.WithSeconds(), cron.WithChain(cron.SkipIfStillRunning(cron.DiscardLogger)))
+
+	reminderCron := &ReminderCron{
+		cron:            c,
+		ctx:             ctx,
+		reminderUsecase: reminderUsecase,
+	}
+
+	_, err := c.AddFunc(REMINDER_CRON_INTERVAL, reminderCron.dispatch)
+	if err != nil {
+		logger.Log.Error("Failed to schedule reminder cron job", zap.Error(err))
+		return reminderCron
+	}
+
+	// Start cron in a goroutine
+	go func() {
+		c.Start()
+		logger.Log.Info("Reminder cron job started - will send due reminders every minute")
+
+		// Wait for context cancellation
+		<-ctx.Done()
+		c.Stop()
+		logger.Log.Info("Reminder cron job stopped")
+	}()
+
+	return reminderCron
+}
+
+func (r *ReminderCron) dispatch() {
+	sent, err := r.reminderUsecase.DispatchDueReminders(r.ctx)
+	if err != nil {
+		logger.Log.Error("Failed to dispatch reminders", zap.Error(err))
+		return
+	}
+
+	if sent > 0 {
+		logger.Log.Info("Sent reminders", zap.Int("sent", sent))
+	}
+}
@@ -0,0 +1,84 @@
+package cron
+
+import (
+	"app/internal/usecase"
+	"app/pkg/logger"
+	"context"
+
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+const (
+	// RULE_CRON_INTERVAL defines how often scheduled rules are checked
+	// "0 * * * * *" means every minute
+	RULE_CRON_INTERVAL = "0 * * * * *"
+	// RULE_EVENT_CRON_INTERVAL defines how often the rules of queued changes are run
+	// "*/5 * * * * *" means every 5 seconds
+	RULE_EVENT_CRON_INTERVAL = "*/5 * * * * *"
+)
+
+type RuleCron struct {
+	cron        *cron.Cron
+	ctx         context.Context
+	ruleUsecase *usecase.RuleUsecase
+}
+
+func NewRuleCron(ctx context.Context, ruleUsecase *usecase.RuleUsecase) *RuleCron {
+	// A slow run is skipped rather than overlapped by the next tick
+	c := cron.New(cron.WithSeconds(), cron.WithChain(cron.SkipIfStillRunning(cron.DiscardLogger)))
+
+	ruleCron := &RuleCron{
+		cron:        c,
+		ctx:         ctx,
+		ruleUsecase: ruleUsecase,
+	}
+
+	_, err := c.AddFunc(RULE_CRON_INTERVAL, ruleCron.run)
+	if err != nil {
+		logger.Log.Error("Failed to schedule rule cron job", zap.Error(err))
+		return ruleCron
+	}
+	_, err = c.AddFunc(RULE_EVENT_CRON_INTERVAL, ruleCron.runEvents)
+	if err != nil {
+		logger.Log.Error("Failed to schedule rule event cron job", zap.Error(err))
+		return ruleCron
+	}
+
+	// Start cron in a goroutine
+	go func() {
+		c.Start()
+		logger.Log.Info("Rule cron job started - will run the rules of changes every 5 seconds and check scheduled rules every minute")
+
+		// Wait for context cancellation
+		<-ctx.Done()
+		c.Stop()
+		logger.Log.Info("Rule cron job stopped")
+	}()
+
+	return ruleCron
+}
+
+func (m *RuleCron) run() {
+	fired, err := m.ruleUsecase.RunScheduledRules(m.ctx)
+	if err != nil {
+		logger.Log.Error("Failed to run scheduled rules", zap.Error(err))
+		return
+	}
+
+	if fired > 0 {
+		logger.Log.Info("Fired scheduled rules", zap.Int("fired", fired))
+	}
+}
+
+func (m *RuleCron) runEvents() {
+	done, err := m.ruleUsecase.RunEventRules(m.ctx)
+	if err != nil {
+		logger.Log.Error("Failed to run event rules", zap.Error(err))
+		return
+	}
+
+	if done > 0 {
+		logger.Log.Info("Ran event rules", zap.Int("events", done))
+	}
+}
@@ -0,0 +1,65 @@
+package cron
+
+import (
+	"app/internal/repository"
+	"app/pkg/logger"
+	"context"
+
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+const (
+	// SORT_ORDER_CRON_INTERVAL defines how often task lists with worn-out sort orders are rebalanced
+	// "0 35 * * * *" means every hour at minute 35
+	SORT_ORDER_CRON_INTERVAL = "0 35 * * * *"
+
+	// SORT_ORDER_MAX_KEY_LENGTH is the key length past which a list gets fresh keys.
+	// Rebalanced keys are 1 to 3 characters for lists of up to a few hundred thousand tasks.
+	SORT_ORDER_MAX_KEY_LENGTH = 12
+)
+
+type SortOrderCron struct {
+	cron     *cron.Cron
+	ctx      context.Context
+	taskRepo *repository.TaskRepository
+}
+
+func NewSortOrderCron(ctx context.Context, taskRepo *repository.TaskRepository) *SortOrderCron {
+	c := cron.New(cron.WithSeconds())
+
+	sortOrderCron := &SortOrderCron{
+		cron:     c,
+		ctx:      ctx,
+		taskRepo: taskRepo,
+	}
+
+	_, err := c.AddFunc(SORT_ORDER_CRON_INTERVAL, sortOrderCron.rebalance)
+	if err != nil {
+		logger.Log.Error("Failed to schedule sort order cron job", zap.Error(err))
+		return sortOrderCron
+	}
+
+	// Start cron in a goroutine
+	go func() {
+		c.Start()
+		logger.Log.Info("Sort order cron job started - will rebalance task sort orders every hour")
+
+		// Wait for context cancellation
+		<-ctx.Done()
+		c.Stop()
+		logger.Log.Info("Sort order cron job stopped")
+	}()
+
+	return sortOrderCron
+}
+
+func (s *SortOrderCron) rebalance() {
+	rewritten, err := s.taskRepo.RebalanceSortOrders(s.ctx, SORT_ORDER_MAX_KEY_LENGTH)
+	if err != nil {
+		logger.Log.Error("Failed to rebalance task sort orders", zap.Error(err))
+		return
+	}
+
+	logger.Log.Info("Rebalanced task sort orders", zap.Int("rewritten", rewritten))
+}
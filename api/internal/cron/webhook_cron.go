@@ -0,0 +1,64 @@
+package cron
+
+import (
+	"app/internal/usecase"
+	"app/pkg/logger"
+	"context"
+
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+const (
+	// WEBHOOK_CRON_INTERVAL defines how often pending webhook deliveries are posted
+	// "*/15 * * * * *" means every 15 seconds
+	WEBHOOK_CRON_INTERVAL = "*/15 * * * * *"
+)
+
+type WebhookCron struct {
+	cron           *cron.Cron
+	ctx            context.Context
+	webhookUsecase *usecase.WebhookUsecase
+}
+
+func NewWebhookCron(ctx context.Context, webhookUsecase *usecase.WebhookUsecase) *WebhookCron {
+	// A slow run is skipped rather than overlapped by the next tick
+	c := cron.New(cron.WithSeconds(), cron.WithChain(cron.SkipIfStillRunning(cron.DiscardLogger)))
+
+	webhookCron := &WebhookCron{
+		cron:           c,
+		ctx:            ctx,
+		webhookUsecase: webhookUsecase,
+	}
+
+	_, err := c.AddFunc(WEBHOOK_CRON_INTERVAL, webhookCron.dispatch)
+	if err != nil {
+		logger.Log.Error("Failed to schedule webhook cron job", zap.Error(err))
+		return webhookCron
+	}
+
+	// Start cron in a goroutine
+	go func() {
+		c.Start()
+		logger.Log.Info("Webhook cron job started - will post webhook deliveries every 15 seconds")
+
+		// Wait for context cancellation
+		<-ctx.Done()
+		c.Stop()
+		logger.Log.Info("Webhook cron job stopped")
+	}()
+
+	return webhookCron
+}
+
+func (m *WebhookCron) dispatch() {
+	sent, err := m.webhookUsecase.DispatchDeliveries(m.ctx)
+	if err != nil {
+		logger.Log.Error("Failed to dispatch webhook deliveries", zap.Error(err))
+		return
+	}
+
+	if sent > 0 {
+		logger.Log.Info("Sent webhook deliveries", zap.Int("sent", sent))
+	}
+}
@@ -0,0 +1,64 @@
+-- +migrate Up
+-- Minutes before the due date to send a reminder. NULL falls back to the user's default.
+ALTER TABLE "tasks" ADD COLUMN "reminder_offsets" JSONB;
+ALTER TABLE "users" ADD COLUMN "reminder_offsets" JSONB NOT NULL DEFAULT '[]';
+
+CREATE TABLE "device_tokens"(
+    "id" UUID NOT NULL DEFAULT uuid_generate_v4(),
+    "user_id" UUID NOT NULL,
+    "token" TEXT NOT NULL,
+    "platform" VARCHAR(255) NOT NULL,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+ALTER TABLE
+    "device_tokens" ADD PRIMARY KEY("id");
+
+CREATE TABLE "reminder_deliveries"(
+    "id" UUID NOT NULL DEFAULT uuid_generate_v4(),
+    "user_id" UUID NOT NULL,
+    "task_id" UUID NOT NULL,
+    "due_date" TIMESTAMPTZ NOT NULL,
+    "offset_minutes" INTEGER NOT NULL,
+    "channel" VARCHAR(255) NOT NULL,
+    "status" VARCHAR(255) NOT NULL CHECK("status" IN('pending', 'sending', 'sent', 'failed', 'skipped', 'cancelled')) DEFAULT 'pending',
+    "attempts" INTEGER NOT NULL DEFAULT 0,
+    "last_error" TEXT,
+    "remind_at" TIMESTAMPTZ NOT NULL,
+    "next_attempt_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "sent_at" TIMESTAMPTZ,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+ALTER TABLE
+    "reminder_deliveries" ADD PRIMARY KEY("id");
+
+-- Foreign keys
+ALTER TABLE
+    "device_tokens" ADD CONSTRAINT "device_tokens_user_id_foreign" FOREIGN KEY("user_id") REFERENCES "users"("id") ON DELETE CASCADE;
+ALTER TABLE
+    "reminder_deliveries" ADD CONSTRAINT "reminder_deliveries_user_id_foreign" FOREIGN KEY("user_id") REFERENCES "users"("id") ON DELETE CASCADE;
+ALTER TABLE
+    "reminder_deliveries" ADD CONSTRAINT "reminder_deliveries_task_id_foreign" FOREIGN KEY("task_id") REFERENCES "tasks"("id") ON DELETE CASCADE;
+
+-- Indexes
+CREATE UNIQUE INDEX "idx_device_tokens_token" ON "device_tokens"("token");
+CREATE INDEX "idx_device_tokens_user_id" ON "device_tokens"("user_id");
+-- One delivery per occurrence, offset and channel, so a reminder is never sent twice
+CREATE UNIQUE INDEX "idx_reminder_deliveries_dedupe" ON "reminder_deliveries"("task_id", "due_date", "offset_minutes", "channel");
+CREATE INDEX "idx_reminder_deliveries_pending" ON "reminder_deliveries"("next_attempt_at") WHERE "status" IN('pending', 'failed');
+CREATE INDEX "idx_reminder_deliveries_user_id" ON "reminder_deliveries"("user_id", "created_at");
+CREATE INDEX "idx_tasks_due_date" ON "tasks"("due_date") WHERE "due_date" IS NOT NULL AND "deleted_at" IS NULL;
+
+-- +migrate Down
+DROP INDEX IF EXISTS "idx_tasks_due_date";
+DROP INDEX IF EXISTS "idx_reminder_deliveries_user_id";
+DROP INDEX IF EXISTS "idx_reminder_deliveries_pending";
+DROP INDEX IF EXISTS "idx_reminder_deliveries_dedupe";
+DROP INDEX IF EXISTS "idx_device_tokens_user_id";
+DROP INDEX IF EXISTS "idx_device_tokens_token";
+DROP TABLE IF EXISTS "reminder_deliveries";
+DROP TABLE IF EXISTS "device_tokens";
+
+ALTER TABLE "users" DROP COLUMN "reminder_offsets";
+ALTER TABLE "tasks" DROP COLUMN "reminder_offsets";
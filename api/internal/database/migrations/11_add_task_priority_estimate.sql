@@ -0,0 +1,15 @@
+-- +migrate Up
+ALTER TABLE "tasks" ADD COLUMN "priority" SMALLINT NOT NULL CHECK("priority" BETWEEN 0 AND 4) DEFAULT 0;
+ALTER TABLE "tasks" ADD COLUMN "estimate_minutes" INTEGER CHECK("estimate_minutes" > 0);
+ALTER TABLE "tasks" ADD COLUMN "start_date" TIMESTAMPTZ;
+
+CREATE INDEX "idx_tasks_user_id_priority" ON "tasks"("user_id", "priority") WHERE "deleted_at" IS NULL;
+CREATE INDEX "idx_tasks_user_id_start_date" ON "tasks"("user_id", "start_date") WHERE "start_date" IS NOT NULL AND "deleted_at" IS NULL;
+
+-- +migrate Down
+DROP INDEX IF EXISTS "idx_tasks_user_id_start_date";
+DROP INDEX IF EXISTS "idx_tasks_user_id_priority";
+
+ALTER TABLE "tasks" DROP COLUMN "start_date";
+ALTER TABLE "tasks" DROP COLUMN "estimate_minutes";
+ALTER TABLE "tasks" DROP COLUMN "priority";
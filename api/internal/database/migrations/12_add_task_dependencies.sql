@@ -0,0 +1,32 @@
+-- +migrate Up
+CREATE TABLE "task_dependencies"(
+    "task_id" UUID NOT NULL,
+    "blocked_by_task_id" UUID NOT NULL,
+    "user_id" UUID NOT NULL,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY("task_id", "blocked_by_task_id"),
+    CONSTRAINT "task_dependencies_not_self" CHECK("task_id" <> "blocked_by_task_id")
+);
+
+-- Kept up to date whenever dependencies or blocker statuses change, so clients can sync it
+ALTER TABLE "tasks" ADD COLUMN "blocked" BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Foreign keys
+ALTER TABLE
+    "task_dependencies" ADD CONSTRAINT "task_dependencies_task_id_foreign" FOREIGN KEY("task_id") REFERENCES "tasks"("id") ON DELETE CASCADE;
+ALTER TABLE
+    "task_dependencies" ADD CONSTRAINT "task_dependencies_blocked_by_task_id_foreign" FOREIGN KEY("blocked_by_task_id") REFERENCES "tasks"("id") ON DELETE CASCADE;
+ALTER TABLE
+    "task_dependencies" ADD CONSTRAINT "task_dependencies_user_id_foreign" FOREIGN KEY("user_id") REFERENCES "users"("id") ON DELETE CASCADE;
+
+-- Indexes
+CREATE INDEX "idx_task_dependencies_blocked_by_task_id" ON "task_dependencies"("blocked_by_task_id");
+CREATE INDEX "idx_task_dependencies_user_id" ON "task_dependencies"("user_id");
+
+-- +migrate Down
+DROP INDEX IF EXISTS "idx_task_dependencies_user_id";
+DROP INDEX IF EXISTS "idx_task_dependencies_blocked_by_task_id";
+
+ALTER TABLE "tasks" DROP COLUMN "blocked";
+
+DROP TABLE IF EXISTS "task_dependencies";
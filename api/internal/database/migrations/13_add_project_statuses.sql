@@ -0,0 +1,44 @@
+-- +migrate Up
+-- Custom workflow statuses. Each maps to a category, and tasks.status keeps holding
+-- the category code (0=todo, 1=doing, 2=done, -1=cancelled) so existing clients keep working.
+CREATE TABLE "project_statuses"(
+    "id" UUID NOT NULL DEFAULT uuid_generate_v4(),
+    "user_id" UUID NOT NULL,
+    "project_id" UUID NOT NULL,
+    "name" VARCHAR(255) NOT NULL,
+    "category" VARCHAR(255) NOT NULL CHECK("category" IN('todo', 'doing', 'done', 'cancelled')),
+    "sort_order" TEXT,
+    "color" VARCHAR(255),
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" TIMESTAMPTZ
+);
+ALTER TABLE
+    "project_statuses" ADD PRIMARY KEY("id");
+
+ALTER TABLE "tasks" ADD COLUMN "status_id" UUID;
+
+-- Foreign keys
+ALTER TABLE
+    "project_statuses" ADD CONSTRAINT "project_statuses_user_id_foreign" FOREIGN KEY("user_id") REFERENCES "users"("id") ON DELETE CASCADE;
+ALTER TABLE
+    "project_statuses" ADD CONSTRAINT "project_statuses_project_id_foreign" FOREIGN KEY("project_id") REFERENCES "projects"("id") ON DELETE CASCADE;
+ALTER TABLE
+    "tasks" ADD CONSTRAINT "tasks_status_id_foreign" FOREIGN KEY("status_id") REFERENCES "project_statuses"("id") ON DELETE SET NULL;
+
+-- Indexes
+CREATE INDEX "idx_project_statuses_project_id" ON "project_statuses"("project_id");
+CREATE INDEX "idx_project_statuses_user_id_updated_at" ON "project_statuses"("user_id", "updated_at");
+CREATE UNIQUE INDEX "idx_project_statuses_project_id_name" ON "project_statuses"("project_id", LOWER("name")) WHERE "deleted_at" IS NULL;
+CREATE INDEX "idx_tasks_status_id" ON "tasks"("status_id");
+
+-- +migrate Down
+DROP INDEX IF EXISTS "idx_tasks_status_id";
+DROP INDEX IF EXISTS "idx_project_statuses_project_id_name";
+DROP INDEX IF EXISTS "idx_project_statuses_user_id_updated_at";
+DROP INDEX IF EXISTS "idx_project_statuses_project_id";
+
+ALTER TABLE "tasks" DROP CONSTRAINT "tasks_status_id_foreign";
+ALTER TABLE "tasks" DROP COLUMN "status_id";
+
+DROP TABLE IF EXISTS "project_statuses";
@@ -0,0 +1,6 @@
+-- +migrate Up
+-- Sort orders are fractional index keys and compare byte by byte
+CREATE INDEX "idx_tasks_list_sort_order" ON "tasks"("user_id", "project_id", "parent_task_id", "sort_order" COLLATE "C") WHERE "deleted_at" IS NULL;
+
+-- +migrate Down
+DROP INDEX IF EXISTS "idx_tasks_list_sort_order";
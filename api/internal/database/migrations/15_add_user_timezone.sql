@@ -0,0 +1,6 @@
+-- +migrate Up
+-- IANA time zone name, used to split dates into days
+ALTER TABLE "users" ADD COLUMN "timezone" TEXT NOT NULL DEFAULT 'UTC';
+
+-- +migrate Down
+ALTER TABLE "users" DROP COLUMN "timezone";
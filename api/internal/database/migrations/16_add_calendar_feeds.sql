@@ -0,0 +1,20 @@
+-- +migrate Up
+-- Secret iCalendar feed per user. Only a SHA-256 hash of the token is stored.
+CREATE TABLE "calendar_feeds"(
+    "id" UUID NOT NULL DEFAULT uuid_generate_v4(),
+    "user_id" UUID NOT NULL,
+    "token_hash" TEXT NOT NULL,
+    "last_accessed_at" TIMESTAMPTZ,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+ALTER TABLE
+    "calendar_feeds" ADD PRIMARY KEY("id");
+ALTER TABLE
+    "calendar_feeds" ADD CONSTRAINT "calendar_feeds_user_id_foreign" FOREIGN KEY("user_id") REFERENCES "users"("id") ON DELETE CASCADE;
+
+CREATE UNIQUE INDEX "idx_calendar_feeds_user_id" ON "calendar_feeds"("user_id");
+CREATE UNIQUE INDEX "idx_calendar_feeds_token_hash" ON "calendar_feeds"("token_hash");
+
+-- +migrate Down
+DROP TABLE IF EXISTS "calendar_feeds";
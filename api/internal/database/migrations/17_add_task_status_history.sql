@@ -0,0 +1,32 @@
+-- +migrate Up
+-- Set when a task is completed and cleared when it is reopened
+ALTER TABLE "tasks" ADD COLUMN "completed_at" TIMESTAMPTZ;
+
+-- Best guess for tasks completed before completion times were recorded
+UPDATE "tasks" SET "completed_at" = "updated_at" WHERE "status" = 2;
+
+-- One row per status change, so completions survive a recurring task being reopened
+CREATE TABLE "task_status_changes"(
+    "id" UUID NOT NULL DEFAULT uuid_generate_v4(),
+    "task_id" UUID NOT NULL,
+    "user_id" UUID NOT NULL,
+    "from_status" INTEGER,
+    "to_status" INTEGER NOT NULL,
+    "status_id" UUID,
+    "changed_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+ALTER TABLE
+    "task_status_changes" ADD PRIMARY KEY("id");
+ALTER TABLE
+    "task_status_changes" ADD CONSTRAINT "task_status_changes_task_id_foreign" FOREIGN KEY("task_id") REFERENCES "tasks"("id") ON DELETE CASCADE;
+ALTER TABLE
+    "task_status_changes" ADD CONSTRAINT "task_status_changes_user_id_foreign" FOREIGN KEY("user_id") REFERENCES "users"("id") ON DELETE CASCADE;
+
+CREATE INDEX "idx_task_status_changes_user_id_changed_at" ON "task_status_changes"("user_id", "changed_at");
+CREATE INDEX "idx_task_status_changes_task_id" ON "task_status_changes"("task_id");
+CREATE INDEX "idx_tasks_user_id_completed_at" ON "tasks"("user_id", "completed_at") WHERE "completed_at" IS NOT NULL;
+
+-- +migrate Down
+DROP INDEX IF EXISTS "idx_tasks_user_id_completed_at";
+DROP TABLE IF EXISTS "task_status_changes";
+ALTER TABLE "tasks" DROP COLUMN "completed_at";
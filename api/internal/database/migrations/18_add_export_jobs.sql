@@ -0,0 +1,25 @@
+-- +migrate Up
+-- Background account exports. The finished zip is kept in the row until it expires.
+CREATE TABLE "export_jobs"(
+    "id" UUID NOT NULL DEFAULT uuid_generate_v4(),
+    "user_id" UUID NOT NULL,
+    "status" VARCHAR(255) NOT NULL CHECK("status" IN('pending', 'running', 'completed', 'failed')) DEFAULT 'pending',
+    "error" TEXT,
+    "archive" BYTEA,
+    "size_bytes" BIGINT,
+    "started_at" TIMESTAMPTZ,
+    "completed_at" TIMESTAMPTZ,
+    "expires_at" TIMESTAMPTZ,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+ALTER TABLE
+    "export_jobs" ADD PRIMARY KEY("id");
+ALTER TABLE
+    "export_jobs" ADD CONSTRAINT "export_jobs_user_id_foreign" FOREIGN KEY("user_id") REFERENCES "users"("id") ON DELETE CASCADE;
+
+CREATE INDEX "idx_export_jobs_user_id_created_at" ON "export_jobs"("user_id", "created_at");
+CREATE INDEX "idx_export_jobs_pending" ON "export_jobs"("created_at") WHERE "status" = 'pending';
+
+-- +migrate Down
+DROP TABLE IF EXISTS "export_jobs";
@@ -0,0 +1,33 @@
+-- +migrate Up
+-- Public read-only links to a note or a collection. Only SHA-256 hashes of tokens and bcrypt hashes of passwords are stored.
+CREATE TABLE "share_links"(
+    "id" UUID NOT NULL DEFAULT uuid_generate_v4(),
+    "user_id" UUID NOT NULL,
+    "note_id" UUID,
+    "collection_id" UUID,
+    "token_hash" TEXT NOT NULL,
+    "password_hash" TEXT,
+    "expires_at" TIMESTAMPTZ,
+    "revoked_at" TIMESTAMPTZ,
+    "view_count" BIGINT NOT NULL DEFAULT 0,
+    "last_viewed_at" TIMESTAMPTZ,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK(("note_id" IS NULL) <> ("collection_id" IS NULL))
+);
+ALTER TABLE
+    "share_links" ADD PRIMARY KEY("id");
+ALTER TABLE
+    "share_links" ADD CONSTRAINT "share_links_user_id_foreign" FOREIGN KEY("user_id") REFERENCES "users"("id") ON DELETE CASCADE;
+ALTER TABLE
+    "share_links" ADD CONSTRAINT "share_links_note_id_foreign" FOREIGN KEY("note_id") REFERENCES "notes"("id") ON DELETE CASCADE;
+ALTER TABLE
+    "share_links" ADD CONSTRAINT "share_links_collection_id_foreign" FOREIGN KEY("collection_id") REFERENCES "collections"("id") ON DELETE CASCADE;
+
+CREATE UNIQUE INDEX "idx_share_links_token_hash" ON "share_links"("token_hash");
+CREATE INDEX "idx_share_links_user_id_created_at" ON "share_links"("user_id", "created_at");
+CREATE INDEX "idx_share_links_note_id" ON "share_links"("note_id") WHERE "revoked_at" IS NULL;
+CREATE INDEX "idx_share_links_collection_id" ON "share_links"("collection_id") WHERE "revoked_at" IS NULL;
+
+-- +migrate Down
+DROP TABLE IF EXISTS "share_links";
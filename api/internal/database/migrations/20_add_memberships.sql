@@ -0,0 +1,69 @@
+-- +migrate Up
+-- Members of a project or a collection besides the user who owns it. Removed members keep their row
+-- until they sync again, so their devices learn to drop what was shared.
+CREATE TABLE "memberships"(
+    "id" UUID NOT NULL DEFAULT uuid_generate_v4(),
+    "user_id" UUID NOT NULL,
+    "project_id" UUID,
+    "collection_id" UUID,
+    "role" VARCHAR(16) NOT NULL CHECK("role" IN ('viewer', 'editor', 'owner')),
+    "invited_by" UUID,
+    "removed_at" TIMESTAMPTZ,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK(("project_id" IS NULL) <> ("collection_id" IS NULL))
+);
+ALTER TABLE
+    "memberships" ADD PRIMARY KEY("id");
+ALTER TABLE
+    "memberships" ADD CONSTRAINT "memberships_user_id_foreign" FOREIGN KEY("user_id") REFERENCES "users"("id") ON DELETE CASCADE;
+ALTER TABLE
+    "memberships" ADD CONSTRAINT "memberships_project_id_foreign" FOREIGN KEY("project_id") REFERENCES "projects"("id") ON DELETE CASCADE;
+ALTER TABLE
+    "memberships" ADD CONSTRAINT "memberships_collection_id_foreign" FOREIGN KEY("collection_id") REFERENCES "collections"("id") ON DELETE CASCADE;
+ALTER TABLE
+    "memberships" ADD CONSTRAINT "memberships_invited_by_foreign" FOREIGN KEY("invited_by") REFERENCES "users"("id") ON DELETE SET NULL;
+
+CREATE UNIQUE INDEX "idx_memberships_user_id_project_id" ON "memberships"("user_id", "project_id") WHERE "removed_at" IS NULL AND "project_id" IS NOT NULL;
+CREATE UNIQUE INDEX "idx_memberships_user_id_collection_id" ON "memberships"("user_id", "collection_id") WHERE "removed_at" IS NULL AND "collection_id" IS NOT NULL;
+CREATE INDEX "idx_memberships_project_id" ON "memberships"("project_id") WHERE "project_id" IS NOT NULL;
+CREATE INDEX "idx_memberships_collection_id" ON "memberships"("collection_id") WHERE "collection_id" IS NOT NULL;
+CREATE INDEX "idx_memberships_user_id_updated_at" ON "memberships"("user_id", "updated_at");
+
+-- Invitations to join a project or a collection, sent by email. Only a SHA-256 hash of the token is stored.
+CREATE TABLE "invitations"(
+    "id" UUID NOT NULL DEFAULT uuid_generate_v4(),
+    "inviter_id" UUID NOT NULL,
+    "project_id" UUID,
+    "collection_id" UUID,
+    "email" VARCHAR(255) NOT NULL,
+    "role" VARCHAR(16) NOT NULL CHECK("role" IN ('viewer', 'editor', 'owner')),
+    "token_hash" TEXT NOT NULL,
+    "accepted_by" UUID,
+    "accepted_at" TIMESTAMPTZ,
+    "declined_at" TIMESTAMPTZ,
+    "revoked_at" TIMESTAMPTZ,
+    "expires_at" TIMESTAMPTZ NOT NULL,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK(("project_id" IS NULL) <> ("collection_id" IS NULL))
+);
+ALTER TABLE
+    "invitations" ADD PRIMARY KEY("id");
+ALTER TABLE
+    "invitations" ADD CONSTRAINT "invitations_inviter_id_foreign" FOREIGN KEY("inviter_id") REFERENCES "users"("id") ON DELETE CASCADE;
+ALTER TABLE
+    "invitations" ADD CONSTRAINT "invitations_project_id_foreign" FOREIGN KEY("project_id") REFERENCES "projects"("id") ON DELETE CASCADE;
+ALTER TABLE
+    "invitations" ADD CONSTRAINT "invitations_collection_id_foreign" FOREIGN KEY("collection_id") REFERENCES "collections"("id") ON DELETE CASCADE;
+ALTER TABLE
+    "invitations" ADD CONSTRAINT "invitations_accepted_by_foreign" FOREIGN KEY("accepted_by") REFERENCES "users"("id") ON DELETE SET NULL;
+
+CREATE UNIQUE INDEX "idx_invitations_token_hash" ON "invitations"("token_hash");
+CREATE INDEX "idx_invitations_email" ON "invitations"(LOWER("email")) WHERE "accepted_at" IS NULL AND "declined_at" IS NULL AND "revoked_at" IS NULL;
+CREATE INDEX "idx_invitations_project_id" ON "invitations"("project_id") WHERE "project_id" IS NOT NULL;
+CREATE INDEX "idx_invitations_collection_id" ON "invitations"("collection_id") WHERE "collection_id" IS NOT NULL;
+
+-- +migrate Down
+DROP TABLE IF EXISTS "invitations";
+DROP TABLE IF EXISTS "memberships";
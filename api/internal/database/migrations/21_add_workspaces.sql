@@ -0,0 +1,71 @@
+-- +migrate Up
+-- Team workspaces that own projects and collections. Their rows are stored under the account of the
+-- user who created the workspace, who always counts as its owner and has no membership of their own.
+CREATE TABLE "workspaces"(
+    "id" UUID NOT NULL DEFAULT uuid_generate_v4(),
+    "user_id" UUID NOT NULL,
+    "title" VARCHAR(255) NOT NULL,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" TIMESTAMPTZ
+);
+ALTER TABLE
+    "workspaces" ADD PRIMARY KEY("id");
+ALTER TABLE
+    "workspaces" ADD CONSTRAINT "workspaces_user_id_foreign" FOREIGN KEY("user_id") REFERENCES "users"("id") ON DELETE CASCADE;
+
+ALTER TABLE "projects" ADD COLUMN "workspace_id" UUID;
+ALTER TABLE "collections" ADD COLUMN "workspace_id" UUID;
+ALTER TABLE "chats" ADD COLUMN "workspace_id" UUID;
+
+-- Workspace members and invitations share the tables of project and collection ones
+ALTER TABLE "memberships" ADD COLUMN "workspace_id" UUID;
+ALTER TABLE "memberships" DROP CONSTRAINT "memberships_check";
+ALTER TABLE "memberships" ADD CONSTRAINT "memberships_check" CHECK(num_nonnulls("project_id", "collection_id", "workspace_id") = 1);
+ALTER TABLE "invitations" ADD COLUMN "workspace_id" UUID;
+ALTER TABLE "invitations" DROP CONSTRAINT "invitations_check";
+ALTER TABLE "invitations" ADD CONSTRAINT "invitations_check" CHECK(num_nonnulls("project_id", "collection_id", "workspace_id") = 1);
+
+-- Foreign keys
+ALTER TABLE
+    "projects" ADD CONSTRAINT "projects_workspace_id_foreign" FOREIGN KEY("workspace_id") REFERENCES "workspaces"("id") ON DELETE SET NULL;
+ALTER TABLE
+    "collections" ADD CONSTRAINT "collections_workspace_id_foreign" FOREIGN KEY("workspace_id") REFERENCES "workspaces"("id") ON DELETE SET NULL;
+ALTER TABLE
+    "chats" ADD CONSTRAINT "chats_workspace_id_foreign" FOREIGN KEY("workspace_id") REFERENCES "workspaces"("id") ON DELETE CASCADE;
+ALTER TABLE
+    "memberships" ADD CONSTRAINT "memberships_workspace_id_foreign" FOREIGN KEY("workspace_id") REFERENCES "workspaces"("id") ON DELETE CASCADE;
+ALTER TABLE
+    "invitations" ADD CONSTRAINT "invitations_workspace_id_foreign" FOREIGN KEY("workspace_id") REFERENCES "workspaces"("id") ON DELETE CASCADE;
+
+-- Indexes
+CREATE INDEX "idx_workspaces_user_id" ON "workspaces"("user_id") WHERE "deleted_at" IS NULL;
+CREATE INDEX "idx_projects_workspace_id" ON "projects"("workspace_id") WHERE "workspace_id" IS NOT NULL;
+CREATE INDEX "idx_collections_workspace_id" ON "collections"("workspace_id") WHERE "workspace_id" IS NOT NULL;
+CREATE INDEX "idx_chats_user_id_workspace_id" ON "chats"("user_id", "workspace_id");
+CREATE UNIQUE INDEX "idx_memberships_user_id_workspace_id" ON "memberships"("user_id", "workspace_id") WHERE "removed_at" IS NULL AND "workspace_id" IS NOT NULL;
+CREATE INDEX "idx_memberships_workspace_id" ON "memberships"("workspace_id") WHERE "workspace_id" IS NOT NULL;
+CREATE INDEX "idx_invitations_workspace_id" ON "invitations"("workspace_id") WHERE "workspace_id" IS NOT NULL;
+
+-- +migrate Down
+DROP INDEX IF EXISTS "idx_invitations_workspace_id";
+DROP INDEX IF EXISTS "idx_memberships_workspace_id";
+DROP INDEX IF EXISTS "idx_memberships_user_id_workspace_id";
+DROP INDEX IF EXISTS "idx_chats_user_id_workspace_id";
+DROP INDEX IF EXISTS "idx_collections_workspace_id";
+DROP INDEX IF EXISTS "idx_projects_workspace_id";
+
+DELETE FROM "invitations" WHERE "workspace_id" IS NOT NULL;
+ALTER TABLE "invitations" DROP CONSTRAINT "invitations_check";
+ALTER TABLE "invitations" DROP COLUMN "workspace_id";
+ALTER TABLE "invitations" ADD CONSTRAINT "invitations_check" CHECK(("project_id" IS NULL) <> ("collection_id" IS NULL));
+DELETE FROM "memberships" WHERE "workspace_id" IS NOT NULL;
+ALTER TABLE "memberships" DROP CONSTRAINT "memberships_check";
+ALTER TABLE "memberships" DROP COLUMN "workspace_id";
+ALTER TABLE "memberships" ADD CONSTRAINT "memberships_check" CHECK(("project_id" IS NULL) <> ("collection_id" IS NULL));
+
+ALTER TABLE "chats" DROP COLUMN "workspace_id";
+ALTER TABLE "collections" DROP COLUMN "workspace_id";
+ALTER TABLE "projects" DROP COLUMN "workspace_id";
+
+DROP TABLE IF EXISTS "workspaces";
@@ -0,0 +1,69 @@
+-- +migrate Up
+-- Discussion on a task or a note. Unlike the items themselves, a comment stays with the user who
+-- wrote it, even on a shared project or collection.
+CREATE TABLE "comments"(
+    "id" UUID NOT NULL DEFAULT uuid_generate_v4(),
+    "user_id" UUID NOT NULL,
+    "task_id" UUID,
+    "note_id" UUID,
+    "content" TEXT NOT NULL,
+    -- Users the content @mentions who see the task or note
+    "mentioned_user_ids" JSONB NOT NULL DEFAULT '[]',
+    "edited_at" TIMESTAMPTZ,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" TIMESTAMPTZ,
+    CHECK(("task_id" IS NULL) <> ("note_id" IS NULL))
+);
+ALTER TABLE
+    "comments" ADD PRIMARY KEY("id");
+ALTER TABLE "comments" ADD COLUMN "search_vector" TSVECTOR GENERATED ALWAYS AS (
+    to_tsvector('simple', COALESCE("content", ''))
+) STORED;
+
+-- The notification of each user a comment ever mentioned. A user is notified once per comment,
+-- however often it is edited.
+CREATE TABLE "comment_mentions"(
+    "id" UUID NOT NULL DEFAULT uuid_generate_v4(),
+    "comment_id" UUID NOT NULL,
+    "user_id" UUID NOT NULL,
+    "status" VARCHAR(255) NOT NULL CHECK("status" IN('pending', 'sending', 'sent', 'failed', 'skipped', 'cancelled')) DEFAULT 'pending',
+    "attempts" INTEGER NOT NULL DEFAULT 0,
+    "last_error" TEXT,
+    "next_attempt_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "sent_at" TIMESTAMPTZ,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+ALTER TABLE
+    "comment_mentions" ADD PRIMARY KEY("id");
+
+-- Foreign keys
+ALTER TABLE
+    "comments" ADD CONSTRAINT "comments_user_id_foreign" FOREIGN KEY("user_id") REFERENCES "users"("id") ON DELETE CASCADE;
+ALTER TABLE
+    "comments" ADD CONSTRAINT "comments_task_id_foreign" FOREIGN KEY("task_id") REFERENCES "tasks"("id") ON DELETE CASCADE;
+ALTER TABLE
+    "comments" ADD CONSTRAINT "comments_note_id_foreign" FOREIGN KEY("note_id") REFERENCES "notes"("id") ON DELETE CASCADE;
+ALTER TABLE
+    "comment_mentions" ADD CONSTRAINT "comment_mentions_comment_id_foreign" FOREIGN KEY("comment_id") REFERENCES "comments"("id") ON DELETE CASCADE;
+ALTER TABLE
+    "comment_mentions" ADD CONSTRAINT "comment_mentions_user_id_foreign" FOREIGN KEY("user_id") REFERENCES "users"("id") ON DELETE CASCADE;
+
+-- Indexes
+CREATE INDEX "idx_comments_task_id" ON "comments"("task_id", "created_at") WHERE "task_id" IS NOT NULL;
+CREATE INDEX "idx_comments_note_id" ON "comments"("note_id", "created_at") WHERE "note_id" IS NOT NULL;
+CREATE INDEX "idx_comments_updated_at" ON "comments"("updated_at");
+CREATE INDEX "idx_comments_search_vector" ON "comments" USING GIN("search_vector");
+CREATE UNIQUE INDEX "idx_comment_mentions_comment_id_user_id" ON "comment_mentions"("comment_id", "user_id");
+CREATE INDEX "idx_comment_mentions_pending" ON "comment_mentions"("next_attempt_at") WHERE "status" IN('pending', 'failed');
+
+-- +migrate Down
+DROP INDEX IF EXISTS "idx_comment_mentions_pending";
+DROP INDEX IF EXISTS "idx_comment_mentions_comment_id_user_id";
+DROP INDEX IF EXISTS "idx_comments_search_vector";
+DROP INDEX IF EXISTS "idx_comments_updated_at";
+DROP INDEX IF EXISTS "idx_comments_note_id";
+DROP INDEX IF EXISTS "idx_comments_task_id";
+DROP TABLE IF EXISTS "comment_mentions";
+DROP TABLE IF EXISTS "comments";
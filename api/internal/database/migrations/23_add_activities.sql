@@ -0,0 +1,59 @@
+-- +migrate Up
+-- Append-only log of every create, update, delete and restore of a synced entity. Entries keep
+-- their context IDs without foreign keys, so they outlive the entities they are about.
+CREATE TABLE "activities"(
+    "id" UUID NOT NULL DEFAULT uuid_generate_v4(),
+    -- The user who made the change
+    "actor_id" UUID NOT NULL,
+    -- The user whose row changed, who differs from the actor on shared projects and collections
+    "owner_id" UUID NOT NULL,
+    "entity_type" VARCHAR(255) NOT NULL CHECK("entity_type" IN('task', 'project', 'note', 'collection', 'tag', 'status', 'comment')),
+    "entity_id" UUID NOT NULL,
+    "action" VARCHAR(255) NOT NULL CHECK("action" IN('create', 'update', 'delete', 'restore')),
+    -- What made the change: sync, board or import
+    "source" VARCHAR(255) NOT NULL,
+    -- The device the client said it synced from, if any
+    "device_id" VARCHAR(255),
+    -- {"field": {"from": ..., "to": ...}} for each column that changed
+    "changes" JSONB NOT NULL DEFAULT '{}',
+    "project_id" UUID,
+    "collection_id" UUID,
+    "task_id" UUID,
+    "note_id" UUID,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+ALTER TABLE
+    "activities" ADD PRIMARY KEY("id");
+
+-- +migrate StatementBegin
+CREATE FUNCTION "reject_activity_update"() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'activities are append-only';
+END;
+$$ LANGUAGE plpgsql;
+-- +migrate StatementEnd
+CREATE TRIGGER "activities_append_only" BEFORE UPDATE ON "activities"
+    FOR EACH ROW EXECUTE FUNCTION "reject_activity_update"();
+
+-- Foreign keys
+ALTER TABLE
+    "activities" ADD CONSTRAINT "activities_actor_id_foreign" FOREIGN KEY("actor_id") REFERENCES "users"("id") ON DELETE CASCADE;
+ALTER TABLE
+    "activities" ADD CONSTRAINT "activities_owner_id_foreign" FOREIGN KEY("owner_id") REFERENCES "users"("id") ON DELETE CASCADE;
+
+-- Indexes
+CREATE INDEX "idx_activities_actor_id" ON "activities"("actor_id", "created_at");
+CREATE INDEX "idx_activities_owner_id" ON "activities"("owner_id", "created_at");
+CREATE INDEX "idx_activities_project_id" ON "activities"("project_id", "created_at") WHERE "project_id" IS NOT NULL;
+CREATE INDEX "idx_activities_note_id" ON "activities"("note_id", "created_at") WHERE "note_id" IS NOT NULL;
+CREATE INDEX "idx_activities_entity" ON "activities"("entity_type", "entity_id", "created_at");
+
+-- +migrate Down
+DROP INDEX IF EXISTS "idx_activities_entity";
+DROP INDEX IF EXISTS "idx_activities_note_id";
+DROP INDEX IF EXISTS "idx_activities_project_id";
+DROP INDEX IF EXISTS "idx_activities_owner_id";
+DROP INDEX IF EXISTS "idx_activities_actor_id";
+DROP TRIGGER IF EXISTS "activities_append_only" ON "activities";
+DROP FUNCTION IF EXISTS "reject_activity_update";
+DROP TABLE IF EXISTS "activities";
@@ -0,0 +1,68 @@
+-- +migrate Up
+-- An endpoint a user wants told about changes to their items, or to a project they are a member of
+CREATE TABLE "webhooks"(
+    "id" UUID NOT NULL DEFAULT uuid_generate_v4(),
+    "user_id" UUID NOT NULL,
+    -- Only changes in this project when set
+    "project_id" UUID,
+    "url" TEXT NOT NULL,
+    "description" VARCHAR(255),
+    -- Key of the HMAC-SHA256 signature of each payload. Kept as is since every delivery needs it.
+    "secret" VARCHAR(255) NOT NULL,
+    -- Event types the webhook subscribes to, e.g. ["task.created", "task.completed"]
+    "event_types" JSONB NOT NULL DEFAULT '[]',
+    "active" BOOLEAN NOT NULL DEFAULT TRUE,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" TIMESTAMPTZ
+);
+ALTER TABLE
+    "webhooks" ADD PRIMARY KEY("id");
+
+-- Each event sent, or to send, to a webhook, with the outcome of the last attempt
+CREATE TABLE "webhook_deliveries"(
+    "id" UUID NOT NULL DEFAULT uuid_generate_v4(),
+    "webhook_id" UUID NOT NULL,
+    -- The activity log entry the event was raised for, kept without a foreign key like the log itself
+    "activity_id" UUID,
+    "event_type" VARCHAR(255) NOT NULL,
+    "payload" JSONB NOT NULL,
+    "status" VARCHAR(255) NOT NULL CHECK("status" IN('pending', 'sending', 'sent', 'failed', 'cancelled')) DEFAULT 'pending',
+    "attempts" INTEGER NOT NULL DEFAULT 0,
+    "last_error" TEXT,
+    "response_status" INTEGER,
+    "response_body" TEXT,
+    "duration_ms" INTEGER,
+    -- The delivery this one sends again, when it was redelivered on request
+    "redelivery_of" UUID,
+    "next_attempt_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "sent_at" TIMESTAMPTZ,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+ALTER TABLE
+    "webhook_deliveries" ADD PRIMARY KEY("id");
+
+-- Foreign keys
+ALTER TABLE
+    "webhooks" ADD CONSTRAINT "webhooks_user_id_foreign" FOREIGN KEY("user_id") REFERENCES "users"("id") ON DELETE CASCADE;
+ALTER TABLE
+    "webhooks" ADD CONSTRAINT "webhooks_project_id_foreign" FOREIGN KEY("project_id") REFERENCES "projects"("id") ON DELETE CASCADE;
+ALTER TABLE
+    "webhook_deliveries" ADD CONSTRAINT "webhook_deliveries_webhook_id_foreign" FOREIGN KEY("webhook_id") REFERENCES "webhooks"("id") ON DELETE CASCADE;
+ALTER TABLE
+    "webhook_deliveries" ADD CONSTRAINT "webhook_deliveries_redelivery_of_foreign" FOREIGN KEY("redelivery_of") REFERENCES "webhook_deliveries"("id") ON DELETE SET NULL;
+
+-- Indexes
+CREATE INDEX "idx_webhooks_user_id" ON "webhooks"("user_id") WHERE "deleted_at" IS NULL;
+CREATE INDEX "idx_webhooks_project_id" ON "webhooks"("project_id") WHERE "deleted_at" IS NULL AND "project_id" IS NOT NULL;
+CREATE INDEX "idx_webhook_deliveries_webhook_id" ON "webhook_deliveries"("webhook_id", "created_at");
+CREATE INDEX "idx_webhook_deliveries_pending" ON "webhook_deliveries"("next_attempt_at") WHERE "status" IN('pending', 'failed');
+
+-- +migrate Down
+DROP INDEX IF EXISTS "idx_webhook_deliveries_pending";
+DROP INDEX IF EXISTS "idx_webhook_deliveries_webhook_id";
+DROP INDEX IF EXISTS "idx_webhooks_project_id";
+DROP INDEX IF EXISTS "idx_webhooks_user_id";
+DROP TABLE IF EXISTS "webhook_deliveries";
+DROP TABLE IF EXISTS "webhooks";
@@ -0,0 +1,71 @@
+-- +migrate Up
+-- An automation of a user's: when its trigger fires for a task or note that meets its conditions,
+-- its actions are applied as sync changes made on the user's behalf
+CREATE TABLE "rules"(
+    "id" UUID NOT NULL DEFAULT uuid_generate_v4(),
+    "user_id" UUID NOT NULL,
+    -- The workspace the rule runs in, or the personal space when NULL
+    "workspace_id" UUID,
+    "name" VARCHAR(255) NOT NULL,
+    -- e.g. task.created, task.completed, note.updated, or task.overdue which is checked on a schedule
+    "trigger" VARCHAR(255) NOT NULL,
+    -- [{"field": "priority", "op": "eq", "value": 4}], all of which must hold
+    "conditions" JSONB NOT NULL DEFAULT '[]',
+    -- [{"type": "add_tag", "tag_id": "..."}], applied in order
+    "actions" JSONB NOT NULL DEFAULT '[]',
+    "active" BOOLEAN NOT NULL DEFAULT TRUE,
+    -- Log the changes the rule would make without making them
+    "dry_run" BOOLEAN NOT NULL DEFAULT FALSE,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" TIMESTAMPTZ
+);
+ALTER TABLE
+    "rules" ADD PRIMARY KEY("id");
+
+-- Each time a rule fired for an entity, with the changes it made or would have made
+CREATE TABLE "rule_executions"(
+    "id" UUID NOT NULL DEFAULT uuid_generate_v4(),
+    "rule_id" UUID NOT NULL,
+    "trigger" VARCHAR(255) NOT NULL,
+    "entity_type" VARCHAR(255) NOT NULL,
+    "entity_id" UUID NOT NULL,
+    -- The activity log entry that fired the rule, kept without a foreign key like the log itself
+    "activity_id" UUID,
+    -- What fired the rule for the entity: the activity ID, or the due date for task.overdue. A rule
+    -- fires once per key, so a tick that overlaps the last one doesn't repeat it.
+    "trigger_key" VARCHAR(255) NOT NULL,
+    "status" VARCHAR(255) NOT NULL CHECK("status" IN('pending', 'applied', 'dry_run', 'skipped', 'failed')) DEFAULT 'pending',
+    "dry_run" BOOLEAN NOT NULL DEFAULT FALSE,
+    -- The sync changes the actions made, or would have made on a dry run
+    "changes" JSONB NOT NULL DEFAULT '[]',
+    "error" TEXT,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+ALTER TABLE
+    "rule_executions" ADD PRIMARY KEY("id");
+
+-- Foreign keys
+ALTER TABLE
+    "rules" ADD CONSTRAINT "rules_user_id_foreign" FOREIGN KEY("user_id") REFERENCES "users"("id") ON DELETE CASCADE;
+ALTER TABLE
+    "rules" ADD CONSTRAINT "rules_workspace_id_foreign" FOREIGN KEY("workspace_id") REFERENCES "workspaces"("id") ON DELETE CASCADE;
+ALTER TABLE
+    "rule_executions" ADD CONSTRAINT "rule_executions_rule_id_foreign" FOREIGN KEY("rule_id") REFERENCES "rules"("id") ON DELETE CASCADE;
+
+-- Indexes
+CREATE INDEX "idx_rules_user_id" ON "rules"("user_id") WHERE "deleted_at" IS NULL;
+CREATE INDEX "idx_rules_workspace_id" ON "rules"("workspace_id") WHERE "deleted_at" IS NULL AND "workspace_id" IS NOT NULL;
+CREATE INDEX "idx_rules_trigger" ON "rules"("trigger") WHERE "deleted_at" IS NULL AND "active";
+CREATE UNIQUE INDEX "idx_rule_executions_trigger_key" ON "rule_executions"("rule_id", "entity_id", "trigger_key");
+CREATE INDEX "idx_rule_executions_rule_id" ON "rule_executions"("rule_id", "created_at");
+
+-- +migrate Down
+DROP INDEX IF EXISTS "idx_rule_executions_rule_id";
+DROP INDEX IF EXISTS "idx_rule_executions_trigger_key";
+DROP INDEX IF EXISTS "idx_rules_trigger";
+DROP INDEX IF EXISTS "idx_rules_workspace_id";
+DROP INDEX IF EXISTS "idx_rules_user_id";
+DROP TABLE IF EXISTS "rule_executions";
+DROP TABLE IF EXISTS "rules";
@@ -0,0 +1,20 @@
+-- +migrate Up
+-- The due date a task had when its status changed, so a completion can be judged late after
+-- a recurring task has moved on to its next due date
+ALTER TABLE "task_status_changes" ADD COLUMN "due_date" TIMESTAMPTZ;
+
+-- Only tasks that never moved on still have the due date they were completed against
+UPDATE "task_status_changes" c SET "due_date" = t."due_date"
+FROM "tasks" t
+WHERE t."id" = c."task_id" AND c."to_status" = 2
+    AND (t."recurrence_rule" IS NULL OR t."recurrence_mode" = 'spawn');
+
+-- Completions from before the history was kept, at the time migration 17 guessed for them
+INSERT INTO "task_status_changes" ("task_id", "user_id", "from_status", "to_status", "status_id", "changed_at", "due_date")
+SELECT t."id", t."user_id", 0, 2, t."status_id", t."completed_at", t."due_date"
+FROM "tasks" t
+WHERE t."status" = 2 AND t."completed_at" IS NOT NULL
+    AND NOT EXISTS (SELECT 1 FROM "task_status_changes" c WHERE c."task_id" = t."id" AND c."to_status" = 2);
+
+-- +migrate Down
+ALTER TABLE "task_status_changes" DROP COLUMN "due_date";
@@ -0,0 +1,11 @@
+-- +migrate Up
+-- Finished archives are written to files instead of being kept in the row
+ALTER TABLE "export_jobs" ADD COLUMN "archive_path" TEXT;
+
+-- Archives stored in rows can't be moved to files here, so they expire and get cleaned up
+UPDATE "export_jobs" SET "expires_at" = CURRENT_TIMESTAMP WHERE "archive" IS NOT NULL;
+ALTER TABLE "export_jobs" DROP COLUMN "archive";
+
+-- +migrate Down
+ALTER TABLE "export_jobs" ADD COLUMN "archive" BYTEA;
+ALTER TABLE "export_jobs" DROP COLUMN "archive_path";
@@ -0,0 +1,27 @@
+-- +migrate Up
+-- The activity log entries that may fire rules, queued in the transaction that logged them and
+-- removed once the rule worker has run the rules for them
+CREATE TABLE "rule_events"(
+    "id" UUID NOT NULL DEFAULT uuid_generate_v4(),
+    -- Kept without a foreign key like the log itself
+    "activity_id" UUID NOT NULL,
+    -- The workspace the changed item is in, or the personal space when NULL
+    "workspace_id" UUID,
+    "attempts" INTEGER NOT NULL DEFAULT 0,
+    -- When a worker last took the event, which another worker may take again once it's stale
+    "claimed_at" TIMESTAMPTZ,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+ALTER TABLE
+    "rule_events" ADD PRIMARY KEY("id");
+
+-- Foreign keys
+ALTER TABLE
+    "rule_events" ADD CONSTRAINT "rule_events_workspace_id_foreign" FOREIGN KEY("workspace_id") REFERENCES "workspaces"("id") ON DELETE CASCADE;
+
+-- Indexes
+CREATE INDEX "idx_rule_events_created_at" ON "rule_events"("created_at");
+
+-- +migrate Down
+DROP INDEX IF EXISTS "idx_rule_events_created_at";
+DROP TABLE IF EXISTS "rule_events";
@@ -0,0 +1,19 @@
+-- +migrate Up
+ALTER TABLE "notes" ADD COLUMN "search_vector" TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', COALESCE("title", '')), 'A') ||
+    setweight(to_tsvector('simple', COALESCE("content", '')), 'B')
+) STORED;
+ALTER TABLE "tasks" ADD COLUMN "search_vector" TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', COALESCE("title", '')), 'A') ||
+    setweight(to_tsvector('simple', COALESCE("description", '')), 'B')
+) STORED;
+
+CREATE INDEX "idx_notes_search_vector" ON "notes" USING GIN("search_vector");
+CREATE INDEX "idx_tasks_search_vector" ON "tasks" USING GIN("search_vector");
+
+-- +migrate Down
+DROP INDEX IF EXISTS "idx_tasks_search_vector";
+DROP INDEX IF EXISTS "idx_notes_search_vector";
+
+ALTER TABLE "tasks" DROP COLUMN "search_vector";
+ALTER TABLE "notes" DROP COLUMN "search_vector";
@@ -0,0 +1,49 @@
+-- +migrate Up
+CREATE TABLE "tags"(
+    "id" UUID NOT NULL DEFAULT uuid_generate_v4(),
+    "user_id" UUID NOT NULL,
+    "name" VARCHAR(255) NOT NULL,
+    "color" VARCHAR(255),
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "updated_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    "deleted_at" TIMESTAMPTZ
+);
+ALTER TABLE
+    "tags" ADD PRIMARY KEY("id");
+
+CREATE TABLE "note_tags"(
+    "note_id" UUID NOT NULL,
+    "tag_id" UUID NOT NULL,
+    PRIMARY KEY("note_id", "tag_id")
+);
+
+CREATE TABLE "task_tags"(
+    "task_id" UUID NOT NULL,
+    "tag_id" UUID NOT NULL,
+    PRIMARY KEY("task_id", "tag_id")
+);
+
+-- Foreign keys
+ALTER TABLE
+    "tags" ADD CONSTRAINT "tags_user_id_foreign" FOREIGN KEY("user_id") REFERENCES "users"("id") ON DELETE CASCADE;
+ALTER TABLE
+    "note_tags" ADD CONSTRAINT "note_tags_note_id_foreign" FOREIGN KEY("note_id") REFERENCES "notes"("id") ON DELETE CASCADE;
+ALTER TABLE
+    "note_tags" ADD CONSTRAINT "note_tags_tag_id_foreign" FOREIGN KEY("tag_id") REFERENCES "tags"("id") ON DELETE CASCADE;
+ALTER TABLE
+    "task_tags" ADD CONSTRAINT "task_tags_task_id_foreign" FOREIGN KEY("task_id") REFERENCES "tasks"("id") ON DELETE CASCADE;
+ALTER TABLE
+    "task_tags" ADD CONSTRAINT "task_tags_tag_id_foreign" FOREIGN KEY("tag_id") REFERENCES "tags"("id") ON DELETE CASCADE;
+
+-- Indexes
+CREATE UNIQUE INDEX "idx_tags_user_id_name" ON "tags"("user_id", LOWER("name")) WHERE "deleted_at" IS NULL;
+CREATE INDEX "idx_note_tags_tag_id" ON "note_tags"("tag_id");
+CREATE INDEX "idx_task_tags_tag_id" ON "task_tags"("tag_id");
+
+-- +migrate Down
+DROP INDEX IF EXISTS "idx_task_tags_tag_id";
+DROP INDEX IF EXISTS "idx_note_tags_tag_id";
+DROP INDEX IF EXISTS "idx_tags_user_id_name";
+DROP TABLE IF EXISTS "task_tags";
+DROP TABLE IF EXISTS "note_tags";
+DROP TABLE IF EXISTS "tags";
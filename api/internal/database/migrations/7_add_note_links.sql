@@ -0,0 +1,30 @@
+-- +migrate Up
+CREATE TABLE "note_links"(
+    "id" UUID NOT NULL DEFAULT uuid_generate_v4(),
+    "user_id" UUID NOT NULL,
+    "source_note_id" UUID NOT NULL,
+    "target_note_id" UUID,
+    "target_title" TEXT NOT NULL,
+    "created_at" TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+ALTER TABLE
+    "note_links" ADD PRIMARY KEY("id");
+
+-- Foreign keys
+ALTER TABLE
+    "note_links" ADD CONSTRAINT "note_links_user_id_foreign" FOREIGN KEY("user_id") REFERENCES "users"("id") ON DELETE CASCADE;
+ALTER TABLE
+    "note_links" ADD CONSTRAINT "note_links_source_note_id_foreign" FOREIGN KEY("source_note_id") REFERENCES "notes"("id") ON DELETE CASCADE;
+ALTER TABLE
+    "note_links" ADD CONSTRAINT "note_links_target_note_id_foreign" FOREIGN KEY("target_note_id") REFERENCES "notes"("id") ON DELETE SET NULL;
+
+-- Indexes
+CREATE INDEX "idx_note_links_source_note_id" ON "note_links"("source_note_id");
+CREATE INDEX "idx_note_links_target_note_id" ON "note_links"("target_note_id");
+CREATE INDEX "idx_note_links_dangling" ON "note_links"("user_id", LOWER("target_title")) WHERE "target_note_id" IS NULL;
+
+-- +migrate Down
+DROP INDEX IF EXISTS "idx_note_links_dangling";
+DROP INDEX IF EXISTS "idx_note_links_target_note_id";
+DROP INDEX IF EXISTS "idx_note_links_source_note_id";
+DROP TABLE IF EXISTS "note_links";
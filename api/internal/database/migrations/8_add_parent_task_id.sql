@@ -0,0 +1,15 @@
+-- +migrate Up
+ALTER TABLE "tasks" ADD COLUMN "parent_task_id" UUID;
+
+ALTER TABLE "tasks" ADD CONSTRAINT "tasks_parent_task_id_foreign" FOREIGN KEY("parent_task_id") REFERENCES "tasks"("id") ON DELETE CASCADE;
+ALTER TABLE "tasks" ADD CONSTRAINT "tasks_parent_task_id_not_self" CHECK("parent_task_id" <> "id");
+
+CREATE INDEX "idx_tasks_parent_task_id" ON "tasks"("parent_task_id");
+
+-- +migrate Down
+DROP INDEX IF EXISTS "idx_tasks_parent_task_id";
+
+ALTER TABLE "tasks" DROP CONSTRAINT "tasks_parent_task_id_not_self";
+ALTER TABLE "tasks" DROP CONSTRAINT "tasks_parent_task_id_foreign";
+
+ALTER TABLE "tasks" DROP COLUMN "parent_task_id";
@@ -0,0 +1,17 @@
+-- +migrate Up
+ALTER TABLE "tasks" ADD COLUMN "recurrence_rule" TEXT;
+ALTER TABLE "tasks" ADD COLUMN "recurrence_mode" TEXT NOT NULL CHECK("recurrence_mode" IN('roll', 'spawn')) DEFAULT 'roll';
+ALTER TABLE "tasks" ADD COLUMN "recurrence_start" TIMESTAMPTZ;
+ALTER TABLE "tasks" ADD COLUMN "recurrence_series_id" UUID;
+
+CREATE INDEX "idx_tasks_recurrence_rule" ON "tasks"("recurrence_mode", "due_date") WHERE "recurrence_rule" IS NOT NULL AND "deleted_at" IS NULL;
+CREATE UNIQUE INDEX "idx_tasks_recurrence_series_id_due_date" ON "tasks"("recurrence_series_id", "due_date") WHERE "recurrence_series_id" IS NOT NULL AND "deleted_at" IS NULL;
+
+-- +migrate Down
+DROP INDEX IF EXISTS "idx_tasks_recurrence_series_id_due_date";
+DROP INDEX IF EXISTS "idx_tasks_recurrence_rule";
+
+ALTER TABLE "tasks" DROP COLUMN "recurrence_series_id";
+ALTER TABLE "tasks" DROP COLUMN "recurrence_start";
+ALTER TABLE "tasks" DROP COLUMN "recurrence_mode";
+ALTER TABLE "tasks" DROP COLUMN "recurrence_rule";
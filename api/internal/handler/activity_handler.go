@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"app/internal/contract"
+	"app/internal/middleware"
+	"app/internal/usecase"
+	"app/pkg/logger"
+	"app/pkg/util"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type ActivityHandler struct {
+	activityUsecase *usecase.ActivityUsecase
+}
+
+func NewActivityHandler(activityUsecase *usecase.ActivityUsecase) *ActivityHandler {
+	return &ActivityHandler{activityUsecase: activityUsecase}
+}
+
+func (h *ActivityHandler) RegisterRoutes(app *fiber.App) {
+	app.Get("/v1/activity", middleware.AuthGuard(), h.ListUserActivity)
+	app.Get("/v1/projects/:project_id/activity", middleware.AuthGuard(), h.ListProjectActivity)
+	app.Get("/v1/notes/:note_id/activity", middleware.AuthGuard(), h.ListNoteActivity)
+}
+
+// @Tags Activity
+// @Summary List my activity
+// @Description List the changes the user made and the changes others made to the user's items, newest first.
+// @Description Every create, update, delete and restore through sync, the board or an import is logged with the columns it changed,
+// @Description along with what it did to other items, such as subtasks, blocked tasks and recurring task instances. Tasks and notes
+// @Description log their tag_ids and a task its blocked_by_task_ids; a note's content is logged as its SHA-256 digest and length.
+// @Description Changes the server makes on its own schedule are logged with source system and credited to the owner.
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number (default: 1)" default(1)
+// @Param limit query int false "Items per page (default: 20)" default(20)
+// @Success 200 {object} util.PaginatedResponse{data=util.PaginatedData{items=[]contract.ActivityRes}}
+// @Failure 400 {object} util.BaseResponse
+// @Failure 401 {object} util.BaseResponse
+// @Router /v1/activity [get]
+func (h *ActivityHandler) ListUserActivity(c *fiber.Ctx) error {
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	req, err := parseActivityReq(c)
+	if err != nil {
+		return err
+	}
+
+	items, total, err := h.activityUsecase.ListUserActivity(c.Context(), claims.ID, req)
+	if err != nil {
+		logger.Log.Error("Failed to list user activity", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToPaginatedResponse(items, req.Page, req.Limit, total))
+}
+
+// @Tags Activity
+// @Summary List project activity
+// @Description List the changes to a project, its statuses, its tasks and their comments, newest first. Any member of the project may read it.
+// @Produce json
+// @Security BearerAuth
+// @Param project_id path string true "Project ID"
+// @Param page query int false "Page number (default: 1)" default(1)
+// @Param limit query int false "Items per page (default: 20)" default(20)
+// @Success 200 {object} util.PaginatedResponse{data=util.PaginatedData{items=[]contract.ActivityRes}}
+// @Failure 400 {object} util.BaseResponse
+// @Failure 401 {object} util.BaseResponse
+// @Failure 404 {object} util.BaseResponse
+// @Router /v1/projects/{project_id}/activity [get]
+func (h *ActivityHandler) ListProjectActivity(c *fiber.Ctx) error {
+	projectID := c.Params("project_id")
+	if projectID == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "project_id is required")
+	}
+
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	req, err := parseActivityReq(c)
+	if err != nil {
+		return err
+	}
+
+	items, total, err := h.activityUsecase.ListProjectActivity(c.Context(), claims.ID, projectID, req)
+	if err != nil {
+		logger.Log.Error("Failed to list project activity", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToPaginatedResponse(items, req.Page, req.Limit, total))
+}
+
+// @Tags Activity
+// @Summary List note activity
+// @Description List the changes to a note and its comments, newest first. The note's owner and the members of its collection may read it, also once the note is deleted.
+// @Produce json
+// @Security BearerAuth
+// @Param note_id path string true "Note ID"
+// @Param page query int false "Page number (default: 1)" default(1)
+// @Param limit query int false "Items per page (default: 20)" default(20)
+// @Success 200 {object} util.PaginatedResponse{data=util.PaginatedData{items=[]contract.ActivityRes}}
+// @Failure 400 {object} util.BaseResponse
+// @Failure 401 {object} util.BaseResponse
+// @Failure 404 {object} util.BaseResponse
+// @Router /v1/notes/{note_id}/activity [get]
+func (h *ActivityHandler) ListNoteActivity(c *fiber.Ctx) error {
+	noteID := c.Params("note_id")
+	if noteID == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "note_id is required")
+	}
+
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	req, err := parseActivityReq(c)
+	if err != nil {
+		return err
+	}
+
+	items, total, err := h.activityUsecase.ListNoteActivity(c.Context(), claims.ID, noteID, req)
+	if err != nil {
+		logger.Log.Error("Failed to list note activity", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToPaginatedResponse(items, req.Page, req.Limit, total))
+}
+
+// parseActivityReq reads and checks the paging of an activity feed
+func parseActivityReq(c *fiber.Ctx) (*contract.ActivityReq, error) {
+	req := contract.ActivityReq{Page: 1, Limit: 20}
+	if err := c.QueryParser(&req); err != nil {
+		logger.Log.Warn("Failed to parse query", zap.Error(err))
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Invalid query parameters")
+	}
+
+	if err := util.ValidateStruct(&req); err != nil {
+		logger.Log.Warn("Validation error", zap.Error(err))
+		return nil, err
+	}
+
+	if req.Page < 1 {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "page must be greater than 0")
+	}
+	if req.Limit < 1 || req.Limit > 100 {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "limit must be between 1 and 100")
+	}
+
+	return &req, nil
+}
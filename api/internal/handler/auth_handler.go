@@ -37,6 +37,7 @@ func NewAuthHandler(authUsecase *usecase.AuthUsecase, userUsecase *usecase.UserU
 
 func (h *AuthHandler) RegisterRoutes(app *fiber.App) {
 	app.Get("/v1/auth/me", middleware.AuthGuard(), h.GetCurrentUser)
+	app.Patch("/v1/auth/me", middleware.AuthGuard(), h.UpdateCurrentUser)
 	app.Post("/v1/auth/refresh-token", h.RefreshToken)
 	app.Get("/v1/auth/google/login", h.GoogleLogin)
 	app.Get("/v1/auth/google/callback", h.GoogleCallback)
@@ -96,6 +97,44 @@ func (h *AuthHandler) GetCurrentUser(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(user))
 }
 
+// @Tags Auth
+// @Summary Update current user
+// @Description Update the current user's settings, e.g. the time zone dates are split into days in
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body contract.UpdateUserReq true "Settings to change"
+// @Success 200 {object} util.BaseResponse{data=contract.UserRes}
+// @Failure 400 {object} util.BaseResponse
+// @Failure 401 {object} util.BaseResponse
+// @Router /v1/auth/me [patch]
+func (h *AuthHandler) UpdateCurrentUser(c *fiber.Ctx) error {
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims: %v", err)
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	var req contract.UpdateUserReq
+	if err := c.BodyParser(&req); err != nil {
+		logger.Log.Warn("Failed to parse request body: %v", err)
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := util.ValidateStruct(&req); err != nil {
+		logger.Log.Warn("Validation error: %v", err)
+		return err
+	}
+
+	user, err := h.authUsecase.UpdateUser(claims.ID, &req)
+	if err != nil {
+		logger.Log.Error("Failed to update user: %v", err)
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(user))
+}
+
 // @Tags Auth
 // @Summary Initiate Google OAuth login
 // @Description Redirects to Google OAuth login page
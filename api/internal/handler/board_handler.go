@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"app/internal/contract"
+	"app/internal/middleware"
+	"app/internal/usecase"
+	"app/pkg/logger"
+	"app/pkg/util"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type BoardHandler struct {
+	boardUsecase *usecase.BoardUsecase
+}
+
+func NewBoardHandler(boardUsecase *usecase.BoardUsecase) *BoardHandler {
+	return &BoardHandler{boardUsecase: boardUsecase}
+}
+
+func (h *BoardHandler) RegisterRoutes(app *fiber.App) {
+	boardGroup := app.Group("/v1/projects/:project_id/board")
+	boardGroup.Get("/", middleware.AuthGuard(), h.GetBoard)
+	boardGroup.Post("/move", middleware.AuthGuard(), h.MoveTask)
+}
+
+// @Tags Board
+// @Summary Get a project board
+// @Description Get the project's top-level tasks grouped into one column per status, each column in sort order and paginated on its own.
+// @Description Categories without custom statuses, or with tasks still on the built-in status, get a built-in column.
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param project_id path string true "Project ID"
+// @Param status_id query string false "Only the column of this custom status"
+// @Param category query string false "Only the built-in column of this category (todo, doing, done, cancelled)"
+// @Param page query int false "Page number of every column (default: 1)" default(1)
+// @Param limit query int false "Tasks per column (default: 20)" default(20)
+// @Success 200 {object} util.BaseResponse{data=contract.BoardRes}
+// @Failure 400 {object} util.BaseResponse
+// @Failure 401 {object} util.BaseResponse
+// @Failure 404 {object} util.BaseResponse
+// @Router /v1/projects/{project_id}/board [get]
+func (h *BoardHandler) GetBoard(c *fiber.Ctx) error {
+	projectID := c.Params("project_id")
+	if projectID == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "project_id is required")
+	}
+
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	req := contract.BoardReq{Page: 1, Limit: 20}
+	if err := c.QueryParser(&req); err != nil {
+		logger.Log.Warn("Failed to parse query", zap.Error(err))
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid query parameters")
+	}
+
+	if err := util.ValidateStruct(&req); err != nil {
+		logger.Log.Warn("Validation error", zap.Error(err))
+		return err
+	}
+
+	if req.Page < 1 {
+		return fiber.NewError(fiber.StatusBadRequest, "page must be greater than 0")
+	}
+	if req.Limit < 1 || req.Limit > 100 {
+		return fiber.NewError(fiber.StatusBadRequest, "limit must be between 1 and 100")
+	}
+
+	res, err := h.boardUsecase.GetBoard(c.Context(), claims.ID, projectID, &req)
+	if err != nil {
+		logger.Log.Error("Failed to get board", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(res))
+}
+
+// @Tags Board
+// @Summary Move a task on a project board
+// @Description Move a task to a column and a position in one step. Changing the column changes the task's status
+// @Description with the same effects as a status change from sync, e.g. moving a recurring task to done advances it.
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param project_id path string true "Project ID"
+// @Param request body contract.MoveBoardTaskReq true "Move"
+// @Success 200 {object} util.BaseResponse{data=contract.BoardTaskRes}
+// @Failure 400 {object} util.BaseResponse
+// @Failure 401 {object} util.BaseResponse
+// @Failure 403 {object} util.BaseResponse
+// @Failure 404 {object} util.BaseResponse
+// @Router /v1/projects/{project_id}/board/move [post]
+func (h *BoardHandler) MoveTask(c *fiber.Ctx) error {
+	projectID := c.Params("project_id")
+	if projectID == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "project_id is required")
+	}
+
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	var req contract.MoveBoardTaskReq
+	if err := c.BodyParser(&req); err != nil {
+		logger.Log.Warn("Failed to parse request body", zap.Error(err))
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := util.ValidateStruct(&req); err != nil {
+		logger.Log.Warn("Validation error", zap.Error(err))
+		return err
+	}
+
+	res, err := h.boardUsecase.MoveBoardTask(c.Context(), claims.ID, projectID, &req)
+	if err != nil {
+		logger.Log.Error("Failed to move board task", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(res))
+}
@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"app/internal/contract"
+	"app/internal/middleware"
+	"app/internal/usecase"
+	"app/pkg/logger"
+	"app/pkg/util"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type CalendarHandler struct {
+	calendarUsecase *usecase.CalendarUsecase
+}
+
+func NewCalendarHandler(calendarUsecase *usecase.CalendarUsecase) *CalendarHandler {
+	return &CalendarHandler{calendarUsecase: calendarUsecase}
+}
+
+func (h *CalendarHandler) RegisterRoutes(app *fiber.App) {
+	calendarGroup := app.Group("/v1/calendar")
+	calendarGroup.Get("", middleware.AuthGuard(), h.GetCalendar)
+	calendarGroup.Get("/feed", middleware.AuthGuard(), h.GetFeed)
+	calendarGroup.Post("/feed", middleware.AuthGuard(), h.RotateFeed)
+	calendarGroup.Delete("/feed", middleware.AuthGuard(), h.DeleteFeed)
+	// Calendar apps can't send a bearer token, so the secret token in the URL authenticates the feed
+	calendarGroup.Get("/feed/:token", h.RenderFeed)
+}
+
+// @Tags Calendar
+// @Summary Get the calendar
+// @Description Get the tasks of the workspace active in the token, or of the personal space, due on each day of a date range,
+// @Description split into days in the user's time zone.
+// @Description Later occurrences of recurring tasks are included, as are open tasks that are overdue. Ranges are up to 62 days.
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param from query string true "First day (YYYY-MM-DD)"
+// @Param to query string true "Last day, inclusive (YYYY-MM-DD)"
+// @Param timezone query string false "IANA time zone, e.g. Europe/Berlin (default: the user's time zone)"
+// @Param project_id query string false "Only tasks in this project"
+// @Param include_completed query bool false "Also show completed tasks (default: true)" default(true)
+// @Success 200 {object} util.BaseResponse{data=contract.CalendarRes}
+// @Failure 400 {object} util.BaseResponse
+// @Failure 401 {object} util.BaseResponse
+// @Router /v1/calendar [get]
+func (h *CalendarHandler) GetCalendar(c *fiber.Ctx) error {
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	req := contract.CalendarReq{IncludeCompleted: true}
+	if err := c.QueryParser(&req); err != nil {
+		logger.Log.Warn("Failed to parse query", zap.Error(err))
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid query parameters")
+	}
+
+	if err := util.ValidateStruct(&req); err != nil {
+		logger.Log.Warn("Validation error", zap.Error(err))
+		return err
+	}
+
+	res, err := h.calendarUsecase.GetCalendar(c.Context(), claims.ID, claims.WorkspaceID, &req)
+	if err != nil {
+		logger.Log.Error("Failed to get calendar", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(res))
+}
+
+// @Tags Calendar
+// @Summary Get the calendar feed
+// @Description Get whether the iCalendar feed is on. The feed URL itself is only shown when it is created or rotated.
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} util.BaseResponse{data=contract.CalendarFeedRes}
+// @Failure 401 {object} util.BaseResponse
+// @Router /v1/calendar/feed [get]
+func (h *CalendarHandler) GetFeed(c *fiber.Ctx) error {
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	res, err := h.calendarUsecase.GetFeed(c.Context(), claims.ID)
+	if err != nil {
+		logger.Log.Error("Failed to get calendar feed", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(res))
+}
+
+// @Tags Calendar
+// @Summary Create or rotate the calendar feed
+// @Description Turn the iCalendar feed of tasks with due dates on, with a new secret URL. A previous URL stops working.
+// @Description The feed covers the personal space and the projects shared with the user, never a workspace.
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} util.BaseResponse{data=contract.CalendarFeedRes}
+// @Failure 401 {object} util.BaseResponse
+// @Router /v1/calendar/feed [post]
+func (h *CalendarHandler) RotateFeed(c *fiber.Ctx) error {
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	res, err := h.calendarUsecase.RotateFeed(c.Context(), claims.ID)
+	if err != nil {
+		logger.Log.Error("Failed to rotate calendar feed", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(res))
+}
+
+// @Tags Calendar
+// @Summary Turn the calendar feed off
+// @Description Turn the iCalendar feed off. Its URL stops working.
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} util.BaseResponse
+// @Failure 401 {object} util.BaseResponse
+// @Router /v1/calendar/feed [delete]
+func (h *CalendarHandler) DeleteFeed(c *fiber.Ctx) error {
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	if err := h.calendarUsecase.DeleteFeed(c.Context(), claims.ID); err != nil {
+		logger.Log.Error("Failed to delete calendar feed", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(nil))
+}
+
+// @Tags Calendar
+// @Summary Calendar feed
+// @Description iCalendar feed of tasks due from 90 days ago on, for subscribing from calendar apps. Authenticated by the secret token in the URL.
+// @Description Only has tasks of the personal space and the projects shared with the user.
+// @Produce text/calendar
+// @Param token path string true "Feed token, optionally followed by .ics"
+// @Param project_id query string false "Only tasks in this project"
+// @Param kind query string false "event (default) or todo"
+// @Success 200 {string} string "iCalendar document"
+// @Failure 400 {object} util.BaseResponse
+// @Failure 404 {object} util.BaseResponse
+// @Router /v1/calendar/feed/{token} [get]
+func (h *CalendarHandler) RenderFeed(c *fiber.Ctx) error {
+	token := strings.TrimSuffix(c.Params("token"), ".ics")
+	if token == "" {
+		return fiber.NewError(fiber.StatusNotFound, "Calendar feed not found")
+	}
+
+	var query contract.CalendarFeedQuery
+	if err := c.QueryParser(&query); err != nil {
+		logger.Log.Warn("Failed to parse query", zap.Error(err))
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid query parameters")
+	}
+
+	if err := util.ValidateStruct(&query); err != nil {
+		logger.Log.Warn("Validation error", zap.Error(err))
+		return err
+	}
+
+	body, err := h.calendarUsecase.RenderFeed(c.Context(), token, &query)
+	if err != nil {
+		logger.Log.Warn("Failed to render calendar feed", zap.Error(err))
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, "text/calendar; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, `inline; filename="memr.ics"`)
+	c.Set(fiber.HeaderCacheControl, "private, max-age=300")
+	return c.Status(fiber.StatusOK).SendString(body)
+}
@@ -35,7 +35,7 @@ func (h *ChatHandler) RegisterRoutes(app *fiber.App) {
 
 // @Tags Chat
 // @Summary Start a new chat
-// @Description Create a new chat session
+// @Description Create a new chat session in the active workspace. The assistant only sees what is in it.
 // @Accept json
 // @Produce json
 // @Security BearerAuth
@@ -49,7 +49,7 @@ func (h *ChatHandler) StartChat(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
 	}
 
-	res, err := h.chatUsecase.StartChat(c.Context(), claims.ID)
+	res, err := h.chatUsecase.StartChat(c.Context(), claims.ID, claims.WorkspaceID)
 	if err != nil {
 		logger.Log.Error("Failed to start chat", zap.Error(err))
 		return err
@@ -60,7 +60,7 @@ func (h *ChatHandler) StartChat(c *fiber.Ctx) error {
 
 // @Tags Chat
 // @Summary List chats
-// @Description Get a paginated list of chats for the authenticated user
+// @Description Get a paginated list of chats for the authenticated user in the active workspace
 // @Accept json
 // @Produce json
 // @Security BearerAuth
@@ -89,7 +89,7 @@ func (h *ChatHandler) ListChats(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusBadRequest, "limit must be between 1 and 100")
 	}
 
-	chats, total, err := h.chatUsecase.ListChats(c.Context(), claims.ID, page, limit)
+	chats, total, err := h.chatUsecase.ListChats(c.Context(), claims.ID, claims.WorkspaceID, page, limit)
 	if err != nil {
 		logger.Log.Error("Failed to list chats", zap.Error(err))
 		return err
@@ -136,11 +136,11 @@ func (h *ChatHandler) SendMessage(c *fiber.Ctx) error {
 	// Check if streaming is requested
 	streamParam := c.Query("stream", "false")
 	if streamParam == "true" {
-		return h.handleStreamingMessage(c, chatID, claims.ID, req.Message)
+		return h.handleStreamingMessage(c, chatID, claims.ID, claims.WorkspaceID, req.Message)
 	}
 
 	// Non-streaming response (backward compatible)
-	res, err := h.chatUsecase.SendMessage(c.Context(), chatID, claims.ID, req.Message)
+	res, err := h.chatUsecase.SendMessage(c.Context(), chatID, claims.ID, claims.WorkspaceID, req.Message)
 	if err != nil {
 		logger.Log.Error("Failed to send message", zap.Error(err))
 		return err
@@ -150,7 +150,7 @@ func (h *ChatHandler) SendMessage(c *fiber.Ctx) error {
 }
 
 // handleStreamingMessage handles streaming response using SSE
-func (h *ChatHandler) handleStreamingMessage(c *fiber.Ctx, chatID, userID, message string) error {
+func (h *ChatHandler) handleStreamingMessage(c *fiber.Ctx, chatID, userID, workspaceID, message string) error {
 	// Set SSE headers
 	c.Set("Content-Type", "text/event-stream")
 	c.Set("Cache-Control", "no-cache")
@@ -176,7 +176,7 @@ func (h *ChatHandler) handleStreamingMessage(c *fiber.Ctx, chatID, userID, messa
 		streamWriter.writer = w
 
 		// Call usecase to stream the message using the captured context
-		err := h.chatUsecase.SendMessageStream(requestCtx, chatID, userID, message, streamWriter)
+		err := h.chatUsecase.SendMessageStream(requestCtx, chatID, userID, workspaceID, message, streamWriter)
 		if err != nil {
 			logger.Log.Error("Failed to stream message", zap.Error(err), zap.String("chatID", chatID))
 			// Try to send error chunk
@@ -249,7 +249,7 @@ func (h *ChatHandler) GetChatHistory(c *fiber.Ctx) error {
 		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
 	}
 
-	res, err := h.chatUsecase.GetChatHistory(c.Context(), chatID, claims.ID)
+	res, err := h.chatUsecase.GetChatHistory(c.Context(), chatID, claims.ID, claims.WorkspaceID)
 	if err != nil {
 		logger.Log.Error("Failed to get chat history", zap.Error(err))
 		return err
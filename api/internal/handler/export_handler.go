@@ -0,0 +1,156 @@
+package handler
+
+import (
+	"app/internal/middleware"
+	"app/internal/usecase"
+	"app/pkg/logger"
+	"app/pkg/util"
+	"fmt"
+	"os"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type ExportHandler struct {
+	exportUsecase *usecase.ExportUsecase
+}
+
+func NewExportHandler(exportUsecase *usecase.ExportUsecase) *ExportHandler {
+	return &ExportHandler{exportUsecase: exportUsecase}
+}
+
+func (h *ExportHandler) RegisterRoutes(app *fiber.App) {
+	exportGroup := app.Group("/v1/exports")
+	exportGroup.Post("", middleware.AuthGuard(), h.RequestExport)
+	exportGroup.Get("", middleware.AuthGuard(), h.ListExports)
+	exportGroup.Get("/:export_id", middleware.AuthGuard(), h.GetExport)
+	exportGroup.Get("/:export_id/download", middleware.AuthGuard(), h.DownloadExport)
+}
+
+// @Tags Export
+// @Summary Request an account export
+// @Description Start building a zip of the account in the background: one Markdown file per note in a folder per collection,
+// @Description plus JSON files for tasks, projects, collections, tags and chat history. Poll the export until it is completed,
+// @Description then download it within 7 days. If an export is already in progress, that one is returned.
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} util.BaseResponse{data=contract.ExportJobRes}
+// @Failure 401 {object} util.BaseResponse
+// @Router /v1/exports [post]
+func (h *ExportHandler) RequestExport(c *fiber.Ctx) error {
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	res, err := h.exportUsecase.RequestExport(c.Context(), claims.ID)
+	if err != nil {
+		logger.Log.Error("Failed to request export", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(res))
+}
+
+// @Tags Export
+// @Summary List exports
+// @Description List the most recent account exports, newest first
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} util.BaseResponse{data=[]contract.ExportJobRes}
+// @Failure 401 {object} util.BaseResponse
+// @Router /v1/exports [get]
+func (h *ExportHandler) ListExports(c *fiber.Ctx) error {
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	res, err := h.exportUsecase.ListExports(c.Context(), claims.ID)
+	if err != nil {
+		logger.Log.Error("Failed to list exports", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(res))
+}
+
+// @Tags Export
+// @Summary Get an export
+// @Description Get the progress of an account export
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param export_id path string true "Export ID"
+// @Success 200 {object} util.BaseResponse{data=contract.ExportJobRes}
+// @Failure 400 {object} util.BaseResponse
+// @Failure 401 {object} util.BaseResponse
+// @Failure 404 {object} util.BaseResponse
+// @Router /v1/exports/{export_id} [get]
+func (h *ExportHandler) GetExport(c *fiber.Ctx) error {
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	exportID := c.Params("export_id")
+	if exportID == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "export_id is required")
+	}
+
+	res, err := h.exportUsecase.GetExport(c.Context(), claims.ID, exportID)
+	if err != nil {
+		logger.Log.Error("Failed to get export", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(res))
+}
+
+// @Tags Export
+// @Summary Download an export
+// @Description Download the zip archive of a completed export
+// @Produce application/zip
+// @Security BearerAuth
+// @Param export_id path string true "Export ID"
+// @Success 200 {file} file
+// @Failure 400 {object} util.BaseResponse
+// @Failure 401 {object} util.BaseResponse
+// @Failure 404 {object} util.BaseResponse
+// @Router /v1/exports/{export_id}/download [get]
+func (h *ExportHandler) DownloadExport(c *fiber.Ctx) error {
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	exportID := c.Params("export_id")
+	if exportID == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "export_id is required")
+	}
+
+	fileName, archivePath, err := h.exportUsecase.DownloadExport(c.Context(), claims.ID, exportID)
+	if err != nil {
+		logger.Log.Error("Failed to download export", zap.Error(err))
+		return err
+	}
+
+	archive, err := os.Open(archivePath)
+	if err != nil {
+		logger.Log.Error("Failed to open export archive", zap.Error(err), zap.String("exportID", exportID))
+		return fiber.NewError(fiber.StatusNotFound, "Export not found, not finished yet or expired")
+	}
+
+	c.Set(fiber.HeaderContentType, "application/zip")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, fileName))
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+	// The stream closes the file once it is sent
+	return c.Status(fiber.StatusOK).SendStream(archive)
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"app/internal/contract"
+	"app/internal/middleware"
+	"app/internal/usecase"
+	"app/pkg/logger"
+	"app/pkg/util"
+	"archive/zip"
+	"io"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type ImportHandler struct {
+	importUsecase *usecase.ImportUsecase
+}
+
+func NewImportHandler(importUsecase *usecase.ImportUsecase) *ImportHandler {
+	return &ImportHandler{importUsecase: importUsecase}
+}
+
+func (h *ImportHandler) RegisterRoutes(app *fiber.App) {
+	importGroup := app.Group("/v1/import")
+	importGroup.Post("/markdown", middleware.AuthGuard(), h.ImportMarkdown)
+	importGroup.Post("/tasks", middleware.AuthGuard(), h.ImportTasks)
+}
+
+// @Tags Import
+// @Summary Import Markdown notes
+// @Description Import a zip of Markdown files, such as an Obsidian vault. Each .md file becomes a note and its folder a collection.
+// @Description YAML front-matter title and tags become the note's title and tags, and [[wikilinks]] are kept and resolved.
+// @Description Hidden files and folders are ignored. Embeddings are generated in the background.
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "Zip archive of Markdown files"
+// @Success 200 {object} util.BaseResponse{data=contract.ImportMarkdownRes}
+// @Failure 400 {object} util.BaseResponse
+// @Failure 401 {object} util.BaseResponse
+// @Router /v1/import/markdown [post]
+func (h *ImportHandler) ImportMarkdown(c *fiber.Ctx) error {
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		logger.Log.Warn("Failed to get import file", zap.Error(err))
+		return fiber.NewError(fiber.StatusBadRequest, "file is required")
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		logger.Log.Error("Failed to open import file", zap.Error(err))
+		return err
+	}
+	defer file.Close()
+
+	archive, err := zip.NewReader(file, fileHeader.Size)
+	if err != nil {
+		logger.Log.Warn("Failed to read import archive", zap.Error(err))
+		return fiber.NewError(fiber.StatusBadRequest, "file must be a zip archive")
+	}
+
+	res, err := h.importUsecase.ImportMarkdown(c.Context(), claims.ID, archive)
+	if err != nil {
+		logger.Log.Error("Failed to import Markdown notes", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(res))
+}
+
+// @Tags Import
+// @Summary Import tasks
+// @Description Import tasks from a CSV or JSON file exported from a spreadsheet or another task manager.
+// @Description A JSON file is an array of tasks, or an object with a "tasks" array, with the fields title, description, project,
+// @Description status, priority, dueDate, startDate, estimateMinutes and tags. CSV columns are mapped onto the same fields.
+// @Description Projects and tags are created when missing, dates without a UTC offset are in the user's time zone and statuses
+// @Description are matched to the project's custom statuses or to todo, doing, done and cancelled. Rows that can't be read are
+// @Description reported and the others imported. With dry_run nothing is saved.
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "CSV or JSON file of tasks"
+// @Param format formData string false "csv or json, defaults to the file extension"
+// @Param mapping formData string false "JSON object of task fields to CSV column headers"
+// @Param date_order formData string false "mdy, dmy or ymd, for numeric dates"
+// @Param timezone formData string false "IANA time zone of dates without an offset"
+// @Param dry_run formData bool false "Preview without saving"
+// @Success 200 {object} util.BaseResponse{data=contract.ImportTasksRes}
+// @Failure 400 {object} util.BaseResponse
+// @Failure 401 {object} util.BaseResponse
+// @Router /v1/import/tasks [post]
+func (h *ImportHandler) ImportTasks(c *fiber.Ctx) error {
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	var req contract.ImportTasksReq
+	if err := c.BodyParser(&req); err != nil {
+		logger.Log.Warn("Failed to parse request body", zap.Error(err))
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := util.ValidateStruct(&req); err != nil {
+		logger.Log.Warn("Validation error", zap.Error(err))
+		return err
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		logger.Log.Warn("Failed to get import file", zap.Error(err))
+		return fiber.NewError(fiber.StatusBadRequest, "file is required")
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		logger.Log.Error("Failed to open import file", zap.Error(err))
+		return err
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		logger.Log.Error("Failed to read import file", zap.Error(err))
+		return err
+	}
+
+	res, err := h.importUsecase.ImportTasks(c.Context(), claims.ID, &req, fileHeader.Filename, data)
+	if err != nil {
+		logger.Log.Error("Failed to import tasks", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(res))
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"app/internal/config"
+	"app/internal/middleware"
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestImportRoutesTakeLargeBodies(t *testing.T) {
+	app := fiber.New(config.FiberConfig())
+	app.Use(middleware.BodyLimitConfig())
+	NewImportHandler(nil).RegisterRoutes(app)
+
+	paths := []string{}
+	for _, route := range app.GetRoutes(true) {
+		if route.Method == fiber.MethodPost && strings.HasPrefix(route.Path, "/v1/import") {
+			paths = append(paths, route.Path)
+		}
+	}
+	if len(paths) == 0 {
+		t.Fatal("no import routes registered")
+	}
+
+	for _, path := range paths {
+		t.Run(path, func(t *testing.T) {
+			// Bodies within the import limit get through to authentication
+			tests := []struct {
+				size       int
+				wantStatus int
+			}{
+				{size: config.DefaultBodyLimit + 1, wantStatus: fiber.StatusUnauthorized},
+				{size: config.ImportBodyLimit + 1, wantStatus: fiber.StatusRequestEntityTooLarge},
+			}
+			for _, tt := range tests {
+				req := httptest.NewRequest(fiber.MethodPost, path, bytes.NewReader(make([]byte, tt.size)))
+				res, err := app.Test(req, -1)
+				if err != nil {
+					t.Fatalf("request failed: %v", err)
+				}
+				if res.StatusCode != tt.wantStatus {
+					t.Errorf("%d byte body: status = %d, want %d", tt.size, res.StatusCode, tt.wantStatus)
+				}
+			}
+		})
+	}
+}
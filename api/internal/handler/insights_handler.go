@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"app/internal/contract"
+	"app/internal/middleware"
+	"app/internal/usecase"
+	"app/pkg/logger"
+	"app/pkg/util"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type InsightsHandler struct {
+	insightsUsecase *usecase.InsightsUsecase
+}
+
+func NewInsightsHandler(insightsUsecase *usecase.InsightsUsecase) *InsightsHandler {
+	return &InsightsHandler{insightsUsecase: insightsUsecase}
+}
+
+func (h *InsightsHandler) RegisterRoutes(app *fiber.App) {
+	insightsGroup := app.Group("/v1/insights")
+	insightsGroup.Get("", middleware.AuthGuard(), h.GetInsights)
+}
+
+// @Tags Insights
+// @Summary Get productivity insights
+// @Description Get tasks completed per day, overdue counts, the average time from creation to completion,
+// @Description the busiest projects and the notes written per collection over a date range of up to 366 days.
+// @Description Days are in the user's time zone.
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param from query string true "First day (YYYY-MM-DD)"
+// @Param to query string true "Last day, inclusive (YYYY-MM-DD)"
+// @Param timezone query string false "IANA time zone, e.g. Europe/Berlin (default: the user's time zone)"
+// @Success 200 {object} util.BaseResponse{data=contract.InsightsRes}
+// @Failure 400 {object} util.BaseResponse
+// @Failure 401 {object} util.BaseResponse
+// @Router /v1/insights [get]
+func (h *InsightsHandler) GetInsights(c *fiber.Ctx) error {
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	var req contract.InsightsReq
+	if err := c.QueryParser(&req); err != nil {
+		logger.Log.Warn("Failed to parse query", zap.Error(err))
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid query parameters")
+	}
+
+	if err := util.ValidateStruct(&req); err != nil {
+		logger.Log.Warn("Validation error", zap.Error(err))
+		return err
+	}
+
+	res, err := h.insightsUsecase.GetInsights(c.Context(), claims.ID, &req)
+	if err != nil {
+		logger.Log.Error("Failed to get insights", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(res))
+}
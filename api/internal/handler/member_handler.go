@@ -0,0 +1,404 @@
+package handler
+
+import (
+	"app/internal/contract"
+	"app/internal/middleware"
+	"app/internal/usecase"
+	"app/pkg/logger"
+	"app/pkg/util"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type MemberHandler struct {
+	memberUsecase *usecase.MemberUsecase
+}
+
+func NewMemberHandler(memberUsecase *usecase.MemberUsecase) *MemberHandler {
+	return &MemberHandler{memberUsecase: memberUsecase}
+}
+
+func (h *MemberHandler) RegisterRoutes(app *fiber.App) {
+	// Projects, collections and workspaces share their member routes; memberTarget tells them apart
+	for _, prefix := range []string{"/v1/projects/:project_id", "/v1/collections/:collection_id", "/v1/workspaces/:workspace_id"} {
+		targetGroup := app.Group(prefix)
+		targetGroup.Get("/members", middleware.AuthGuard(), h.ListMembers)
+		targetGroup.Patch("/members/:user_id", middleware.AuthGuard(), h.UpdateMember)
+		targetGroup.Delete("/members/:user_id", middleware.AuthGuard(), h.RemoveMember)
+		targetGroup.Post("/invitations", middleware.AuthGuard(), h.Invite)
+		targetGroup.Delete("/invitations/:invitation_id", middleware.AuthGuard(), h.RevokeInvitation)
+	}
+
+	invitationGroup := app.Group("/v1/invitations")
+	invitationGroup.Get("", middleware.AuthGuard(), h.ListInvitations)
+	invitationGroup.Post("/accept", middleware.AuthGuard(), h.AcceptInvitationByToken)
+	invitationGroup.Post("/:invitation_id/accept", middleware.AuthGuard(), h.AcceptInvitation)
+	invitationGroup.Post("/:invitation_id/decline", middleware.AuthGuard(), h.DeclineInvitation)
+}
+
+// @Tags Member
+// @Summary List members
+// @Description List everyone with access to a project, collection or workspace, its owner first. Owners also get the pending invitations.
+// @Produce json
+// @Security BearerAuth
+// @Param project_id path string true "Project ID"
+// @Param collection_id path string true "Collection ID"
+// @Param workspace_id path string true "Workspace ID"
+// @Success 200 {object} util.BaseResponse{data=contract.MembersRes}
+// @Failure 401 {object} util.BaseResponse
+// @Failure 404 {object} util.BaseResponse
+// @Router /v1/projects/{project_id}/members [get]
+// @Router /v1/collections/{collection_id}/members [get]
+// @Router /v1/workspaces/{workspace_id}/members [get]
+func (h *MemberHandler) ListMembers(c *fiber.Ctx) error {
+	targetType, targetID, err := memberTarget(c)
+	if err != nil {
+		return err
+	}
+
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	res, err := h.memberUsecase.ListMembers(c.Context(), claims.ID, targetType, targetID)
+	if err != nil {
+		logger.Log.Error("Failed to list members", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(res))
+}
+
+// @Tags Member
+// @Summary Invite a member
+// @Description Email an invitation to join a project, collection or workspace with a role. Only owners can invite.
+// @Description A new invitation to the same address replaces the pending one.
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param project_id path string true "Project ID"
+// @Param collection_id path string true "Collection ID"
+// @Param workspace_id path string true "Workspace ID"
+// @Param request body contract.InviteMemberReq true "Invitation"
+// @Success 200 {object} util.BaseResponse{data=contract.InvitationRes}
+// @Failure 400 {object} util.BaseResponse
+// @Failure 401 {object} util.BaseResponse
+// @Failure 403 {object} util.BaseResponse
+// @Failure 404 {object} util.BaseResponse
+// @Failure 409 {object} util.BaseResponse
+// @Router /v1/projects/{project_id}/invitations [post]
+// @Router /v1/collections/{collection_id}/invitations [post]
+// @Router /v1/workspaces/{workspace_id}/invitations [post]
+func (h *MemberHandler) Invite(c *fiber.Ctx) error {
+	targetType, targetID, err := memberTarget(c)
+	if err != nil {
+		return err
+	}
+
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	var req contract.InviteMemberReq
+	if err := c.BodyParser(&req); err != nil {
+		logger.Log.Warn("Failed to parse request body", zap.Error(err))
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := util.ValidateStruct(&req); err != nil {
+		logger.Log.Warn("Validation error", zap.Error(err))
+		return err
+	}
+
+	res, err := h.memberUsecase.Invite(c.Context(), claims.ID, targetType, targetID, &req)
+	if err != nil {
+		logger.Log.Error("Failed to invite member", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(res))
+}
+
+// @Tags Member
+// @Summary Revoke an invitation
+// @Description Revoke a pending invitation to a project, collection or workspace. Only owners can revoke.
+// @Produce json
+// @Security BearerAuth
+// @Param project_id path string true "Project ID"
+// @Param collection_id path string true "Collection ID"
+// @Param workspace_id path string true "Workspace ID"
+// @Param invitation_id path string true "Invitation ID"
+// @Success 200 {object} util.BaseResponse
+// @Failure 401 {object} util.BaseResponse
+// @Failure 403 {object} util.BaseResponse
+// @Failure 404 {object} util.BaseResponse
+// @Router /v1/projects/{project_id}/invitations/{invitation_id} [delete]
+// @Router /v1/collections/{collection_id}/invitations/{invitation_id} [delete]
+// @Router /v1/workspaces/{workspace_id}/invitations/{invitation_id} [delete]
+func (h *MemberHandler) RevokeInvitation(c *fiber.Ctx) error {
+	targetType, targetID, err := memberTarget(c)
+	if err != nil {
+		return err
+	}
+	invitationID := c.Params("invitation_id")
+	if invitationID == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "invitation_id is required")
+	}
+
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	if err := h.memberUsecase.RevokeInvitation(c.Context(), claims.ID, targetType, targetID, invitationID); err != nil {
+		logger.Log.Error("Failed to revoke invitation", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(nil))
+}
+
+// @Tags Member
+// @Summary Change a member's role
+// @Description Change the role of a member of a project, collection or workspace. Only owners can change roles, and the creator always stays owner.
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param project_id path string true "Project ID"
+// @Param collection_id path string true "Collection ID"
+// @Param workspace_id path string true "Workspace ID"
+// @Param user_id path string true "Member's user ID"
+// @Param request body contract.UpdateMemberReq true "Role"
+// @Success 200 {object} util.BaseResponse
+// @Failure 400 {object} util.BaseResponse
+// @Failure 401 {object} util.BaseResponse
+// @Failure 403 {object} util.BaseResponse
+// @Failure 404 {object} util.BaseResponse
+// @Router /v1/projects/{project_id}/members/{user_id} [patch]
+// @Router /v1/collections/{collection_id}/members/{user_id} [patch]
+// @Router /v1/workspaces/{workspace_id}/members/{user_id} [patch]
+func (h *MemberHandler) UpdateMember(c *fiber.Ctx) error {
+	targetType, targetID, err := memberTarget(c)
+	if err != nil {
+		return err
+	}
+	memberUserID := c.Params("user_id")
+	if memberUserID == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "user_id is required")
+	}
+
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	var req contract.UpdateMemberReq
+	if err := c.BodyParser(&req); err != nil {
+		logger.Log.Warn("Failed to parse request body", zap.Error(err))
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := util.ValidateStruct(&req); err != nil {
+		logger.Log.Warn("Validation error", zap.Error(err))
+		return err
+	}
+
+	if err := h.memberUsecase.UpdateMember(c.Context(), claims.ID, targetType, targetID, memberUserID, &req); err != nil {
+		logger.Log.Error("Failed to update member", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(nil))
+}
+
+// @Tags Member
+// @Summary Remove a member
+// @Description Remove a member from a project, collection or workspace. Owners can remove anyone but the creator, and members can remove themselves to leave.
+// @Description The member's devices drop what was shared on their next sync.
+// @Produce json
+// @Security BearerAuth
+// @Param project_id path string true "Project ID"
+// @Param collection_id path string true "Collection ID"
+// @Param workspace_id path string true "Workspace ID"
+// @Param user_id path string true "Member's user ID"
+// @Success 200 {object} util.BaseResponse
+// @Failure 400 {object} util.BaseResponse
+// @Failure 401 {object} util.BaseResponse
+// @Failure 403 {object} util.BaseResponse
+// @Failure 404 {object} util.BaseResponse
+// @Router /v1/projects/{project_id}/members/{user_id} [delete]
+// @Router /v1/collections/{collection_id}/members/{user_id} [delete]
+// @Router /v1/workspaces/{workspace_id}/members/{user_id} [delete]
+func (h *MemberHandler) RemoveMember(c *fiber.Ctx) error {
+	targetType, targetID, err := memberTarget(c)
+	if err != nil {
+		return err
+	}
+	memberUserID := c.Params("user_id")
+	if memberUserID == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "user_id is required")
+	}
+
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	if err := h.memberUsecase.RemoveMember(c.Context(), claims.ID, targetType, targetID, memberUserID); err != nil {
+		logger.Log.Error("Failed to remove member", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(nil))
+}
+
+// @Tags Member
+// @Summary List my invitations
+// @Description List the pending invitations sent to the user's email address, which must be verified
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} util.BaseResponse{data=contract.InvitationsRes}
+// @Failure 401 {object} util.BaseResponse
+// @Failure 403 {object} util.BaseResponse
+// @Router /v1/invitations [get]
+func (h *MemberHandler) ListInvitations(c *fiber.Ctx) error {
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	res, err := h.memberUsecase.ListInvitations(c.Context(), claims.ID)
+	if err != nil {
+		logger.Log.Error("Failed to list invitations", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(res))
+}
+
+// @Tags Member
+// @Summary Accept an invitation by token
+// @Description Accept the invitation an email link carries. The token is enough, so it works from an account with another address.
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body contract.AcceptInvitationReq true "Token"
+// @Success 200 {object} util.BaseResponse{data=contract.MembershipRes}
+// @Failure 400 {object} util.BaseResponse
+// @Failure 401 {object} util.BaseResponse
+// @Failure 404 {object} util.BaseResponse
+// @Failure 409 {object} util.BaseResponse
+// @Router /v1/invitations/accept [post]
+func (h *MemberHandler) AcceptInvitationByToken(c *fiber.Ctx) error {
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	var req contract.AcceptInvitationReq
+	if err := c.BodyParser(&req); err != nil {
+		logger.Log.Warn("Failed to parse request body", zap.Error(err))
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := util.ValidateStruct(&req); err != nil {
+		logger.Log.Warn("Validation error", zap.Error(err))
+		return err
+	}
+
+	res, err := h.memberUsecase.AcceptInvitationByToken(c.Context(), claims.ID, &req)
+	if err != nil {
+		logger.Log.Error("Failed to accept invitation", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(res))
+}
+
+// @Tags Member
+// @Summary Accept an invitation
+// @Description Accept one of the pending invitations sent to the user's verified email address
+// @Produce json
+// @Security BearerAuth
+// @Param invitation_id path string true "Invitation ID"
+// @Success 200 {object} util.BaseResponse{data=contract.MembershipRes}
+// @Failure 401 {object} util.BaseResponse
+// @Failure 403 {object} util.BaseResponse
+// @Failure 404 {object} util.BaseResponse
+// @Failure 409 {object} util.BaseResponse
+// @Router /v1/invitations/{invitation_id}/accept [post]
+func (h *MemberHandler) AcceptInvitation(c *fiber.Ctx) error {
+	invitationID := c.Params("invitation_id")
+	if invitationID == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "invitation_id is required")
+	}
+
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	res, err := h.memberUsecase.AcceptInvitation(c.Context(), claims.ID, invitationID)
+	if err != nil {
+		logger.Log.Error("Failed to accept invitation", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(res))
+}
+
+// @Tags Member
+// @Summary Decline an invitation
+// @Description Decline one of the pending invitations sent to the user's verified email address
+// @Produce json
+// @Security BearerAuth
+// @Param invitation_id path string true "Invitation ID"
+// @Success 200 {object} util.BaseResponse
+// @Failure 401 {object} util.BaseResponse
+// @Failure 403 {object} util.BaseResponse
+// @Failure 404 {object} util.BaseResponse
+// @Router /v1/invitations/{invitation_id}/decline [post]
+func (h *MemberHandler) DeclineInvitation(c *fiber.Ctx) error {
+	invitationID := c.Params("invitation_id")
+	if invitationID == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "invitation_id is required")
+	}
+
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	if err := h.memberUsecase.DeclineInvitation(c.Context(), claims.ID, invitationID); err != nil {
+		logger.Log.Error("Failed to decline invitation", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(nil))
+}
+
+// memberTarget reads whether a member route is for a project, a collection or a workspace, and its ID
+func memberTarget(c *fiber.Ctx) (string, string, error) {
+	if projectID := c.Params("project_id"); projectID != "" {
+		return usecase.MemberTargetProject, projectID, nil
+	}
+	if collectionID := c.Params("collection_id"); collectionID != "" {
+		return usecase.MemberTargetCollection, collectionID, nil
+	}
+	if workspaceID := c.Params("workspace_id"); workspaceID != "" {
+		return usecase.MemberTargetWorkspace, workspaceID, nil
+	}
+	return "", "", fiber.NewError(fiber.StatusBadRequest, "project_id, collection_id or workspace_id is required")
+}
@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"app/internal/contract"
+	"app/internal/middleware"
+	"app/internal/usecase"
+	"app/pkg/logger"
+	"app/pkg/util"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type NoteHandler struct {
+	noteUsecase *usecase.NoteUsecase
+}
+
+func NewNoteHandler(noteUsecase *usecase.NoteUsecase) *NoteHandler {
+	return &NoteHandler{noteUsecase: noteUsecase}
+}
+
+func (h *NoteHandler) RegisterRoutes(app *fiber.App) {
+	noteGroup := app.Group("/v1/notes")
+	noteGroup.Get("/graph", middleware.AuthGuard(), h.GetNoteGraph)
+	noteGroup.Get("/links/dangling", middleware.AuthGuard(), h.ListDanglingLinks)
+	noteGroup.Post("/links/resolve", middleware.AuthGuard(), h.ResolveDanglingLinks)
+	noteGroup.Get("/:note_id/related", middleware.AuthGuard(), h.GetRelatedNotes)
+	noteGroup.Get("/:note_id/backlinks", middleware.AuthGuard(), h.GetBacklinks)
+}
+
+// @Tags Note
+// @Summary Get related notes
+// @Description Get the notes most semantically similar to a note, based on stored embeddings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param note_id path string true "Note ID"
+// @Param collection_id query string false "Only return notes in this collection"
+// @Param min_score query number false "Minimum cosine similarity between 0 and 1 (default: 0.3)" default(0.3)
+// @Param limit query int false "Maximum number of notes (default: 5)" default(5)
+// @Success 200 {object} util.BaseResponse{data=contract.RelatedNotesRes}
+// @Failure 400 {object} util.BaseResponse
+// @Failure 401 {object} util.BaseResponse
+// @Failure 404 {object} util.BaseResponse
+// @Router /v1/notes/{note_id}/related [get]
+func (h *NoteHandler) GetRelatedNotes(c *fiber.Ctx) error {
+	noteID := c.Params("note_id")
+	if _, err := uuid.Parse(noteID); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid note_id")
+	}
+
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	req := contract.RelatedNotesReq{MinScore: 0.3, Limit: 5}
+	if err := c.QueryParser(&req); err != nil {
+		logger.Log.Warn("Failed to parse query", zap.Error(err))
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid query parameters")
+	}
+
+	if err := util.ValidateStruct(&req); err != nil {
+		logger.Log.Warn("Validation error", zap.Error(err))
+		return err
+	}
+
+	res, err := h.noteUsecase.GetRelatedNotes(c.Context(), noteID, claims.ID, &req)
+	if err != nil {
+		logger.Log.Error("Failed to get related notes", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(res))
+}
+
+// @Tags Note
+// @Summary Get backlinks
+// @Description Get the notes that reference a note with a [[wiki link]]
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param note_id path string true "Note ID"
+// @Success 200 {object} util.BaseResponse{data=contract.BacklinksRes}
+// @Failure 400 {object} util.BaseResponse
+// @Failure 401 {object} util.BaseResponse
+// @Failure 404 {object} util.BaseResponse
+// @Router /v1/notes/{note_id}/backlinks [get]
+func (h *NoteHandler) GetBacklinks(c *fiber.Ctx) error {
+	noteID := c.Params("note_id")
+	if _, err := uuid.Parse(noteID); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid note_id")
+	}
+
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	res, err := h.noteUsecase.GetBacklinks(c.Context(), noteID, claims.ID)
+	if err != nil {
+		logger.Log.Error("Failed to get backlinks", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(res))
+}
+
+// @Tags Note
+// @Summary List dangling links
+// @Description List [[wiki links]] that don't point at an existing note
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} util.BaseResponse{data=contract.DanglingLinksRes}
+// @Failure 401 {object} util.BaseResponse
+// @Router /v1/notes/links/dangling [get]
+func (h *NoteHandler) ListDanglingLinks(c *fiber.Ctx) error {
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	res, err := h.noteUsecase.ListDanglingLinks(c.Context(), claims.ID)
+	if err != nil {
+		logger.Log.Error("Failed to list dangling links", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(res))
+}
+
+// @Tags Note
+// @Summary Resolve dangling links
+// @Description Re-point dangling [[wiki links]] at notes whose title now matches
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} util.BaseResponse{data=contract.ResolveLinksRes}
+// @Failure 401 {object} util.BaseResponse
+// @Router /v1/notes/links/resolve [post]
+func (h *NoteHandler) ResolveDanglingLinks(c *fiber.Ctx) error {
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	res, err := h.noteUsecase.ResolveDanglingLinks(c.Context(), claims.ID)
+	if err != nil {
+		logger.Log.Error("Failed to resolve dangling links", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(res))
+}
+
+// @Tags Note
+// @Summary Get note graph
+// @Description Get all notes as nodes and their resolved [[wiki links]] as edges, for graph visualisation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} util.BaseResponse{data=contract.NoteGraphRes}
+// @Failure 401 {object} util.BaseResponse
+// @Router /v1/notes/graph [get]
+func (h *NoteHandler) GetNoteGraph(c *fiber.Ctx) error {
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	res, err := h.noteUsecase.GetNoteGraph(c.Context(), claims.ID)
+	if err != nil {
+		logger.Log.Error("Failed to get note graph", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(res))
+}
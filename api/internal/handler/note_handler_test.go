@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"app/internal/config"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestNoteHandlerRejectsInvalidNoteID(t *testing.T) {
+	h := NewNoteHandler(nil)
+	app := fiber.New(config.FiberConfig())
+	app.Get("/v1/notes/:note_id/related", h.GetRelatedNotes)
+	app.Get("/v1/notes/:note_id/backlinks", h.GetBacklinks)
+
+	tests := []struct {
+		name string
+		path string
+	}{
+		{name: "related", path: "/v1/notes/not-a-uuid/related"},
+		{name: "backlinks", path: "/v1/notes/not-a-uuid/backlinks"},
+		{name: "truncated", path: "/v1/notes/4f9c2b1e-8d3a-4c6f/related"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := app.Test(httptest.NewRequest(fiber.MethodGet, tt.path, nil), -1)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			if res.StatusCode != fiber.StatusBadRequest {
+				t.Errorf("status = %d, want %d", res.StatusCode, fiber.StatusBadRequest)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package handler
+
+import (
+	"app/internal/contract"
+	"app/internal/middleware"
+	"app/internal/usecase"
+	"app/pkg/logger"
+	"app/pkg/util"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type ReminderHandler struct {
+	reminderUsecase *usecase.ReminderUsecase
+}
+
+func NewReminderHandler(reminderUsecase *usecase.ReminderUsecase) *ReminderHandler {
+	return &ReminderHandler{reminderUsecase: reminderUsecase}
+}
+
+func (h *ReminderHandler) RegisterRoutes(app *fiber.App) {
+	reminderGroup := app.Group("/v1/reminders")
+	reminderGroup.Get("/settings", middleware.AuthGuard(), h.GetSettings)
+	reminderGroup.Put("/settings", middleware.AuthGuard(), h.UpdateSettings)
+	reminderGroup.Post("/devices", middleware.AuthGuard(), h.RegisterDevice)
+	reminderGroup.Delete("/devices", middleware.AuthGuard(), h.UnregisterDevice)
+	reminderGroup.Get("/deliveries", middleware.AuthGuard(), h.ListDeliveries)
+}
+
+// @Tags Reminder
+// @Summary Get reminder settings
+// @Description Get the default reminder offsets for tasks without their own, and the channels reminders are sent through
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} util.BaseResponse{data=contract.ReminderSettingsRes}
+// @Failure 401 {object} util.BaseResponse
+// @Router /v1/reminders/settings [get]
+func (h *ReminderHandler) GetSettings(c *fiber.Ctx) error {
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	res, err := h.reminderUsecase.GetSettings(c.Context(), claims.ID)
+	if err != nil {
+		logger.Log.Error("Failed to get reminder settings", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(res))
+}
+
+// @Tags Reminder
+// @Summary Update reminder settings
+// @Description Replace the default reminder offsets, in minutes before the due date. Send [] to turn default reminders off.
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body contract.ReminderSettingsReq true "Reminder settings"
+// @Success 200 {object} util.BaseResponse{data=contract.ReminderSettingsRes}
+// @Failure 400 {object} util.BaseResponse
+// @Failure 401 {object} util.BaseResponse
+// @Router /v1/reminders/settings [put]
+func (h *ReminderHandler) UpdateSettings(c *fiber.Ctx) error {
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	var req contract.ReminderSettingsReq
+	if err := c.BodyParser(&req); err != nil {
+		logger.Log.Warn("Failed to parse request body", zap.Error(err))
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := util.ValidateStruct(&req); err != nil {
+		logger.Log.Warn("Validation error", zap.Error(err))
+		return err
+	}
+
+	res, err := h.reminderUsecase.UpdateSettings(c.Context(), claims.ID, &req)
+	if err != nil {
+		logger.Log.Error("Failed to update reminder settings", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(res))
+}
+
+// @Tags Reminder
+// @Summary Register a device
+// @Description Register an FCM token so push reminders reach this device
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body contract.RegisterDeviceReq true "Device"
+// @Success 200 {object} util.BaseResponse
+// @Failure 400 {object} util.BaseResponse
+// @Failure 401 {object} util.BaseResponse
+// @Router /v1/reminders/devices [post]
+func (h *ReminderHandler) RegisterDevice(c *fiber.Ctx) error {
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	var req contract.RegisterDeviceReq
+	if err := c.BodyParser(&req); err != nil {
+		logger.Log.Warn("Failed to parse request body", zap.Error(err))
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := util.ValidateStruct(&req); err != nil {
+		logger.Log.Warn("Validation error", zap.Error(err))
+		return err
+	}
+
+	if err := h.reminderUsecase.RegisterDevice(c.Context(), claims.ID, &req); err != nil {
+		logger.Log.Error("Failed to register device", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(nil))
+}
+
+// @Tags Reminder
+// @Summary Unregister a device
+// @Description Stop sending push reminders to a device, e.g. on sign-out
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body contract.UnregisterDeviceReq true "Device"
+// @Success 200 {object} util.BaseResponse
+// @Failure 400 {object} util.BaseResponse
+// @Failure 401 {object} util.BaseResponse
+// @Router /v1/reminders/devices [delete]
+func (h *ReminderHandler) UnregisterDevice(c *fiber.Ctx) error {
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	var req contract.UnregisterDeviceReq
+	if err := c.BodyParser(&req); err != nil {
+		logger.Log.Warn("Failed to parse request body", zap.Error(err))
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := util.ValidateStruct(&req); err != nil {
+		logger.Log.Warn("Validation error", zap.Error(err))
+		return err
+	}
+
+	if err := h.reminderUsecase.UnregisterDevice(c.Context(), claims.ID, &req); err != nil {
+		logger.Log.Error("Failed to unregister device", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(nil))
+}
+
+// @Tags Reminder
+// @Summary List reminder deliveries
+// @Description List every reminder that was sent, skipped, cancelled or failed, newest first
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param task_id query string false "Only deliveries for this task"
+// @Param status query string false "Only deliveries with this status (pending, sending, sent, failed, skipped, cancelled)"
+// @Param page query int false "Page number (default: 1)" default(1)
+// @Param limit query int false "Items per page (default: 20)" default(20)
+// @Success 200 {object} util.PaginatedResponse{data=util.PaginatedData{items=[]contract.ReminderDeliveryRes}}
+// @Failure 400 {object} util.BaseResponse
+// @Failure 401 {object} util.BaseResponse
+// @Router /v1/reminders/deliveries [get]
+func (h *ReminderHandler) ListDeliveries(c *fiber.Ctx) error {
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	req := contract.ReminderDeliveriesReq{Page: 1, Limit: 20}
+	if err := c.QueryParser(&req); err != nil {
+		logger.Log.Warn("Failed to parse query", zap.Error(err))
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid query parameters")
+	}
+
+	if err := util.ValidateStruct(&req); err != nil {
+		logger.Log.Warn("Validation error", zap.Error(err))
+		return err
+	}
+
+	if req.Page < 1 {
+		return fiber.NewError(fiber.StatusBadRequest, "page must be greater than 0")
+	}
+	if req.Limit < 1 || req.Limit > 100 {
+		return fiber.NewError(fiber.StatusBadRequest, "limit must be between 1 and 100")
+	}
+
+	items, total, err := h.reminderUsecase.ListDeliveries(c.Context(), claims.ID, &req)
+	if err != nil {
+		logger.Log.Error("Failed to list reminder deliveries", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToPaginatedResponse(items, req.Page, req.Limit, total))
+}
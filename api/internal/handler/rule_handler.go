@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"app/internal/contract"
+	"app/internal/middleware"
+	"app/internal/usecase"
+	"app/pkg/logger"
+	"app/pkg/util"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type RuleHandler struct {
+	ruleUsecase *usecase.RuleUsecase
+}
+
+func NewRuleHandler(ruleUsecase *usecase.RuleUsecase) *RuleHandler {
+	return &RuleHandler{ruleUsecase: ruleUsecase}
+}
+
+func (h *RuleHandler) RegisterRoutes(app *fiber.App) {
+	ruleGroup := app.Group("/v1/rules")
+	ruleGroup.Get("", middleware.AuthGuard(), h.ListRules)
+	ruleGroup.Post("", middleware.AuthGuard(), h.CreateRule)
+	ruleGroup.Patch("/:rule_id", middleware.AuthGuard(), h.UpdateRule)
+	ruleGroup.Delete("/:rule_id", middleware.AuthGuard(), h.DeleteRule)
+	ruleGroup.Get("/:rule_id/executions", middleware.AuthGuard(), h.ListExecutions)
+}
+
+// @Tags Rule
+// @Summary List rules
+// @Description List the user's rules of the workspace active in the token, or of the personal space, oldest first
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} util.BaseResponse{data=[]contract.RuleRes}
+// @Failure 401 {object} util.BaseResponse
+// @Router /v1/rules [get]
+func (h *RuleHandler) ListRules(c *fiber.Ctx) error {
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	res, err := h.ruleUsecase.ListRules(c.Context(), claims.ID, claims.WorkspaceID)
+	if err != nil {
+		logger.Log.Error("Failed to list rules", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(res))
+}
+
+// @Tags Rule
+// @Summary Create a rule
+// @Description Create a rule in the workspace active in the token, or the personal space. When its trigger fires for a task or note
+// @Description that meets all its conditions, its actions are applied as one batch of sync changes made by the user, which don't trigger rules.
+// @Description Event triggers fire in the background, within seconds, on changes made by sync, board moves, imports or the server;
+// @Description task.overdue is checked every minute and fires once per due date. A dry-run rule logs the changes it would make without making them.
+// @Description The projects, collections and tags the actions use must exist and be ones the user can use there.
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body contract.CreateRuleReq true "Rule"
+// @Success 200 {object} util.BaseResponse{data=contract.RuleRes}
+// @Failure 400 {object} util.BaseResponse
+// @Failure 401 {object} util.BaseResponse
+// @Router /v1/rules [post]
+func (h *RuleHandler) CreateRule(c *fiber.Ctx) error {
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	var req contract.CreateRuleReq
+	if err := c.BodyParser(&req); err != nil {
+		logger.Log.Warn("Failed to parse request body", zap.Error(err))
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := util.ValidateStruct(&req); err != nil {
+		logger.Log.Warn("Validation error", zap.Error(err))
+		return err
+	}
+
+	res, err := h.ruleUsecase.CreateRule(c.Context(), claims.ID, claims.WorkspaceID, &req)
+	if err != nil {
+		logger.Log.Error("Failed to create rule", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(res))
+}
+
+// @Tags Rule
+// @Summary Update a rule
+// @Description Change the name, conditions or actions of a rule, turn it on or off, or switch dry run. The trigger can't change.
+// @Description The projects, collections and tags the actions use must exist and be ones the user can use where the rule runs.
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param rule_id path string true "Rule ID"
+// @Param request body contract.UpdateRuleReq true "Rule"
+// @Success 200 {object} util.BaseResponse{data=contract.RuleRes}
+// @Failure 400 {object} util.BaseResponse
+// @Failure 401 {object} util.BaseResponse
+// @Failure 404 {object} util.BaseResponse
+// @Router /v1/rules/{rule_id} [patch]
+func (h *RuleHandler) UpdateRule(c *fiber.Ctx) error {
+	ruleID := c.Params("rule_id")
+	if ruleID == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "rule_id is required")
+	}
+
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	var req contract.UpdateRuleReq
+	if err := c.BodyParser(&req); err != nil {
+		logger.Log.Warn("Failed to parse request body", zap.Error(err))
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := util.ValidateStruct(&req); err != nil {
+		logger.Log.Warn("Validation error", zap.Error(err))
+		return err
+	}
+
+	res, err := h.ruleUsecase.UpdateRule(c.Context(), claims.ID, ruleID, &req)
+	if err != nil {
+		logger.Log.Error("Failed to update rule", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(res))
+}
+
+// @Tags Rule
+// @Summary Delete a rule
+// @Description Delete a rule, which stops it firing
+// @Produce json
+// @Security BearerAuth
+// @Param rule_id path string true "Rule ID"
+// @Success 200 {object} util.BaseResponse
+// @Failure 401 {object} util.BaseResponse
+// @Failure 404 {object} util.BaseResponse
+// @Router /v1/rules/{rule_id} [delete]
+func (h *RuleHandler) DeleteRule(c *fiber.Ctx) error {
+	ruleID := c.Params("rule_id")
+	if ruleID == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "rule_id is required")
+	}
+
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	if err := h.ruleUsecase.DeleteRule(c.Context(), claims.ID, ruleID); err != nil {
+		logger.Log.Error("Failed to delete rule", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(nil))
+}
+
+// @Tags Rule
+// @Summary List rule executions
+// @Description List each time a rule fired, with the sync changes it made or, on a dry run, would have made, newest first
+// @Produce json
+// @Security BearerAuth
+// @Param rule_id path string true "Rule ID"
+// @Param status query string false "Only executions with this status (pending, applied, dry_run, skipped, failed)"
+// @Param page query int false "Page number (default: 1)" default(1)
+// @Param limit query int false "Items per page (default: 20)" default(20)
+// @Success 200 {object} util.PaginatedResponse{data=util.PaginatedData{items=[]contract.RuleExecutionRes}}
+// @Failure 400 {object} util.BaseResponse
+// @Failure 401 {object} util.BaseResponse
+// @Failure 404 {object} util.BaseResponse
+// @Router /v1/rules/{rule_id}/executions [get]
+func (h *RuleHandler) ListExecutions(c *fiber.Ctx) error {
+	ruleID := c.Params("rule_id")
+	if ruleID == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "rule_id is required")
+	}
+
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	req := contract.RuleExecutionsReq{Page: 1, Limit: 20}
+	if err := c.QueryParser(&req); err != nil {
+		logger.Log.Warn("Failed to parse query", zap.Error(err))
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid query parameters")
+	}
+
+	if err := util.ValidateStruct(&req); err != nil {
+		logger.Log.Warn("Validation error", zap.Error(err))
+		return err
+	}
+
+	if req.Page < 1 {
+		return fiber.NewError(fiber.StatusBadRequest, "page must be greater than 0")
+	}
+	if req.Limit < 1 || req.Limit > 100 {
+		return fiber.NewError(fiber.StatusBadRequest, "limit must be between 1 and 100")
+	}
+
+	items, total, err := h.ruleUsecase.ListExecutions(c.Context(), claims.ID, ruleID, &req)
+	if err != nil {
+		logger.Log.Error("Failed to list rule executions", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToPaginatedResponse(items, req.Page, req.Limit, total))
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"app/internal/contract"
+	"app/internal/middleware"
+	"app/internal/usecase"
+	"app/pkg/logger"
+	"app/pkg/util"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type SearchHandler struct {
+	searchUsecase *usecase.SearchUsecase
+}
+
+func NewSearchHandler(searchUsecase *usecase.SearchUsecase) *SearchHandler {
+	return &SearchHandler{searchUsecase: searchUsecase}
+}
+
+func (h *SearchHandler) RegisterRoutes(app *fiber.App) {
+	app.Get("/v1/search", middleware.AuthGuard(), h.Search)
+}
+
+// @Tags Search
+// @Summary Search notes, tasks and comments
+// @Description Full-text search over notes, tasks and comments, fused with semantic similarity on notes using reciprocal rank fusion.
+// @Description Comments come with the task or note they are on and are left out when filtering by tags.
+// @Description Notes only match semantically when they are close to the query, so a search may find nothing.
+// @Description Matched terms in snippets are wrapped in <mark></mark>.
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param q query string true "Search query (supports quoted phrases, OR and -exclusion)"
+// @Param types query string false "Comma-separated entity types to include (note,task,comment)"
+// @Param tags query string false "Comma-separated tag names; results must carry all of them"
+// @Param page query int false "Page number (default: 1)" default(1)
+// @Param limit query int false "Items per page (default: 20)" default(20)
+// @Success 200 {object} util.BaseResponse{data=contract.SearchRes}
+// @Failure 400 {object} util.BaseResponse
+// @Failure 401 {object} util.BaseResponse
+// @Router /v1/search [get]
+func (h *SearchHandler) Search(c *fiber.Ctx) error {
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	req := contract.SearchReq{Page: 1, Limit: 20}
+	if err := c.QueryParser(&req); err != nil {
+		logger.Log.Warn("Failed to parse query", zap.Error(err))
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid query parameters")
+	}
+
+	if err := util.ValidateStruct(&req); err != nil {
+		logger.Log.Warn("Validation error", zap.Error(err))
+		return err
+	}
+
+	if req.Page < 1 {
+		return fiber.NewError(fiber.StatusBadRequest, "page must be greater than 0")
+	}
+	if req.Limit < 1 || req.Limit > 100 {
+		return fiber.NewError(fiber.StatusBadRequest, "limit must be between 1 and 100")
+	}
+
+	res, err := h.searchUsecase.Search(c.Context(), claims.ID, claims.WorkspaceID, &req)
+	if err != nil {
+		logger.Log.Error("Failed to search", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(res))
+}
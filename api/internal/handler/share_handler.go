@@ -0,0 +1,231 @@
+package handler
+
+import (
+	"app/internal/contract"
+	"app/internal/middleware"
+	"app/internal/usecase"
+	"app/pkg/logger"
+	"app/pkg/util"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// sharePageCSP lets shared pages load nothing but their inline styles and remote images
+const sharePageCSP = "default-src 'none'; style-src 'unsafe-inline'; img-src https: http: data:; form-action 'self'; base-uri 'none'; frame-ancestors 'none'"
+
+type ShareHandler struct {
+	shareUsecase *usecase.ShareUsecase
+}
+
+func NewShareHandler(shareUsecase *usecase.ShareUsecase) *ShareHandler {
+	return &ShareHandler{shareUsecase: shareUsecase}
+}
+
+func (h *ShareHandler) RegisterRoutes(app *fiber.App) {
+	shareGroup := app.Group("/v1/shares")
+	shareGroup.Post("", middleware.AuthGuard(), h.CreateLink)
+	shareGroup.Get("", middleware.AuthGuard(), h.ListLinks)
+	shareGroup.Delete("/:share_id", middleware.AuthGuard(), h.RevokeLink)
+
+	// Anyone with the link can view it, so the secret token in the URL authenticates the request
+	sharedGroup := app.Group("/v1/shared")
+	sharedGroup.Get("/:token", h.ViewShare)
+	sharedGroup.Post("/:token", h.ViewShare)
+}
+
+// @Tags Share
+// @Summary Create a share link
+// @Description Create a public read-only link to a note or a collection, optionally with an expiry and a password.
+// @Description The URL is only returned here; the server keeps no copy of its token.
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body contract.CreateShareLinkReq true "Share link"
+// @Success 200 {object} util.BaseResponse{data=contract.ShareLinkRes}
+// @Failure 400 {object} util.BaseResponse
+// @Failure 401 {object} util.BaseResponse
+// @Failure 404 {object} util.BaseResponse
+// @Router /v1/shares [post]
+func (h *ShareHandler) CreateLink(c *fiber.Ctx) error {
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	var req contract.CreateShareLinkReq
+	if err := c.BodyParser(&req); err != nil {
+		logger.Log.Warn("Failed to parse request body", zap.Error(err))
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := util.ValidateStruct(&req); err != nil {
+		logger.Log.Warn("Validation error", zap.Error(err))
+		return err
+	}
+
+	res, err := h.shareUsecase.CreateLink(c.Context(), claims.ID, &req)
+	if err != nil {
+		logger.Log.Error("Failed to create share link", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(res))
+}
+
+// @Tags Share
+// @Summary List share links
+// @Description List the share links that aren't revoked, newest first, including expired ones
+// @Produce json
+// @Security BearerAuth
+// @Param note_id query string false "Only links to this note"
+// @Param collection_id query string false "Only links to this collection"
+// @Success 200 {object} util.BaseResponse{data=contract.ShareLinksRes}
+// @Failure 400 {object} util.BaseResponse
+// @Failure 401 {object} util.BaseResponse
+// @Router /v1/shares [get]
+func (h *ShareHandler) ListLinks(c *fiber.Ctx) error {
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	var req contract.ListShareLinksReq
+	if err := c.QueryParser(&req); err != nil {
+		logger.Log.Warn("Failed to parse query", zap.Error(err))
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid query parameters")
+	}
+
+	if err := util.ValidateStruct(&req); err != nil {
+		logger.Log.Warn("Validation error", zap.Error(err))
+		return err
+	}
+
+	res, err := h.shareUsecase.ListLinks(c.Context(), claims.ID, &req)
+	if err != nil {
+		logger.Log.Error("Failed to list share links", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(res))
+}
+
+// @Tags Share
+// @Summary Revoke a share link
+// @Description Make a share link stop working for good
+// @Produce json
+// @Security BearerAuth
+// @Param share_id path string true "Share link ID"
+// @Success 200 {object} util.BaseResponse
+// @Failure 401 {object} util.BaseResponse
+// @Failure 404 {object} util.BaseResponse
+// @Router /v1/shares/{share_id} [delete]
+func (h *ShareHandler) RevokeLink(c *fiber.Ctx) error {
+	shareID := c.Params("share_id")
+	if shareID == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "share_id is required")
+	}
+
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	if err := h.shareUsecase.RevokeLink(c.Context(), claims.ID, shareID); err != nil {
+		logger.Log.Error("Failed to revoke share link", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(nil))
+}
+
+// @Tags Share
+// @Summary View a shared note or collection
+// @Description Public view of a share link, as a web page or as JSON with format=json. No authentication besides the token in the URL.
+// @Description The password of a protected link goes in the X-Share-Password header, or in the password field of a POST to the same URL.
+// @Description Without it, the web page asks for the password.
+// @Accept json,x-www-form-urlencoded
+// @Produce html,json
+// @Param token path string true "Share link token"
+// @Param format query string false "html (default) or json"
+// @Param X-Share-Password header string false "Password of a protected link"
+// @Success 200 {object} util.BaseResponse{data=contract.SharedContentRes}
+// @Failure 401 {object} util.BaseResponse
+// @Failure 404 {object} util.BaseResponse
+// @Router /v1/shared/{token} [get]
+// @Router /v1/shared/{token} [post]
+func (h *ShareHandler) ViewShare(c *fiber.Ctx) error {
+	token := c.Params("token")
+	if token == "" {
+		return fiber.NewError(fiber.StatusNotFound, "Share link not found")
+	}
+
+	var query contract.SharedContentReq
+	if err := c.QueryParser(&query); err != nil {
+		logger.Log.Warn("Failed to parse query", zap.Error(err))
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid query parameters")
+	}
+
+	if err := util.ValidateStruct(&query); err != nil {
+		logger.Log.Warn("Validation error", zap.Error(err))
+		return err
+	}
+
+	var password *string
+	if header := c.Get("X-Share-Password"); header != "" {
+		password = &header
+	}
+	if c.Method() == fiber.MethodPost {
+		var req contract.SharedPasswordReq
+		if err := c.BodyParser(&req); err != nil {
+			logger.Log.Warn("Failed to parse request body", zap.Error(err))
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+		}
+		password = &req.Password
+	}
+
+	// Shared pages must not leak their URL to the sites they link to, nor be cached or indexed
+	c.Set(fiber.HeaderReferrerPolicy, "no-referrer")
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+	c.Set("X-Robots-Tag", "noindex, nofollow")
+
+	content, err := h.shareUsecase.ViewShare(c.Context(), token, password)
+	var fiberErr *fiber.Error
+	if query.Format != "json" && errors.As(err, &fiberErr) && fiberErr.Code == fiber.StatusUnauthorized {
+		message := ""
+		if password != nil {
+			message = fiberErr.Message
+		}
+		page, err := h.shareUsecase.RenderSharePasswordPage(message)
+		if err != nil {
+			return err
+		}
+		return h.sendSharePage(c, fiber.StatusUnauthorized, page)
+	}
+	if err != nil {
+		logger.Log.Warn("Failed to view share link", zap.Error(err))
+		return err
+	}
+
+	if query.Format == "json" {
+		return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(content))
+	}
+
+	page, err := h.shareUsecase.RenderSharePage(content)
+	if err != nil {
+		return err
+	}
+	return h.sendSharePage(c, fiber.StatusOK, page)
+}
+
+func (h *ShareHandler) sendSharePage(c *fiber.Ctx, status int, page string) error {
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	c.Set(fiber.HeaderContentSecurityPolicy, sharePageCSP)
+	// Notes may embed images from other sites, which don't send the headers helmet's default requires
+	c.Set("Cross-Origin-Embedder-Policy", "unsafe-none")
+	return c.Status(status).SendString(page)
+}
@@ -25,7 +25,11 @@ func (h *SyncHandler) RegisterRoutes(app *fiber.App) {
 
 // @Tags Sync
 // @Summary Sync data
-// @Description Sync data between client and server
+// @Description Sync data between client and server, in the workspace active in the token or the personal space.
+// @Description Fails with 403 once the user is no longer a member of the active workspace.
+// @Description Changes the user may not make, such as edits by a viewer, are skipped and listed in rejected; the rest of the batch is applied.
+// @Description Comments on tasks and notes sync too; @mentions in them notify the users they name by email or push.
+// @Description Every change that alters an entity is logged in the activity log, with the deviceId the request names.
 // @Accept json
 // @Produce json
 // @Security BearerAuth
@@ -33,6 +37,7 @@ func (h *SyncHandler) RegisterRoutes(app *fiber.App) {
 // @Success 200 {object} util.BaseResponse{data=contract.SyncRes}
 // @Failure 400 {object} util.BaseResponse
 // @Failure 401 {object} util.BaseResponse
+// @Failure 403 {object} util.BaseResponse
 // @Router /v1/sync [post]
 func (h *SyncHandler) Sync(c *fiber.Ctx) error {
 	var req contract.SyncReq
@@ -52,7 +57,7 @@ func (h *SyncHandler) Sync(c *fiber.Ctx) error {
 		return err
 	}
 
-	res, err := h.syncUsecase.Sync(c, claims.ID, &req)
+	res, err := h.syncUsecase.Sync(c, claims.ID, claims.WorkspaceID, &req)
 	if err != nil {
 		logger.Log.Warn("Failed to sync data", zap.Error(err))
 		return err
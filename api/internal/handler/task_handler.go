@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"app/internal/contract"
+	"app/internal/middleware"
+	"app/internal/usecase"
+	"app/pkg/logger"
+	"app/pkg/util"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type TaskHandler struct {
+	taskUsecase *usecase.TaskUsecase
+}
+
+func NewTaskHandler(taskUsecase *usecase.TaskUsecase) *TaskHandler {
+	return &TaskHandler{taskUsecase: taskUsecase}
+}
+
+func (h *TaskHandler) RegisterRoutes(app *fiber.App) {
+	taskGroup := app.Group("/v1/tasks")
+	taskGroup.Post("/:task_id/move", middleware.AuthGuard(), h.MoveTask)
+}
+
+// @Tags Task
+// @Summary Move a task
+// @Description Place a task between two tasks of the same project and parent, and get its new sort order.
+// @Description With only afterTaskId it goes right after that task, with only beforeTaskId right before it, and with neither to the end.
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param task_id path string true "Task ID"
+// @Param request body contract.MoveTaskReq true "Neighbours"
+// @Success 200 {object} util.BaseResponse{data=contract.MoveTaskRes}
+// @Failure 400 {object} util.BaseResponse
+// @Failure 401 {object} util.BaseResponse
+// @Failure 404 {object} util.BaseResponse
+// @Router /v1/tasks/{task_id}/move [post]
+func (h *TaskHandler) MoveTask(c *fiber.Ctx) error {
+	taskID := c.Params("task_id")
+	if taskID == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "task_id is required")
+	}
+
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	var req contract.MoveTaskReq
+	if err := c.BodyParser(&req); err != nil {
+		logger.Log.Warn("Failed to parse request body", zap.Error(err))
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := util.ValidateStruct(&req); err != nil {
+		logger.Log.Warn("Validation error", zap.Error(err))
+		return err
+	}
+
+	res, err := h.taskUsecase.MoveTask(c.Context(), claims.ID, taskID, &req)
+	if err != nil {
+		logger.Log.Error("Failed to move task", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(res))
+}
@@ -0,0 +1,260 @@
+package handler
+
+import (
+	"app/internal/contract"
+	"app/internal/middleware"
+	"app/internal/usecase"
+	"app/pkg/logger"
+	"app/pkg/util"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type WebhookHandler struct {
+	webhookUsecase *usecase.WebhookUsecase
+}
+
+func NewWebhookHandler(webhookUsecase *usecase.WebhookUsecase) *WebhookHandler {
+	return &WebhookHandler{webhookUsecase: webhookUsecase}
+}
+
+func (h *WebhookHandler) RegisterRoutes(app *fiber.App) {
+	webhookGroup := app.Group("/v1/webhooks")
+	webhookGroup.Get("", middleware.AuthGuard(), h.ListWebhooks)
+	webhookGroup.Post("", middleware.AuthGuard(), h.CreateWebhook)
+	webhookGroup.Patch("/:webhook_id", middleware.AuthGuard(), h.UpdateWebhook)
+	webhookGroup.Delete("/:webhook_id", middleware.AuthGuard(), h.DeleteWebhook)
+	webhookGroup.Get("/:webhook_id/deliveries", middleware.AuthGuard(), h.ListDeliveries)
+	webhookGroup.Post("/:webhook_id/deliveries/:delivery_id/redeliver", middleware.AuthGuard(), h.Redeliver)
+}
+
+// @Tags Webhook
+// @Summary List webhooks
+// @Description List the user's webhooks, oldest first. Secrets are only returned when a webhook is created.
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} util.BaseResponse{data=[]contract.WebhookRes}
+// @Failure 401 {object} util.BaseResponse
+// @Router /v1/webhooks [get]
+func (h *WebhookHandler) ListWebhooks(c *fiber.Ctx) error {
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	res, err := h.webhookUsecase.ListWebhooks(c.Context(), claims.ID)
+	if err != nil {
+		logger.Log.Error("Failed to list webhooks", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(res))
+}
+
+// @Tags Webhook
+// @Summary Create a webhook
+// @Description Create a webhook that is posted a signed JSON event (contract.WebhookEvent) for each change it subscribes to.
+// @Description The X-Memr-Signature header is "sha256=" and the hex HMAC-SHA256, keyed by the secret, of the X-Memr-Timestamp header, a dot and the body.
+// @Description Failed deliveries are retried with exponential backoff, up to 8 attempts. Keep the secret: it is only returned now.
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body contract.CreateWebhookReq true "Webhook"
+// @Success 200 {object} util.BaseResponse{data=contract.WebhookRes}
+// @Failure 400 {object} util.BaseResponse
+// @Failure 401 {object} util.BaseResponse
+// @Failure 404 {object} util.BaseResponse
+// @Router /v1/webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c *fiber.Ctx) error {
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	var req contract.CreateWebhookReq
+	if err := c.BodyParser(&req); err != nil {
+		logger.Log.Warn("Failed to parse request body", zap.Error(err))
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := util.ValidateStruct(&req); err != nil {
+		logger.Log.Warn("Validation error", zap.Error(err))
+		return err
+	}
+
+	res, err := h.webhookUsecase.CreateWebhook(c.Context(), claims.ID, &req)
+	if err != nil {
+		logger.Log.Error("Failed to create webhook", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(res))
+}
+
+// @Tags Webhook
+// @Summary Update a webhook
+// @Description Change the URL, description, event types or whether a webhook is on. Turning it off cancels its pending deliveries.
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param webhook_id path string true "Webhook ID"
+// @Param request body contract.UpdateWebhookReq true "Webhook"
+// @Success 200 {object} util.BaseResponse{data=contract.WebhookRes}
+// @Failure 400 {object} util.BaseResponse
+// @Failure 401 {object} util.BaseResponse
+// @Failure 404 {object} util.BaseResponse
+// @Router /v1/webhooks/{webhook_id} [patch]
+func (h *WebhookHandler) UpdateWebhook(c *fiber.Ctx) error {
+	webhookID := c.Params("webhook_id")
+	if webhookID == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "webhook_id is required")
+	}
+
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	var req contract.UpdateWebhookReq
+	if err := c.BodyParser(&req); err != nil {
+		logger.Log.Warn("Failed to parse request body", zap.Error(err))
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := util.ValidateStruct(&req); err != nil {
+		logger.Log.Warn("Validation error", zap.Error(err))
+		return err
+	}
+
+	res, err := h.webhookUsecase.UpdateWebhook(c.Context(), claims.ID, webhookID, &req)
+	if err != nil {
+		logger.Log.Error("Failed to update webhook", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(res))
+}
+
+// @Tags Webhook
+// @Summary Delete a webhook
+// @Description Delete a webhook and cancel its pending deliveries
+// @Produce json
+// @Security BearerAuth
+// @Param webhook_id path string true "Webhook ID"
+// @Success 200 {object} util.BaseResponse
+// @Failure 401 {object} util.BaseResponse
+// @Failure 404 {object} util.BaseResponse
+// @Router /v1/webhooks/{webhook_id} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *fiber.Ctx) error {
+	webhookID := c.Params("webhook_id")
+	if webhookID == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "webhook_id is required")
+	}
+
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	if err := h.webhookUsecase.DeleteWebhook(c.Context(), claims.ID, webhookID); err != nil {
+		logger.Log.Error("Failed to delete webhook", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(nil))
+}
+
+// @Tags Webhook
+// @Summary List webhook deliveries
+// @Description List the events posted, or to post, to a webhook with the outcome of the last attempt, newest first
+// @Produce json
+// @Security BearerAuth
+// @Param webhook_id path string true "Webhook ID"
+// @Param status query string false "Only deliveries with this status (pending, sending, sent, failed, cancelled)"
+// @Param page query int false "Page number (default: 1)" default(1)
+// @Param limit query int false "Items per page (default: 20)" default(20)
+// @Success 200 {object} util.PaginatedResponse{data=util.PaginatedData{items=[]contract.WebhookDeliveryRes}}
+// @Failure 400 {object} util.BaseResponse
+// @Failure 401 {object} util.BaseResponse
+// @Failure 404 {object} util.BaseResponse
+// @Router /v1/webhooks/{webhook_id}/deliveries [get]
+func (h *WebhookHandler) ListDeliveries(c *fiber.Ctx) error {
+	webhookID := c.Params("webhook_id")
+	if webhookID == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "webhook_id is required")
+	}
+
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	req := contract.WebhookDeliveriesReq{Page: 1, Limit: 20}
+	if err := c.QueryParser(&req); err != nil {
+		logger.Log.Warn("Failed to parse query", zap.Error(err))
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid query parameters")
+	}
+
+	if err := util.ValidateStruct(&req); err != nil {
+		logger.Log.Warn("Validation error", zap.Error(err))
+		return err
+	}
+
+	if req.Page < 1 {
+		return fiber.NewError(fiber.StatusBadRequest, "page must be greater than 0")
+	}
+	if req.Limit < 1 || req.Limit > 100 {
+		return fiber.NewError(fiber.StatusBadRequest, "limit must be between 1 and 100")
+	}
+
+	items, total, err := h.webhookUsecase.ListDeliveries(c.Context(), claims.ID, webhookID, &req)
+	if err != nil {
+		logger.Log.Error("Failed to list webhook deliveries", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToPaginatedResponse(items, req.Page, req.Limit, total))
+}
+
+// @Tags Webhook
+// @Summary Redeliver a webhook event
+// @Description Queue the event of a past delivery again as a new delivery. The event keeps its ID, so the endpoint can tell it is a repeat.
+// @Produce json
+// @Security BearerAuth
+// @Param webhook_id path string true "Webhook ID"
+// @Param delivery_id path string true "Delivery ID"
+// @Success 200 {object} util.BaseResponse{data=contract.WebhookDeliveryRes}
+// @Failure 400 {object} util.BaseResponse
+// @Failure 401 {object} util.BaseResponse
+// @Failure 404 {object} util.BaseResponse
+// @Router /v1/webhooks/{webhook_id}/deliveries/{delivery_id}/redeliver [post]
+func (h *WebhookHandler) Redeliver(c *fiber.Ctx) error {
+	webhookID := c.Params("webhook_id")
+	if webhookID == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "webhook_id is required")
+	}
+	deliveryID := c.Params("delivery_id")
+	if deliveryID == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "delivery_id is required")
+	}
+
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	res, err := h.webhookUsecase.Redeliver(c.Context(), claims.ID, webhookID, deliveryID)
+	if err != nil {
+		logger.Log.Error("Failed to redeliver webhook event", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(res))
+}
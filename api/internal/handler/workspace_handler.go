@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"app/internal/contract"
+	"app/internal/middleware"
+	"app/internal/usecase"
+	"app/pkg/logger"
+	"app/pkg/util"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type WorkspaceHandler struct {
+	workspaceUsecase *usecase.WorkspaceUsecase
+}
+
+func NewWorkspaceHandler(workspaceUsecase *usecase.WorkspaceUsecase) *WorkspaceHandler {
+	return &WorkspaceHandler{workspaceUsecase: workspaceUsecase}
+}
+
+func (h *WorkspaceHandler) RegisterRoutes(app *fiber.App) {
+	workspaceGroup := app.Group("/v1/workspaces")
+	workspaceGroup.Get("", middleware.AuthGuard(), h.ListWorkspaces)
+	workspaceGroup.Post("", middleware.AuthGuard(), h.CreateWorkspace)
+	workspaceGroup.Post("/switch", middleware.AuthGuard(), h.SwitchWorkspace)
+	workspaceGroup.Patch("/:workspace_id", middleware.AuthGuard(), h.UpdateWorkspace)
+	workspaceGroup.Delete("/:workspace_id", middleware.AuthGuard(), h.DeleteWorkspace)
+}
+
+// @Tags Workspace
+// @Summary List workspaces
+// @Description List the team workspaces the user owns or is a member of, with the active one
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} util.BaseResponse{data=contract.WorkspacesRes}
+// @Failure 401 {object} util.BaseResponse
+// @Router /v1/workspaces [get]
+func (h *WorkspaceHandler) ListWorkspaces(c *fiber.Ctx) error {
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	res, err := h.workspaceUsecase.ListWorkspaces(c.Context(), claims.ID, claims.WorkspaceID)
+	if err != nil {
+		logger.Log.Error("Failed to list workspaces", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(res))
+}
+
+// @Tags Workspace
+// @Summary Create a workspace
+// @Description Create a team workspace owned by the user. Invite members through its invitations route.
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body contract.CreateWorkspaceReq true "Workspace"
+// @Success 200 {object} util.BaseResponse{data=contract.WorkspaceRes}
+// @Failure 400 {object} util.BaseResponse
+// @Failure 401 {object} util.BaseResponse
+// @Router /v1/workspaces [post]
+func (h *WorkspaceHandler) CreateWorkspace(c *fiber.Ctx) error {
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	var req contract.CreateWorkspaceReq
+	if err := c.BodyParser(&req); err != nil {
+		logger.Log.Warn("Failed to parse request body", zap.Error(err))
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := util.ValidateStruct(&req); err != nil {
+		logger.Log.Warn("Validation error", zap.Error(err))
+		return err
+	}
+
+	res, err := h.workspaceUsecase.CreateWorkspace(c.Context(), claims.ID, &req)
+	if err != nil {
+		logger.Log.Error("Failed to create workspace", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(res))
+}
+
+// @Tags Workspace
+// @Summary Switch workspace
+// @Description Issue new tokens with another active workspace, or with none for the personal space.
+// @Description Sync, search and chats then only see what is in it, so clients sync from scratch after switching.
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body contract.SwitchWorkspaceReq true "Workspace"
+// @Success 200 {object} util.BaseResponse{data=contract.SwitchWorkspaceRes}
+// @Failure 400 {object} util.BaseResponse
+// @Failure 401 {object} util.BaseResponse
+// @Failure 404 {object} util.BaseResponse
+// @Router /v1/workspaces/switch [post]
+func (h *WorkspaceHandler) SwitchWorkspace(c *fiber.Ctx) error {
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	var req contract.SwitchWorkspaceReq
+	if err := c.BodyParser(&req); err != nil {
+		logger.Log.Warn("Failed to parse request body", zap.Error(err))
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := util.ValidateStruct(&req); err != nil {
+		logger.Log.Warn("Validation error", zap.Error(err))
+		return err
+	}
+
+	res, err := h.workspaceUsecase.SwitchWorkspace(c.Context(), claims.ID, &req)
+	if err != nil {
+		logger.Log.Error("Failed to switch workspace", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(res))
+}
+
+// @Tags Workspace
+// @Summary Rename a workspace
+// @Description Rename a workspace. Only owners can rename it.
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param workspace_id path string true "Workspace ID"
+// @Param request body contract.UpdateWorkspaceReq true "Workspace"
+// @Success 200 {object} util.BaseResponse
+// @Failure 400 {object} util.BaseResponse
+// @Failure 401 {object} util.BaseResponse
+// @Failure 403 {object} util.BaseResponse
+// @Failure 404 {object} util.BaseResponse
+// @Router /v1/workspaces/{workspace_id} [patch]
+func (h *WorkspaceHandler) UpdateWorkspace(c *fiber.Ctx) error {
+	workspaceID := c.Params("workspace_id")
+	if workspaceID == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "workspace_id is required")
+	}
+
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	var req contract.UpdateWorkspaceReq
+	if err := c.BodyParser(&req); err != nil {
+		logger.Log.Warn("Failed to parse request body", zap.Error(err))
+		return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := util.ValidateStruct(&req); err != nil {
+		logger.Log.Warn("Validation error", zap.Error(err))
+		return err
+	}
+
+	if err := h.workspaceUsecase.UpdateWorkspace(c.Context(), claims.ID, workspaceID, &req); err != nil {
+		logger.Log.Error("Failed to update workspace", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(nil))
+}
+
+// @Tags Workspace
+// @Summary Delete a workspace
+// @Description Delete a workspace once its projects and collections are deleted. Only owners can delete it.
+// @Produce json
+// @Security BearerAuth
+// @Param workspace_id path string true "Workspace ID"
+// @Success 200 {object} util.BaseResponse
+// @Failure 401 {object} util.BaseResponse
+// @Failure 403 {object} util.BaseResponse
+// @Failure 404 {object} util.BaseResponse
+// @Failure 409 {object} util.BaseResponse
+// @Router /v1/workspaces/{workspace_id} [delete]
+func (h *WorkspaceHandler) DeleteWorkspace(c *fiber.Ctx) error {
+	workspaceID := c.Params("workspace_id")
+	if workspaceID == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "workspace_id is required")
+	}
+
+	claims, err := middleware.GetAuthClaims(c)
+	if err != nil {
+		logger.Log.Warn("Failed to get auth claims", zap.Error(err))
+		return fiber.NewError(fiber.StatusUnauthorized, "Please authenticate")
+	}
+
+	if err := h.workspaceUsecase.DeleteWorkspace(c.Context(), claims.ID, workspaceID); err != nil {
+		logger.Log.Error("Failed to delete workspace", zap.Error(err))
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(util.ToSuccessResponse(nil))
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"app/internal/config"
+	"io"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// BodyLimitConfig caps streamed request bodies at config.DefaultBodyLimit, or at the limit in
+// config.RouteBodyLimits for the path. Bodies within the app's BodyLimit are never streamed.
+func BodyLimitConfig() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		req := c.Request()
+		if !req.IsBodyStream() {
+			return c.Next()
+		}
+
+		limit := config.DefaultBodyLimit
+		for prefix, routeLimit := range config.RouteBodyLimits {
+			if strings.HasPrefix(c.Path(), prefix) {
+				limit = routeLimit
+			}
+		}
+		if req.Header.ContentLength() > limit {
+			return tooLarge(c)
+		}
+
+		// Chunked bodies have no length up front, so read one byte past the limit to catch them
+		body, err := io.ReadAll(io.LimitReader(req.BodyStream(), int64(limit)+1))
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+		}
+		if len(body) > limit {
+			return tooLarge(c)
+		}
+		req.SetBody(body)
+
+		return c.Next()
+	}
+}
+
+// tooLarge refuses a body and closes the connection, since the rest of the body is left unread
+func tooLarge(c *fiber.Ctx) error {
+	c.Context().SetConnectionClose()
+	return fiber.ErrRequestEntityTooLarge
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"app/internal/config"
+	"bytes"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestBodyLimitConfig(t *testing.T) {
+	app := fiber.New(config.FiberConfig())
+	app.Use(BodyLimitConfig())
+	echoLength := func(c *fiber.Ctx) error {
+		return c.SendString(strconv.Itoa(len(c.Body())))
+	}
+	app.Post("/v1/notes", echoLength)
+	app.Post("/v1/import/markdown", echoLength)
+	app.Post("/v1/imports", echoLength)
+
+	tests := []struct {
+		name       string
+		path       string
+		size       int
+		wantStatus int
+	}{
+		{name: "small body", path: "/v1/notes", size: 1 << 10, wantStatus: fiber.StatusOK},
+		{name: "over the default limit", path: "/v1/notes", size: config.DefaultBodyLimit + 1, wantStatus: fiber.StatusRequestEntityTooLarge},
+		{name: "import over the default limit", path: "/v1/import/markdown", size: config.DefaultBodyLimit + 1, wantStatus: fiber.StatusOK},
+		{name: "import over its limit", path: "/v1/import/markdown", size: config.ImportBodyLimit + 1, wantStatus: fiber.StatusRequestEntityTooLarge},
+		{name: "lookalike of the import prefix", path: "/v1/imports", size: config.DefaultBodyLimit + 1, wantStatus: fiber.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(fiber.MethodPost, tt.path, bytes.NewReader(make([]byte, tt.size)))
+			res, err := app.Test(req, -1)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			if res.StatusCode != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", res.StatusCode, tt.wantStatus)
+			}
+			if tt.wantStatus != fiber.StatusOK {
+				return
+			}
+			var body bytes.Buffer
+			body.ReadFrom(res.Body)
+			if body.String() != strconv.Itoa(tt.size) {
+				t.Errorf("handler read %s bytes, want %d", body.String(), tt.size)
+			}
+		})
+	}
+}
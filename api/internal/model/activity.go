@@ -0,0 +1,52 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+const (
+	ActivityActionCreate  = "create"
+	ActivityActionUpdate  = "update"
+	ActivityActionDelete  = "delete"
+	ActivityActionRestore = "restore"
+
+	ActivitySourceSync   = "sync"
+	ActivitySourceBoard  = "board"
+	ActivitySourceImport = "import"
+	// ActivitySourceRule changes never trigger rules, so rules can't set each other off in a loop
+	ActivitySourceRule = "rule"
+	// ActivitySourceSystem changes are made by the server on a schedule, such as spawning the
+	// instances of recurring tasks, and are credited to the owner of the rows
+	ActivitySourceSystem = "system"
+)
+
+// ActivityChange is the value of a column before and after a change, as JSON
+type ActivityChange struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// Activity is an entry of the append-only log of changes to synced entities. The context IDs
+// say which project, collection, task or note the change is about, and aren't foreign keys.
+type Activity struct {
+	ID      string `json:"id" gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	ActorID string `json:"actor_id"`
+	// OwnerID is the user whose row changed, who differs from the actor on shared items
+	OwnerID    string  `json:"owner_id"`
+	EntityType string  `json:"entity_type"`
+	EntityID   string  `json:"entity_id"`
+	Action     string  `json:"action"`
+	Source     string  `json:"source"`
+	DeviceID   *string `json:"device_id"`
+	// Changes holds each column that changed by name
+	Changes      datatypes.JSONType[map[string]ActivityChange] `json:"changes" gorm:"type:jsonb"`
+	ProjectID    *string                                       `json:"project_id"`
+	CollectionID *string                                       `json:"collection_id"`
+	TaskID       *string                                       `json:"task_id"`
+	NoteID       *string                                       `json:"note_id"`
+	CreatedAt    time.Time                                     `gorm:"default:CURRENT_TIMESTAMP"`
+
+	Actor *User `gorm:"foreignKey:ActorID"`
+}
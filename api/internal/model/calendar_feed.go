@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// CalendarFeed is a user's secret iCalendar feed URL. Only a SHA-256 hash of its token is stored.
+type CalendarFeed struct {
+	ID             string     `json:"id" gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	UserID         string     `json:"user_id"`
+	TokenHash      string     `json:"-"`
+	LastAccessedAt *time.Time `json:"last_accessed_at"`
+	CreatedAt      time.Time  `gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt      time.Time  `gorm:"default:CURRENT_TIMESTAMP"`
+
+	User *User `gorm:"foreignKey:UserID"`
+}
@@ -3,9 +3,11 @@ package model
 import "time"
 
 type Chat struct {
-	ID        string    `json:"id" gorm:"primaryKey"`
-	UserID    string    `json:"user_id"`
-	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+	ID     string `json:"id" gorm:"primaryKey"`
+	UserID string `json:"user_id"`
+	// WorkspaceID is the workspace the chat was started in, or nil for the personal space
+	WorkspaceID *string   `json:"workspace_id"`
+	CreatedAt   time.Time `gorm:"default:CURRENT_TIMESTAMP"`
 
 	User     *User     `gorm:"foreignKey:UserID"`
 	Messages []Message `gorm:"foreignKey:ChatID"`
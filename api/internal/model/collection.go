@@ -3,11 +3,13 @@ package model
 import "time"
 
 type Collection struct {
-	ID          string     `json:"id" gorm:"primaryKey"`
-	UserID      string     `json:"user_id"`
-	Title       string     `json:"title"`
-	Description string     `json:"description"`
-	Color       *string    `json:"color"`
+	ID          string  `json:"id" gorm:"primaryKey"`
+	UserID      string  `json:"user_id"`
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	Color       *string `json:"color"`
+	// WorkspaceID is set for a collection of a team workspace, which is stored under its owner's account
+	WorkspaceID *string    `json:"workspace_id"`
 	CreatedAt   time.Time  `gorm:"default:CURRENT_TIMESTAMP"`
 	UpdatedAt   time.Time  `gorm:"default:CURRENT_TIMESTAMP"`
 	DeletedAt   *time.Time `gorm:"index"`
@@ -0,0 +1,60 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+const (
+	MentionStatusPending = "pending"
+	// MentionStatusSending is held while a worker sends the notification
+	MentionStatusSending = "sending"
+	MentionStatusSent    = "sent"
+	// MentionStatusFailed is retried until MentionMaxAttempts is reached
+	MentionStatusFailed = "failed"
+	// MentionStatusSkipped means no channel had anywhere to deliver to
+	MentionStatusSkipped = "skipped"
+	// MentionStatusCancelled means the comment was deleted, or the user can no longer see it, before delivery
+	MentionStatusCancelled = "cancelled"
+
+	MentionMaxAttempts = 5
+)
+
+// Comment is a message on a task or a note. It belongs to the user who wrote it, not to the
+// owner of the task or note.
+type Comment struct {
+	ID      string  `json:"id" gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	UserID  string  `json:"user_id"`
+	TaskID  *string `json:"task_id"`
+	NoteID  *string `json:"note_id"`
+	Content string  `json:"content"`
+	// MentionedUserIDs are the users the content @mentions who see the task or note
+	MentionedUserIDs datatypes.JSONSlice[string] `json:"mentioned_user_ids" gorm:"type:jsonb;default:'[]'"`
+	// EditedAt is set when the content changes after the comment was created
+	EditedAt  *time.Time `json:"edited_at"`
+	CreatedAt time.Time  `gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt time.Time  `gorm:"default:CURRENT_TIMESTAMP"`
+	DeletedAt *time.Time `gorm:"index"`
+
+	User *User `gorm:"foreignKey:UserID"`
+	Task *Task `gorm:"foreignKey:TaskID"`
+	Note *Note `gorm:"foreignKey:NoteID"`
+}
+
+// CommentMention is the notification of a user a comment mentioned, sent once per comment
+type CommentMention struct {
+	ID            string     `json:"id" gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	CommentID     string     `json:"comment_id"`
+	UserID        string     `json:"user_id"`
+	Status        string     `json:"status" gorm:"default:pending"`
+	Attempts      int        `json:"attempts"`
+	LastError     *string    `json:"last_error"`
+	NextAttemptAt time.Time  `json:"next_attempt_at" gorm:"default:CURRENT_TIMESTAMP"`
+	SentAt        *time.Time `json:"sent_at"`
+	CreatedAt     time.Time  `gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt     time.Time  `gorm:"default:CURRENT_TIMESTAMP"`
+
+	User    *User    `gorm:"foreignKey:UserID"`
+	Comment *Comment `gorm:"foreignKey:CommentID"`
+}
@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+type DeviceToken struct {
+	ID        string    `json:"id" gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	UserID    string    `json:"user_id"`
+	Token     string    `json:"token"`
+	Platform  string    `json:"platform"`
+	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+
+	User *User `gorm:"foreignKey:UserID"`
+}
@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+const (
+	ExportStatusPending = "pending"
+	// ExportStatusRunning is held while a worker builds the archive
+	ExportStatusRunning   = "running"
+	ExportStatusCompleted = "completed"
+	ExportStatusFailed    = "failed"
+
+	// ExportRetention is how long a finished export can be downloaded
+	ExportRetention = 7 * 24 * time.Hour
+)
+
+// ExportJob is a background export of a user's account to a zip archive
+type ExportJob struct {
+	ID     string  `json:"id" gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	UserID string  `json:"user_id"`
+	Status string  `json:"status" gorm:"default:pending"`
+	Error  *string `json:"error"`
+	// ArchivePath is the file the finished archive is kept in
+	ArchivePath *string    `json:"-"`
+	SizeBytes   *int64     `json:"size_bytes"`
+	StartedAt   *time.Time `json:"started_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	CreatedAt   time.Time  `gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt   time.Time  `gorm:"default:CURRENT_TIMESTAMP"`
+
+	User *User `gorm:"foreignKey:UserID"`
+}
@@ -0,0 +1,80 @@
+package model
+
+import "time"
+
+const (
+	MemberRoleViewer = "viewer"
+	MemberRoleEditor = "editor"
+	MemberRoleOwner  = "owner"
+)
+
+// memberRoleRanks orders roles by what they allow: viewers read, editors also write, and owners
+// also manage members and may delete
+var memberRoleRanks = map[string]int{
+	MemberRoleViewer: 1,
+	MemberRoleEditor: 2,
+	MemberRoleOwner:  3,
+}
+
+// HigherMemberRole returns whichever of two roles allows more
+func HigherMemberRole(a, b string) string {
+	if memberRoleRanks[b] > memberRoleRanks[a] {
+		return b
+	}
+	return a
+}
+
+// MemberRoleAllows reports whether a role grants at least what the minimum role does.
+// An empty role, for no access, allows nothing.
+func MemberRoleAllows(role, minimum string) bool {
+	return memberRoleRanks[role] > 0 && memberRoleRanks[role] >= memberRoleRanks[minimum]
+}
+
+// Membership gives a user a role on a project or a collection of another user, or on a workspace.
+// Items in it stay owned by that user, who always counts as an owner and has no membership of their own.
+type Membership struct {
+	ID           string  `json:"id" gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	UserID       string  `json:"user_id"`
+	ProjectID    *string `json:"project_id"`
+	CollectionID *string `json:"collection_id"`
+	WorkspaceID  *string `json:"workspace_id"`
+	Role         string  `json:"role"`
+	InvitedBy    *string `json:"invited_by"`
+	// RemovedAt is set when the member leaves or is removed
+	RemovedAt *time.Time `json:"removed_at"`
+	CreatedAt time.Time  `gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt time.Time  `gorm:"default:CURRENT_TIMESTAMP"`
+
+	User       *User       `gorm:"foreignKey:UserID"`
+	Project    *Project    `gorm:"foreignKey:ProjectID"`
+	Collection *Collection `gorm:"foreignKey:CollectionID"`
+	Workspace  *Workspace  `gorm:"foreignKey:WorkspaceID"`
+}
+
+// InvitationTTL is how long an invitation can be accepted
+const InvitationTTL = 14 * 24 * time.Hour
+
+// Invitation asks whoever holds an email address to join a project, a collection or a workspace.
+// Only a SHA-256 hash of its token is stored.
+type Invitation struct {
+	ID           string     `json:"id" gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	InviterID    string     `json:"inviter_id"`
+	ProjectID    *string    `json:"project_id"`
+	CollectionID *string    `json:"collection_id"`
+	WorkspaceID  *string    `json:"workspace_id"`
+	Email        string     `json:"email"`
+	Role         string     `json:"role"`
+	TokenHash    string     `json:"-"`
+	AcceptedBy   *string    `json:"accepted_by"`
+	AcceptedAt   *time.Time `json:"accepted_at"`
+	DeclinedAt   *time.Time `json:"declined_at"`
+	RevokedAt    *time.Time `json:"revoked_at"`
+	ExpiresAt    time.Time  `json:"expires_at"`
+	CreatedAt    time.Time  `gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt    time.Time  `gorm:"default:CURRENT_TIMESTAMP"`
+
+	Inviter    *User       `gorm:"foreignKey:InviterID"`
+	Project    *Project    `gorm:"foreignKey:ProjectID"`
+	Collection *Collection `gorm:"foreignKey:CollectionID"`
+	Workspace  *Workspace  `gorm:"foreignKey:WorkspaceID"`
+}
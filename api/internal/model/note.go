@@ -19,4 +19,5 @@ type Note struct {
 
 	Collection *Collection `gorm:"foreignKey:CollectionID"`
 	User       *User       `gorm:"foreignKey:UserID"`
+	Tags       []Tag       `gorm:"many2many:note_tags"`
 }
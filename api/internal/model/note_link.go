@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// NoteLink is a [[wiki link]] from one note to another. TargetNoteID is nil
+// while the link is dangling, i.e. no note matches TargetTitle yet.
+type NoteLink struct {
+	ID           string    `json:"id" gorm:"primaryKey"`
+	UserID       string    `json:"user_id"`
+	SourceNoteID string    `json:"source_note_id"`
+	TargetNoteID *string   `json:"target_note_id"`
+	TargetTitle  string    `json:"target_title"`
+	CreatedAt    time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+
+	SourceNote *Note `gorm:"foreignKey:SourceNoteID"`
+	TargetNote *Note `gorm:"foreignKey:TargetNoteID"`
+}
@@ -0,0 +1,9 @@
+package model
+
+type NoteTag struct {
+	NoteID string `json:"note_id" gorm:"primaryKey"`
+	TagID  string `json:"tag_id" gorm:"primaryKey"`
+
+	Note Note `gorm:"foreignKey:NoteID"`
+	Tag  Tag  `gorm:"foreignKey:TagID"`
+}
@@ -3,15 +3,18 @@ package model
 import "time"
 
 type Project struct {
-	ID          string     `json:"id" gorm:"primaryKey"`
-	UserID      string     `json:"user_id"`
-	Title       *string    `json:"title"`
-	Description *string    `json:"description"`
-	Color       *string    `json:"color"`
+	ID          string  `json:"id" gorm:"primaryKey"`
+	UserID      string  `json:"user_id"`
+	Title       *string `json:"title"`
+	Description *string `json:"description"`
+	Color       *string `json:"color"`
+	// WorkspaceID is set for a project of a team workspace, which is stored under its owner's account
+	WorkspaceID *string    `json:"workspace_id"`
 	CreatedAt   time.Time  `gorm:"default:CURRENT_TIMESTAMP"`
 	UpdatedAt   time.Time  `gorm:"default:CURRENT_TIMESTAMP"`
 	DeletedAt   *time.Time `gorm:"index"`
 
-	User  *User  `gorm:"foreignKey:UserID"`
-	Tasks []Task `gorm:"foreignKey:ProjectID"`
+	User     *User           `gorm:"foreignKey:UserID"`
+	Tasks    []Task          `gorm:"foreignKey:ProjectID"`
+	Statuses []ProjectStatus `gorm:"foreignKey:ProjectID"`
 }
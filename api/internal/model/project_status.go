@@ -0,0 +1,75 @@
+package model
+
+import "time"
+
+const (
+	StatusCategoryTodo      = "todo"
+	StatusCategoryDoing     = "doing"
+	StatusCategoryDone      = "done"
+	StatusCategoryCancelled = "cancelled"
+)
+
+// StatusCategories lists the categories in workflow order
+var StatusCategories = []string{StatusCategoryTodo, StatusCategoryDoing, StatusCategoryDone, StatusCategoryCancelled}
+
+// statusCategoryCodes maps each category to the Task.Status code it is stored as
+var statusCategoryCodes = map[string]int{
+	StatusCategoryTodo:      TaskStatusPending,
+	StatusCategoryDoing:     TaskStatusInProgress,
+	StatusCategoryDone:      TaskStatusCompleted,
+	StatusCategoryCancelled: TaskStatusCancelled,
+}
+
+// defaultStatusNames names the built-in status of each category, used by tasks without a custom status
+var defaultStatusNames = map[string]string{
+	StatusCategoryTodo:      "To do",
+	StatusCategoryDoing:     "In progress",
+	StatusCategoryDone:      "Done",
+	StatusCategoryCancelled: "Cancelled",
+}
+
+// ProjectStatus is a custom workflow status, e.g. "Backlog" or "QA", defined per project
+type ProjectStatus struct {
+	ID        string     `json:"id" gorm:"primaryKey"`
+	UserID    string     `json:"user_id"`
+	ProjectID string     `json:"project_id"`
+	Name      string     `json:"name"`
+	Category  string     `json:"category"`
+	SortOrder *string    `json:"sort_order"`
+	Color     *string    `json:"color"`
+	CreatedAt time.Time  `gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt time.Time  `gorm:"default:CURRENT_TIMESTAMP"`
+	DeletedAt *time.Time `gorm:"index"`
+
+	User    *User    `gorm:"foreignKey:UserID"`
+	Project *Project `gorm:"foreignKey:ProjectID"`
+}
+
+// StatusCategoryCode returns the Task.Status code of a category
+func StatusCategoryCode(category string) (int, bool) {
+	code, ok := statusCategoryCodes[category]
+	return code, ok
+}
+
+// StatusCategoryOf returns the category of a Task.Status code
+func StatusCategoryOf(code int) string {
+	for category, c := range statusCategoryCodes {
+		if c == code {
+			return category
+		}
+	}
+	return StatusCategoryTodo
+}
+
+// DefaultStatusName returns the name of a category's built-in status
+func DefaultStatusName(category string) string {
+	return defaultStatusNames[category]
+}
+
+// StatusName returns the task's custom status name, or the built-in name of its category
+func (t *Task) StatusName() string {
+	if t.WorkflowStatus != nil && t.WorkflowStatus.DeletedAt == nil {
+		return t.WorkflowStatus.Name
+	}
+	return DefaultStatusName(StatusCategoryOf(t.Status))
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStatusCategoryCode(t *testing.T) {
+	for _, category := range StatusCategories {
+		code, ok := StatusCategoryCode(category)
+		if !ok {
+			t.Errorf("StatusCategoryCode(%q) isn't known", category)
+			continue
+		}
+		if got := StatusCategoryOf(code); got != category {
+			t.Errorf("StatusCategoryOf(%d) = %q, want %q", code, got, category)
+		}
+	}
+
+	if _, ok := StatusCategoryCode("blocked"); ok {
+		t.Error(`StatusCategoryCode("blocked") is known, want it rejected`)
+	}
+	if got := StatusCategoryOf(7); got != StatusCategoryTodo {
+		t.Errorf("StatusCategoryOf(7) = %q, want %q", got, StatusCategoryTodo)
+	}
+}
+
+func TestTaskStatusName(t *testing.T) {
+	deletedAt := time.Now()
+	tests := []struct {
+		name string
+		task Task
+		want string
+	}{
+		{"built-in", Task{Status: TaskStatusInProgress}, "In progress"},
+		{"custom", Task{Status: TaskStatusInProgress, WorkflowStatus: &ProjectStatus{Name: "QA"}}, "QA"},
+		{"deleted custom", Task{Status: TaskStatusCompleted, WorkflowStatus: &ProjectStatus{Name: "Shipped", DeletedAt: &deletedAt}}, "Done"},
+	}
+	for _, tt := range tests {
+		if got := tt.task.StatusName(); got != tt.want {
+			t.Errorf("%s: StatusName() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
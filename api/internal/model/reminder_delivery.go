@@ -0,0 +1,42 @@
+package model
+
+import "time"
+
+const (
+	ReminderStatusPending = "pending"
+	// ReminderStatusSending is held while a worker delivers the reminder
+	ReminderStatusSending = "sending"
+	ReminderStatusSent    = "sent"
+	// ReminderStatusFailed is retried until ReminderMaxAttempts is reached
+	ReminderStatusFailed = "failed"
+	// ReminderStatusSkipped means the channel had nowhere to deliver to, e.g. no registered devices
+	ReminderStatusSkipped = "skipped"
+	// ReminderStatusCancelled means the task was completed, deleted or rescheduled before delivery
+	ReminderStatusCancelled = "cancelled"
+
+	ReminderChannelEmail = "email"
+	ReminderChannelPush  = "push"
+
+	ReminderMaxAttempts = 5
+)
+
+// ReminderDelivery records one reminder for one task occurrence on one channel
+type ReminderDelivery struct {
+	ID            string     `json:"id" gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	UserID        string     `json:"user_id"`
+	TaskID        string     `json:"task_id"`
+	DueDate       time.Time  `json:"due_date"`
+	OffsetMinutes int        `json:"offset_minutes"`
+	Channel       string     `json:"channel"`
+	Status        string     `json:"status" gorm:"default:pending"`
+	Attempts      int        `json:"attempts"`
+	LastError     *string    `json:"last_error"`
+	RemindAt      time.Time  `json:"remind_at"`
+	NextAttemptAt time.Time  `json:"next_attempt_at" gorm:"default:CURRENT_TIMESTAMP"`
+	SentAt        *time.Time `json:"sent_at"`
+	CreatedAt     time.Time  `gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt     time.Time  `gorm:"default:CURRENT_TIMESTAMP"`
+
+	User *User `gorm:"foreignKey:UserID"`
+	Task *Task `gorm:"foreignKey:TaskID"`
+}
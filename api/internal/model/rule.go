@@ -0,0 +1,154 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+const (
+	RuleTriggerTaskCreated   = "task.created"
+	RuleTriggerTaskUpdated   = "task.updated"
+	RuleTriggerTaskCompleted = "task.completed"
+	RuleTriggerTaskRestored  = "task.restored"
+	// RuleTriggerTaskOverdue fires on a schedule, once per due date, for open tasks past their due date
+	RuleTriggerTaskOverdue  = "task.overdue"
+	RuleTriggerNoteCreated  = "note.created"
+	RuleTriggerNoteUpdated  = "note.updated"
+	RuleTriggerNoteRestored = "note.restored"
+
+	RuleOpEq       = "eq"
+	RuleOpNeq      = "neq"
+	RuleOpContains = "contains"
+	RuleOpEmpty    = "empty"
+	RuleOpNotEmpty = "not_empty"
+	// RuleOpChanged and RuleOpChangedTo look at the change that fired the rule, so they never hold
+	// for a scheduled trigger
+	RuleOpChanged   = "changed"
+	RuleOpChangedTo = "changed_to"
+
+	RuleActionMoveToProject    = "move_to_project"
+	RuleActionMoveToCollection = "move_to_collection"
+	RuleActionAddTag           = "add_tag"
+	RuleActionSetStatus        = "set_status"
+	RuleActionSetPriority      = "set_priority"
+	// RuleActionExtractActionItems creates a task for each unchecked "- [ ]" item of a note
+	RuleActionExtractActionItems = "extract_action_items"
+
+	RuleExecutionPending = "pending"
+	RuleExecutionApplied = "applied"
+	// RuleExecutionDryRun holds the changes a dry-run rule would have made
+	RuleExecutionDryRun = "dry_run"
+	// RuleExecutionSkipped means the conditions didn't hold or the actions had nothing to change
+	RuleExecutionSkipped = "skipped"
+	RuleExecutionFailed  = "failed"
+
+	// RuleEventMaxAttempts is how many times the rules of an event are run before it is dropped
+	RuleEventMaxAttempts = 5
+)
+
+// ruleTriggers lists the triggers a rule can have
+var ruleTriggers = []string{
+	RuleTriggerTaskCreated, RuleTriggerTaskUpdated, RuleTriggerTaskCompleted, RuleTriggerTaskRestored, RuleTriggerTaskOverdue,
+	RuleTriggerNoteCreated, RuleTriggerNoteUpdated, RuleTriggerNoteRestored,
+}
+
+// ruleActionEntityTypes maps each action to the entity type it applies to, or "" for both
+var ruleActionEntityTypes = map[string]string{
+	RuleActionMoveToProject:      "task",
+	RuleActionMoveToCollection:   "note",
+	RuleActionAddTag:             "",
+	RuleActionSetStatus:          "task",
+	RuleActionSetPriority:        "task",
+	RuleActionExtractActionItems: "note",
+}
+
+// IsRuleTrigger reports whether a rule can have a trigger
+func IsRuleTrigger(trigger string) bool {
+	for _, t := range ruleTriggers {
+		if t == trigger {
+			return true
+		}
+	}
+	return false
+}
+
+// RuleTriggerEntityType returns the entity type a trigger fires for, e.g. task
+func RuleTriggerEntityType(trigger string) string {
+	entityType, _, _ := strings.Cut(trigger, ".")
+	return entityType
+}
+
+// RuleActionFits reports whether an action applies to the entities of a trigger
+func RuleActionFits(actionType, trigger string) bool {
+	entityType, ok := ruleActionEntityTypes[actionType]
+	return ok && (entityType == "" || entityType == RuleTriggerEntityType(trigger))
+}
+
+// RuleCondition holds when a column of the entity, or tag_ids for its tags, meets the operator
+type RuleCondition struct {
+	Field string `json:"field"`
+	Op    string `json:"op"`
+	Value any    `json:"value,omitempty"`
+}
+
+// RuleAction is a change a rule makes. The parameters its type needs are set.
+type RuleAction struct {
+	Type         string  `json:"type"`
+	ProjectID    *string `json:"project_id,omitempty"`
+	CollectionID *string `json:"collection_id,omitempty"`
+	TagID        *string `json:"tag_id,omitempty"`
+	// Status is a status category: todo, doing, done or cancelled
+	Status   *string `json:"status,omitempty"`
+	Priority *int    `json:"priority,omitempty"`
+}
+
+// Rule applies its actions to a task or note when its trigger fires and its conditions all hold
+type Rule struct {
+	ID     string `json:"id" gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	UserID string `json:"user_id"`
+	// WorkspaceID is the workspace the rule runs in, or nil for the personal space
+	WorkspaceID *string                            `json:"workspace_id"`
+	Name        string                             `json:"name"`
+	Trigger     string                             `json:"trigger"`
+	Conditions  datatypes.JSONSlice[RuleCondition] `json:"conditions" gorm:"type:jsonb;default:'[]'"`
+	Actions     datatypes.JSONSlice[RuleAction]    `json:"actions" gorm:"type:jsonb;default:'[]'"`
+	Active      bool                               `json:"active" gorm:"default:true"`
+	// DryRun rules log the changes they would make without making them
+	DryRun    bool       `json:"dry_run"`
+	CreatedAt time.Time  `gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt time.Time  `gorm:"default:CURRENT_TIMESTAMP"`
+	DeletedAt *time.Time `gorm:"index"`
+}
+
+// RuleExecution is one firing of a rule for an entity, with the sync changes it made
+type RuleExecution struct {
+	ID         string  `json:"id" gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	RuleID     string  `json:"rule_id"`
+	Trigger    string  `json:"trigger"`
+	EntityType string  `json:"entity_type"`
+	EntityID   string  `json:"entity_id"`
+	ActivityID *string `json:"activity_id"`
+	// TriggerKey is what fired the rule, so it fires once per key for an entity
+	TriggerKey string         `json:"trigger_key"`
+	Status     string         `json:"status" gorm:"default:pending"`
+	DryRun     bool           `json:"dry_run"`
+	Changes    datatypes.JSON `json:"changes" gorm:"type:jsonb;default:'[]'"`
+	Error      *string        `json:"error"`
+	CreatedAt  time.Time      `gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt  time.Time      `gorm:"default:CURRENT_TIMESTAMP"`
+}
+
+// RuleEvent is an activity log entry queued for the rule worker, which runs the rules it fires
+type RuleEvent struct {
+	ID         string `json:"id" gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	ActivityID string `json:"activity_id"`
+	// WorkspaceID is the workspace the changed item is in, or nil for the personal space
+	WorkspaceID *string    `json:"workspace_id"`
+	Attempts    int        `json:"attempts"`
+	ClaimedAt   *time.Time `json:"claimed_at"`
+	CreatedAt   time.Time  `gorm:"default:CURRENT_TIMESTAMP"`
+
+	Activity *Activity `gorm:"foreignKey:ActivityID"`
+}
@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// ShareLink is a public read-only link to a note or a collection. Only a SHA-256 hash of its
+// token is stored, and a bcrypt hash of its password if it has one.
+type ShareLink struct {
+	ID           string     `json:"id" gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	UserID       string     `json:"user_id"`
+	NoteID       *string    `json:"note_id"`
+	CollectionID *string    `json:"collection_id"`
+	TokenHash    string     `json:"-"`
+	PasswordHash *string    `json:"-"`
+	ExpiresAt    *time.Time `json:"expires_at"`
+	// RevokedAt is set when the owner revokes the link or deletes what it shares
+	RevokedAt    *time.Time `json:"revoked_at"`
+	ViewCount    int64      `json:"view_count"`
+	LastViewedAt *time.Time `json:"last_viewed_at"`
+	CreatedAt    time.Time  `gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt    time.Time  `gorm:"default:CURRENT_TIMESTAMP"`
+
+	User       *User       `gorm:"foreignKey:UserID"`
+	Note       *Note       `gorm:"foreignKey:NoteID"`
+	Collection *Collection `gorm:"foreignKey:CollectionID"`
+}
+
+// Expired reports whether the link's expiry has passed
+func (l *ShareLink) Expired(now time.Time) bool {
+	return l.ExpiresAt != nil && !l.ExpiresAt.After(now)
+}
@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+type Tag struct {
+	ID        string     `json:"id" gorm:"primaryKey"`
+	UserID    string     `json:"user_id"`
+	Name      string     `json:"name"`
+	Color     *string    `json:"color"`
+	CreatedAt time.Time  `gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt time.Time  `gorm:"default:CURRENT_TIMESTAMP"`
+	DeletedAt *time.Time `gorm:"index"`
+
+	User *User `gorm:"foreignKey:UserID"`
+}
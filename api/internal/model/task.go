@@ -1,20 +1,81 @@
 package model
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// Task.Status holds the category of the task's status; see StatusCategoryCode
+const (
+	TaskStatusCancelled  = -1
+	TaskStatusPending    = 0
+	TaskStatusInProgress = 1
+	TaskStatusCompleted  = 2
+
+	TaskPriorityNone   = 0
+	TaskPriorityLow    = 1
+	TaskPriorityMedium = 2
+	TaskPriorityHigh   = 3
+	TaskPriorityUrgent = 4
+
+	// TaskMaxDepth is how many levels a task hierarchy may have, counting the root task
+	TaskMaxDepth = 3
+
+	// RecurrenceModeRoll moves a completed recurring task to its next due date
+	RecurrenceModeRoll = "roll"
+	// RecurrenceModeSpawn keeps the completed task and creates a new one for the next due date.
+	// Only the newest task of a series carries the recurrence rule.
+	RecurrenceModeSpawn = "spawn"
+)
 
 type Task struct {
-	ID          string     `json:"id" gorm:"primaryKey"`
-	ProjectID   *string    `json:"project_id"`
-	UserID      string     `json:"user_id"`
-	Title       *string    `json:"title"`
-	Description *string    `json:"description"`
-	Status      int        `json:"status"`
-	SortOrder   *string    `json:"sort_order"`
-	DueDate     *time.Time `json:"due_date"`
-	CreatedAt   time.Time  `gorm:"default:CURRENT_TIMESTAMP"`
-	UpdatedAt   time.Time  `gorm:"default:CURRENT_TIMESTAMP"`
-	DeletedAt   *time.Time `gorm:"index"`
-
-	Project *Project `gorm:"foreignKey:ProjectID"`
-	User    *User    `gorm:"foreignKey:UserID"`
+	ID           string  `json:"id" gorm:"primaryKey"`
+	ProjectID    *string `json:"project_id"`
+	ParentTaskID *string `json:"parent_task_id"`
+	UserID       string  `json:"user_id"`
+	Title        *string `json:"title"`
+	Description  *string `json:"description"`
+	Status       int     `json:"status"`
+	// StatusID references a custom ProjectStatus of the task's project
+	StatusID  *string    `json:"status_id"`
+	SortOrder *string    `json:"sort_order"`
+	DueDate   *time.Time `json:"due_date"`
+	// StartDate is when the task is scheduled to be worked on
+	StartDate       *time.Time `json:"start_date"`
+	Priority        int        `json:"priority"`
+	EstimateMinutes *int       `json:"estimate_minutes"`
+	// Blocked is true while any task this one depends on is still open
+	Blocked bool `json:"blocked"`
+	// CompletedAt is when the task was last completed, nil while it is open
+	CompletedAt        *time.Time `json:"completed_at"`
+	RecurrenceRule     *string    `json:"recurrence_rule"`
+	RecurrenceMode     string     `json:"recurrence_mode" gorm:"default:roll"`
+	RecurrenceStart    *time.Time `json:"recurrence_start"`
+	RecurrenceSeriesID *string    `json:"recurrence_series_id"`
+	// Minutes before the due date to send reminders. Nil falls back to the user's default.
+	ReminderOffsets *datatypes.JSONSlice[int] `json:"reminder_offsets" gorm:"type:jsonb"`
+	CreatedAt       time.Time                 `gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt       time.Time                 `gorm:"default:CURRENT_TIMESTAMP"`
+	DeletedAt       *time.Time                `gorm:"index"`
+
+	Project        *Project       `gorm:"foreignKey:ProjectID"`
+	WorkflowStatus *ProjectStatus `gorm:"foreignKey:StatusID"`
+	ParentTask     *Task          `gorm:"foreignKey:ParentTaskID"`
+	Subtasks       []Task         `gorm:"foreignKey:ParentTaskID"`
+	User           *User          `gorm:"foreignKey:UserID"`
+	Tags           []Tag          `gorm:"many2many:task_tags"`
+	// BlockedBy lists the tasks that must be done before this one
+	BlockedBy []TaskDependency `gorm:"foreignKey:TaskID"`
+}
+
+// RecurrenceAnchor is the DTSTART the task's recurrence rule is evaluated from
+func (t *Task) RecurrenceAnchor() time.Time {
+	if t.RecurrenceStart != nil {
+		return *t.RecurrenceStart
+	}
+	if t.DueDate != nil {
+		return *t.DueDate
+	}
+	return t.CreatedAt
 }
@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// TaskDependency records that a task can't be worked on until another task is done
+type TaskDependency struct {
+	TaskID          string    `json:"task_id" gorm:"primaryKey"`
+	BlockedByTaskID string    `json:"blocked_by_task_id" gorm:"primaryKey"`
+	UserID          string    `json:"user_id"`
+	CreatedAt       time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+
+	Task      Task `gorm:"foreignKey:TaskID"`
+	BlockedBy Task `gorm:"foreignKey:BlockedByTaskID"`
+}
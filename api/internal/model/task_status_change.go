@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// TaskStatusChange records a task moving from one status category to another
+type TaskStatusChange struct {
+	ID     string `json:"id" gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	TaskID string `json:"task_id"`
+	UserID string `json:"user_id"`
+	// FromStatus is nil when the task was created with ToStatus
+	FromStatus *int `json:"from_status"`
+	ToStatus   int  `json:"to_status"`
+	// StatusID is the custom status the task had after the change
+	StatusID *string `json:"status_id"`
+	// DueDate is the task's due date at the time of the change
+	DueDate   *time.Time `json:"due_date"`
+	ChangedAt time.Time  `json:"changed_at" gorm:"default:CURRENT_TIMESTAMP"`
+
+	Task *Task `gorm:"foreignKey:TaskID"`
+}
@@ -0,0 +1,9 @@
+package model
+
+type TaskTag struct {
+	TaskID string `json:"task_id" gorm:"primaryKey"`
+	TagID  string `json:"tag_id" gorm:"primaryKey"`
+
+	Task Task `gorm:"foreignKey:TaskID"`
+	Tag  Tag  `gorm:"foreignKey:TagID"`
+}
@@ -1,14 +1,34 @@
 package model
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
 
 type User struct {
-	ID          string     `json:"id" gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
-	Name        string     `json:"name"`
-	Email       string     `json:"email"`
-	IsVerified  bool       `json:"is_verified"`
-	GoogleImage *string    `json:"google_image"`
-	CreatedAt   time.Time  `gorm:"default:CURRENT_TIMESTAMP"`
-	UpdatedAt   time.Time  `gorm:"default:CURRENT_TIMESTAMP"`
-	DeletedAt   *time.Time `gorm:"index"`
+	ID          string  `json:"id" gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	Name        string  `json:"name"`
+	Email       string  `json:"email"`
+	IsVerified  bool    `json:"is_verified"`
+	GoogleImage *string `json:"google_image"`
+	// Default minutes before a task's due date to send reminders
+	ReminderOffsets datatypes.JSONSlice[int] `json:"reminder_offsets" gorm:"type:jsonb;default:'[]'"`
+	// IANA time zone name, e.g. "Europe/Berlin", used to split dates into days
+	Timezone  string     `json:"timezone" gorm:"default:UTC"`
+	CreatedAt time.Time  `gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt time.Time  `gorm:"default:CURRENT_TIMESTAMP"`
+	DeletedAt *time.Time `gorm:"index"`
+}
+
+// Location returns the user's time zone, falling back to UTC if it is unknown
+func (u *User) Location() *time.Location {
+	if u.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(u.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
 }
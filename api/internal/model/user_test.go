@@ -0,0 +1,20 @@
+package model
+
+import "testing"
+
+func TestUserLocation(t *testing.T) {
+	tests := []struct {
+		timezone string
+		want     string
+	}{
+		{"", "UTC"},
+		{"Europe/Berlin", "Europe/Berlin"},
+		{"Mars/Olympus_Mons", "UTC"},
+	}
+	for _, tt := range tests {
+		user := &User{Timezone: tt.timezone}
+		if got := user.Location().String(); got != tt.want {
+			t.Errorf("Location() of %q = %s, want %s", tt.timezone, got, tt.want)
+		}
+	}
+}
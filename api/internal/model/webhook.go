@@ -0,0 +1,115 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+const (
+	WebhookStatusPending = "pending"
+	// WebhookStatusSending is held while a worker posts the event
+	WebhookStatusSending = "sending"
+	WebhookStatusSent    = "sent"
+	// WebhookStatusFailed is retried until WebhookMaxAttempts is reached
+	WebhookStatusFailed = "failed"
+	// WebhookStatusCancelled means the webhook was deleted, turned off or lost access before delivery
+	WebhookStatusCancelled = "cancelled"
+
+	WebhookMaxAttempts = 8
+
+	// WebhookEventTaskCompleted is raised along with task.updated when a task is completed, also
+	// when it is a recurring task that moves on to its next occurrence in the same change
+	WebhookEventTaskCompleted = "task.completed"
+)
+
+// webhookEventActions are the event names of the activity log's actions
+var webhookEventActions = map[string]string{
+	ActivityActionCreate:  "created",
+	ActivityActionUpdate:  "updated",
+	ActivityActionDelete:  "deleted",
+	ActivityActionRestore: "restored",
+}
+
+// WebhookEventType returns the event type of an action on an entity, e.g. note.updated
+func WebhookEventType(entityType, action string) string {
+	return entityType + "." + webhookEventActions[action]
+}
+
+// ActivityEventTypes returns the events an entry of the activity log raises, for webhooks and rules
+func ActivityEventTypes(activity *Activity) []string {
+	eventTypes := []string{WebhookEventType(activity.EntityType, activity.Action)}
+	// completions counts the completions in the task's status history
+	completions, ok := activity.Changes.Data()["completions"]
+	if ok && activity.EntityType == "task" && activityCount(completions.To) > activityCount(completions.From) {
+		eventTypes = append(eventTypes, WebhookEventTaskCompleted)
+	}
+	return eventTypes
+}
+
+// activityCount reads a count logged in the activity log, where JSON made it a float64. Nil counts as 0.
+func activityCount(value any) float64 {
+	switch v := value.(type) {
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	}
+	return 0
+}
+
+// IsWebhookEventType reports whether webhooks can subscribe to an event type
+func IsWebhookEventType(eventType string) bool {
+	if eventType == WebhookEventTaskCompleted {
+		return true
+	}
+	for _, entityType := range []string{"task", "project", "note", "collection", "tag", "status", "comment"} {
+		for action := range webhookEventActions {
+			if eventType == WebhookEventType(entityType, action) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Webhook posts signed events about changes to a URL of the user's
+type Webhook struct {
+	ID     string `json:"id" gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	UserID string `json:"user_id"`
+	// ProjectID limits the webhook to changes in one project. Without it, the webhook gets changes
+	// to the user's own items and changes the user makes.
+	ProjectID   *string                     `json:"project_id"`
+	URL         string                      `json:"url"`
+	Description *string                     `json:"description"`
+	Secret      string                      `json:"-"`
+	EventTypes  datatypes.JSONSlice[string] `json:"event_types" gorm:"type:jsonb;default:'[]'"`
+	Active      bool                        `json:"active" gorm:"default:true"`
+	CreatedAt   time.Time                   `gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt   time.Time                   `gorm:"default:CURRENT_TIMESTAMP"`
+	DeletedAt   *time.Time                  `gorm:"index"`
+}
+
+// WebhookDelivery is one event posted, or to post, to a webhook
+type WebhookDelivery struct {
+	ID             string         `json:"id" gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	WebhookID      string         `json:"webhook_id"`
+	ActivityID     *string        `json:"activity_id"`
+	EventType      string         `json:"event_type"`
+	Payload        datatypes.JSON `json:"payload" gorm:"type:jsonb"`
+	Status         string         `json:"status" gorm:"default:pending"`
+	Attempts       int            `json:"attempts"`
+	LastError      *string        `json:"last_error"`
+	ResponseStatus *int           `json:"response_status"`
+	ResponseBody   *string        `json:"response_body"`
+	DurationMs     *int           `json:"duration_ms"`
+	RedeliveryOf   *string        `json:"redelivery_of"`
+	NextAttemptAt  time.Time      `json:"next_attempt_at" gorm:"default:CURRENT_TIMESTAMP"`
+	SentAt         *time.Time     `json:"sent_at"`
+	CreatedAt      time.Time      `gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt      time.Time      `gorm:"default:CURRENT_TIMESTAMP"`
+
+	Webhook *Webhook `gorm:"foreignKey:WebhookID"`
+}
@@ -0,0 +1,65 @@
+package model
+
+import (
+	"slices"
+	"testing"
+
+	"gorm.io/datatypes"
+)
+
+func TestActivityEventTypes(t *testing.T) {
+	tests := []struct {
+		name          string
+		entityType    string
+		action        string
+		changes       map[string]ActivityChange
+		wantCompleted bool
+	}{
+		{
+			name:          "completed",
+			entityType:    "task",
+			action:        ActivityActionUpdate,
+			changes:       map[string]ActivityChange{"status": {From: 0.0, To: 2.0}, "completions": {From: 0.0, To: 1.0}},
+			wantCompleted: true,
+		},
+		{
+			name:          "roll mode occurrence completed and reopened",
+			entityType:    "task",
+			action:        ActivityActionUpdate,
+			changes:       map[string]ActivityChange{"due_date": {From: "2026-01-05T09:00:00Z", To: "2026-01-12T09:00:00Z"}, "completions": {From: 3.0, To: 4.0}},
+			wantCompleted: true,
+		},
+		{
+			name:          "created completed",
+			entityType:    "task",
+			action:        ActivityActionCreate,
+			changes:       map[string]ActivityChange{"completions": {From: nil, To: 1.0}},
+			wantCompleted: true,
+		},
+		{
+			name:       "reopened",
+			entityType: "task",
+			action:     ActivityActionUpdate,
+			changes:    map[string]ActivityChange{"status": {From: 2.0, To: 0.0}},
+		},
+		{
+			name:       "note",
+			entityType: "note",
+			action:     ActivityActionUpdate,
+			changes:    map[string]ActivityChange{"completions": {From: 0.0, To: 1.0}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			activity := &Activity{EntityType: tt.entityType, Action: tt.action, Changes: datatypes.NewJSONType(tt.changes)}
+			got := ActivityEventTypes(activity)
+			if got[0] != WebhookEventType(tt.entityType, tt.action) {
+				t.Errorf("first event = %s, want %s", got[0], WebhookEventType(tt.entityType, tt.action))
+			}
+			if slices.Contains(got, WebhookEventTaskCompleted) != tt.wantCompleted {
+				t.Errorf("events = %v, want task.completed %v", got, tt.wantCompleted)
+			}
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// Workspace is a team space that owns projects and collections. Their rows are stored under the
+// account of the user who created the workspace, who always counts as its owner.
+type Workspace struct {
+	ID        string     `json:"id" gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	UserID    string     `json:"user_id"`
+	Title     string     `json:"title"`
+	CreatedAt time.Time  `gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt time.Time  `gorm:"default:CURRENT_TIMESTAMP"`
+	DeletedAt *time.Time `gorm:"index"`
+
+	User *User `gorm:"foreignKey:UserID"`
+}
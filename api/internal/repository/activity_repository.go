@@ -0,0 +1,382 @@
+package repository
+
+import (
+	"app/internal/model"
+	"app/pkg/logger"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// activityTables are the tables of the entities the activity log covers, by sync type
+var activityTables = map[string]string{
+	"task":       "tasks",
+	"project":    "projects",
+	"note":       "notes",
+	"collection": "collections",
+	"tag":        "tags",
+	"status":     "project_statuses",
+	"comment":    "comments",
+}
+
+// activityRelations are the rows related to an entity that are snapshotted along with its columns,
+// by sync type, so replacing a task's tags or blockers counts as a change to the task. A task's
+// completions come from its status history, so completing a recurring task that reopens right
+// away still shows.
+var activityRelations = map[string]string{
+	"task": `jsonb_build_object(
+		'tag_ids', (SELECT jsonb_agg(tag_id ORDER BY tag_id) FROM task_tags WHERE task_id = t.id),
+		'blocked_by_task_ids', (SELECT jsonb_agg(blocked_by_task_id ORDER BY blocked_by_task_id) FROM task_dependencies WHERE task_id = t.id),
+		'completions', (SELECT COUNT(*) FROM task_status_changes WHERE task_id = t.id AND to_status = ` + strconv.Itoa(model.TaskStatusCompleted) + `))`,
+	"note": `jsonb_build_object(
+		'tag_ids', (SELECT jsonb_agg(tag_id ORDER BY tag_id) FROM note_tags WHERE note_id = t.id))`,
+}
+
+// activityDigestColumns hold text too long to copy into every entry, by sync type. Their changes
+// are logged as the SHA-256 digest and length of each version.
+var activityDigestColumns = map[string]string{
+	"note": "content",
+}
+
+// activityIgnoredColumns never count as a change: the ID is the entry's entity ID and the
+// timestamps move on every write
+var activityIgnoredColumns = map[string]bool{
+	"id":         true,
+	"created_at": true,
+	"updated_at": true,
+}
+
+// ActivityActor is who makes the changes a transaction logs, and through what
+type ActivityActor struct {
+	UserID string
+	// Source is one of model.ActivitySource*
+	Source   string
+	DeviceID *string
+}
+
+// ActivityFilters picks the entries of the activity log to list. At least one filter is set.
+type ActivityFilters struct {
+	// UserID matches changes the user made or that were made to their rows
+	UserID    *string
+	ProjectID *string
+	NoteID    *string
+	Limit     int
+	Offset    int
+}
+
+type ActivityRepository struct {
+	db *gorm.DB
+}
+
+func NewActivityRepository(db *gorm.DB) *ActivityRepository {
+	return &ActivityRepository{db: db}
+}
+
+// ListActivities returns entries of the activity log with their actors, newest first
+func (r *ActivityRepository) ListActivities(ctx context.Context, filters ActivityFilters) ([]model.Activity, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.Activity{})
+	if filters.UserID != nil {
+		query = query.Where("actor_id = ? OR owner_id = ?", *filters.UserID, *filters.UserID)
+	}
+	if filters.ProjectID != nil {
+		query = query.Where("project_id = ?", *filters.ProjectID)
+	}
+	if filters.NoteID != nil {
+		query = query.Where("note_id = ?", *filters.NoteID)
+	}
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		logger.Log.Error("Failed to count activities", zap.Error(err), zap.Any("filters", filters))
+		return nil, 0, err
+	}
+
+	var activities []model.Activity
+	err := query.
+		Preload("Actor").
+		Order("created_at DESC, id ASC").
+		Limit(filters.Limit).
+		Offset(filters.Offset).
+		Find(&activities).Error
+	if err != nil {
+		logger.Log.Error("Failed to list activities", zap.Error(err), zap.Any("filters", filters))
+		return nil, 0, err
+	}
+
+	return activities, total, nil
+}
+
+// NoteAccess reports whether the user owns a note or sees its collection. Deleted notes count,
+// so their history stays readable.
+func (r *ActivityRepository) NoteAccess(ctx context.Context, userID, noteID string) (bool, error) {
+	var notes []struct {
+		UserID       string
+		CollectionID *string
+	}
+	err := r.db.WithContext(ctx).
+		Table("notes").
+		Select("user_id, collection_id").
+		Where("id = ?", noteID).
+		Limit(1).
+		Scan(&notes).Error
+	if err != nil {
+		logger.Log.Error("Failed to get note owner", zap.Error(err), zap.String("noteID", noteID))
+		return false, err
+	}
+	if len(notes) == 0 {
+		return false, nil
+	}
+	if notes[0].UserID == userID {
+		return true, nil
+	}
+	if notes[0].CollectionID == nil {
+		return false, nil
+	}
+
+	access, err := memberAccess(r.db.WithContext(ctx), userID, MemberTarget{CollectionID: notes[0].CollectionID})
+	if err != nil {
+		logger.Log.Error("Failed to get member access", zap.Error(err), zap.String("userID", userID), zap.String("noteID", noteID))
+		return false, err
+	}
+	return access != nil && access.Role != "", nil
+}
+
+// entitySnapshot returns the columns of an entity as JSON values, or nil if there is no such row
+func entitySnapshot(tx *gorm.DB, entityType, entityID string) (map[string]any, error) {
+	snapshots, err := entitySnapshots(tx, entityType, []string{entityID})
+	if err != nil {
+		return nil, err
+	}
+	return snapshots[entityID], nil
+}
+
+// entitySnapshots returns the snapshots of entities of one type by ID, leaving out those with no row
+func entitySnapshots(tx *gorm.DB, entityType string, entityIDs []string) (map[string]map[string]any, error) {
+	snapshots := map[string]map[string]any{}
+	table, ok := activityTables[entityType]
+	if !ok || len(entityIDs) == 0 {
+		return snapshots, nil
+	}
+
+	columns := `to_jsonb(t) - 'search_vector' - 'embedding'`
+	if relations, ok := activityRelations[entityType]; ok {
+		columns += ` || ` + relations
+	}
+	var rows []struct {
+		ID       string
+		Snapshot string
+	}
+	err := tx.Raw(`SELECT t.id, (`+columns+`)::text AS snapshot FROM `+table+` t WHERE t.id IN ?`, entityIDs).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		var snapshot map[string]any
+		if err := json.Unmarshal([]byte(row.Snapshot), &snapshot); err != nil {
+			return nil, err
+		}
+		snapshots[row.ID] = snapshot
+	}
+	return snapshots, nil
+}
+
+// recordActivity logs what a change did to an entity, given its snapshot from before the change,
+// and queues the webhook events it raises and the rules it may fire. A change that left every
+// column alone isn't logged, and nil is returned for it.
+func recordActivity(tx *gorm.DB, actor ActivityActor, entityType, entityID string, before map[string]any) (*model.Activity, error) {
+	after, err := entitySnapshot(tx, entityType, entityID)
+	if err != nil || after == nil {
+		return nil, err
+	}
+
+	changes := map[string]model.ActivityChange{}
+	for column, to := range after {
+		if activityIgnoredColumns[column] {
+			continue
+		}
+		from := before[column]
+		if before == nil && to == nil {
+			continue
+		}
+		if reflect.DeepEqual(from, to) {
+			continue
+		}
+		if activityDigestColumns[entityType] == column {
+			from, to = activityDigest(from), activityDigest(to)
+		}
+		changes[column] = model.ActivityChange{From: from, To: to}
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	action := model.ActivityActionUpdate
+	switch {
+	case before == nil:
+		action = model.ActivityActionCreate
+	case before["deleted_at"] == nil && after["deleted_at"] != nil:
+		action = model.ActivityActionDelete
+	case before["deleted_at"] != nil && after["deleted_at"] == nil:
+		action = model.ActivityActionRestore
+	}
+
+	activity := model.Activity{
+		ActorID:    actor.UserID,
+		OwnerID:    snapshotString(after, "user_id"),
+		EntityType: entityType,
+		EntityID:   entityID,
+		Action:     action,
+		Source:     actor.Source,
+		DeviceID:   actor.DeviceID,
+		Changes:    datatypes.NewJSONType(changes),
+		// Set here rather than by the database, so entries of one transaction keep their order
+		CreatedAt: time.Now(),
+	}
+	if err := activityContext(tx, &activity, after); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Create(&activity).Error; err != nil {
+		return nil, err
+	}
+	if err := enqueueWebhooks(tx, &activity, after); err != nil {
+		return nil, err
+	}
+	if err := enqueueRules(tx, &activity); err != nil {
+		return nil, err
+	}
+
+	return &activity, nil
+}
+
+// activityDigest stands in for a long text in an entry: its SHA-256 digest and length, or nil for none
+func activityDigest(value any) any {
+	text, ok := value.(string)
+	if !ok {
+		return nil
+	}
+	sum := sha256.Sum256([]byte(text))
+	return map[string]any{"sha256": hex.EncodeToString(sum[:]), "length": utf8.RuneCountInString(text)}
+}
+
+// activityLog logs the changes of a transaction as made by one actor, along with the changes its
+// follow-ups make to other rows than the one a change is for. Entities are tracked before they
+// change and logged by flush once they have. A nil log logs nothing.
+type activityLog struct {
+	actor      ActivityActor
+	tracked    []activityKey
+	before     map[activityKey]map[string]any
+	activities []model.Activity
+}
+
+type activityKey struct {
+	entityType string
+	entityID   string
+}
+
+func newActivityLog(actor ActivityActor) *activityLog {
+	return &activityLog{actor: actor, before: map[activityKey]map[string]any{}}
+}
+
+// track snapshots entities that are about to change. One tracked already keeps its first snapshot,
+// and one with no row yet is logged as created.
+func (l *activityLog) track(tx *gorm.DB, entityType string, entityIDs ...string) error {
+	if l == nil {
+		return nil
+	}
+	var untracked []string
+	for _, entityID := range entityIDs {
+		if _, ok := l.before[activityKey{entityType, entityID}]; !ok && !slices.Contains(untracked, entityID) {
+			untracked = append(untracked, entityID)
+		}
+	}
+	snapshots, err := entitySnapshots(tx, entityType, untracked)
+	if err != nil {
+		return err
+	}
+	for _, entityID := range untracked {
+		key := activityKey{entityType, entityID}
+		l.tracked = append(l.tracked, key)
+		l.before[key] = snapshots[entityID]
+	}
+	return nil
+}
+
+// flush logs what changed on each tracked entity since it was tracked, in the order they were
+// tracked, and stops tracking them
+func (l *activityLog) flush(tx *gorm.DB) error {
+	if l == nil {
+		return nil
+	}
+	tracked := l.tracked
+	l.tracked = nil
+	for _, key := range tracked {
+		before := l.before[key]
+		delete(l.before, key)
+		activity, err := recordActivity(tx, l.actor, key.entityType, key.entityID, before)
+		if err != nil {
+			return err
+		}
+		if activity != nil {
+			l.activities = append(l.activities, *activity)
+		}
+	}
+	return nil
+}
+
+// activityContext sets the project, collection, task and note an entry is about
+func activityContext(tx *gorm.DB, activity *model.Activity, snapshot map[string]any) error {
+	switch activity.EntityType {
+	case "task":
+		activity.TaskID = &activity.EntityID
+		activity.ProjectID = snapshotStringPtr(snapshot, "project_id")
+	case "status":
+		activity.ProjectID = snapshotStringPtr(snapshot, "project_id")
+	case "project":
+		activity.ProjectID = &activity.EntityID
+	case "note":
+		activity.NoteID = &activity.EntityID
+		activity.CollectionID = snapshotStringPtr(snapshot, "collection_id")
+	case "collection":
+		activity.CollectionID = &activity.EntityID
+	case "comment":
+		activity.TaskID = snapshotStringPtr(snapshot, "task_id")
+		activity.NoteID = snapshotStringPtr(snapshot, "note_id")
+		parent, err := findCommentParent(tx, activity.TaskID, activity.NoteID, false)
+		if err != nil || parent == nil {
+			return err
+		}
+		if activity.TaskID != nil {
+			activity.ProjectID = parent.ContainerID
+		} else {
+			activity.CollectionID = parent.ContainerID
+		}
+	}
+	return nil
+}
+
+func snapshotString(snapshot map[string]any, column string) string {
+	value, _ := snapshot[column].(string)
+	return value
+}
+
+func snapshotStringPtr(snapshot map[string]any, column string) *string {
+	value, ok := snapshot[column].(string)
+	if !ok {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"app/internal/contract"
+	"app/internal/model"
+	"app/pkg/util"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// findActivity returns the newest entry for an entity with the given action, failing the test if there is none
+func findActivity(t *testing.T, activities []model.Activity, entityID, action string) model.Activity {
+	t.Helper()
+
+	for i := len(activities) - 1; i >= 0; i-- {
+		if activities[i].EntityID == entityID && activities[i].Action == action {
+			return activities[i]
+		}
+	}
+	t.Fatalf("no %s entry for %s in %d entries", action, entityID, len(activities))
+	return model.Activity{}
+}
+
+func TestTagOnlyEditIsLogged(t *testing.T) {
+	db := testDB(t)
+	repo := newTestSyncRepository(t, db)
+	userID := testUser(t, db)
+
+	tagID, taskID := uuid.NewString(), uuid.NewString()
+	applyChanges(t, repo, userID,
+		contract.Change{Type: "tag", EntityID: tagID, Name: util.ToPointer("#work")},
+		contract.Change{Type: "task", EntityID: taskID, Title: util.ToPointer("Tagged")},
+	)
+
+	activities, err := repo.ApplyChanges(userID, "", []contract.Change{
+		{Type: "task", EntityID: taskID, TagIDs: &[]string{tagID}},
+	}, model.ActivitySourceSync)
+	if err != nil {
+		t.Fatalf("failed to tag task: %v", err)
+	}
+
+	activity := findActivity(t, activities, taskID, model.ActivityActionUpdate)
+	change, ok := activity.Changes.Data()["tag_ids"]
+	if !ok {
+		t.Fatalf("changes = %v, want tag_ids", activity.Changes.Data())
+	}
+	if change.From != nil || !equalIDs(change.To, tagID) {
+		t.Errorf("tag_ids changed from %v to %v, want from nil to [%s]", change.From, change.To, tagID)
+	}
+}
+
+// equalIDs reports whether a snapshot value is exactly the given list of IDs
+func equalIDs(value any, ids ...string) bool {
+	values, ok := value.([]any)
+	if !ok || len(values) != len(ids) {
+		return false
+	}
+	for i, id := range ids {
+		if values[i] != id {
+			return false
+		}
+	}
+	return true
+}
+
+func TestDerivedTaskChangesAreLogged(t *testing.T) {
+	db := testDB(t)
+	repo := newTestSyncRepository(t, db)
+	userID := testUser(t, db)
+
+	blockerID, blockedID := uuid.NewString(), uuid.NewString()
+	activities, err := repo.ApplyChanges(userID, "", []contract.Change{
+		{Type: "task", EntityID: blockerID, Title: util.ToPointer("Blocker")},
+		{Type: "task", EntityID: blockedID, Title: util.ToPointer("Blocked"), BlockedByTaskIDs: &[]string{blockerID}},
+	}, model.ActivitySourceSync)
+	if err != nil {
+		t.Fatalf("failed to create tasks: %v", err)
+	}
+	dependency := findActivity(t, activities, blockedID, model.ActivityActionUpdate).Changes.Data()
+	if !equalIDs(dependency["blocked_by_task_ids"].To, blockerID) {
+		t.Errorf("blocked_by_task_ids changed to %v, want [%s]", dependency["blocked_by_task_ids"].To, blockerID)
+	}
+	if dependency["blocked"].To != true {
+		t.Errorf("blocked changed to %v, want true", dependency["blocked"].To)
+	}
+
+	activities, err = repo.ApplyChanges(userID, "", []contract.Change{
+		{Type: "task", EntityID: blockerID, Status: util.ToPointer(model.TaskStatusCompleted)},
+	}, model.ActivitySourceSync)
+	if err != nil {
+		t.Fatalf("failed to complete blocker: %v", err)
+	}
+	unblocked := findActivity(t, activities, blockedID, model.ActivityActionUpdate).Changes.Data()
+	if unblocked["blocked"].From != true || unblocked["blocked"].To != false {
+		t.Errorf("blocked changed from %v to %v, want from true to false", unblocked["blocked"].From, unblocked["blocked"].To)
+	}
+}
+
+func TestSpawnedRecurringTaskIsLogged(t *testing.T) {
+	db := testDB(t)
+	repo := newTestSyncRepository(t, db)
+	userID := testUser(t, db)
+
+	taskID := uuid.NewString()
+	applyChanges(t, repo, userID, contract.Change{
+		Type:           "task",
+		EntityID:       taskID,
+		Title:          util.ToPointer("Water plants"),
+		DueDate:        util.ToPointer("2026-01-05T09:00:00Z"),
+		RecurrenceRule: util.ToPointer("FREQ=WEEKLY"),
+		RecurrenceMode: util.ToPointer(model.RecurrenceModeSpawn),
+	})
+
+	activities, err := repo.ApplyChanges(userID, "", []contract.Change{
+		{Type: "task", EntityID: taskID, Status: util.ToPointer(model.TaskStatusCompleted)},
+	}, model.ActivitySourceSync)
+	if err != nil {
+		t.Fatalf("failed to complete task: %v", err)
+	}
+
+	var spawnedIDs []string
+	err = db.Model(&model.Task{}).Where("recurrence_series_id = ? AND id <> ?", taskID, taskID).Pluck("id", &spawnedIDs).Error
+	if err != nil || len(spawnedIDs) != 1 {
+		t.Fatalf("failed to find spawned task: %v, got %d", err, len(spawnedIDs))
+	}
+	created := findActivity(t, activities, spawnedIDs[0], model.ActivityActionCreate)
+	if created.Source != model.ActivitySourceSync || created.ActorID != userID {
+		t.Errorf("spawned task logged as %s by %s, want sync by the user", created.Source, created.ActorID)
+	}
+}
+
+func TestNoteContentIsLoggedAsDigest(t *testing.T) {
+	db := testDB(t)
+	repo := newTestSyncRepository(t, db)
+	userID := testUser(t, db)
+
+	noteID := uuid.NewString()
+	content := strings.Repeat("A long note. ", 1000)
+	activities, err := repo.ApplyChanges(userID, "", []contract.Change{
+		{Type: "note", EntityID: noteID, Title: util.ToPointer("Notes"), Content: &content},
+	}, model.ActivitySourceSync)
+	if err != nil {
+		t.Fatalf("failed to create note: %v", err)
+	}
+
+	change := findActivity(t, activities, noteID, model.ActivityActionCreate).Changes.Data()["content"]
+	digest, ok := change.To.(map[string]any)
+	if !ok || digest["length"] != len(content) {
+		t.Errorf("content logged as %v, want its digest and length", change.To)
+	}
+}
+
+func TestActivityDigest(t *testing.T) {
+	if got := activityDigest(nil); got != nil {
+		t.Errorf("activityDigest(nil) = %v, want nil", got)
+	}
+
+	got, ok := activityDigest("héllo").(map[string]any)
+	if !ok {
+		t.Fatalf("activityDigest(%q) = %v, want a digest", "héllo", got)
+	}
+	if got["length"] != 5 {
+		t.Errorf("length = %v, want 5", got["length"])
+	}
+	if got["sha256"] != "3c48591d8d098a4538f5e013dfcf406e948eac4d3277b10bf614e295d6068179" {
+		t.Errorf("sha256 = %v, want the digest of the UTF-8 bytes", got["sha256"])
+	}
+}
+
+func TestRollModeCompletionRaisesCompletedEvent(t *testing.T) {
+	db := testDB(t)
+	repo := newTestSyncRepository(t, db)
+	userID := testUser(t, db)
+
+	taskID := uuid.NewString()
+	applyChanges(t, repo, userID, contract.Change{
+		Type:           "task",
+		EntityID:       taskID,
+		Title:          util.ToPointer("Stand-up"),
+		DueDate:        util.ToPointer("2026-01-05T09:00:00Z"),
+		RecurrenceRule: util.ToPointer("FREQ=DAILY"),
+		RecurrenceMode: util.ToPointer(model.RecurrenceModeRoll),
+	})
+
+	activities, err := repo.ApplyChanges(userID, "", []contract.Change{
+		{Type: "task", EntityID: taskID, Status: util.ToPointer(model.TaskStatusCompleted)},
+	}, model.ActivitySourceSync)
+	if err != nil {
+		t.Fatalf("failed to complete task: %v", err)
+	}
+
+	activity := findActivity(t, activities, taskID, model.ActivityActionUpdate)
+	if !slices.Contains(model.ActivityEventTypes(&activity), model.WebhookEventTaskCompleted) {
+		t.Errorf("events = %v, want task.completed for a roll-mode task reopened in the same change", model.ActivityEventTypes(&activity))
+	}
+}
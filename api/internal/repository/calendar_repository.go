@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"app/internal/model"
+	"app/pkg/logger"
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CalendarRepository struct {
+	db *gorm.DB
+}
+
+func NewCalendarRepository(db *gorm.DB) *CalendarRepository {
+	return &CalendarRepository{db: db}
+}
+
+// CalendarFilters narrows the tasks shown on a calendar
+type CalendarFilters struct {
+	// WorkspaceID is the space the calendar shows; empty is the personal space
+	WorkspaceID      string
+	ProjectID        *string
+	IncludeCompleted bool
+}
+
+var openTaskStatuses = []int{model.TaskStatusPending, model.TaskStatusInProgress}
+
+// ListDueTasks returns the user's tasks due within [from, to), earliest first. Cancelled tasks are left out.
+func (r *CalendarRepository) ListDueTasks(ctx context.Context, userID string, from, to time.Time, filters CalendarFilters) ([]model.Task, error) {
+	query := r.calendarTasks(ctx, userID, filters).
+		Where("due_date >= ? AND due_date < ?", from, to)
+	if filters.IncludeCompleted {
+		query = query.Where("status <> ?", model.TaskStatusCancelled)
+	} else {
+		query = query.Where("status IN ?", openTaskStatuses)
+	}
+
+	var tasks []model.Task
+	if err := withCalendarAssociations(query).Order("due_date ASC, priority DESC").Find(&tasks).Error; err != nil {
+		logger.Log.Error("Failed to list calendar tasks", zap.Error(err), zap.String("userID", userID))
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// ListOverdueTasks returns up to limit of the user's open tasks due before the given time, earliest first,
+// and how many there are
+func (r *CalendarRepository) ListOverdueTasks(ctx context.Context, userID string, before time.Time, filters CalendarFilters, limit int) ([]model.Task, int64, error) {
+	query := r.calendarTasks(ctx, userID, filters).
+		Where("due_date < ? AND status IN ?", before, openTaskStatuses)
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		logger.Log.Error("Failed to count overdue tasks", zap.Error(err), zap.String("userID", userID))
+		return nil, 0, err
+	}
+
+	var tasks []model.Task
+	err := withCalendarAssociations(query.Session(&gorm.Session{})).
+		Order("due_date ASC, priority DESC").
+		Limit(limit).
+		Find(&tasks).Error
+	if err != nil {
+		logger.Log.Error("Failed to list overdue tasks", zap.Error(err), zap.String("userID", userID))
+		return nil, 0, err
+	}
+
+	return tasks, total, nil
+}
+
+// ListRecurringTasks returns the user's open recurring tasks that may still recur before the given time
+func (r *CalendarRepository) ListRecurringTasks(ctx context.Context, userID string, before time.Time, filters CalendarFilters) ([]model.Task, error) {
+	var tasks []model.Task
+	err := withCalendarAssociations(r.calendarTasks(ctx, userID, filters)).
+		Where("recurrence_rule IS NOT NULL AND status IN ?", openTaskStatuses).
+		Where("due_date IS NULL OR due_date < ?", before).
+		Find(&tasks).Error
+	if err != nil {
+		logger.Log.Error("Failed to list recurring tasks", zap.Error(err), zap.String("userID", userID))
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// GetFeed returns the user's calendar feed, or nil if it is turned off
+func (r *CalendarRepository) GetFeed(ctx context.Context, userID string) (*model.CalendarFeed, error) {
+	var feeds []model.CalendarFeed
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Limit(1).Find(&feeds).Error; err != nil {
+		logger.Log.Error("Failed to get calendar feed", zap.Error(err), zap.String("userID", userID))
+		return nil, err
+	}
+	if len(feeds) == 0 {
+		return nil, nil
+	}
+	return &feeds[0], nil
+}
+
+// SaveFeedToken turns the user's calendar feed on, or replaces its token so the old URL stops working
+func (r *CalendarRepository) SaveFeedToken(ctx context.Context, userID, tokenHash string) error {
+	feed := &model.CalendarFeed{
+		UserID:    userID,
+		TokenHash: tokenHash,
+	}
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"token_hash":       tokenHash,
+			"last_accessed_at": nil,
+			"updated_at":       gorm.Expr("CURRENT_TIMESTAMP"),
+		}),
+	}).Create(feed).Error
+	if err != nil {
+		logger.Log.Error("Failed to save calendar feed token", zap.Error(err), zap.String("userID", userID))
+		return err
+	}
+
+	return nil
+}
+
+// DeleteFeed turns the user's calendar feed off
+func (r *CalendarRepository) DeleteFeed(ctx context.Context, userID string) error {
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&model.CalendarFeed{}).Error; err != nil {
+		logger.Log.Error("Failed to delete calendar feed", zap.Error(err), zap.String("userID", userID))
+		return err
+	}
+	return nil
+}
+
+// AccessFeed returns the calendar feed with the given token hash, with its user, and records the access.
+// Returns nil if no feed has that token.
+func (r *CalendarRepository) AccessFeed(ctx context.Context, tokenHash string) (*model.CalendarFeed, error) {
+	var feeds []model.CalendarFeed
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Where("token_hash = ?", tokenHash).
+		Limit(1).
+		Find(&feeds).Error
+	if err != nil {
+		logger.Log.Error("Failed to find calendar feed", zap.Error(err))
+		return nil, err
+	}
+	if len(feeds) == 0 {
+		return nil, nil
+	}
+
+	// Losing an access time isn't worth failing the feed over
+	err = r.db.WithContext(ctx).Model(&model.CalendarFeed{}).
+		Where("id = ?", feeds[0].ID).
+		UpdateColumn("last_accessed_at", gorm.Expr("CURRENT_TIMESTAMP")).Error
+	if err != nil {
+		logger.Log.Warn("Failed to record calendar feed access", zap.Error(err), zap.String("feedID", feeds[0].ID))
+	}
+
+	return &feeds[0], nil
+}
+
+// ListFeedTasks returns up to limit of the tasks due since the given time in the user's personal space,
+// shared projects included, earliest first. Feeds have no workspace, since calendar apps can't switch one.
+func (r *CalendarRepository) ListFeedTasks(ctx context.Context, userID string, since time.Time, projectID *string, limit int) ([]model.Task, error) {
+	query := r.calendarTasks(ctx, userID, CalendarFilters{ProjectID: projectID}).
+		Where("due_date >= ?", since)
+
+	var tasks []model.Task
+	err := query.Preload("Project").
+		Preload("WorkflowStatus").
+		Order("due_date ASC").
+		Limit(limit).
+		Find(&tasks).Error
+	if err != nil {
+		logger.Log.Error("Failed to list calendar feed tasks", zap.Error(err), zap.String("userID", userID))
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+// calendarTasks scopes a query to the live tasks the user sees in the filters' space that match them
+func (r *CalendarRepository) calendarTasks(ctx context.Context, userID string, filters CalendarFilters) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&model.Task{}).
+		Where(scopeItemsSQL("tasks", "project_id", filters.WorkspaceID), map[string]any{
+			"user_id":      userID,
+			"workspace_id": filters.WorkspaceID,
+		}).
+		Where("tasks.deleted_at IS NULL")
+	if filters.ProjectID != nil {
+		query = query.Where("tasks.project_id = ?", *filters.ProjectID)
+	}
+	return query
+}
+
+// withCalendarAssociations loads what a calendar shows of each task
+func withCalendarAssociations(query *gorm.DB) *gorm.DB {
+	return query.Preload("WorkflowStatus").Preload("Tags", "deleted_at IS NULL")
+}
@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"app/internal/contract"
+	"app/internal/model"
+	"app/pkg/util"
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestListDueTasksStatuses(t *testing.T) {
+	db := testDB(t)
+	syncRepo := newTestSyncRepository(t, db)
+	calendarRepo := NewCalendarRepository(db)
+	userID := testUser(t, db)
+
+	from := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	due := func(day int) *string {
+		return util.ToPointer(from.AddDate(0, 0, day).Format(time.RFC3339))
+	}
+	openID, completedID := uuid.NewString(), uuid.NewString()
+	applyChanges(t, syncRepo, userID,
+		contract.Change{Type: "task", EntityID: openID, Title: util.ToPointer("Open"), DueDate: due(1)},
+		contract.Change{Type: "task", EntityID: completedID, Title: util.ToPointer("Completed"), DueDate: due(2), Status: util.ToPointer(model.TaskStatusCompleted)},
+		contract.Change{Type: "task", EntityID: uuid.NewString(), Title: util.ToPointer("Cancelled"), DueDate: due(3), Status: util.ToPointer(model.TaskStatusCancelled)},
+		contract.Change{Type: "task", EntityID: uuid.NewString(), Title: util.ToPointer("Next week"), DueDate: due(7)},
+	)
+
+	tests := []struct {
+		name    string
+		filters CalendarFilters
+		want    []string
+	}{
+		{"open only", CalendarFilters{}, []string{openID}},
+		{"with completed", CalendarFilters{IncludeCompleted: true}, []string{openID, completedID}},
+	}
+	for _, tt := range tests {
+		tasks, err := calendarRepo.ListDueTasks(context.Background(), userID, from, from.AddDate(0, 0, 7), tt.filters)
+		if err != nil {
+			t.Fatalf("failed to list due tasks: %v", err)
+		}
+		ids := make([]string, 0, len(tasks))
+		for _, task := range tasks {
+			ids = append(ids, task.ID)
+		}
+		if !slices.Equal(ids, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, ids, tt.want)
+		}
+	}
+}
+
+func TestListOverdueTasksCountsAll(t *testing.T) {
+	db := testDB(t)
+	syncRepo := newTestSyncRepository(t, db)
+	calendarRepo := NewCalendarRepository(db)
+	userID := testUser(t, db)
+
+	now := time.Now().UTC()
+	earliestID := uuid.NewString()
+	applyChanges(t, syncRepo, userID,
+		contract.Change{Type: "task", EntityID: uuid.NewString(), Title: util.ToPointer("Yesterday"), DueDate: util.ToPointer(now.AddDate(0, 0, -1).Format(time.RFC3339))},
+		contract.Change{Type: "task", EntityID: earliestID, Title: util.ToPointer("Last week"), DueDate: util.ToPointer(now.AddDate(0, 0, -7).Format(time.RFC3339))},
+		contract.Change{Type: "task", EntityID: uuid.NewString(), Title: util.ToPointer("Two days ago"), DueDate: util.ToPointer(now.AddDate(0, 0, -2).Format(time.RFC3339))},
+		contract.Change{Type: "task", EntityID: uuid.NewString(), Title: util.ToPointer("Done late"), DueDate: util.ToPointer(now.AddDate(0, 0, -3).Format(time.RFC3339)), Status: util.ToPointer(model.TaskStatusCompleted)},
+	)
+
+	tasks, total, err := calendarRepo.ListOverdueTasks(context.Background(), userID, now, CalendarFilters{}, 2)
+	if err != nil {
+		t.Fatalf("failed to list overdue tasks: %v", err)
+	}
+	if total != 3 || len(tasks) != 2 {
+		t.Fatalf("got %d of %d overdue tasks, want 2 of 3", len(tasks), total)
+	}
+	if tasks[0].ID != earliestID {
+		t.Errorf("first overdue task is %s, want the earliest", tasks[0].ID)
+	}
+}
+
+func TestCalendarTasksStayInTheirSpace(t *testing.T) {
+	db := testDB(t)
+	syncRepo := newTestSyncRepository(t, db)
+	calendarRepo := NewCalendarRepository(db)
+	ownerID := testUser(t, db)
+	memberID := testUser(t, db)
+	outsiderID := testUser(t, db)
+
+	workspace := model.Workspace{UserID: ownerID, Title: "Team"}
+	if err := db.Create(&workspace).Error; err != nil {
+		t.Fatalf("failed to create workspace: %v", err)
+	}
+	membership := model.Membership{UserID: memberID, WorkspaceID: &workspace.ID, Role: model.MemberRoleViewer, InvitedBy: &ownerID}
+	if err := db.Create(&membership).Error; err != nil {
+		t.Fatalf("failed to create membership: %v", err)
+	}
+
+	from := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	due := util.ToPointer(from.AddDate(0, 0, 1).Format(time.RFC3339))
+	personalID, projectID, teamID := uuid.NewString(), uuid.NewString(), uuid.NewString()
+	applyChanges(t, syncRepo, ownerID,
+		contract.Change{Type: "task", EntityID: personalID, Title: util.ToPointer("Personal"), DueDate: due},
+	)
+	_, err := syncRepo.ApplyChanges(ownerID, workspace.ID, []contract.Change{
+		{Type: "project", EntityID: projectID, Title: util.ToPointer("Team project")},
+		{Type: "task", EntityID: teamID, ProjectID: &projectID, Title: util.ToPointer("Team"), DueDate: due},
+	}, model.ActivitySourceSync)
+	if err != nil {
+		t.Fatalf("failed to sync the workspace: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		userID      string
+		workspaceID string
+		want        []string
+	}{
+		{"owner's personal space", ownerID, "", []string{personalID}},
+		{"owner's workspace", ownerID, workspace.ID, []string{teamID}},
+		{"member's workspace", memberID, workspace.ID, []string{teamID}},
+		{"member's personal space", memberID, "", []string{}},
+		{"outsider claiming the workspace", outsiderID, workspace.ID, []string{}},
+	}
+	for _, tt := range tests {
+		filters := CalendarFilters{WorkspaceID: tt.workspaceID}
+		tasks, err := calendarRepo.ListDueTasks(context.Background(), tt.userID, from, from.AddDate(0, 0, 7), filters)
+		if err != nil {
+			t.Fatalf("failed to list due tasks: %v", err)
+		}
+		ids := []string{}
+		for _, task := range tasks {
+			ids = append(ids, task.ID)
+		}
+		if !slices.Equal(ids, tt.want) {
+			t.Errorf("%s: got due tasks %v, want %v", tt.name, ids, tt.want)
+		}
+
+		_, total, err := calendarRepo.ListOverdueTasks(context.Background(), tt.userID, from.AddDate(0, 0, 7), filters, 10)
+		if err != nil {
+			t.Fatalf("failed to list overdue tasks: %v", err)
+		}
+		if total != int64(len(tt.want)) {
+			t.Errorf("%s: got %d overdue tasks, want %d", tt.name, total, len(tt.want))
+		}
+	}
+}
+
+func TestFeedTasksArePersonal(t *testing.T) {
+	db := testDB(t)
+	syncRepo := newTestSyncRepository(t, db)
+	calendarRepo := NewCalendarRepository(db)
+	userID := testUser(t, db)
+	sharerID := testUser(t, db)
+
+	workspace := model.Workspace{UserID: userID, Title: "Team"}
+	if err := db.Create(&workspace).Error; err != nil {
+		t.Fatalf("failed to create workspace: %v", err)
+	}
+
+	now := time.Now().UTC()
+	due := util.ToPointer(now.AddDate(0, 0, 1).Format(time.RFC3339))
+	personalID, sharedID, teamProjectID, sharedProjectID := uuid.NewString(), uuid.NewString(), uuid.NewString(), uuid.NewString()
+	applyChanges(t, syncRepo, userID,
+		contract.Change{Type: "task", EntityID: personalID, Title: util.ToPointer("Personal"), DueDate: due},
+	)
+	applyChanges(t, syncRepo, sharerID,
+		contract.Change{Type: "project", EntityID: sharedProjectID, Title: util.ToPointer("Shared")},
+		contract.Change{Type: "task", EntityID: sharedID, ProjectID: &sharedProjectID, Title: util.ToPointer("Shared"), DueDate: due},
+	)
+	membership := model.Membership{UserID: userID, ProjectID: &sharedProjectID, Role: model.MemberRoleViewer, InvitedBy: &sharerID}
+	if err := db.Create(&membership).Error; err != nil {
+		t.Fatalf("failed to create membership: %v", err)
+	}
+	_, err := syncRepo.ApplyChanges(userID, workspace.ID, []contract.Change{
+		{Type: "project", EntityID: teamProjectID, Title: util.ToPointer("Team project")},
+		{Type: "task", EntityID: uuid.NewString(), ProjectID: &teamProjectID, Title: util.ToPointer("Team"), DueDate: due},
+	}, model.ActivitySourceSync)
+	if err != nil {
+		t.Fatalf("failed to sync the workspace: %v", err)
+	}
+
+	tasks, err := calendarRepo.ListFeedTasks(context.Background(), userID, now, nil, 10)
+	if err != nil {
+		t.Fatalf("failed to list feed tasks: %v", err)
+	}
+	ids := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+	}
+	slices.Sort(ids)
+	want := []string{personalID, sharedID}
+	slices.Sort(want)
+	if !slices.Equal(ids, want) {
+		t.Errorf("got feed tasks %v, want the personal and shared ones %v", ids, want)
+	}
+}
+
+func TestRotatedFeedTokenReplacesTheOldOne(t *testing.T) {
+	db := testDB(t)
+	calendarRepo := NewCalendarRepository(db)
+	userID := testUser(t, db)
+	ctx := context.Background()
+
+	oldHash, newHash := uuid.NewString(), uuid.NewString()
+	if err := calendarRepo.SaveFeedToken(ctx, userID, oldHash); err != nil {
+		t.Fatalf("failed to turn the feed on: %v", err)
+	}
+	feed, err := calendarRepo.AccessFeed(ctx, oldHash)
+	if err != nil || feed == nil || feed.UserID != userID {
+		t.Fatalf("got feed %v (%v), want the user's", feed, err)
+	}
+
+	if err := calendarRepo.SaveFeedToken(ctx, userID, newHash); err != nil {
+		t.Fatalf("failed to rotate the feed: %v", err)
+	}
+	if feed, err := calendarRepo.AccessFeed(ctx, oldHash); err != nil || feed != nil {
+		t.Errorf("the old token still opens feed %v (%v)", feed, err)
+	}
+	if feed, err := calendarRepo.AccessFeed(ctx, newHash); err != nil || feed == nil {
+		t.Errorf("the new token doesn't open the feed (%v)", err)
+	}
+
+	if err := calendarRepo.DeleteFeed(ctx, userID); err != nil {
+		t.Fatalf("failed to turn the feed off: %v", err)
+	}
+	if feed, err := calendarRepo.AccessFeed(ctx, newHash); err != nil || feed != nil {
+		t.Errorf("a turned off feed still opens with %v (%v)", feed, err)
+	}
+}
@@ -19,12 +19,15 @@ func NewChatRepository(db *gorm.DB) *ChatRepository {
 	return &ChatRepository{db: db}
 }
 
-// CreateChat creates a new chat
-func (r *ChatRepository) CreateChat(ctx context.Context, userID string) (*model.Chat, error) {
+// CreateChat creates a new chat in a workspace, or in the personal space for an empty workspaceID
+func (r *ChatRepository) CreateChat(ctx context.Context, userID, workspaceID string) (*model.Chat, error) {
 	chat := &model.Chat{
 		ID:     uuid.New().String(),
 		UserID: userID,
 	}
+	if workspaceID != "" {
+		chat.WorkspaceID = &workspaceID
+	}
 
 	err := r.db.WithContext(ctx).Create(chat).Error
 	if err != nil {
@@ -35,10 +38,10 @@ func (r *ChatRepository) CreateChat(ctx context.Context, userID string) (*model.
 	return chat, nil
 }
 
-// GetChatByID retrieves a chat by ID with user validation
-func (r *ChatRepository) GetChatByID(ctx context.Context, chatID, userID string) (*model.Chat, error) {
+// GetChatByID retrieves a chat by ID with user validation. Only chats of the active workspace are found.
+func (r *ChatRepository) GetChatByID(ctx context.Context, chatID, userID, workspaceID string) (*model.Chat, error) {
 	var chat model.Chat
-	err := r.db.WithContext(ctx).
+	err := chatsInWorkspace(r.db.WithContext(ctx), workspaceID).
 		Where("id = ? AND user_id = ?", chatID, userID).
 		First(&chat).Error
 
@@ -128,12 +131,12 @@ func (r *ChatRepository) GetChatHistory(ctx context.Context, chatID string) ([]m
 	return messages, nil
 }
 
-// ListChatsByUserID retrieves chats for a user with pagination, ordered by latest message created_at DESC
-func (r *ChatRepository) ListChatsByUserID(ctx context.Context, userID string, page, limit int) ([]model.Chat, int64, error) {
+// ListChatsByUserID retrieves chats for a user in the active workspace with pagination, ordered by latest message created_at DESC
+func (r *ChatRepository) ListChatsByUserID(ctx context.Context, userID, workspaceID string, page, limit int) ([]model.Chat, int64, error) {
 	var total int64
 
 	// Count total chats
-	err := r.db.WithContext(ctx).
+	err := chatsInWorkspace(r.db.WithContext(ctx), workspaceID).
 		Model(&model.Chat{}).
 		Where("user_id = ?", userID).
 		Count(&total).Error
@@ -149,7 +152,7 @@ func (r *ChatRepository) ListChatsByUserID(ctx context.Context, userID string, p
 
 	// Get chats ordered by latest message timestamp (or created_at if no messages)
 	// Use subquery to order by updated_at, then preload messages
-	err = r.db.WithContext(ctx).
+	err = chatsInWorkspace(r.db.WithContext(ctx), workspaceID).
 		Where("user_id = ?", userID).
 		Order(`
 			COALESCE(
@@ -210,3 +213,11 @@ func (r *ChatRepository) CountUserMessages(ctx context.Context, userID string) (
 
 	return count, nil
 }
+
+// chatsInWorkspace limits a query to the chats started in a workspace, or in the personal space for an empty workspaceID
+func chatsInWorkspace(query *gorm.DB, workspaceID string) *gorm.DB {
+	if workspaceID == "" {
+		return query.Where("workspace_id IS NULL")
+	}
+	return query.Where("workspace_id = ?", workspaceID)
+}
@@ -0,0 +1,378 @@
+package repository
+
+import (
+	"app/internal/contract"
+	"app/internal/model"
+	"app/pkg/logger"
+	"app/pkg/util"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrCommentIncomplete     = errors.New("a new comment needs content and either a task or a note")
+	ErrCommentParentNotFound = errors.New("task or note to comment on not found")
+	ErrCommentForbidden      = errors.New("only the author can edit a comment, and only the author or an owner can delete it")
+)
+
+type CommentRepository struct {
+	db *gorm.DB
+}
+
+func NewCommentRepository(db *gorm.DB) *CommentRepository {
+	return &CommentRepository{db: db}
+}
+
+// ClaimMentions marks up to limit mention notifications that are ready to be attempted as sending and
+// returns them. Rows claimed by another worker are skipped.
+func (r *CommentRepository) ClaimMentions(ctx context.Context, limit int) ([]model.CommentMention, error) {
+	query := `
+		UPDATE comment_mentions
+		SET status = @sending, attempts = attempts + 1, updated_at = CURRENT_TIMESTAMP
+		WHERE id IN (
+			SELECT id FROM comment_mentions
+			WHERE status IN @ready AND attempts < @max_attempts AND next_attempt_at <= CURRENT_TIMESTAMP
+			ORDER BY created_at
+			LIMIT @limit
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`
+
+	var mentions []model.CommentMention
+	err := r.db.WithContext(ctx).Raw(query, map[string]any{
+		"sending":      model.MentionStatusSending,
+		"ready":        []string{model.MentionStatusPending, model.MentionStatusFailed},
+		"max_attempts": model.MentionMaxAttempts,
+		"limit":        limit,
+	}).Scan(&mentions).Error
+	if err != nil {
+		logger.Log.Error("Failed to claim mention notifications", zap.Error(err))
+		return nil, err
+	}
+
+	return mentions, nil
+}
+
+// AbandonStaleMentions gives up on notifications left in sending by a worker that stopped mid-send.
+// They are not retried because the notification may already have gone out.
+func (r *CommentRepository) AbandonStaleMentions(ctx context.Context, olderThan time.Duration) (int64, error) {
+	res := r.db.WithContext(ctx).
+		Model(&model.CommentMention{}).
+		Where("status = ? AND updated_at < ?", model.MentionStatusSending, time.Now().Add(-olderThan)).
+		Updates(map[string]any{
+			"status":     model.MentionStatusFailed,
+			"attempts":   model.MentionMaxAttempts,
+			"last_error": "notification was interrupted and may or may not have been sent",
+			"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+		})
+	if res.Error != nil {
+		logger.Log.Error("Failed to abandon stale mention notifications", zap.Error(res.Error))
+		return 0, res.Error
+	}
+
+	return res.RowsAffected, nil
+}
+
+// MarkMention records the outcome of a notification attempt
+func (r *CommentRepository) MarkMention(ctx context.Context, id, status string, lastError *string, nextAttemptAt *time.Time) error {
+	updates := map[string]any{
+		"status":     status,
+		"last_error": lastError,
+		"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+	}
+	if status == model.MentionStatusSent {
+		updates["sent_at"] = gorm.Expr("CURRENT_TIMESTAMP")
+	}
+	if nextAttemptAt != nil {
+		updates["next_attempt_at"] = *nextAttemptAt
+	}
+
+	err := r.db.WithContext(ctx).
+		Model(&model.CommentMention{}).
+		Where("id = ?", id).
+		Updates(updates).Error
+	if err != nil {
+		logger.Log.Error("Failed to mark mention notification", zap.Error(err), zap.String("mentionID", id), zap.String("status", status))
+		return err
+	}
+
+	return nil
+}
+
+// GetMentionComment returns the comment a notification is for, with its author and its task or
+// note, or nil if the comment or what it is on was deleted, an edit dropped the mention, or the
+// mentioned user no longer sees the task or note
+func (r *CommentRepository) GetMentionComment(ctx context.Context, mention *model.CommentMention) (*model.Comment, error) {
+	db := r.db.WithContext(ctx)
+	var comments []model.Comment
+	err := db.
+		Preload("User").
+		Preload("Task").
+		Preload("Note").
+		Where("id = ? AND deleted_at IS NULL", mention.CommentID).
+		Limit(1).
+		Find(&comments).Error
+	if err != nil {
+		logger.Log.Error("Failed to get mention comment", zap.Error(err), zap.String("commentID", mention.CommentID))
+		return nil, err
+	}
+	if len(comments) == 0 || !slices.Contains(comments[0].MentionedUserIDs, mention.UserID) {
+		return nil, nil
+	}
+
+	parent, err := findCommentParent(db, comments[0].TaskID, comments[0].NoteID, true)
+	if err != nil {
+		logger.Log.Error("Failed to get comment parent", zap.Error(err), zap.String("commentID", mention.CommentID))
+		return nil, err
+	}
+	if parent == nil {
+		return nil, nil
+	}
+	role, err := commentParentRole(db, mention.UserID, parent)
+	if err != nil {
+		logger.Log.Error("Failed to get comment parent access", zap.Error(err), zap.String("commentID", mention.CommentID))
+		return nil, err
+	}
+	if role == "" {
+		return nil, nil
+	}
+
+	return &comments[0], nil
+}
+
+// commentParent is the task or note a comment is on
+type commentParent struct {
+	ID      string
+	OwnerID string
+	// ContainerID is the item's project or collection, if any
+	ContainerID *string
+	// table is tasks or notes, and column the one that ties it to its project or collection
+	table  string
+	column string
+}
+
+// findCommentParent returns the task or note a comment is on, whichever is set, or nil if it is
+// missing or, when live is set, deleted
+func findCommentParent(tx *gorm.DB, taskID, noteID *string, live bool) (*commentParent, error) {
+	parent := commentParent{table: "tasks", column: "project_id"}
+	id := util.ToValue(taskID)
+	if taskID == nil {
+		parent = commentParent{table: "notes", column: "collection_id"}
+		id = util.ToValue(noteID)
+	}
+
+	query := tx.Table(parent.table).
+		Select("id, user_id AS owner_id, "+parent.column+" AS container_id").
+		Where("id = ?", id)
+	if live {
+		query = query.Where("deleted_at IS NULL")
+	}
+	var rows []commentParent
+	if err := query.Limit(1).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	rows[0].table, rows[0].column = parent.table, parent.column
+	return &rows[0], nil
+}
+
+// commentParentRole returns the user's role on the project or collection of a task or note, or owner
+// on their own item in neither. It is empty when the user doesn't see the item.
+func commentParentRole(tx *gorm.DB, userID string, parent *commentParent) (string, error) {
+	if parent.ContainerID == nil {
+		if parent.OwnerID == userID {
+			return model.MemberRoleOwner, nil
+		}
+		return "", nil
+	}
+
+	access, err := memberAccess(tx, userID, memberTarget(parent.column, *parent.ContainerID))
+	if err != nil || access == nil {
+		return "", err
+	}
+	return access.Role, nil
+}
+
+// syncCommentOwner returns whose comment a change writes, which is always its author's. Anyone who sees
+// the task or note in the scope synced may comment on it. Only the author edits a comment, while an
+// owner of the project or collection may also delete it. A comment never moves to another task or note.
+func syncCommentOwner(tx *gorm.DB, userID string, workspace *syncWorkspace, change *contract.Change) (string, error) {
+	var existing []model.Comment
+	if err := tx.Where("id = ?", change.EntityID).Limit(1).Find(&existing).Error; err != nil {
+		return "", err
+	}
+
+	if len(existing) > 0 {
+		change.TaskID, change.NoteID = existing[0].TaskID, existing[0].NoteID
+	} else {
+		if util.ToValue(change.TaskID) == "" {
+			change.TaskID = nil
+		}
+		if util.ToValue(change.NoteID) == "" {
+			change.NoteID = nil
+		}
+		if (change.TaskID == nil) == (change.NoteID == nil) || util.ToValue(change.Content) == "" {
+			return "", ErrCommentIncomplete
+		}
+	}
+
+	// Comments on a task or note that was deleted can still be deleted
+	deleting := len(existing) > 0 && change.DeletedAt != nil
+	parent, err := findCommentParent(tx, change.TaskID, change.NoteID, !deleting)
+	if err != nil {
+		return "", err
+	}
+	if parent == nil {
+		return "", ErrCommentParentNotFound
+	}
+	workspaceID := ""
+	if workspace != nil {
+		workspaceID = workspace.ID
+	}
+	var visible int64
+	err = tx.Table(parent.table+" t").
+		Where("t.id = @id AND "+scopeItemsSQL("t", parent.column, workspaceID), map[string]any{
+			"id":           parent.ID,
+			"user_id":      userID,
+			"workspace_id": workspaceID,
+		}).
+		Count(&visible).Error
+	if err != nil {
+		return "", err
+	}
+	if visible == 0 {
+		return "", ErrCommentParentNotFound
+	}
+
+	if len(existing) == 0 || existing[0].UserID == userID {
+		return userID, nil
+	}
+	if change.DeletedAt == nil {
+		return "", ErrCommentForbidden
+	}
+	role, err := commentParentRole(tx, userID, parent)
+	if err != nil {
+		return "", err
+	}
+	if !model.MemberRoleAllows(role, model.MemberRoleOwner) {
+		return "", ErrCommentForbidden
+	}
+	// Someone else's comment is only deleted, never edited
+	change.Content = nil
+	return existing[0].UserID, nil
+}
+
+// syncCommentMentions resolves the @mentions in a comment to the users who see its task or note,
+// other than the author, stores them on the comment and enqueues a notification for each
+func syncCommentMentions(tx *gorm.DB, authorID, commentID, content string, parent *commentParent) error {
+	mentionedUserIDs := []string{}
+	if handles := util.ParseMentions(content); len(handles) > 0 {
+		candidates, err := commentAudience(tx, parent)
+		if err != nil {
+			return err
+		}
+		mentionedUserIDs = resolveMentions(handles, candidates, authorID)
+	}
+
+	err := tx.Model(&model.Comment{}).
+		Where("id = ?", commentID).
+		UpdateColumn("mentioned_user_ids", datatypes.NewJSONSlice(mentionedUserIDs)).Error
+	if err != nil {
+		return err
+	}
+	if len(mentionedUserIDs) == 0 {
+		return nil
+	}
+
+	mentions := make([]model.CommentMention, 0, len(mentionedUserIDs))
+	for _, userID := range mentionedUserIDs {
+		mentions = append(mentions, model.CommentMention{CommentID: commentID, UserID: userID})
+	}
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "comment_id"}, {Name: "user_id"}},
+		DoNothing: true,
+	}).Omit(clause.Associations).Create(&mentions).Error
+}
+
+// commentAudience returns the live users who see a task or note: the owner of its project or
+// collection, its members and, for one of a workspace, the workspace's owner and members. An item
+// in neither is only seen by its owner.
+func commentAudience(tx *gorm.DB, parent *commentParent) ([]model.User, error) {
+	var users []model.User
+	if parent.ContainerID == nil {
+		err := tx.Where("id = ? AND deleted_at IS NULL", parent.OwnerID).Find(&users).Error
+		return users, err
+	}
+
+	containers := memberTarget(parent.column, "").table()
+	err := tx.Raw(`
+		SELECT * FROM users WHERE deleted_at IS NULL AND id IN (
+			SELECT user_id FROM `+containers+` WHERE id = @id
+			UNION SELECT user_id FROM memberships WHERE `+parent.column+` = @id AND removed_at IS NULL
+			UNION SELECT w.user_id FROM workspaces w JOIN `+containers+` c ON c.workspace_id = w.id
+				WHERE c.id = @id AND w.deleted_at IS NULL
+			UNION SELECT m.user_id FROM memberships m JOIN `+containers+` c ON c.workspace_id = m.workspace_id
+				WHERE c.id = @id AND m.removed_at IS NULL)`,
+		map[string]any{"id": *parent.ContainerID}).Scan(&users).Error
+	return users, err
+}
+
+// resolveMentions returns the users that mention handles name, in the order of the handles. A full
+// email address wins; otherwise a handle must name exactly one of the candidates, so an ambiguous
+// one mentions nobody. The author is never mentioned.
+func resolveMentions(handles []string, candidates []model.User, authorID string) []string {
+	exact := map[string]string{}
+	loose := map[string][]string{}
+	for _, user := range candidates {
+		email := strings.ToLower(strings.TrimSpace(user.Email))
+		for _, handle := range util.MentionHandles(user.Name, user.Email) {
+			if handle == email {
+				exact[handle] = user.ID
+				continue
+			}
+			if !slices.Contains(loose[handle], user.ID) {
+				loose[handle] = append(loose[handle], user.ID)
+			}
+		}
+	}
+
+	userIDs := []string{}
+	for _, handle := range handles {
+		userID, ok := exact[handle]
+		if !ok && len(loose[handle]) == 1 {
+			userID, ok = loose[handle][0], true
+		}
+		if ok && userID != authorID && !slices.Contains(userIDs, userID) {
+			userIDs = append(userIDs, userID)
+		}
+	}
+	return userIDs
+}
+
+// commentChangesSQL matches the comments on the tasks and notes @user_id sees in the scope synced
+// that were updated since @from. In the personal space it also matches all of them on a project or
+// collection whose membership started or changed since. Its arguments are those of sharedChangesSQL.
+func commentChangesSQL(workspaceID string) string {
+	changed := `(comments.updated_at > @from AND (
+		comments.task_id IN (SELECT t.id FROM tasks t WHERE ` + scopeItemsSQL("t", "project_id", workspaceID) + `)
+		OR comments.note_id IN (SELECT n.id FROM notes n WHERE ` + scopeItemsSQL("n", "collection_id", workspaceID) + `)))`
+	if workspaceID != "" {
+		return changed
+	}
+	return `(` + changed + `
+		OR comments.task_id IN (SELECT t.id FROM tasks t JOIN memberships m ON m.project_id = t.project_id
+			WHERE m.user_id = @user_id AND m.removed_at IS NULL AND m.updated_at > @from)
+		OR comments.note_id IN (SELECT n.id FROM notes n JOIN memberships m ON m.collection_id = n.collection_id
+			WHERE m.user_id = @user_id AND m.removed_at IS NULL AND m.updated_at > @from))`
+}
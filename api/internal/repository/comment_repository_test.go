@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"app/internal/contract"
+	"app/internal/model"
+	"app/pkg/util"
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestResolveMentions(t *testing.T) {
+	candidates := []model.User{
+		{ID: "ana", Name: "Ana Lima", Email: "ana@example.com"},
+		{ID: "ana2", Name: "Ana", Email: "ana@example.org"},
+		{ID: "bob", Name: "Bob", Email: "bob@example.com"},
+	}
+
+	tests := []struct {
+		name     string
+		handles  []string
+		authorID string
+		want     []string
+	}{
+		{name: "email address", handles: []string{"ana@example.org"}, want: []string{"ana2"}},
+		{name: "unique name", handles: []string{"analima", "bob"}, want: []string{"ana", "bob"}},
+		{name: "ambiguous handle", handles: []string{"ana"}, want: []string{}},
+		{name: "author", handles: []string{"bob@example.com", "ana@example.com"}, authorID: "bob", want: []string{"ana"}},
+		{name: "repeats", handles: []string{"bob", "bob@example.com"}, want: []string{"bob"}},
+		{name: "unknown", handles: []string{"carol"}, want: []string{}},
+	}
+	for _, tt := range tests {
+		if got := resolveMentions(tt.handles, candidates, tt.authorID); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCommentMentionsOnlyTheTasksAudience(t *testing.T) {
+	db := testDB(t)
+	repo := newTestSyncRepository(t, db)
+	commentRepo := NewCommentRepository(db)
+	ownerID, memberID, outsiderID := testUser(t, db), testUser(t, db), testUser(t, db)
+
+	projectID, taskID := uuid.NewString(), uuid.NewString()
+	applyChanges(t, repo, ownerID,
+		contract.Change{Type: "project", EntityID: projectID, Title: util.ToPointer("Shared")},
+		contract.Change{Type: "task", EntityID: taskID, ProjectID: &projectID, Title: util.ToPointer("Launch")},
+	)
+	membership := model.Membership{UserID: memberID, ProjectID: &projectID, Role: model.MemberRoleEditor, InvitedBy: &ownerID}
+	if err := db.Create(&membership).Error; err != nil {
+		t.Fatalf("failed to create membership: %v", err)
+	}
+
+	email := func(userID string) string {
+		t.Helper()
+		var user model.User
+		if err := db.First(&user, "id = ?", userID).Error; err != nil {
+			t.Fatalf("failed to get user: %v", err)
+		}
+		return user.Email
+	}
+
+	commentID := uuid.NewString()
+	applyChanges(t, repo, ownerID, contract.Change{
+		Type:     "comment",
+		EntityID: commentID,
+		TaskID:   &taskID,
+		Content:  util.ToPointer("@" + email(memberID) + " @" + email(outsiderID) + " @" + email(ownerID) + " please check"),
+	})
+
+	var comment model.Comment
+	if err := db.First(&comment, "id = ?", commentID).Error; err != nil {
+		t.Fatalf("failed to get comment: %v", err)
+	}
+	if !slices.Equal(comment.MentionedUserIDs, []string{memberID}) {
+		t.Errorf("mentioned users = %v, want only the member", comment.MentionedUserIDs)
+	}
+	var mentions []model.CommentMention
+	if err := db.Where("comment_id = ?", commentID).Find(&mentions).Error; err != nil {
+		t.Fatalf("failed to get mentions: %v", err)
+	}
+	if len(mentions) != 1 || mentions[0].UserID != memberID || mentions[0].Status != model.MentionStatusPending {
+		t.Fatalf("mentions = %+v, want one pending for the member", mentions)
+	}
+
+	got, err := commentRepo.GetMentionComment(context.Background(), &mentions[0])
+	if err != nil || got == nil {
+		t.Fatalf("got comment %v (%v) for the mention, want it", got, err)
+	}
+
+	applyChanges(t, repo, ownerID, contract.Change{Type: "comment", EntityID: commentID, TaskID: &taskID, Content: util.ToPointer("Never mind")})
+	if got, err := commentRepo.GetMentionComment(context.Background(), &mentions[0]); err != nil || got != nil {
+		t.Errorf("got comment %v (%v) after an edit dropped the mention, want none", got, err)
+	}
+}
+
+func TestCommentPermissions(t *testing.T) {
+	db := testDB(t)
+	repo := newTestSyncRepository(t, db)
+	ownerID, memberID, outsiderID := testUser(t, db), testUser(t, db), testUser(t, db)
+
+	projectID, taskID, commentID := uuid.NewString(), uuid.NewString(), uuid.NewString()
+	applyChanges(t, repo, ownerID,
+		contract.Change{Type: "project", EntityID: projectID, Title: util.ToPointer("Shared")},
+		contract.Change{Type: "task", EntityID: taskID, ProjectID: &projectID, Title: util.ToPointer("Launch")},
+	)
+	membership := model.Membership{UserID: memberID, ProjectID: &projectID, Role: model.MemberRoleViewer, InvitedBy: &ownerID}
+	if err := db.Create(&membership).Error; err != nil {
+		t.Fatalf("failed to create membership: %v", err)
+	}
+	applyChanges(t, repo, memberID, contract.Change{Type: "comment", EntityID: commentID, TaskID: &taskID, Content: util.ToPointer("Looks good")})
+
+	deletedAt := util.ToPointer(time.Now().UTC().Format(time.RFC3339))
+	tests := []struct {
+		name   string
+		userID string
+		change contract.Change
+		want   error
+	}{
+		{
+			name:   "outsider comments",
+			userID: outsiderID,
+			change: contract.Change{Type: "comment", EntityID: uuid.NewString(), TaskID: &taskID, Content: util.ToPointer("Hi")},
+			want:   ErrCommentParentNotFound,
+		},
+		{
+			name:   "comment on nothing",
+			userID: ownerID,
+			change: contract.Change{Type: "comment", EntityID: uuid.NewString(), Content: util.ToPointer("Hi")},
+			want:   ErrCommentIncomplete,
+		},
+		{
+			name:   "owner edits the member's comment",
+			userID: ownerID,
+			change: contract.Change{Type: "comment", EntityID: commentID, Content: util.ToPointer("Edited")},
+			want:   ErrCommentForbidden,
+		},
+		{
+			name:   "owner deletes the member's comment",
+			userID: ownerID,
+			change: contract.Change{Type: "comment", EntityID: commentID, DeletedAt: deletedAt},
+		},
+	}
+	for _, tt := range tests {
+		_, err := repo.ApplyChanges(tt.userID, "", []contract.Change{tt.change}, model.ActivitySourceSync)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.want)
+		}
+	}
+
+	var comment model.Comment
+	if err := db.First(&comment, "id = ?", commentID).Error; err != nil {
+		t.Fatalf("failed to get comment: %v", err)
+	}
+	if comment.Content != "Looks good" || comment.DeletedAt == nil || comment.UserID != memberID {
+		t.Errorf("comment = %q by %s, deleted at %v, want the member's unedited comment deleted", comment.Content, comment.UserID, comment.DeletedAt)
+	}
+}
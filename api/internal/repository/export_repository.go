@@ -0,0 +1,290 @@
+package repository
+
+import (
+	"app/internal/model"
+	"app/pkg/logger"
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type ExportRepository struct {
+	db *gorm.DB
+}
+
+func NewExportRepository(db *gorm.DB) *ExportRepository {
+	return &ExportRepository{db: db}
+}
+
+// ExportData is everything of a user's account that goes into an export
+type ExportData struct {
+	Notes       []model.Note
+	Tasks       []model.Task
+	Projects    []model.Project
+	Collections []model.Collection
+	Tags        []model.Tag
+	Chats       []model.Chat
+}
+
+const exportJobColumns = "id, user_id, status, error, archive_path, size_bytes, started_at, completed_at, expires_at, created_at, updated_at"
+
+// CreateJob queues an export for the user, or returns the one already queued or running
+func (r *ExportRepository) CreateJob(ctx context.Context, userID string) (*model.ExportJob, error) {
+	var job model.ExportJob
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Serializes concurrent requests of the same user
+		if err := tx.Exec("SELECT id FROM users WHERE id = ? FOR UPDATE", userID).Error; err != nil {
+			return err
+		}
+
+		var active []model.ExportJob
+		err := tx.Select(exportJobColumns).
+			Where("user_id = ? AND status IN ?", userID, []string{model.ExportStatusPending, model.ExportStatusRunning}).
+			Limit(1).
+			Find(&active).Error
+		if err != nil {
+			return err
+		}
+		if len(active) > 0 {
+			job = active[0]
+			return nil
+		}
+
+		job = model.ExportJob{UserID: userID, Status: model.ExportStatusPending}
+		return tx.Create(&job).Error
+	})
+	if err != nil {
+		logger.Log.Error("Failed to create export job", zap.Error(err), zap.String("userID", userID))
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// GetJob returns one of the user's export jobs, or nil if there is no such job
+func (r *ExportRepository) GetJob(ctx context.Context, userID, jobID string) (*model.ExportJob, error) {
+	var jobs []model.ExportJob
+	err := r.db.WithContext(ctx).
+		Select(exportJobColumns).
+		Where("id = ? AND user_id = ?", jobID, userID).
+		Limit(1).
+		Find(&jobs).Error
+	if err != nil {
+		logger.Log.Error("Failed to get export job", zap.Error(err), zap.String("jobID", jobID))
+		return nil, err
+	}
+	if len(jobs) == 0 {
+		return nil, nil
+	}
+
+	return &jobs[0], nil
+}
+
+// ListJobs returns the user's most recent export jobs, newest first
+func (r *ExportRepository) ListJobs(ctx context.Context, userID string, limit int) ([]model.ExportJob, error) {
+	var jobs []model.ExportJob
+	err := r.db.WithContext(ctx).
+		Select(exportJobColumns).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&jobs).Error
+	if err != nil {
+		logger.Log.Error("Failed to list export jobs", zap.Error(err), zap.String("userID", userID))
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// GetArchive returns a completed, unexpired export job, whose archive can be downloaded, or nil if there is none
+func (r *ExportRepository) GetArchive(ctx context.Context, userID, jobID string) (*model.ExportJob, error) {
+	var jobs []model.ExportJob
+	err := r.db.WithContext(ctx).
+		Select(exportJobColumns).
+		Where("id = ? AND user_id = ? AND status = ? AND expires_at > CURRENT_TIMESTAMP", jobID, userID, model.ExportStatusCompleted).
+		Limit(1).
+		Find(&jobs).Error
+	if err != nil {
+		logger.Log.Error("Failed to get export archive", zap.Error(err), zap.String("jobID", jobID))
+		return nil, err
+	}
+	if len(jobs) == 0 {
+		return nil, nil
+	}
+
+	return &jobs[0], nil
+}
+
+// ClaimJob marks the oldest pending export as running and returns it, or nil if none is waiting
+func (r *ExportRepository) ClaimJob(ctx context.Context) (*model.ExportJob, error) {
+	query := `
+		UPDATE export_jobs
+		SET status = @running, started_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE id = (
+			SELECT id FROM export_jobs
+			WHERE status = @pending
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + exportJobColumns
+
+	var jobs []model.ExportJob
+	err := r.db.WithContext(ctx).Raw(query, map[string]any{
+		"running": model.ExportStatusRunning,
+		"pending": model.ExportStatusPending,
+	}).Scan(&jobs).Error
+	if err != nil {
+		logger.Log.Error("Failed to claim export job", zap.Error(err))
+		return nil, err
+	}
+	if len(jobs) == 0 {
+		return nil, nil
+	}
+
+	return &jobs[0], nil
+}
+
+// CompleteJob records the file holding the finished archive of a running export
+func (r *ExportRepository) CompleteJob(ctx context.Context, jobID, archivePath string, sizeBytes int64) error {
+	err := r.db.WithContext(ctx).
+		Model(&model.ExportJob{}).
+		Where("id = ? AND status = ?", jobID, model.ExportStatusRunning).
+		Updates(map[string]any{
+			"status":       model.ExportStatusCompleted,
+			"archive_path": archivePath,
+			"size_bytes":   sizeBytes,
+			"completed_at": gorm.Expr("CURRENT_TIMESTAMP"),
+			"expires_at":   time.Now().Add(model.ExportRetention),
+		}).Error
+	if err != nil {
+		logger.Log.Error("Failed to complete export job", zap.Error(err), zap.String("jobID", jobID))
+		return err
+	}
+
+	return nil
+}
+
+// FailJob records why an export could not be built
+func (r *ExportRepository) FailJob(ctx context.Context, jobID, reason string) error {
+	err := r.db.WithContext(ctx).
+		Model(&model.ExportJob{}).
+		Where("id = ?", jobID).
+		Updates(map[string]any{
+			"status":       model.ExportStatusFailed,
+			"error":        reason,
+			"completed_at": gorm.Expr("CURRENT_TIMESTAMP"),
+		}).Error
+	if err != nil {
+		logger.Log.Error("Failed to fail export job", zap.Error(err), zap.String("jobID", jobID))
+		return err
+	}
+
+	return nil
+}
+
+// AbandonStaleJobs fails exports left running by a worker that stopped mid-build
+func (r *ExportRepository) AbandonStaleJobs(ctx context.Context, olderThan time.Duration) (int64, error) {
+	res := r.db.WithContext(ctx).
+		Model(&model.ExportJob{}).
+		Where("status = ? AND started_at < ?", model.ExportStatusRunning, time.Now().Add(-olderThan)).
+		Updates(map[string]any{
+			"status":       model.ExportStatusFailed,
+			"error":        "the export was interrupted, please request a new one",
+			"completed_at": gorm.Expr("CURRENT_TIMESTAMP"),
+		})
+	if res.Error != nil {
+		logger.Log.Error("Failed to abandon stale export jobs", zap.Error(res.Error))
+		return 0, res.Error
+	}
+
+	return res.RowsAffected, nil
+}
+
+// DeleteExpiredJobs removes expired exports and failed ones past the retention period,
+// and returns the deleted jobs so their archive files can be removed
+func (r *ExportRepository) DeleteExpiredJobs(ctx context.Context) ([]model.ExportJob, error) {
+	var jobs []model.ExportJob
+	err := r.db.WithContext(ctx).Raw(`
+		DELETE FROM export_jobs
+		WHERE expires_at < CURRENT_TIMESTAMP OR (status = ? AND created_at < ?)
+		RETURNING `+exportJobColumns,
+		model.ExportStatusFailed, time.Now().Add(-model.ExportRetention)).
+		Scan(&jobs).Error
+	if err != nil {
+		logger.Log.Error("Failed to delete expired export jobs", zap.Error(err))
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// GetExportData loads the user's live notes, tasks, projects, collections and tags, and their chat history
+func (r *ExportRepository) GetExportData(ctx context.Context, userID string) (*ExportData, error) {
+	db := r.db.WithContext(ctx)
+	liveTags := func(tx *gorm.DB) *gorm.DB {
+		return tx.Where("deleted_at IS NULL")
+	}
+	var data ExportData
+
+	err := db.Omit("embedding").
+		Preload("Tags", liveTags).
+		Where("user_id = ? AND deleted_at IS NULL", userID).
+		Order("created_at ASC").
+		Find(&data.Notes).Error
+	if err != nil {
+		logger.Log.Error("Failed to load notes for export", zap.Error(err), zap.String("userID", userID))
+		return nil, err
+	}
+
+	err = db.Preload("Tags", liveTags).
+		Preload("BlockedBy").
+		Where("user_id = ? AND deleted_at IS NULL", userID).
+		Order("created_at ASC").
+		Find(&data.Tasks).Error
+	if err != nil {
+		logger.Log.Error("Failed to load tasks for export", zap.Error(err), zap.String("userID", userID))
+		return nil, err
+	}
+
+	err = db.Preload("Statuses", func(tx *gorm.DB) *gorm.DB {
+		return tx.Where("deleted_at IS NULL").Order(sortOrderColumn + " ASC NULLS LAST, created_at ASC")
+	}).
+		Where("user_id = ? AND deleted_at IS NULL", userID).
+		Order("created_at ASC").
+		Find(&data.Projects).Error
+	if err != nil {
+		logger.Log.Error("Failed to load projects for export", zap.Error(err), zap.String("userID", userID))
+		return nil, err
+	}
+
+	err = db.Where("user_id = ? AND deleted_at IS NULL", userID).Order("created_at ASC").Find(&data.Collections).Error
+	if err != nil {
+		logger.Log.Error("Failed to load collections for export", zap.Error(err), zap.String("userID", userID))
+		return nil, err
+	}
+
+	err = db.Where("user_id = ? AND deleted_at IS NULL", userID).Order("name ASC").Find(&data.Tags).Error
+	if err != nil {
+		logger.Log.Error("Failed to load tags for export", zap.Error(err), zap.String("userID", userID))
+		return nil, err
+	}
+
+	byCreatedAt := func(tx *gorm.DB) *gorm.DB {
+		return tx.Order("created_at ASC")
+	}
+	err = db.Preload("Messages", byCreatedAt).
+		Preload("Messages.ToolCalls", byCreatedAt).
+		Where("user_id = ?", userID).
+		Order("created_at ASC").
+		Find(&data.Chats).Error
+	if err != nil {
+		logger.Log.Error("Failed to load chats for export", zap.Error(err), zap.String("userID", userID))
+		return nil, err
+	}
+
+	return &data, nil
+}
@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"app/internal/model"
+	"app/pkg/logger"
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type InsightsRepository struct {
+	db *gorm.DB
+}
+
+func NewInsightsRepository(db *gorm.DB) *InsightsRepository {
+	return &InsightsRepository{db: db}
+}
+
+// InsightsRange is the period insights are computed over, [From, To), and the time zone its days are in
+type InsightsRange struct {
+	From     time.Time
+	To       time.Time
+	Timezone string
+}
+
+// DayCount is a count for one day, as YYYY-MM-DD
+type DayCount struct {
+	Day   string
+	Count int64
+}
+
+// TaskTotals are the task aggregates of a range
+type TaskTotals struct {
+	Created int64
+	// CompletedLate counts completions in the range after the due date the task had then
+	CompletedLate int64
+	// Overdue counts open tasks due before now, whatever the range
+	Overdue int64
+	// AverageCompletionSeconds is the mean time to each completion in the range, from the task's
+	// creation or, if it was completed before, its previous completion. Nil if there were none.
+	AverageCompletionSeconds *float64
+}
+
+// ProjectCount is the number of tasks completed in a project
+type ProjectCount struct {
+	ProjectID string
+	Title     *string
+	Color     *string
+	Completed int64
+}
+
+// CollectionCount is the number of notes written in a collection. A nil CollectionID
+// stands for the notes outside any collection.
+type CollectionCount struct {
+	CollectionID *string
+	Title        *string
+	Color        *string
+	Notes        int64
+}
+
+// CountCompletionsByDay returns, for each day of the range with completions, how many tasks were completed.
+// Completions come from the status history, so a recurring task counts once for each day it was completed.
+func (r *InsightsRepository) CountCompletionsByDay(ctx context.Context, userID string, period InsightsRange) ([]DayCount, error) {
+	var counts []DayCount
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT to_char(c.changed_at AT TIME ZONE @timezone, 'YYYY-MM-DD') AS day, COUNT(DISTINCT c.task_id) AS count
+		FROM task_status_changes c
+		JOIN tasks t ON t.id = c.task_id AND t.deleted_at IS NULL
+		WHERE c.user_id = @user_id AND c.to_status = @completed
+			AND c.changed_at >= @from AND c.changed_at < @to
+		GROUP BY day
+		ORDER BY day`,
+		map[string]any{
+			"timezone":  period.Timezone,
+			"user_id":   userID,
+			"completed": model.TaskStatusCompleted,
+			"from":      period.From,
+			"to":        period.To,
+		}).
+		Scan(&counts).Error
+	if err != nil {
+		logger.Log.Error("Failed to count completions by day", zap.Error(err), zap.String("userID", userID))
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// GetTaskTotals returns the range's task aggregates. Completions come from the status history,
+// so recurring tasks reopened since count for each time they were completed.
+func (r *InsightsRepository) GetTaskTotals(ctx context.Context, userID string, period InsightsRange) (*TaskTotals, error) {
+	var totals TaskTotals
+	err := r.db.WithContext(ctx).Raw(`
+		WITH completions AS (
+			SELECT c.changed_at, c.due_date, t.created_at,
+				LAG(c.changed_at) OVER (PARTITION BY c.task_id ORDER BY c.changed_at) AS previous_completed_at
+			FROM task_status_changes c
+			JOIN tasks t ON t.id = c.task_id AND t.deleted_at IS NULL
+			WHERE c.user_id = @user_id AND c.to_status = @completed AND c.changed_at < @to
+		)
+		SELECT tasks.created, tasks.overdue, completed.completed_late, completed.average_completion_seconds
+		FROM (
+			SELECT
+				COUNT(*) FILTER (WHERE created_at >= @from AND created_at < @to) AS created,
+				COUNT(*) FILTER (WHERE status IN @open AND due_date < CURRENT_TIMESTAMP) AS overdue
+			FROM tasks
+			WHERE user_id = @user_id AND deleted_at IS NULL
+		) tasks, (
+			SELECT
+				COUNT(*) FILTER (WHERE changed_at > due_date) AS completed_late,
+				AVG(EXTRACT(EPOCH FROM changed_at - COALESCE(previous_completed_at, created_at))) AS average_completion_seconds
+			FROM completions
+			WHERE changed_at >= @from
+		) completed`,
+		map[string]any{
+			"user_id":   userID,
+			"open":      openTaskStatuses,
+			"completed": model.TaskStatusCompleted,
+			"from":      period.From,
+			"to":        period.To,
+		}).
+		Scan(&totals).Error
+	if err != nil {
+		logger.Log.Error("Failed to get task totals", zap.Error(err), zap.String("userID", userID))
+		return nil, err
+	}
+
+	return &totals, nil
+}
+
+// ListBusiestProjects returns up to limit projects with the most tasks completed in the range, busiest first
+func (r *InsightsRepository) ListBusiestProjects(ctx context.Context, userID string, period InsightsRange, limit int) ([]ProjectCount, error) {
+	var counts []ProjectCount
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT p.id AS project_id, p.title, p.color, COUNT(DISTINCT c.task_id) AS completed
+		FROM task_status_changes c
+		JOIN tasks t ON t.id = c.task_id AND t.deleted_at IS NULL
+		JOIN projects p ON p.id = t.project_id AND p.deleted_at IS NULL
+		WHERE c.user_id = @user_id AND c.to_status = @completed
+			AND c.changed_at >= @from AND c.changed_at < @to
+		GROUP BY p.id, p.title, p.color
+		ORDER BY completed DESC, p.title ASC
+		LIMIT @limit`,
+		map[string]any{
+			"user_id":   userID,
+			"completed": model.TaskStatusCompleted,
+			"from":      period.From,
+			"to":        period.To,
+			"limit":     limit,
+		}).
+		Scan(&counts).Error
+	if err != nil {
+		logger.Log.Error("Failed to list busiest projects", zap.Error(err), zap.String("userID", userID))
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// CountNotesByCollection returns how many notes were created in the range per collection, most first
+func (r *InsightsRepository) CountNotesByCollection(ctx context.Context, userID string, period InsightsRange) ([]CollectionCount, error) {
+	var counts []CollectionCount
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT c.id AS collection_id, c.title, c.color, COUNT(*) AS notes
+		FROM notes n
+		LEFT JOIN collections c ON c.id = n.collection_id AND c.deleted_at IS NULL
+		WHERE n.user_id = @user_id AND n.deleted_at IS NULL
+			AND n.created_at >= @from AND n.created_at < @to
+		GROUP BY c.id, c.title, c.color
+		ORDER BY notes DESC, c.title ASC NULLS LAST`,
+		map[string]any{
+			"user_id": userID,
+			"from":    period.From,
+			"to":      period.To,
+		}).
+		Scan(&counts).Error
+	if err != nil {
+		logger.Log.Error("Failed to count notes by collection", zap.Error(err), zap.String("userID", userID))
+		return nil, err
+	}
+
+	return counts, nil
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"app/internal/contract"
+	"app/internal/model"
+	"app/pkg/util"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestTaskTotalsCountReopenedRecurringCompletions(t *testing.T) {
+	db := testDB(t)
+	syncRepo := newTestSyncRepository(t, db)
+	insightsRepo := NewInsightsRepository(db)
+	userID := testUser(t, db)
+
+	taskID := uuid.NewString()
+	applyChanges(t, syncRepo, userID, contract.Change{
+		Type:           "task",
+		EntityID:       taskID,
+		Title:          util.ToPointer("Pay rent"),
+		DueDate:        util.ToPointer(time.Now().AddDate(0, 0, -2).Format(time.RFC3339)),
+		RecurrenceRule: util.ToPointer("FREQ=WEEKLY"),
+		RecurrenceMode: util.ToPointer(model.RecurrenceModeRoll),
+	})
+
+	// Late, then on time; each completion reopens the task with its next due date
+	applyChanges(t, syncRepo, userID, contract.Change{Type: "task", EntityID: taskID, Status: util.ToPointer(model.TaskStatusCompleted)})
+	applyChanges(t, syncRepo, userID, contract.Change{Type: "task", EntityID: taskID, Status: util.ToPointer(model.TaskStatusCompleted)})
+
+	period := InsightsRange{From: time.Now().Add(-time.Hour), To: time.Now().Add(time.Hour), Timezone: "UTC"}
+	totals, err := insightsRepo.GetTaskTotals(context.Background(), userID, period)
+	if err != nil {
+		t.Fatalf("failed to get totals: %v", err)
+	}
+	if totals.CompletedLate != 1 {
+		t.Errorf("completed late = %d, want 1", totals.CompletedLate)
+	}
+	if totals.AverageCompletionSeconds == nil {
+		t.Error("average completion time is missing for a reopened task")
+	}
+
+	days, err := insightsRepo.CountCompletionsByDay(context.Background(), userID, period)
+	if err != nil {
+		t.Fatalf("failed to count completions: %v", err)
+	}
+	if len(days) != 1 || days[0].Count != 1 {
+		t.Errorf("completions by day = %+v, want the task once", days)
+	}
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"app/internal/model"
+	"app/pkg/logger"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	migrate "github.com/rubenv/sql-migrate"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+var (
+	testDBOnce sync.Once
+	testDBConn *gorm.DB
+	testDBErr  error
+)
+
+func TestMain(m *testing.M) {
+	logger.InitDefault()
+	os.Exit(m.Run())
+}
+
+// testDB returns a connection to the database in TEST_DATABASE_URL, migrated up, or skips the test
+// when it isn't set. The database needs the uuid-ossp and vector extensions, like the app's.
+// Tests don't clean up: each one works with users of its own from testUser.
+func testDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	testDBOnce.Do(func() {
+		testDBConn, testDBErr = gorm.Open(postgres.Open(dsn), &gorm.Config{
+			Logger:                 gormlogger.Default.LogMode(gormlogger.Silent),
+			SkipDefaultTransaction: true,
+			TranslateError:         true,
+		})
+		if testDBErr != nil {
+			return
+		}
+		sqlDB, err := testDBConn.DB()
+		if err != nil {
+			testDBErr = err
+			return
+		}
+		_, testDBErr = migrate.Exec(sqlDB, "postgres", &migrate.FileMigrationSource{Dir: "../database/migrations"}, migrate.Up)
+	})
+	if testDBErr != nil {
+		t.Fatalf("failed to set up the test database: %v", testDBErr)
+	}
+
+	return testDBConn
+}
+
+// testUser creates a user with a unique email and returns its ID
+func testUser(t *testing.T, db *gorm.DB) string {
+	t.Helper()
+
+	user := model.User{Name: "Test", Email: uuid.NewString() + "@example.com"}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+	return user.ID
+}
@@ -0,0 +1,444 @@
+package repository
+
+import (
+	"app/internal/model"
+	"app/pkg/logger"
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrMemberTargetNotFound = errors.New("project, collection or workspace not found")
+	ErrMemberNotFound       = errors.New("member not found")
+	ErrAlreadyMember        = errors.New("already a member of this project, collection or workspace")
+	ErrInvitationNotFound   = errors.New("invitation not found or no longer valid")
+
+	ErrShareForbidden     = errors.New("you don't have permission to change this shared item")
+	ErrShareMoveForbidden = errors.New("items can't move between projects or collections of different users")
+)
+
+// pendingInvitationSQL matches invitations that can still be accepted
+const pendingInvitationSQL = "accepted_at IS NULL AND declined_at IS NULL AND revoked_at IS NULL AND expires_at > CURRENT_TIMESTAMP"
+
+// memberWorkspaceIDsSQL selects the live workspaces @user_id owns or is a member of
+const memberWorkspaceIDsSQL = `SELECT id FROM workspaces WHERE deleted_at IS NULL AND (user_id = @user_id OR id IN (
+	SELECT workspace_id FROM memberships WHERE user_id = @user_id AND workspace_id IS NOT NULL AND removed_at IS NULL))`
+
+// ScopeContainerIDsSQL selects the projects or collections @user_id sees in a scope. The personal
+// space, for an empty workspaceID, has the user's own outside any workspace and those shared with
+// them; a workspace has its own if the user is a member. column is project_id or collection_id.
+func ScopeContainerIDsSQL(column, workspaceID string) string {
+	table := memberTarget(column, "").table()
+	if workspaceID == "" {
+		return `SELECT id FROM ` + table + ` WHERE user_id = @user_id AND workspace_id IS NULL
+			UNION SELECT ` + column + ` FROM memberships WHERE user_id = @user_id AND ` + column + ` IS NOT NULL AND removed_at IS NULL`
+	}
+	return `SELECT id FROM ` + table + ` WHERE workspace_id = @workspace_id AND workspace_id IN (` + memberWorkspaceIDsSQL + `)`
+}
+
+// scopeItemsSQL matches the rows of alias that @user_id sees in a scope, where column ties them to
+// their project or collection. The personal space also has the user's items in neither.
+func scopeItemsSQL(alias, column, workspaceID string) string {
+	inScope := alias + `.` + column + ` IN (` + ScopeContainerIDsSQL(column, workspaceID) + `)`
+	if workspaceID == "" {
+		return `((` + alias + `.user_id = @user_id AND ` + alias + `.` + column + ` IS NULL) OR ` + inScope + `)`
+	}
+	return inScope
+}
+
+// MemberTarget is the project, the collection or the workspace a membership or an invitation is for.
+// Exactly one is set.
+type MemberTarget struct {
+	ProjectID    *string
+	CollectionID *string
+	WorkspaceID  *string
+}
+
+func (t MemberTarget) id() string {
+	switch {
+	case t.ProjectID != nil:
+		return *t.ProjectID
+	case t.CollectionID != nil:
+		return *t.CollectionID
+	}
+	return *t.WorkspaceID
+}
+
+func (t MemberTarget) table() string {
+	switch {
+	case t.ProjectID != nil:
+		return "projects"
+	case t.CollectionID != nil:
+		return "collections"
+	}
+	return "workspaces"
+}
+
+// column is the column of memberships and invitations that points at the target
+func (t MemberTarget) column() string {
+	switch {
+	case t.ProjectID != nil:
+		return "project_id"
+	case t.CollectionID != nil:
+		return "collection_id"
+	}
+	return "workspace_id"
+}
+
+// MemberAccess is who owns a project, a collection or a workspace and what a user may do with it
+type MemberAccess struct {
+	OwnerID string
+	Title   *string
+	// WorkspaceID is the workspace a project or collection belongs to
+	WorkspaceID *string
+	// Role is owner for the user who owns it. On a project or collection of a workspace it is the
+	// higher of the user's role there and their role on the workspace.
+	Role string
+}
+
+type MembershipRepository struct {
+	db *gorm.DB
+}
+
+func NewMembershipRepository(db *gorm.DB) *MembershipRepository {
+	return &MembershipRepository{db: db}
+}
+
+// Access returns the user's access to a live project, collection or workspace, or nil if it is gone or the user isn't a member
+func (r *MembershipRepository) Access(ctx context.Context, userID string, target MemberTarget) (*MemberAccess, error) {
+	access, err := memberAccess(r.db.WithContext(ctx), userID, target)
+	if err != nil {
+		logger.Log.Error("Failed to get member access", zap.Error(err), zap.String("userID", userID), zap.String("targetID", target.id()))
+		return nil, err
+	}
+	if access == nil || access.Role == "" {
+		return nil, nil
+	}
+
+	return access, nil
+}
+
+// ListMembers returns the current members of a project, collection or workspace with their users, oldest first.
+// The user who owns it isn't among them.
+func (r *MembershipRepository) ListMembers(ctx context.Context, target MemberTarget) ([]model.Membership, error) {
+	var members []model.Membership
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Where(target.column()+" = ? AND removed_at IS NULL", target.id()).
+		Order("created_at ASC").
+		Find(&members).Error
+	if err != nil {
+		logger.Log.Error("Failed to list members", zap.Error(err), zap.String("targetID", target.id()))
+		return nil, err
+	}
+
+	return members, nil
+}
+
+// UpdateMemberRole changes the role of a current member. Returns false if the user isn't one.
+func (r *MembershipRepository) UpdateMemberRole(ctx context.Context, target MemberTarget, userID, role string) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Model(&model.Membership{}).
+		Where("user_id = ? AND "+target.column()+" = ? AND removed_at IS NULL", userID, target.id()).
+		Update("role", role)
+	if res.Error != nil {
+		logger.Log.Error("Failed to update member role", zap.Error(res.Error), zap.String("userID", userID), zap.String("targetID", target.id()))
+		return false, res.Error
+	}
+
+	return res.RowsAffected > 0, nil
+}
+
+// RemoveMember ends a membership. The row stays so the member's devices learn to drop what was
+// shared on their next sync. Returns false if the user isn't a member.
+func (r *MembershipRepository) RemoveMember(ctx context.Context, target MemberTarget, userID string) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Model(&model.Membership{}).
+		Where("user_id = ? AND "+target.column()+" = ? AND removed_at IS NULL", userID, target.id()).
+		Updates(map[string]any{
+			"removed_at": gorm.Expr("CURRENT_TIMESTAMP"),
+			"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+		})
+	if res.Error != nil {
+		logger.Log.Error("Failed to remove member", zap.Error(res.Error), zap.String("userID", userID), zap.String("targetID", target.id()))
+		return false, res.Error
+	}
+
+	return res.RowsAffected > 0, nil
+}
+
+// CreateInvitation saves an invitation, revoking any pending one to the same address for the same target
+func (r *MembershipRepository) CreateInvitation(ctx context.Context, invitation *model.Invitation) error {
+	target := MemberTarget{ProjectID: invitation.ProjectID, CollectionID: invitation.CollectionID, WorkspaceID: invitation.WorkspaceID}
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.Invitation{}).
+			Where(target.column()+" = ? AND LOWER(email) = LOWER(?) AND "+pendingInvitationSQL, target.id(), invitation.Email).
+			Updates(map[string]any{
+				"revoked_at": gorm.Expr("CURRENT_TIMESTAMP"),
+				"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+			}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(invitation).Error
+	})
+	if err != nil {
+		logger.Log.Error("Failed to create invitation", zap.Error(err), zap.String("targetID", target.id()))
+		return err
+	}
+
+	return nil
+}
+
+// ListInvitations returns the pending invitations of a project, collection or workspace, newest first
+func (r *MembershipRepository) ListInvitations(ctx context.Context, target MemberTarget) ([]model.Invitation, error) {
+	var invitations []model.Invitation
+	err := r.withInvitationDetails(r.db.WithContext(ctx)).
+		Where(target.column()+" = ? AND "+pendingInvitationSQL, target.id()).
+		Order("created_at DESC").
+		Find(&invitations).Error
+	if err != nil {
+		logger.Log.Error("Failed to list invitations", zap.Error(err), zap.String("targetID", target.id()))
+		return nil, err
+	}
+
+	return invitations, nil
+}
+
+// RevokeInvitation revokes a pending invitation of a project, collection or workspace. Returns false if there is no such invitation.
+func (r *MembershipRepository) RevokeInvitation(ctx context.Context, target MemberTarget, invitationID string) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Model(&model.Invitation{}).
+		Where("id = ? AND "+target.column()+" = ? AND "+pendingInvitationSQL, invitationID, target.id()).
+		Updates(map[string]any{
+			"revoked_at": gorm.Expr("CURRENT_TIMESTAMP"),
+			"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+		})
+	if res.Error != nil {
+		logger.Log.Error("Failed to revoke invitation", zap.Error(res.Error), zap.String("invitationID", invitationID))
+		return false, res.Error
+	}
+
+	return res.RowsAffected > 0, nil
+}
+
+// ListPendingInvitations returns the pending invitations sent to an email address, newest first
+func (r *MembershipRepository) ListPendingInvitations(ctx context.Context, email string) ([]model.Invitation, error) {
+	var invitations []model.Invitation
+	err := r.withInvitationDetails(r.db.WithContext(ctx)).
+		Where("LOWER(email) = LOWER(?) AND "+pendingInvitationSQL, email).
+		Order("created_at DESC").
+		Find(&invitations).Error
+	if err != nil {
+		logger.Log.Error("Failed to list pending invitations", zap.Error(err))
+		return nil, err
+	}
+
+	return invitations, nil
+}
+
+// GetPendingInvitation returns a pending invitation sent to an email address, or nil if there is none
+func (r *MembershipRepository) GetPendingInvitation(ctx context.Context, invitationID, email string) (*model.Invitation, error) {
+	var invitations []model.Invitation
+	err := r.withInvitationDetails(r.db.WithContext(ctx)).
+		Where("id = ? AND LOWER(email) = LOWER(?) AND "+pendingInvitationSQL, invitationID, email).
+		Limit(1).
+		Find(&invitations).Error
+	if err != nil {
+		logger.Log.Error("Failed to get invitation", zap.Error(err), zap.String("invitationID", invitationID))
+		return nil, err
+	}
+	if len(invitations) == 0 {
+		return nil, nil
+	}
+
+	return &invitations[0], nil
+}
+
+// GetInvitationByToken returns the pending invitation with the given token hash, or nil if there is none
+func (r *MembershipRepository) GetInvitationByToken(ctx context.Context, tokenHash string) (*model.Invitation, error) {
+	var invitations []model.Invitation
+	err := r.withInvitationDetails(r.db.WithContext(ctx)).
+		Where("token_hash = ? AND "+pendingInvitationSQL, tokenHash).
+		Limit(1).
+		Find(&invitations).Error
+	if err != nil {
+		logger.Log.Error("Failed to get invitation by token", zap.Error(err))
+		return nil, err
+	}
+	if len(invitations) == 0 {
+		return nil, nil
+	}
+
+	return &invitations[0], nil
+}
+
+// AcceptInvitation makes the user a member with the invitation's role. A user who is already
+// a member takes the new role instead.
+func (r *MembershipRepository) AcceptInvitation(ctx context.Context, userID, invitationID string) (*model.Membership, error) {
+	var membership model.Membership
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var invitations []model.Invitation
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND "+pendingInvitationSQL, invitationID).
+			Limit(1).
+			Find(&invitations).Error
+		if err != nil {
+			return err
+		}
+		if len(invitations) == 0 {
+			return ErrInvitationNotFound
+		}
+		invitation := invitations[0]
+		target := MemberTarget{ProjectID: invitation.ProjectID, CollectionID: invitation.CollectionID, WorkspaceID: invitation.WorkspaceID}
+
+		var ownerIDs []string
+		err = tx.Table(target.table()).
+			Where("id = ? AND deleted_at IS NULL", target.id()).
+			Pluck("user_id", &ownerIDs).Error
+		if err != nil {
+			return err
+		}
+		if len(ownerIDs) == 0 {
+			return ErrMemberTargetNotFound
+		}
+		if ownerIDs[0] == userID {
+			return ErrAlreadyMember
+		}
+
+		res := tx.Model(&model.Membership{}).
+			Where("user_id = ? AND "+target.column()+" = ? AND removed_at IS NULL", userID, target.id()).
+			Updates(map[string]any{"role": invitation.Role, "invited_by": invitation.InviterID})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			created := &model.Membership{
+				UserID:       userID,
+				ProjectID:    invitation.ProjectID,
+				CollectionID: invitation.CollectionID,
+				WorkspaceID:  invitation.WorkspaceID,
+				Role:         invitation.Role,
+				InvitedBy:    &invitation.InviterID,
+			}
+			if err := tx.Create(created).Error; err != nil {
+				return err
+			}
+		}
+
+		err = tx.Where("user_id = ? AND "+target.column()+" = ? AND removed_at IS NULL", userID, target.id()).
+			First(&membership).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&model.Invitation{}).
+			Where("id = ?", invitation.ID).
+			Updates(map[string]any{
+				"accepted_by": userID,
+				"accepted_at": gorm.Expr("CURRENT_TIMESTAMP"),
+				"updated_at":  gorm.Expr("CURRENT_TIMESTAMP"),
+			}).Error
+	})
+	if err != nil {
+		if !errors.Is(err, ErrInvitationNotFound) && !errors.Is(err, ErrMemberTargetNotFound) && !errors.Is(err, ErrAlreadyMember) {
+			logger.Log.Error("Failed to accept invitation", zap.Error(err), zap.String("invitationID", invitationID))
+		}
+		return nil, err
+	}
+
+	return &membership, nil
+}
+
+// DeclineInvitation declines a pending invitation. Returns false if it is no longer pending.
+func (r *MembershipRepository) DeclineInvitation(ctx context.Context, invitationID string) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Model(&model.Invitation{}).
+		Where("id = ? AND "+pendingInvitationSQL, invitationID).
+		Updates(map[string]any{
+			"declined_at": gorm.Expr("CURRENT_TIMESTAMP"),
+			"updated_at":  gorm.Expr("CURRENT_TIMESTAMP"),
+		})
+	if res.Error != nil {
+		logger.Log.Error("Failed to decline invitation", zap.Error(res.Error), zap.String("invitationID", invitationID))
+		return false, res.Error
+	}
+
+	return res.RowsAffected > 0, nil
+}
+
+// withInvitationDetails preloads who sent each invitation and the title of what it is for
+func (r *MembershipRepository) withInvitationDetails(tx *gorm.DB) *gorm.DB {
+	return tx.Preload("Inviter").
+		Preload("Project", func(tx *gorm.DB) *gorm.DB { return tx.Select("id", "title") }).
+		Preload("Collection", func(tx *gorm.DB) *gorm.DB { return tx.Select("id", "title") }).
+		Preload("Workspace", func(tx *gorm.DB) *gorm.DB { return tx.Select("id", "title") })
+}
+
+// memberAccess returns who owns a live project, collection or workspace and the user's role on it,
+// which is empty when the user isn't a member. Returns nil if it is gone.
+//
+// The rows of a workspace's projects and collections are stored under the workspace owner's
+// account, so holding them grants nothing: roles there come from the workspace and from memberships.
+func memberAccess(tx *gorm.DB, userID string, target MemberTarget) (*MemberAccess, error) {
+	args := map[string]any{
+		"user_id": userID,
+		"owner":   model.MemberRoleOwner,
+		"id":      target.id(),
+	}
+	if target.WorkspaceID != nil {
+		var rows []MemberAccess
+		err := tx.Raw(`
+			SELECT t.user_id AS owner_id, t.title,
+				CASE WHEN t.user_id = @user_id THEN @owner ELSE COALESCE(m.role, '') END AS role
+			FROM workspaces t
+			LEFT JOIN memberships m ON m.workspace_id = t.id AND m.user_id = @user_id AND m.removed_at IS NULL
+			WHERE t.id = @id AND t.deleted_at IS NULL`, args).Scan(&rows).Error
+		if err != nil {
+			return nil, err
+		}
+		if len(rows) == 0 {
+			return nil, nil
+		}
+		return &rows[0], nil
+	}
+
+	var rows []struct {
+		MemberAccess
+		WorkspaceRole string
+	}
+	err := tx.Raw(`
+		SELECT t.user_id AS owner_id, t.title, t.workspace_id,
+			CASE WHEN t.user_id = @user_id AND t.workspace_id IS NULL THEN @owner ELSE COALESCE(m.role, '') END AS role,
+			CASE WHEN w.user_id = @user_id THEN @owner ELSE COALESCE(wm.role, '') END AS workspace_role
+		FROM `+target.table()+` t
+		LEFT JOIN memberships m ON m.`+target.column()+` = t.id AND m.user_id = @user_id AND m.removed_at IS NULL
+		LEFT JOIN workspaces w ON w.id = t.workspace_id AND w.deleted_at IS NULL
+		LEFT JOIN memberships wm ON wm.workspace_id = w.id AND wm.user_id = @user_id AND wm.removed_at IS NULL
+		WHERE t.id = @id AND t.deleted_at IS NULL`, args).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	access := rows[0].MemberAccess
+	access.Role = model.HigherMemberRole(access.Role, rows[0].WorkspaceRole)
+	return &access, nil
+}
+
+// memberTarget returns the target a project_id, collection_id or workspace_id column points at
+func memberTarget(column, id string) MemberTarget {
+	switch column {
+	case "project_id":
+		return MemberTarget{ProjectID: &id}
+	case "collection_id":
+		return MemberTarget{CollectionID: &id}
+	}
+	return MemberTarget{WorkspaceID: &id}
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"app/internal/model"
+	"app/pkg/logger"
+	"context"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type NoteRepository struct {
+	db *gorm.DB
+}
+
+func NewNoteRepository(db *gorm.DB) *NoteRepository {
+	return &NoteRepository{db: db}
+}
+
+// GetNoteByID retrieves a non-deleted note by ID with user validation
+func (r *NoteRepository) GetNoteByID(ctx context.Context, noteID, userID string) (*model.Note, error) {
+	var note model.Note
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ? AND deleted_at IS NULL", noteID, userID).
+		First(&note).Error
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		logger.Log.Error("Failed to get note", zap.Error(err), zap.String("noteID", noteID), zap.String("userID", userID))
+		return nil, err
+	}
+
+	return &note, nil
+}
+
+// RelatedNoteFilters represents filters for related note lookup
+type RelatedNoteFilters struct {
+	CollectionID *string
+	MinScore     float64
+	Limit        int
+}
+
+// RelatedNote is a note together with its cosine similarity to the source note
+type RelatedNote struct {
+	model.Note
+	Score float64
+}
+
+// GetRelatedNotes returns the notes whose embeddings are closest to the source note's embedding
+func (r *NoteRepository) GetRelatedNotes(ctx context.Context, userID string, source *model.Note, filters RelatedNoteFilters) ([]RelatedNote, error) {
+	var related []RelatedNote
+
+	query := `SELECT id, user_id, collection_id, title, content, created_at, updated_at, deleted_at, 1 - (embedding <=> ?) AS score
+		FROM notes
+		WHERE user_id = ? AND id <> ? AND deleted_at IS NULL AND embedding IS NOT NULL
+		AND 1 - (embedding <=> ?) >= ?`
+	args := []interface{}{source.Embedding, userID, source.ID, source.Embedding, filters.MinScore}
+
+	if filters.CollectionID != nil && *filters.CollectionID != "" {
+		query += " AND collection_id = ?"
+		args = append(args, *filters.CollectionID)
+	}
+
+	query += " ORDER BY embedding <=> ? LIMIT ?"
+	args = append(args, source.Embedding, filters.Limit)
+
+	err := r.db.WithContext(ctx).Raw(query, args...).Scan(&related).Error
+	if err != nil {
+		logger.Log.Error("Failed to get related notes", zap.Error(err), zap.String("noteID", source.ID), zap.String("userID", userID))
+		return nil, err
+	}
+
+	return related, nil
+}
+
+// GetBacklinks returns the non-deleted notes that link to the given note
+func (r *NoteRepository) GetBacklinks(ctx context.Context, noteID, userID string) ([]model.Note, error) {
+	var notes []model.Note
+
+	err := r.db.WithContext(ctx).
+		Select("id, user_id, collection_id, title, created_at, updated_at").
+		Where("user_id = ? AND deleted_at IS NULL", userID).
+		Where("id IN (SELECT source_note_id FROM note_links WHERE target_note_id = ? AND user_id = ?)", noteID, userID).
+		Order("updated_at DESC").
+		Find(&notes).Error
+	if err != nil {
+		logger.Log.Error("Failed to get backlinks", zap.Error(err), zap.String("noteID", noteID), zap.String("userID", userID))
+		return nil, err
+	}
+
+	return notes, nil
+}
+
+// DanglingLink is a link whose target doesn't match any live note
+type DanglingLink struct {
+	SourceNoteID string
+	SourceTitle  *string
+	TargetTitle  string
+}
+
+// ListDanglingLinks returns links that are unresolved or point at a deleted note
+func (r *NoteRepository) ListDanglingLinks(ctx context.Context, userID string) ([]DanglingLink, error) {
+	var links []DanglingLink
+
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT l.source_note_id, s.title AS source_title, l.target_title
+		FROM note_links l
+		JOIN notes s ON s.id = l.source_note_id AND s.deleted_at IS NULL
+		LEFT JOIN notes t ON t.id = l.target_note_id
+		WHERE l.user_id = ? AND (l.target_note_id IS NULL OR t.deleted_at IS NOT NULL)
+		ORDER BY l.target_title ASC, s.updated_at DESC`, userID).
+		Scan(&links).Error
+	if err != nil {
+		logger.Log.Error("Failed to list dangling links", zap.Error(err), zap.String("userID", userID))
+		return nil, err
+	}
+
+	return links, nil
+}
+
+// ResolveDanglingLinks re-points dangling links at live notes whose title matches,
+// returning the number of links that were resolved
+func (r *NoteRepository) ResolveDanglingLinks(ctx context.Context, userID string) (int64, error) {
+	res := r.db.WithContext(ctx).Exec(`
+		UPDATE note_links l
+		SET target_note_id = (
+			SELECT n.id FROM notes n
+			WHERE n.user_id = l.user_id AND n.deleted_at IS NULL AND LOWER(n.title) = LOWER(l.target_title)
+			ORDER BY n.updated_at DESC
+			LIMIT 1
+		)
+		WHERE l.user_id = ?
+		AND (l.target_note_id IS NULL OR EXISTS (SELECT 1 FROM notes t WHERE t.id = l.target_note_id AND t.deleted_at IS NOT NULL))
+		AND EXISTS (
+			SELECT 1 FROM notes n
+			WHERE n.user_id = l.user_id AND n.deleted_at IS NULL AND LOWER(n.title) = LOWER(l.target_title)
+		)`, userID)
+	if res.Error != nil {
+		logger.Log.Error("Failed to resolve dangling links", zap.Error(res.Error), zap.String("userID", userID))
+		return 0, res.Error
+	}
+
+	return res.RowsAffected, nil
+}
+
+// GetNoteGraph returns every live note and the resolved links between them
+func (r *NoteRepository) GetNoteGraph(ctx context.Context, userID string) (notes []model.Note, links []model.NoteLink, err error) {
+	err = r.db.WithContext(ctx).
+		Select("id, user_id, collection_id, title, created_at, updated_at").
+		Where("user_id = ? AND deleted_at IS NULL", userID).
+		Find(&notes).Error
+	if err != nil {
+		logger.Log.Error("Failed to get graph nodes", zap.Error(err), zap.String("userID", userID))
+		return nil, nil, err
+	}
+
+	err = r.db.WithContext(ctx).Raw(`
+		SELECT l.id, l.user_id, l.source_note_id, l.target_note_id, l.target_title, l.created_at
+		FROM note_links l
+		JOIN notes s ON s.id = l.source_note_id AND s.deleted_at IS NULL
+		JOIN notes t ON t.id = l.target_note_id AND t.deleted_at IS NULL
+		WHERE l.user_id = ? AND l.source_note_id <> l.target_note_id`, userID).
+		Scan(&links).Error
+	if err != nil {
+		logger.Log.Error("Failed to get graph edges", zap.Error(err), zap.String("userID", userID))
+		return nil, nil, err
+	}
+
+	return notes, links, nil
+}
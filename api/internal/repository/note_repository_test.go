@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"app/internal/contract"
+	"app/internal/model"
+	"app/pkg/util"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pgvector/pgvector-go"
+	"gorm.io/gorm"
+)
+
+// testNoteWithEmbedding creates a note of the user's whose embedding points along the given weights
+// of its first dimensions
+func testNoteWithEmbedding(t *testing.T, db *gorm.DB, repo *SyncRepository, userID string, weights ...float32) *model.Note {
+	t.Helper()
+
+	noteID := uuid.NewString()
+	applyChanges(t, repo, userID, contract.Change{Type: "note", EntityID: noteID, Title: util.ToPointer("Note")})
+
+	values := make([]float32, 1536)
+	copy(values, weights)
+	embedding := pgvector.NewVector(values)
+	if err := db.Model(&model.Note{}).Where("id = ?", noteID).Update("embedding", embedding).Error; err != nil {
+		t.Fatalf("failed to set embedding: %v", err)
+	}
+	return &model.Note{ID: noteID, UserID: userID, Embedding: &embedding}
+}
+
+func TestGetRelatedNotesOrdersBySimilarity(t *testing.T) {
+	db := testDB(t)
+	repo := newTestSyncRepository(t, db)
+	noteRepo := NewNoteRepository(db)
+	userID, otherID := testUser(t, db), testUser(t, db)
+
+	source := testNoteWithEmbedding(t, db, repo, userID, 1, 0, 0)
+	closest := testNoteWithEmbedding(t, db, repo, userID, 1, 0.1, 0)
+	near := testNoteWithEmbedding(t, db, repo, userID, 1, 0.5, 0)
+	testNoteWithEmbedding(t, db, repo, userID, 0, 0, 1)
+	testNoteWithEmbedding(t, db, repo, otherID, 1, 0, 0)
+
+	related, err := noteRepo.GetRelatedNotes(context.Background(), userID, source, RelatedNoteFilters{MinScore: 0.5, Limit: 10})
+	if err != nil {
+		t.Fatalf("failed to get related notes: %v", err)
+	}
+	if len(related) != 2 {
+		t.Fatalf("got %d related notes, want the 2 of the user's above the minimum score", len(related))
+	}
+	if related[0].ID != closest.ID || related[1].ID != near.ID {
+		t.Errorf("related notes are %s, %s, want the closest first", related[0].ID, related[1].ID)
+	}
+	if related[0].Score <= related[1].Score || related[0].Score > 1 {
+		t.Errorf("scores are %v, %v, want them descending and at most 1", related[0].Score, related[1].Score)
+	}
+}
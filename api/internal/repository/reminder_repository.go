@@ -0,0 +1,266 @@
+package repository
+
+import (
+	"app/internal/model"
+	"app/pkg/logger"
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReminderRepository struct {
+	db *gorm.DB
+}
+
+func NewReminderRepository(db *gorm.DB) *ReminderRepository {
+	return &ReminderRepository{db: db}
+}
+
+// ReminderDeliveryFilters represents filters for the delivery log
+type ReminderDeliveryFilters struct {
+	TaskID *string
+	Status *string
+	Limit  int
+	Offset int
+}
+
+// EnqueueDueReminders records a pending delivery on the channel for every reminder that fell due
+// after since. Existing deliveries are left alone, so each reminder is enqueued once.
+func (r *ReminderRepository) EnqueueDueReminders(ctx context.Context, channel string, since time.Time) (int64, error) {
+	// Task offsets override the user's default. Anything that isn't a JSON array means no reminders.
+	query := `
+		INSERT INTO reminder_deliveries (user_id, task_id, due_date, offset_minutes, channel, remind_at)
+		SELECT t.user_id, t.id, t.due_date, o.offset_minutes, @channel, t.due_date - make_interval(mins => o.offset_minutes)
+		FROM tasks t
+		JOIN users u ON u.id = t.user_id AND u.deleted_at IS NULL
+		CROSS JOIN LATERAL (
+			SELECT DISTINCT value::int AS offset_minutes
+			FROM jsonb_array_elements_text(CASE
+				WHEN jsonb_typeof(COALESCE(t.reminder_offsets, u.reminder_offsets)) = 'array'
+				THEN COALESCE(t.reminder_offsets, u.reminder_offsets)
+				ELSE '[]'::jsonb
+			END)
+		) o
+		WHERE t.deleted_at IS NULL
+			AND t.due_date IS NOT NULL
+			AND t.status NOT IN @done
+			AND t.due_date - make_interval(mins => o.offset_minutes) <= @now
+			AND t.due_date - make_interval(mins => o.offset_minutes) > @since
+		ON CONFLICT (task_id, due_date, offset_minutes, channel) DO NOTHING`
+
+	res := r.db.WithContext(ctx).Exec(query, map[string]any{
+		"channel": channel,
+		"done":    []int{model.TaskStatusCompleted, model.TaskStatusCancelled},
+		"now":     time.Now(),
+		"since":   since,
+	})
+	if res.Error != nil {
+		logger.Log.Error("Failed to enqueue due reminders", zap.Error(res.Error), zap.String("channel", channel))
+		return 0, res.Error
+	}
+
+	return res.RowsAffected, nil
+}
+
+// ClaimDeliveries marks up to limit deliveries that are ready to be attempted as sending and returns them.
+// Rows claimed by another worker are skipped.
+func (r *ReminderRepository) ClaimDeliveries(ctx context.Context, limit int) ([]model.ReminderDelivery, error) {
+	query := `
+		UPDATE reminder_deliveries
+		SET status = @sending, attempts = attempts + 1, updated_at = CURRENT_TIMESTAMP
+		WHERE id IN (
+			SELECT id FROM reminder_deliveries
+			WHERE status IN @ready AND attempts < @max_attempts AND next_attempt_at <= CURRENT_TIMESTAMP
+			ORDER BY remind_at
+			LIMIT @limit
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`
+
+	var deliveries []model.ReminderDelivery
+	err := r.db.WithContext(ctx).Raw(query, map[string]any{
+		"sending":      model.ReminderStatusSending,
+		"ready":        []string{model.ReminderStatusPending, model.ReminderStatusFailed},
+		"max_attempts": model.ReminderMaxAttempts,
+		"limit":        limit,
+	}).Scan(&deliveries).Error
+	if err != nil {
+		logger.Log.Error("Failed to claim reminder deliveries", zap.Error(err))
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// AbandonStaleDeliveries gives up on deliveries left in sending by a worker that stopped mid-send.
+// They are not retried because the reminder may already have gone out.
+func (r *ReminderRepository) AbandonStaleDeliveries(ctx context.Context, olderThan time.Duration) (int64, error) {
+	res := r.db.WithContext(ctx).
+		Model(&model.ReminderDelivery{}).
+		Where("status = ? AND updated_at < ?", model.ReminderStatusSending, time.Now().Add(-olderThan)).
+		Updates(map[string]any{
+			"status":     model.ReminderStatusFailed,
+			"attempts":   model.ReminderMaxAttempts,
+			"last_error": "delivery was interrupted and may or may not have been sent",
+			"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+		})
+	if res.Error != nil {
+		logger.Log.Error("Failed to abandon stale reminder deliveries", zap.Error(res.Error))
+		return 0, res.Error
+	}
+
+	return res.RowsAffected, nil
+}
+
+// MarkDelivery records the outcome of a delivery attempt
+func (r *ReminderRepository) MarkDelivery(ctx context.Context, id, status string, lastError *string, nextAttemptAt *time.Time) error {
+	updates := map[string]any{
+		"status":     status,
+		"last_error": lastError,
+		"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+	}
+	if status == model.ReminderStatusSent {
+		updates["sent_at"] = gorm.Expr("CURRENT_TIMESTAMP")
+	}
+	if nextAttemptAt != nil {
+		updates["next_attempt_at"] = *nextAttemptAt
+	}
+
+	err := r.db.WithContext(ctx).
+		Model(&model.ReminderDelivery{}).
+		Where("id = ?", id).
+		Updates(updates).Error
+	if err != nil {
+		logger.Log.Error("Failed to mark reminder delivery", zap.Error(err), zap.String("deliveryID", id), zap.String("status", status))
+		return err
+	}
+
+	return nil
+}
+
+// GetDeliveryTask returns the delivery's task with its user, or nil if the task
+// has since been completed, deleted or moved to another due date
+func (r *ReminderRepository) GetDeliveryTask(ctx context.Context, delivery *model.ReminderDelivery) (*model.Task, error) {
+	var tasks []model.Task
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Preload("Project").
+		Where("id = ? AND deleted_at IS NULL AND due_date = ?", delivery.TaskID, delivery.DueDate).
+		Where("status NOT IN ?", []int{model.TaskStatusCompleted, model.TaskStatusCancelled}).
+		Limit(1).
+		Find(&tasks).Error
+	if err != nil {
+		logger.Log.Error("Failed to get reminder task", zap.Error(err), zap.String("taskID", delivery.TaskID))
+		return nil, err
+	}
+	if len(tasks) == 0 {
+		return nil, nil
+	}
+
+	return &tasks[0], nil
+}
+
+// ListDeliveries returns the user's delivery log, newest first
+func (r *ReminderRepository) ListDeliveries(ctx context.Context, userID string, filters ReminderDeliveryFilters) ([]model.ReminderDelivery, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.ReminderDelivery{}).Where("user_id = ?", userID)
+	if filters.TaskID != nil {
+		query = query.Where("task_id = ?", *filters.TaskID)
+	}
+	if filters.Status != nil {
+		query = query.Where("status = ?", *filters.Status)
+	}
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		logger.Log.Error("Failed to count reminder deliveries", zap.Error(err), zap.String("userID", userID))
+		return nil, 0, err
+	}
+
+	var deliveries []model.ReminderDelivery
+	err := query.
+		Order("remind_at DESC, channel ASC").
+		Limit(filters.Limit).
+		Offset(filters.Offset).
+		Find(&deliveries).Error
+	if err != nil {
+		logger.Log.Error("Failed to list reminder deliveries", zap.Error(err), zap.String("userID", userID))
+		return nil, 0, err
+	}
+
+	return deliveries, total, nil
+}
+
+// UpdateUserReminderOffsets sets the default reminder offsets for the user's tasks
+func (r *ReminderRepository) UpdateUserReminderOffsets(ctx context.Context, userID string, offsets []int) error {
+	err := r.db.WithContext(ctx).
+		Model(&model.User{}).
+		Where("id = ?", userID).
+		Update("reminder_offsets", datatypes.NewJSONSlice(offsets)).Error
+	if err != nil {
+		logger.Log.Error("Failed to update reminder offsets", zap.Error(err), zap.String("userID", userID))
+		return err
+	}
+
+	return nil
+}
+
+// RegisterDeviceToken stores a push token for the user, taking it over if another account had it
+func (r *ReminderRepository) RegisterDeviceToken(ctx context.Context, userID, token, platform string) error {
+	deviceToken := &model.DeviceToken{
+		UserID:   userID,
+		Token:    token,
+		Platform: platform,
+	}
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "token"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"user_id":    userID,
+			"platform":   platform,
+			"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+		}),
+	}).Create(deviceToken).Error
+	if err != nil {
+		logger.Log.Error("Failed to register device token", zap.Error(err), zap.String("userID", userID))
+		return err
+	}
+
+	return nil
+}
+
+// DeleteDeviceTokens removes the given push tokens. An empty userID removes them for any user.
+func (r *ReminderRepository) DeleteDeviceTokens(ctx context.Context, userID string, tokens []string) error {
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	query := r.db.WithContext(ctx).Where("token IN ?", tokens)
+	if userID != "" {
+		query = query.Where("user_id = ?", userID)
+	}
+	if err := query.Delete(&model.DeviceToken{}).Error; err != nil {
+		logger.Log.Error("Failed to delete device tokens", zap.Error(err), zap.String("userID", userID))
+		return err
+	}
+
+	return nil
+}
+
+// ListDeviceTokens returns the user's push tokens
+func (r *ReminderRepository) ListDeviceTokens(ctx context.Context, userID string) ([]string, error) {
+	var tokens []string
+	err := r.db.WithContext(ctx).
+		Model(&model.DeviceToken{}).
+		Where("user_id = ?", userID).
+		Pluck("token", &tokens).Error
+	if err != nil {
+		logger.Log.Error("Failed to list device tokens", zap.Error(err), zap.String("userID", userID))
+		return nil, err
+	}
+
+	return tokens, nil
+}
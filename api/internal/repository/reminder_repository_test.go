@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"app/internal/contract"
+	"app/internal/model"
+	"app/pkg/util"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// taskDeliveries returns the reminder deliveries of a task
+func taskDeliveries(t *testing.T, db *gorm.DB, taskID string) []model.ReminderDelivery {
+	t.Helper()
+
+	var deliveries []model.ReminderDelivery
+	if err := db.Where("task_id = ?", taskID).Order("offset_minutes").Find(&deliveries).Error; err != nil {
+		t.Fatalf("failed to list deliveries: %v", err)
+	}
+	return deliveries
+}
+
+func TestEnqueueDueRemindersOncePerOffset(t *testing.T) {
+	db := testDB(t)
+	repo := newTestSyncRepository(t, db)
+	reminderRepo := NewReminderRepository(db)
+	userID := testUser(t, db)
+	ctx := context.Background()
+
+	taskID := uuid.NewString()
+	applyChanges(t, repo, userID, contract.Change{
+		Type:            "task",
+		EntityID:        taskID,
+		Title:           util.ToPointer("Call the bank"),
+		DueDate:         util.ToPointer(time.Now().Add(30 * time.Minute).Format(time.RFC3339)),
+		ReminderOffsets: &[]int{60, 5},
+	})
+
+	since := time.Now().Add(-24 * time.Hour)
+	for range 2 {
+		if _, err := reminderRepo.EnqueueDueReminders(ctx, model.ReminderChannelEmail, since); err != nil {
+			t.Fatalf("failed to enqueue reminders: %v", err)
+		}
+	}
+
+	deliveries := taskDeliveries(t, db, taskID)
+	if len(deliveries) != 1 {
+		t.Fatalf("enqueued %d deliveries, want 1 for the offset that fell due", len(deliveries))
+	}
+	if deliveries[0].OffsetMinutes != 60 || deliveries[0].Status != model.ReminderStatusPending {
+		t.Errorf("enqueued offset %d as %s, want 60 as pending", deliveries[0].OffsetMinutes, deliveries[0].Status)
+	}
+}
+
+func TestEnqueueDueRemindersSkipsDoneTasks(t *testing.T) {
+	db := testDB(t)
+	repo := newTestSyncRepository(t, db)
+	reminderRepo := NewReminderRepository(db)
+	userID := testUser(t, db)
+
+	taskID := uuid.NewString()
+	applyChanges(t, repo, userID, contract.Change{
+		Type:            "task",
+		EntityID:        taskID,
+		Title:           util.ToPointer("Already done"),
+		Status:          util.ToPointer(model.TaskStatusCompleted),
+		DueDate:         util.ToPointer(time.Now().Add(time.Minute).Format(time.RFC3339)),
+		ReminderOffsets: &[]int{10},
+	})
+
+	if _, err := reminderRepo.EnqueueDueReminders(context.Background(), model.ReminderChannelEmail, time.Now().Add(-24*time.Hour)); err != nil {
+		t.Fatalf("failed to enqueue reminders: %v", err)
+	}
+	if deliveries := taskDeliveries(t, db, taskID); len(deliveries) != 0 {
+		t.Errorf("enqueued %d deliveries for a completed task, want none", len(deliveries))
+	}
+}
@@ -0,0 +1,442 @@
+package repository
+
+import (
+	"app/internal/model"
+	"app/pkg/logger"
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrRuleNotFound = errors.New("rule not found")
+
+// RuleExecutionFilters represents filters for a rule's execution log
+type RuleExecutionFilters struct {
+	Status *string
+	Limit  int
+	Offset int
+}
+
+// RuleTarget is an entity a scheduled rule fires for, with the key it fires under
+type RuleTarget struct {
+	ID         string
+	TriggerKey string
+}
+
+type RuleRepository struct {
+	db *gorm.DB
+}
+
+func NewRuleRepository(db *gorm.DB) *RuleRepository {
+	return &RuleRepository{db: db}
+}
+
+func (r *RuleRepository) CreateRule(ctx context.Context, rule *model.Rule) error {
+	if err := r.db.WithContext(ctx).Create(rule).Error; err != nil {
+		logger.Log.Error("Failed to create rule", zap.Error(err), zap.String("userID", rule.UserID))
+		return err
+	}
+
+	return nil
+}
+
+// ListRules returns the user's live rules of the personal space, for an empty workspaceID, or of
+// a workspace, oldest first
+func (r *RuleRepository) ListRules(ctx context.Context, userID, workspaceID string) ([]model.Rule, error) {
+	query := r.db.WithContext(ctx).Where("user_id = ? AND deleted_at IS NULL", userID)
+	if workspaceID != "" {
+		query = query.Where("workspace_id = ?", workspaceID)
+	} else {
+		query = query.Where("workspace_id IS NULL")
+	}
+
+	var rules []model.Rule
+	if err := query.Order("created_at ASC").Find(&rules).Error; err != nil {
+		logger.Log.Error("Failed to list rules", zap.Error(err), zap.String("userID", userID))
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// GetRule returns a live rule of the user's, or nil if there is none
+func (r *RuleRepository) GetRule(ctx context.Context, userID, ruleID string) (*model.Rule, error) {
+	var rules []model.Rule
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ? AND deleted_at IS NULL", ruleID, userID).
+		Limit(1).
+		Find(&rules).Error
+	if err != nil {
+		logger.Log.Error("Failed to get rule", zap.Error(err), zap.String("ruleID", ruleID))
+		return nil, err
+	}
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	return &rules[0], nil
+}
+
+// UpdateRule changes the given columns of a live rule of the user's
+func (r *RuleRepository) UpdateRule(ctx context.Context, userID, ruleID string, updates map[string]any) error {
+	updates["updated_at"] = gorm.Expr("CURRENT_TIMESTAMP")
+	res := r.db.WithContext(ctx).
+		Model(&model.Rule{}).
+		Where("id = ? AND user_id = ? AND deleted_at IS NULL", ruleID, userID).
+		Updates(updates)
+	if res.Error != nil {
+		logger.Log.Error("Failed to update rule", zap.Error(res.Error), zap.String("ruleID", ruleID))
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrRuleNotFound
+	}
+
+	return nil
+}
+
+// DeleteRule deletes a rule of the user's. Its execution log stays until the rule is purged with its user.
+func (r *RuleRepository) DeleteRule(ctx context.Context, userID, ruleID string) error {
+	res := r.db.WithContext(ctx).
+		Model(&model.Rule{}).
+		Where("id = ? AND user_id = ? AND deleted_at IS NULL", ruleID, userID).
+		Updates(map[string]any{
+			"deleted_at": gorm.Expr("CURRENT_TIMESTAMP"),
+			"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+		})
+	if res.Error != nil {
+		logger.Log.Error("Failed to delete rule", zap.Error(res.Error), zap.String("ruleID", ruleID))
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrRuleNotFound
+	}
+
+	return nil
+}
+
+// ListEventRules returns the active rules with one of the triggers that run where a change was made:
+// every member's rules of a workspace, or the given users' rules of the personal space
+func (r *RuleRepository) ListEventRules(ctx context.Context, workspaceID string, userIDs, triggers []string) ([]model.Rule, error) {
+	query := r.db.WithContext(ctx).Where("trigger IN ? AND active AND deleted_at IS NULL", triggers)
+	if workspaceID != "" {
+		query = query.Where("workspace_id = ?", workspaceID)
+	} else {
+		query = query.Where("workspace_id IS NULL AND user_id IN ?", userIDs)
+	}
+
+	var rules []model.Rule
+	if err := query.Order("created_at ASC").Find(&rules).Error; err != nil {
+		logger.Log.Error("Failed to list event rules", zap.Error(err), zap.String("workspaceID", workspaceID))
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// ListScheduledRules returns the active rules with a trigger checked on a schedule
+func (r *RuleRepository) ListScheduledRules(ctx context.Context, trigger string) ([]model.Rule, error) {
+	var rules []model.Rule
+	err := r.db.WithContext(ctx).
+		Where("trigger = ? AND active AND deleted_at IS NULL", trigger).
+		Order("created_at ASC").
+		Find(&rules).Error
+	if err != nil {
+		logger.Log.Error("Failed to list scheduled rules", zap.Error(err), zap.String("trigger", trigger))
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// OverdueTasks returns the open tasks in a rule's space that are past their due date and the rule
+// hasn't fired for since they were given it
+func (r *RuleRepository) OverdueTasks(ctx context.Context, rule *model.Rule, limit int) ([]RuleTarget, error) {
+	workspaceID := ""
+	if rule.WorkspaceID != nil {
+		workspaceID = *rule.WorkspaceID
+	}
+
+	var targets []RuleTarget
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT t.id, 'overdue:' || to_char(t.due_date AT TIME ZONE 'UTC', 'YYYY-MM-DD"T"HH24:MI:SS"Z"') AS trigger_key
+		FROM tasks t
+		WHERE `+scopeItemsSQL("t", "project_id", workspaceID)+`
+			AND t.deleted_at IS NULL
+			AND t.status IN @open
+			AND t.due_date < @now
+			AND NOT EXISTS (
+				SELECT 1 FROM rule_executions e
+				WHERE e.rule_id = @rule_id AND e.entity_id = t.id
+					AND e.trigger_key = 'overdue:' || to_char(t.due_date AT TIME ZONE 'UTC', 'YYYY-MM-DD"T"HH24:MI:SS"Z"')
+			)
+		ORDER BY t.due_date ASC
+		LIMIT @limit`, map[string]any{
+		"user_id":      rule.UserID,
+		"workspace_id": workspaceID,
+		"open":         []int{model.TaskStatusPending, model.TaskStatusInProgress},
+		"now":          time.Now(),
+		"rule_id":      rule.ID,
+		"limit":        limit,
+	}).Scan(&targets).Error
+	if err != nil {
+		logger.Log.Error("Failed to list overdue tasks", zap.Error(err), zap.String("ruleID", rule.ID))
+		return nil, err
+	}
+
+	return targets, nil
+}
+
+// EntitySnapshot returns the columns of a task or note as JSON values, or nil if there is no such row
+func (r *RuleRepository) EntitySnapshot(ctx context.Context, entityType, entityID string) (map[string]any, error) {
+	snapshot, err := entitySnapshot(r.db.WithContext(ctx), entityType, entityID)
+	if err != nil {
+		logger.Log.Error("Failed to get entity snapshot", zap.Error(err), zap.String("entityType", entityType), zap.String("entityID", entityID))
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// EntityTagIDs returns the IDs of the tags on a task or note
+func (r *RuleRepository) EntityTagIDs(ctx context.Context, entityType, entityID string) ([]string, error) {
+	table, column := "task_tags", "task_id"
+	if entityType == "note" {
+		table, column = "note_tags", "note_id"
+	}
+
+	var tagIDs []string
+	err := r.db.WithContext(ctx).
+		Table(table).
+		Where(column+" = ?", entityID).
+		Order("tag_id ASC").
+		Pluck("tag_id", &tagIDs).Error
+	if err != nil {
+		logger.Log.Error("Failed to get entity tags", zap.Error(err), zap.String("entityType", entityType), zap.String("entityID", entityID))
+		return nil, err
+	}
+
+	return tagIDs, nil
+}
+
+// ExistingTaskIDs returns which of the IDs are taken by a task, deleted or not
+func (r *RuleRepository) ExistingTaskIDs(ctx context.Context, taskIDs []string) ([]string, error) {
+	if len(taskIDs) == 0 {
+		return nil, nil
+	}
+
+	var existing []string
+	err := r.db.WithContext(ctx).
+		Model(&model.Task{}).
+		Where("id IN ?", taskIDs).
+		Pluck("id", &existing).Error
+	if err != nil {
+		logger.Log.Error("Failed to get existing tasks", zap.Error(err))
+		return nil, err
+	}
+
+	return existing, nil
+}
+
+// CreateExecution logs that a rule fired for an entity. Returns false without logging it when the
+// rule already fired for the entity under the same key.
+func (r *RuleRepository) CreateExecution(ctx context.Context, execution *model.RuleExecution) (bool, error) {
+	created, err := createRuleExecution(r.db.WithContext(ctx), execution)
+	if err != nil {
+		logger.Log.Error("Failed to create rule execution", zap.Error(err), zap.String("ruleID", execution.RuleID))
+		return false, err
+	}
+
+	return created, nil
+}
+
+// createRuleExecution logs a rule's firing unless it already fired for the entity under the same key
+func createRuleExecution(tx *gorm.DB, execution *model.RuleExecution) (bool, error) {
+	res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(execution)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+// ClaimEvents takes up to limit queued events, along with the ones another worker took longer than
+// staleAfter ago without finishing, and returns them with their activity log entries
+func (r *RuleRepository) ClaimEvents(ctx context.Context, limit int, staleAfter time.Duration) ([]model.RuleEvent, error) {
+	query := `
+		UPDATE rule_events
+		SET claimed_at = CURRENT_TIMESTAMP, attempts = attempts + 1
+		WHERE id IN (
+			SELECT id FROM rule_events
+			WHERE claimed_at IS NULL OR claimed_at < @stale
+			ORDER BY created_at
+			LIMIT @limit
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`
+
+	var events []model.RuleEvent
+	err := r.db.WithContext(ctx).Raw(query, map[string]any{
+		"stale": time.Now().Add(-staleAfter),
+		"limit": limit,
+	}).Scan(&events).Error
+	if err != nil {
+		logger.Log.Error("Failed to claim rule events", zap.Error(err))
+		return nil, err
+	}
+	if len(events) == 0 {
+		return events, nil
+	}
+
+	activityIDs := make([]string, 0, len(events))
+	for _, event := range events {
+		activityIDs = append(activityIDs, event.ActivityID)
+	}
+	var activities []model.Activity
+	if err := r.db.WithContext(ctx).Where("id IN ?", activityIDs).Find(&activities).Error; err != nil {
+		logger.Log.Error("Failed to get activities of claimed rule events", zap.Error(err))
+		return nil, err
+	}
+	for i := range events {
+		for j := range activities {
+			if activities[j].ID == events[i].ActivityID {
+				events[i].Activity = &activities[j]
+			}
+		}
+	}
+
+	return events, nil
+}
+
+// DeleteEvent takes an event off the queue once its rules have run
+func (r *RuleRepository) DeleteEvent(ctx context.Context, eventID string) error {
+	if err := r.db.WithContext(ctx).Delete(&model.RuleEvent{}, "id = ?", eventID).Error; err != nil {
+		logger.Log.Error("Failed to delete rule event", zap.Error(err), zap.String("eventID", eventID))
+		return err
+	}
+
+	return nil
+}
+
+// ContainerInScope reports whether a live project or collection is one the user sees in a scope,
+// the personal space for an empty workspaceID or a workspace. column is project_id or collection_id.
+func (r *RuleRepository) ContainerInScope(ctx context.Context, userID, workspaceID, column, containerID string) (bool, error) {
+	var exists bool
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT EXISTS (
+			SELECT 1 FROM `+memberTarget(column, "").table()+`
+			WHERE id = @id AND deleted_at IS NULL AND id IN (`+ScopeContainerIDsSQL(column, workspaceID)+`)
+		)`, map[string]any{
+		"id":           containerID,
+		"user_id":      userID,
+		"workspace_id": workspaceID,
+	}).Scan(&exists).Error
+	if err != nil {
+		logger.Log.Error("Failed to check rule container", zap.Error(err), zap.String("userID", userID), zap.String("containerID", containerID))
+		return false, err
+	}
+
+	return exists, nil
+}
+
+// HasTag reports whether the user has a live tag
+func (r *RuleRepository) HasTag(ctx context.Context, userID, tagID string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&model.Tag{}).
+		Where("id = ? AND user_id = ? AND deleted_at IS NULL", tagID, userID).
+		Count(&count).Error
+	if err != nil {
+		logger.Log.Error("Failed to check rule tag", zap.Error(err), zap.String("userID", userID), zap.String("tagID", tagID))
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// enqueueRules queues an activity log entry for the rule worker when a rule may fire for it. Changes
+// made by rules aren't queued, so rules can't set each other off in a loop.
+func enqueueRules(tx *gorm.DB, activity *model.Activity) error {
+	if activity.Source == model.ActivitySourceRule {
+		return nil
+	}
+	var triggers []string
+	for _, eventType := range model.ActivityEventTypes(activity) {
+		if model.IsRuleTrigger(eventType) {
+			triggers = append(triggers, eventType)
+		}
+	}
+	if len(triggers) == 0 {
+		return nil
+	}
+
+	workspaceID, err := activityWorkspaceID(tx, activity)
+	if err != nil {
+		return err
+	}
+	// Workspace rules see every member's changes, personal ones the changes of and to their user
+	query := tx.Model(&model.Rule{}).Where("trigger IN ? AND active AND deleted_at IS NULL", triggers)
+	if workspaceID != nil {
+		query = query.Where("workspace_id = ?", *workspaceID)
+	} else {
+		query = query.Where("workspace_id IS NULL AND user_id IN ?", []string{activity.ActorID, activity.OwnerID})
+	}
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return nil
+	}
+
+	return tx.Create(&model.RuleEvent{ActivityID: activity.ID, WorkspaceID: workspaceID}).Error
+}
+
+// activityWorkspaceID returns the workspace of the project or collection an entry is about, or nil
+// for the personal space
+func activityWorkspaceID(tx *gorm.DB, activity *model.Activity) (*string, error) {
+	table, containerID := "projects", activity.ProjectID
+	if activity.EntityType == "note" {
+		table, containerID = "collections", activity.CollectionID
+	}
+	if containerID == nil {
+		return nil, nil
+	}
+
+	var container struct{ WorkspaceID *string }
+	if err := tx.Table(table).Select("workspace_id").Where("id = ?", *containerID).Scan(&container).Error; err != nil {
+		return nil, err
+	}
+	return container.WorkspaceID, nil
+}
+
+// ListExecutions returns the execution log of a rule, newest first
+func (r *RuleRepository) ListExecutions(ctx context.Context, ruleID string, filters RuleExecutionFilters) ([]model.RuleExecution, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.RuleExecution{}).Where("rule_id = ?", ruleID)
+	if filters.Status != nil {
+		query = query.Where("status = ?", *filters.Status)
+	}
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		logger.Log.Error("Failed to count rule executions", zap.Error(err), zap.String("ruleID", ruleID))
+		return nil, 0, err
+	}
+
+	var executions []model.RuleExecution
+	err := query.
+		Order("created_at DESC, id ASC").
+		Limit(filters.Limit).
+		Offset(filters.Offset).
+		Find(&executions).Error
+	if err != nil {
+		logger.Log.Error("Failed to list rule executions", zap.Error(err), zap.String("ruleID", ruleID))
+		return nil, 0, err
+	}
+
+	return executions, total, nil
+}
@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"app/internal/contract"
+	"app/internal/model"
+	"app/pkg/util"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// testRule creates an active personal rule of the user's with a trigger and no conditions
+func testRule(t *testing.T, db *gorm.DB, userID, trigger string, actions ...model.RuleAction) *model.Rule {
+	t.Helper()
+
+	rule := &model.Rule{UserID: userID, Name: trigger, Trigger: trigger, Actions: actions, Active: true}
+	if err := NewRuleRepository(db).CreateRule(context.Background(), rule); err != nil {
+		t.Fatalf("failed to create rule: %v", err)
+	}
+	return rule
+}
+
+// queuedRuleEvent reports whether an activity log entry is queued for the rule worker
+func queuedRuleEvent(t *testing.T, db *gorm.DB, activityID string) bool {
+	t.Helper()
+
+	var count int64
+	if err := db.Model(&model.RuleEvent{}).Where("activity_id = ?", activityID).Count(&count).Error; err != nil {
+		t.Fatalf("failed to count rule events: %v", err)
+	}
+	return count > 0
+}
+
+func TestRollModeCompletionQueuesCompletedRule(t *testing.T) {
+	db := testDB(t)
+	repo := newTestSyncRepository(t, db)
+	userID := testUser(t, db)
+	testRule(t, db, userID, model.RuleTriggerTaskCompleted)
+
+	taskID := uuid.NewString()
+	applyChanges(t, repo, userID, contract.Change{
+		Type:           "task",
+		EntityID:       taskID,
+		Title:          util.ToPointer("Stand-up"),
+		DueDate:        util.ToPointer("2026-01-05T09:00:00Z"),
+		RecurrenceRule: util.ToPointer("FREQ=DAILY"),
+		RecurrenceMode: util.ToPointer(model.RecurrenceModeRoll),
+	})
+
+	activities, err := repo.ApplyChanges(userID, "", []contract.Change{
+		{Type: "task", EntityID: taskID, Status: util.ToPointer(model.TaskStatusCompleted)},
+	}, model.ActivitySourceSync)
+	if err != nil {
+		t.Fatalf("failed to complete task: %v", err)
+	}
+
+	activity := findActivity(t, activities, taskID, model.ActivityActionUpdate)
+	if !queuedRuleEvent(t, db, activity.ID) {
+		t.Error("completing a roll-mode task didn't queue its task.completed rule")
+	}
+}
+
+func TestImportQueuesRules(t *testing.T) {
+	db := testDB(t)
+	repo := newTestSyncRepository(t, db)
+	userID := testUser(t, db)
+	testRule(t, db, userID, model.RuleTriggerTaskCreated)
+
+	results, err := repo.ImportTasks(context.Background(), userID, []ImportedTask{{Title: "Imported", Category: "todo"}}, false)
+	if err != nil || len(results) != 1 {
+		t.Fatalf("failed to import task: %v", err)
+	}
+
+	var activity model.Activity
+	err = db.Where("entity_id = ? AND action = ?", results[0].TaskID, model.ActivityActionCreate).Take(&activity).Error
+	if err != nil {
+		t.Fatalf("failed to find import entry: %v", err)
+	}
+	if !queuedRuleEvent(t, db, activity.ID) {
+		t.Error("an imported task didn't queue its task.created rule")
+	}
+}
+
+func TestRuleChangesDontQueueRules(t *testing.T) {
+	db := testDB(t)
+	repo := newTestSyncRepository(t, db)
+	userID := testUser(t, db)
+	testRule(t, db, userID, model.RuleTriggerTaskCreated)
+
+	activities, err := repo.ApplyChanges(userID, "", []contract.Change{
+		{Type: "task", EntityID: uuid.NewString(), Title: util.ToPointer("Made by a rule")},
+	}, model.ActivitySourceRule)
+	if err != nil {
+		t.Fatalf("failed to apply rule changes: %v", err)
+	}
+
+	for _, activity := range activities {
+		if queuedRuleEvent(t, db, activity.ID) {
+			t.Errorf("the rule's %s of %s was queued for rules", activity.Action, activity.EntityID)
+		}
+	}
+}
+
+func TestApplyRuleChangesOncePerTriggerKey(t *testing.T) {
+	db := testDB(t)
+	repo := newTestSyncRepository(t, db)
+	userID := testUser(t, db)
+	rule := testRule(t, db, userID, model.RuleTriggerTaskUpdated)
+
+	taskID := uuid.NewString()
+	applyChanges(t, repo, userID, contract.Change{Type: "task", EntityID: taskID, Title: util.ToPointer("Escalate")})
+
+	apply := func(priority int) bool {
+		t.Helper()
+		execution := &model.RuleExecution{
+			RuleID:     rule.ID,
+			Trigger:    rule.Trigger,
+			EntityType: "task",
+			EntityID:   taskID,
+			TriggerKey: "retried",
+			Status:     model.RuleExecutionApplied,
+			Changes:    datatypes.JSON("[]"),
+		}
+		applied, err := repo.ApplyRuleChanges(rule, execution, []contract.Change{
+			{Type: "task", EntityID: taskID, Priority: &priority},
+		})
+		if err != nil {
+			t.Fatalf("failed to apply rule changes: %v", err)
+		}
+		return applied
+	}
+
+	if !apply(4) {
+		t.Fatal("the first firing wasn't applied")
+	}
+	if apply(1) {
+		t.Error("the second firing under the same key was applied")
+	}
+
+	var task model.Task
+	if err := db.Where("id = ?", taskID).Take(&task).Error; err != nil {
+		t.Fatalf("failed to get task: %v", err)
+	}
+	if task.Priority != 4 {
+		t.Errorf("priority = %d, want 4 from the first firing only", task.Priority)
+	}
+	var executions int64
+	if err := db.Model(&model.RuleExecution{}).Where("rule_id = ?", rule.ID).Count(&executions).Error; err != nil {
+		t.Fatalf("failed to count executions: %v", err)
+	}
+	if executions != 1 {
+		t.Errorf("logged %d executions, want 1", executions)
+	}
+}
+
+func TestClaimEventsTakesStaleEventsAgain(t *testing.T) {
+	db := testDB(t)
+	ruleRepo := NewRuleRepository(db)
+	ctx := context.Background()
+
+	event := &model.RuleEvent{ActivityID: uuid.NewString()}
+	if err := db.Create(event).Error; err != nil {
+		t.Fatalf("failed to queue event: %v", err)
+	}
+	t.Cleanup(func() { db.Delete(&model.RuleEvent{}, "id = ?", event.ID) })
+
+	claimed := func(staleAfter time.Duration) bool {
+		t.Helper()
+		events, err := ruleRepo.ClaimEvents(ctx, 1000, staleAfter)
+		if err != nil {
+			t.Fatalf("failed to claim events: %v", err)
+		}
+		for _, e := range events {
+			if e.ID == event.ID {
+				return true
+			}
+		}
+		return false
+	}
+
+	if !claimed(time.Hour) {
+		t.Fatal("a queued event wasn't claimed")
+	}
+	if claimed(time.Hour) {
+		t.Error("an event another worker just took was claimed again")
+	}
+	if !claimed(0) {
+		t.Error("a stale event wasn't claimed again")
+	}
+}
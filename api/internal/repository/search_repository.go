@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"app/pkg/logger"
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pgvector/pgvector-go"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	// rrfK dampens the contribution of lower ranks in reciprocal rank fusion
+	rrfK = 60
+	// searchCandidates is the fewest rows each ranker contributes before fusion. Deeper pages
+	// take more, so each ranker reaches at least as far as the page does.
+	searchCandidates = 50
+	// searchMaxDistance is the largest cosine distance at which a note still counts as a semantic match
+	searchMaxDistance = 0.6
+)
+
+type SearchRepository struct {
+	db *gorm.DB
+}
+
+func NewSearchRepository(db *gorm.DB) *SearchRepository {
+	return &SearchRepository{db: db}
+}
+
+// SearchFilters represents filters for hybrid search
+type SearchFilters struct {
+	// WorkspaceID is the active workspace, or empty for the personal space
+	WorkspaceID    string
+	Query          string
+	QueryEmbedding []float32
+	Types          []string
+	Tags           []string
+	Limit          int
+	Offset         int
+}
+
+// SearchResult is a single fused hit across notes, tasks and comments
+type SearchResult struct {
+	Type string
+	ID   string
+	// Title of a comment is that of its task or note
+	Title     *string
+	Snippet   string
+	Score     float64
+	UpdatedAt time.Time
+	// TaskID and NoteID are set on comments, for the task or note they are on
+	TaskID *string
+	NoteID *string
+}
+
+// Search ranks notes, tasks and comments by full-text rank and embedding similarity,
+// then merges the rankings with reciprocal rank fusion. Only what the user
+// sees in the active workspace is searched: in the personal space that is
+// their own notes and tasks and those of collections and projects shared with them,
+// along with the comments on all of these.
+func (r *SearchRepository) Search(ctx context.Context, userID string, filters SearchFilters) ([]SearchResult, error) {
+	args := map[string]any{
+		"user_id":      userID,
+		"workspace_id": filters.WorkspaceID,
+		"query":        filters.Query,
+		"k":            rrfK,
+		"candidates":   max(searchCandidates, filters.Offset+filters.Limit),
+		"max_distance": searchMaxDistance,
+		"limit":        filters.Limit,
+		"offset":       filters.Offset,
+	}
+
+	// Entities must carry every requested tag. Tags are matched by name, since a shared item
+	// may carry the tags of each of the users who see it.
+	var noteTagFilter, taskTagFilter string
+	if len(filters.Tags) > 0 {
+		args["tags"] = filters.Tags
+		args["tag_count"] = len(filters.Tags)
+		noteTagFilter = ` AND n.id IN (
+			SELECT nt.note_id FROM note_tags nt JOIN tags tg ON tg.id = nt.tag_id
+			WHERE tg.deleted_at IS NULL AND LOWER(tg.name) IN @tags
+			GROUP BY nt.note_id HAVING COUNT(DISTINCT LOWER(tg.name)) = @tag_count)`
+		taskTagFilter = ` AND t.id IN (
+			SELECT tt.task_id FROM task_tags tt JOIN tags tg ON tg.id = tt.tag_id
+			WHERE tg.deleted_at IS NULL AND LOWER(tg.name) IN @tags
+			GROUP BY tt.task_id HAVING COUNT(DISTINCT LOWER(tg.name)) = @tag_count)`
+	}
+
+	rankers := []string{}
+	if searchIncludes(filters.Types, "note") {
+		rankers = append(rankers, `(SELECT 'note' AS type, n.id, ROW_NUMBER() OVER (ORDER BY ts_rank_cd(n.search_vector, q.tsq) DESC) AS rnk
+			FROM notes n, q
+			WHERE `+scopeItemsSQL("n", "collection_id", filters.WorkspaceID)+` AND n.deleted_at IS NULL AND n.search_vector @@ q.tsq`+noteTagFilter+`
+			ORDER BY rnk LIMIT @candidates)`)
+	}
+	if searchIncludes(filters.Types, "task") {
+		rankers = append(rankers, `(SELECT 'task' AS type, t.id, ROW_NUMBER() OVER (ORDER BY ts_rank_cd(t.search_vector, q.tsq) DESC) AS rnk
+			FROM tasks t, q
+			WHERE `+scopeItemsSQL("t", "project_id", filters.WorkspaceID)+` AND t.deleted_at IS NULL AND t.search_vector @@ q.tsq`+taskTagFilter+`
+			ORDER BY rnk LIMIT @candidates)`)
+	}
+	// Comments carry no tags, so a tag filter leaves them out
+	if searchIncludes(filters.Types, "comment") && len(filters.Tags) == 0 {
+		rankers = append(rankers, `(SELECT 'comment' AS type, c.id, ROW_NUMBER() OVER (ORDER BY ts_rank_cd(c.search_vector, q.tsq) DESC) AS rnk
+			FROM comments c, q
+			WHERE (c.task_id IN (SELECT t.id FROM tasks t WHERE `+scopeItemsSQL("t", "project_id", filters.WorkspaceID)+` AND t.deleted_at IS NULL)
+				OR c.note_id IN (SELECT n.id FROM notes n WHERE `+scopeItemsSQL("n", "collection_id", filters.WorkspaceID)+` AND n.deleted_at IS NULL))
+				AND c.deleted_at IS NULL AND c.search_vector @@ q.tsq
+			ORDER BY rnk LIMIT @candidates)`)
+	}
+	// Only notes carry embeddings, so the semantic ranker never contributes tasks. Notes too far
+	// from the query don't count, so a query that matches nothing finds nothing.
+	if len(filters.QueryEmbedding) > 0 && searchIncludes(filters.Types, "note") {
+		args["embedding"] = pgvector.NewVector(filters.QueryEmbedding)
+		rankers = append(rankers, `(SELECT 'note' AS type, n.id, ROW_NUMBER() OVER (ORDER BY n.embedding <=> @embedding) AS rnk
+			FROM notes n
+			WHERE `+scopeItemsSQL("n", "collection_id", filters.WorkspaceID)+` AND n.deleted_at IS NULL AND n.embedding IS NOT NULL
+				AND n.embedding <=> @embedding <= @max_distance`+noteTagFilter+`
+			ORDER BY rnk LIMIT @candidates)`)
+	}
+	if len(rankers) == 0 {
+		return []SearchResult{}, nil
+	}
+
+	query := `
+		WITH q AS (SELECT websearch_to_tsquery('simple', @query) AS tsq),
+		ranked AS (` + strings.Join(rankers, " UNION ALL ") + `),
+		fused AS (
+			SELECT type, id, SUM(1.0 / (@k + rnk)) AS score
+			FROM ranked
+			GROUP BY type, id
+		)
+		SELECT
+			f.type,
+			f.id,
+			COALESCE(n.title, t.title, ct.title, cn.title) AS title,
+			ts_headline('simple', COALESCE(n.content, t.description, c.content, ''), q.tsq,
+				'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=20, MinWords=5, FragmentDelimiter=" … "') AS snippet,
+			f.score,
+			COALESCE(n.updated_at, t.updated_at, c.updated_at) AS updated_at,
+			c.task_id,
+			c.note_id
+		FROM fused f
+		CROSS JOIN q
+		LEFT JOIN notes n ON f.type = 'note' AND n.id = f.id
+		LEFT JOIN tasks t ON f.type = 'task' AND t.id = f.id
+		LEFT JOIN comments c ON f.type = 'comment' AND c.id = f.id
+		LEFT JOIN tasks ct ON ct.id = c.task_id
+		LEFT JOIN notes cn ON cn.id = c.note_id
+		ORDER BY f.score DESC, updated_at DESC
+		LIMIT @limit OFFSET @offset`
+
+	var results []SearchResult
+	err := r.db.WithContext(ctx).Raw(query, args).Scan(&results).Error
+	if err != nil {
+		logger.Log.Error("Failed to search", zap.Error(err), zap.String("userID", userID), zap.String("query", filters.Query))
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func searchIncludes(types []string, entityType string) bool {
+	if len(types) == 0 {
+		return true
+	}
+	for _, t := range types {
+		if t == entityType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"app/internal/contract"
+	"app/internal/model"
+	"app/pkg/util"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// searchWord returns a word no other row holds, so a search for it only finds the test's rows
+func searchWord() string {
+	return "w" + strings.ReplaceAll(uuid.NewString(), "-", "")
+}
+
+func TestSearchRanksTitleMatchesFirst(t *testing.T) {
+	db := testDB(t)
+	repo := newTestSyncRepository(t, db)
+	searchRepo := NewSearchRepository(db)
+	userID := testUser(t, db)
+
+	word := searchWord()
+	titleID, contentID, taskID := uuid.NewString(), uuid.NewString(), uuid.NewString()
+	applyChanges(t, repo, userID,
+		contract.Change{Type: "note", EntityID: contentID, Title: util.ToPointer("Notes"), Content: util.ToPointer("Mentions " + word + " once")},
+		contract.Change{Type: "note", EntityID: titleID, Title: util.ToPointer("About " + word)},
+		contract.Change{Type: "task", EntityID: taskID, Title: util.ToPointer("Follow up on " + word)},
+	)
+
+	results, err := searchRepo.Search(context.Background(), userID, SearchFilters{Query: word, Types: []string{"note"}, Limit: 10})
+	if err != nil {
+		t.Fatalf("failed to search: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("found %d notes, want 2", len(results))
+	}
+	if results[0].ID != titleID {
+		t.Errorf("first result is %s, want the note with the word in its title", results[0].ID)
+	}
+	if !strings.Contains(results[1].Snippet, "<mark>") {
+		t.Errorf("snippet = %q, want the match marked", results[1].Snippet)
+	}
+
+	results, err = searchRepo.Search(context.Background(), userID, SearchFilters{Query: word, Limit: 10})
+	if err != nil {
+		t.Fatalf("failed to search: %v", err)
+	}
+	if len(results) != 3 {
+		t.Errorf("found %d results across types, want 3", len(results))
+	}
+}
+
+func TestSearchOnlyFindsTheUsersItems(t *testing.T) {
+	db := testDB(t)
+	repo := newTestSyncRepository(t, db)
+	searchRepo := NewSearchRepository(db)
+	userID, otherID := testUser(t, db), testUser(t, db)
+
+	word := searchWord()
+	applyChanges(t, repo, otherID, contract.Change{Type: "note", EntityID: uuid.NewString(), Title: util.ToPointer("Private " + word)})
+
+	results, err := searchRepo.Search(context.Background(), userID, SearchFilters{Query: word, Limit: 10})
+	if err != nil {
+		t.Fatalf("failed to search: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("found %d of another user's items, want none", len(results))
+	}
+}
+
+func TestSearchIncludes(t *testing.T) {
+	tests := []struct {
+		types      []string
+		entityType string
+		want       bool
+	}{
+		{nil, "note", true},
+		{[]string{"task"}, "task", true},
+		{[]string{"task"}, "note", false},
+		{[]string{"note", "comment"}, "comment", true},
+	}
+	for _, tt := range tests {
+		if got := searchIncludes(tt.types, tt.entityType); got != tt.want {
+			t.Errorf("searchIncludes(%v, %q) = %v, want %v", tt.types, tt.entityType, got, tt.want)
+		}
+	}
+}
+
+func TestSearchSkipsDistantNotes(t *testing.T) {
+	db := testDB(t)
+	repo := newTestSyncRepository(t, db)
+	searchRepo := NewSearchRepository(db)
+	userID := testUser(t, db)
+
+	near := testNoteWithEmbedding(t, db, repo, userID, 1, 0.2, 0)
+	testNoteWithEmbedding(t, db, repo, userID, 0, 0, 1)
+
+	query := make([]float32, 1536)
+	query[0] = 1
+	results, err := searchRepo.Search(context.Background(), userID, SearchFilters{Query: searchWord(), QueryEmbedding: query, Limit: 10})
+	if err != nil {
+		t.Fatalf("failed to search: %v", err)
+	}
+	if len(results) != 1 || results[0].ID != near.ID {
+		t.Errorf("found %d notes, want only the one close to the query", len(results))
+	}
+}
+
+func TestSearchMatchesCollaboratorsTags(t *testing.T) {
+	db := testDB(t)
+	repo := newTestSyncRepository(t, db)
+	searchRepo := NewSearchRepository(db)
+	ownerID, memberID := testUser(t, db), testUser(t, db)
+
+	word := searchWord()
+	projectID, taskID, tagID := uuid.NewString(), uuid.NewString(), uuid.NewString()
+	applyChanges(t, repo, ownerID,
+		contract.Change{Type: "tag", EntityID: tagID, Name: util.ToPointer("Launch")},
+		contract.Change{Type: "project", EntityID: projectID, Title: util.ToPointer("Shared")},
+		contract.Change{Type: "task", EntityID: taskID, ProjectID: &projectID, Title: util.ToPointer("Plan " + word), TagIDs: &[]string{tagID}},
+	)
+	membership := model.Membership{UserID: memberID, ProjectID: &projectID, Role: model.MemberRoleViewer, InvitedBy: &ownerID}
+	if err := db.Create(&membership).Error; err != nil {
+		t.Fatalf("failed to create membership: %v", err)
+	}
+
+	results, err := searchRepo.Search(context.Background(), memberID, SearchFilters{Query: word, Tags: []string{"launch"}, Limit: 10})
+	if err != nil {
+		t.Fatalf("failed to search: %v", err)
+	}
+	if len(results) != 1 || results[0].ID != taskID {
+		t.Errorf("found %d results, want the shared task tagged by its owner", len(results))
+	}
+}
@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"app/internal/model"
+	"app/pkg/logger"
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var ErrShareTargetNotFound = errors.New("note or collection not found")
+
+type ShareRepository struct {
+	db *gorm.DB
+}
+
+func NewShareRepository(db *gorm.DB) *ShareRepository {
+	return &ShareRepository{db: db}
+}
+
+// CreateLink saves a share link after checking the user owns the live note or collection it shares
+func (r *ShareRepository) CreateLink(ctx context.Context, link *model.ShareLink) error {
+	target := r.db.WithContext(ctx).Model(&model.Note{})
+	targetID := link.NoteID
+	if link.CollectionID != nil {
+		target = r.db.WithContext(ctx).Model(&model.Collection{})
+		targetID = link.CollectionID
+	}
+
+	var count int64
+	err := target.Where("id = ? AND user_id = ? AND deleted_at IS NULL", *targetID, link.UserID).Count(&count).Error
+	if err != nil {
+		logger.Log.Error("Failed to find share target", zap.Error(err), zap.String("userID", link.UserID))
+		return err
+	}
+	if count == 0 {
+		return ErrShareTargetNotFound
+	}
+
+	if err := r.db.WithContext(ctx).Create(link).Error; err != nil {
+		logger.Log.Error("Failed to create share link", zap.Error(err), zap.String("userID", link.UserID))
+		return err
+	}
+
+	return nil
+}
+
+// GetLink returns one of the user's links with the title of what it shares, or nil if there is no such link
+func (r *ShareRepository) GetLink(ctx context.Context, userID, linkID string) (*model.ShareLink, error) {
+	var links []model.ShareLink
+	err := r.withTargetTitles(r.db.WithContext(ctx)).
+		Where("id = ? AND user_id = ?", linkID, userID).
+		Limit(1).
+		Find(&links).Error
+	if err != nil {
+		logger.Log.Error("Failed to get share link", zap.Error(err), zap.String("linkID", linkID))
+		return nil, err
+	}
+	if len(links) == 0 {
+		return nil, nil
+	}
+
+	return &links[0], nil
+}
+
+// ListLinks returns the user's links that aren't revoked, newest first, optionally only those of one note or collection
+func (r *ShareRepository) ListLinks(ctx context.Context, userID string, noteID, collectionID *string) ([]model.ShareLink, error) {
+	query := r.withTargetTitles(r.db.WithContext(ctx)).
+		Where("user_id = ? AND revoked_at IS NULL", userID)
+	if noteID != nil {
+		query = query.Where("note_id = ?", *noteID)
+	}
+	if collectionID != nil {
+		query = query.Where("collection_id = ?", *collectionID)
+	}
+
+	var links []model.ShareLink
+	if err := query.Order("created_at DESC").Find(&links).Error; err != nil {
+		logger.Log.Error("Failed to list share links", zap.Error(err), zap.String("userID", userID))
+		return nil, err
+	}
+
+	return links, nil
+}
+
+// RevokeLink revokes one of the user's links. Returns false if there is no such link that is still live.
+func (r *ShareRepository) RevokeLink(ctx context.Context, userID, linkID string) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Model(&model.ShareLink{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", linkID, userID).
+		Updates(map[string]any{
+			"revoked_at": gorm.Expr("CURRENT_TIMESTAMP"),
+			"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+		})
+	if res.Error != nil {
+		logger.Log.Error("Failed to revoke share link", zap.Error(res.Error), zap.String("linkID", linkID))
+		return false, res.Error
+	}
+
+	return res.RowsAffected > 0, nil
+}
+
+// GetLinkByToken returns the unrevoked, unexpired link with the given token hash, or nil if there is none
+func (r *ShareRepository) GetLinkByToken(ctx context.Context, tokenHash string) (*model.ShareLink, error) {
+	var links []model.ShareLink
+	err := r.db.WithContext(ctx).
+		Where("token_hash = ? AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > CURRENT_TIMESTAMP)", tokenHash).
+		Limit(1).
+		Find(&links).Error
+	if err != nil {
+		logger.Log.Error("Failed to get share link", zap.Error(err))
+		return nil, err
+	}
+	if len(links) == 0 {
+		return nil, nil
+	}
+
+	return &links[0], nil
+}
+
+// RecordView counts a view of a link
+func (r *ShareRepository) RecordView(ctx context.Context, linkID string) error {
+	err := r.db.WithContext(ctx).
+		Model(&model.ShareLink{}).
+		Where("id = ?", linkID).
+		UpdateColumns(map[string]any{
+			"view_count":     gorm.Expr("view_count + 1"),
+			"last_viewed_at": gorm.Expr("CURRENT_TIMESTAMP"),
+		}).Error
+	if err != nil {
+		logger.Log.Error("Failed to record share link view", zap.Error(err), zap.String("linkID", linkID))
+		return err
+	}
+
+	return nil
+}
+
+// GetSharedNote returns the user's live note with its tags, or nil if it is gone
+func (r *ShareRepository) GetSharedNote(ctx context.Context, userID, noteID string) (*model.Note, error) {
+	var notes []model.Note
+	err := r.db.WithContext(ctx).
+		Omit("embedding").
+		Preload("Tags", "deleted_at IS NULL").
+		Where("id = ? AND user_id = ? AND deleted_at IS NULL", noteID, userID).
+		Limit(1).
+		Find(&notes).Error
+	if err != nil {
+		logger.Log.Error("Failed to get shared note", zap.Error(err), zap.String("noteID", noteID))
+		return nil, err
+	}
+	if len(notes) == 0 {
+		return nil, nil
+	}
+
+	return &notes[0], nil
+}
+
+// GetSharedCollection returns the user's live collection with up to noteLimit of its live notes
+// and their tags, in title order, or nil if it is gone
+func (r *ShareRepository) GetSharedCollection(ctx context.Context, userID, collectionID string, noteLimit int) (*model.Collection, error) {
+	var collections []model.Collection
+	err := r.db.WithContext(ctx).
+		Preload("Notes", func(tx *gorm.DB) *gorm.DB {
+			return tx.Omit("embedding").
+				Where("deleted_at IS NULL").
+				Order("LOWER(title) ASC NULLS LAST, created_at ASC").
+				Limit(noteLimit)
+		}).
+		Preload("Notes.Tags", "deleted_at IS NULL").
+		Where("id = ? AND user_id = ? AND deleted_at IS NULL", collectionID, userID).
+		Limit(1).
+		Find(&collections).Error
+	if err != nil {
+		logger.Log.Error("Failed to get shared collection", zap.Error(err), zap.String("collectionID", collectionID))
+		return nil, err
+	}
+	if len(collections) == 0 {
+		return nil, nil
+	}
+
+	return &collections[0], nil
+}
+
+// withTargetTitles preloads the title of the note or collection each link shares
+func (r *ShareRepository) withTargetTitles(tx *gorm.DB) *gorm.DB {
+	return tx.Preload("Note", func(tx *gorm.DB) *gorm.DB { return tx.Select("id", "title") }).
+		Preload("Collection", func(tx *gorm.DB) *gorm.DB { return tx.Select("id", "title") })
+}
+
+// revokeShareLinks revokes the live links to a note or collection, so deleting it through sync
+// also takes it offline. column is note_id or collection_id.
+func revokeShareLinks(tx *gorm.DB, userID, column, id string) error {
+	return tx.Model(&model.ShareLink{}).
+		Where("user_id = ? AND revoked_at IS NULL AND "+column+" = ?", userID, id).
+		Updates(map[string]any{
+			"revoked_at": gorm.Expr("CURRENT_TIMESTAMP"),
+			"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+		}).Error
+}
@@ -3,18 +3,59 @@ package repository
 import (
 	"app/internal/contract"
 	"app/internal/model"
+	"app/pkg/fractional"
 	"app/pkg/logger"
 	"app/pkg/openai"
+	"app/pkg/recurrence"
 	"app/pkg/util"
 	"context"
+	"errors"
+	"maps"
+	"slices"
 	"sort"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/pgvector/pgvector-go"
 	"go.uber.org/zap"
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+var (
+	ErrTaskParentNotFound = errors.New("parent task not found")
+	ErrTaskCycle          = errors.New("a task cannot be nested under itself or one of its subtasks")
+	ErrTaskTooDeep        = errors.New("task hierarchy is too deep")
+
+	ErrTaskDependencyNotFound = errors.New("blocking task not found")
+	ErrTaskDependencyCycle    = errors.New("a task cannot be blocked by itself or by a task it blocks")
+
+	ErrStatusNotFound        = errors.New("status not found in the task's project")
+	ErrStatusProjectNotFound = errors.New("status project not found")
+	ErrStatusIncomplete      = errors.New("a new status needs a project, name and category")
+
+	ErrTagNameRequired = errors.New("a tag needs a name")
+)
+
+// syncOrder decides which entity types are applied first, so changes can
+// reference entities created earlier in the same batch. Comments go last,
+// after the tasks and notes they are on.
+var syncOrder = map[string]int{
+	"tag":     0,
+	"project": 1,
+	"status":  2,
+	"comment": 4,
+}
+
+func syncTypeOrder(changeType string) int {
+	if order, ok := syncOrder[changeType]; ok {
+		return order
+	}
+	return 3
+}
+
 type SyncRepository struct {
 	db           *gorm.DB
 	openaiClient *openai.OpenAIClient
@@ -27,49 +68,116 @@ func NewSyncRepository(db *gorm.DB, openaiClient *openai.OpenAIClient) *SyncRepo
 	}
 }
 
-func (r *SyncRepository) GetChanges(userID string, from string) (changes []contract.Change, err error) {
+// GetChanges returns what changed since from in the personal space, for an empty workspaceID, or
+// in a workspace the user is a member of
+func (r *SyncRepository) GetChanges(userID, workspaceID string, from string) (changes []contract.Change, err error) {
 
 	var tasks []model.Task
 	var projects []model.Project
 	var notes []model.Note
 	var collections []model.Collection
+	var tags []model.Tag
+	var statuses []model.ProjectStatus
+	var comments []model.Comment
+	var memberships []model.Membership
+	var removedMemberships []model.Membership
+
+	scope := map[string]any{"user_id": userID, "workspace_id": workspaceID, "from": from}
+	changesSQL := sharedChangesSQL
+	workspaceRole := ""
+	if workspaceID != "" {
+		workspaceRole, err = r.workspaceScope(userID, workspaceID, scope)
+		if err != nil {
+			return nil, err
+		}
+		changesSQL = workspaceChangesSQL
+	}
 
-	err = r.db.Where("user_id = ? AND updated_at > ?", userID, from).Order("updated_at ASC").Unscoped().Find(&tasks).Error
+	err = r.db.Where(changesSQL("tasks", "project_id", "project_id"), scope).Order("updated_at ASC").Unscoped().Preload("Tags").Preload("BlockedBy").Find(&tasks).Error
 	if err != nil {
 		logger.Log.Error("Failed to get tasks", zap.Error(err), zap.String("userID", userID), zap.String("from", from))
 		return nil, err
 	}
-	err = r.db.Where("user_id = ? AND updated_at > ?", userID, from).Order("updated_at ASC").Unscoped().Find(&projects).Error
+	err = r.db.Where(changesSQL("projects", "id", "project_id"), scope).Order("updated_at ASC").Unscoped().Find(&projects).Error
 	if err != nil {
 		logger.Log.Error("Failed to get projects", zap.Error(err), zap.String("userID", userID), zap.String("from", from))
 		return nil, err
 	}
-	err = r.db.Where("user_id = ? AND updated_at > ?", userID, from).Order("updated_at ASC").Unscoped().Find(&notes).Error
+	err = r.db.Where(changesSQL("notes", "collection_id", "collection_id"), scope).Order("updated_at ASC").Unscoped().Preload("Tags").Find(&notes).Error
 	if err != nil {
 		logger.Log.Error("Failed to get notes", zap.Error(err), zap.String("userID", userID), zap.String("from", from))
 		return nil, err
 	}
-	err = r.db.Where("user_id = ? AND updated_at > ?", userID, from).Order("updated_at ASC").Unscoped().Find(&collections).Error
+	err = r.db.Where(changesSQL("collections", "id", "collection_id"), scope).Order("updated_at ASC").Unscoped().Find(&collections).Error
 	if err != nil {
 		logger.Log.Error("Failed to get collections", zap.Error(err), zap.String("userID", userID), zap.String("from", from))
 		return nil, err
 	}
+	err = r.db.Where("user_id = ? AND updated_at > ?", userID, from).Order("updated_at ASC").Unscoped().Find(&tags).Error
+	if err != nil {
+		logger.Log.Error("Failed to get tags", zap.Error(err), zap.String("userID", userID), zap.String("from", from))
+		return nil, err
+	}
+
+	err = r.db.Where(changesSQL("project_statuses", "project_id", "project_id"), scope).Order("updated_at ASC").Unscoped().Find(&statuses).Error
+	if err != nil {
+		logger.Log.Error("Failed to get statuses", zap.Error(err), zap.String("userID", userID), zap.String("from", from))
+		return nil, err
+	}
+
+	err = r.db.Where(commentChangesSQL(workspaceID), scope).Order("updated_at ASC").Find(&comments).Error
+	if err != nil {
+		logger.Log.Error("Failed to get comments", zap.Error(err), zap.String("userID", userID), zap.String("from", from))
+		return nil, err
+	}
+
+	// Workspace memberships are left out: the workspace role comes from workspaceScope, and a
+	// member who leaves a workspace can no longer sync it
+	err = r.db.Where("user_id = ? AND removed_at IS NULL AND workspace_id IS NULL", userID).Find(&memberships).Error
+	if err != nil {
+		logger.Log.Error("Failed to get memberships", zap.Error(err), zap.String("userID", userID))
+		return nil, err
+	}
+	err = r.db.Where("user_id = ? AND removed_at > ? AND workspace_id IS NULL", userID, from).Find(&removedMemberships).Error
+	if err != nil {
+		logger.Log.Error("Failed to get removed memberships", zap.Error(err), zap.String("userID", userID), zap.String("from", from))
+		return nil, err
+	}
+
+	// Project and collection IDs are UUIDs, so one map holds the roles on both
+	roles := map[string]string{}
+	for _, membership := range memberships {
+		roles[util.ToValue(membership.ProjectID)+util.ToValue(membership.CollectionID)] = membership.Role
+	}
 
 	changes = []contract.Change{}
 
 	for _, task := range tasks {
 		changes = append(changes, contract.Change{
-			Type:        "task",
-			EntityID:    task.ID,
-			Title:       task.Title,
-			Description: task.Description,
-			ProjectID:   task.ProjectID,
-			SortOrder:   task.SortOrder,
-			DueDate:     util.TimePtrToStringPtr(task.DueDate, time.RFC3339),
-			Status:      util.ToPointer(task.Status),
-			UpdatedAt:   task.UpdatedAt.UTC().Format(time.RFC3339),
-			CreatedAt:   task.CreatedAt.UTC().Format(time.RFC3339),
-			DeletedAt:   util.TimePtrToStringPtr(task.DeletedAt, time.RFC3339),
+			Type:               "task",
+			EntityID:           task.ID,
+			Title:              task.Title,
+			Description:        task.Description,
+			ProjectID:          task.ProjectID,
+			ParentTaskID:       util.ToPointer(util.ToValue(task.ParentTaskID)),
+			SortOrder:          task.SortOrder,
+			RecurrenceRule:     task.RecurrenceRule,
+			RecurrenceMode:     util.ToPointer(task.RecurrenceMode),
+			RecurrenceSeriesID: task.RecurrenceSeriesID,
+			ReminderOffsets:    toReminderOffsets(task.ReminderOffsets),
+			DueDate:            util.TimePtrToStringPtr(task.DueDate, time.RFC3339),
+			StartDate:          util.TimePtrToStringPtr(task.StartDate, time.RFC3339),
+			Priority:           util.ToPointer(task.Priority),
+			EstimateMinutes:    task.EstimateMinutes,
+			Status:             util.ToPointer(task.Status),
+			StatusID:           task.StatusID,
+			TagIDs:             ownTagIDs(userID, task.UserID, task.Tags),
+			BlockedByTaskIDs:   toBlockedByTaskIDs(task.BlockedBy),
+			Blocked:            util.ToPointer(task.Blocked),
+			CompletedAt:        util.TimePtrToStringPtr(task.CompletedAt, time.RFC3339),
+			UpdatedAt:          task.UpdatedAt.UTC().Format(time.RFC3339),
+			CreatedAt:          task.CreatedAt.UTC().Format(time.RFC3339),
+			DeletedAt:          util.TimePtrToStringPtr(task.DeletedAt, time.RFC3339),
 		})
 	}
 
@@ -80,6 +188,8 @@ func (r *SyncRepository) GetChanges(userID string, from string) (changes []contr
 			Title:       project.Title,
 			Description: project.Description,
 			Color:       project.Color,
+			Role:        memberRole(roles, workspaceRole, userID, project.UserID, project.ID),
+			WorkspaceID: project.WorkspaceID,
 			UpdatedAt:   project.UpdatedAt.UTC().Format(time.RFC3339),
 			CreatedAt:   project.CreatedAt.UTC().Format(time.RFC3339),
 			DeletedAt:   util.TimePtrToStringPtr(project.DeletedAt, time.RFC3339),
@@ -93,6 +203,7 @@ func (r *SyncRepository) GetChanges(userID string, from string) (changes []contr
 			CollectionID: note.CollectionID,
 			Title:        note.Title,
 			Content:      note.Content,
+			TagIDs:       ownTagIDs(userID, note.UserID, note.Tags),
 			UpdatedAt:    note.UpdatedAt.UTC().Format(time.RFC3339),
 			CreatedAt:    note.CreatedAt.UTC().Format(time.RFC3339),
 			DeletedAt:    util.TimePtrToStringPtr(note.DeletedAt, time.RFC3339),
@@ -106,12 +217,67 @@ func (r *SyncRepository) GetChanges(userID string, from string) (changes []contr
 			Title:       util.ToPointer(collection.Title),
 			Description: util.ToPointer(collection.Description),
 			Color:       collection.Color,
+			Role:        memberRole(roles, workspaceRole, userID, collection.UserID, collection.ID),
+			WorkspaceID: collection.WorkspaceID,
 			UpdatedAt:   collection.UpdatedAt.UTC().Format(time.RFC3339),
 			CreatedAt:   collection.CreatedAt.UTC().Format(time.RFC3339),
 			DeletedAt:   util.TimePtrToStringPtr(collection.DeletedAt, time.RFC3339),
 		})
 	}
 
+	for _, tag := range tags {
+		changes = append(changes, contract.Change{
+			Type:      "tag",
+			EntityID:  tag.ID,
+			Name:      util.ToPointer(tag.Name),
+			Color:     tag.Color,
+			UpdatedAt: tag.UpdatedAt.UTC().Format(time.RFC3339),
+			CreatedAt: tag.CreatedAt.UTC().Format(time.RFC3339),
+			DeletedAt: util.TimePtrToStringPtr(tag.DeletedAt, time.RFC3339),
+		})
+	}
+
+	for _, status := range statuses {
+		changes = append(changes, contract.Change{
+			Type:      "status",
+			EntityID:  status.ID,
+			ProjectID: util.ToPointer(status.ProjectID),
+			Name:      util.ToPointer(status.Name),
+			Category:  util.ToPointer(status.Category),
+			SortOrder: status.SortOrder,
+			Color:     status.Color,
+			UpdatedAt: status.UpdatedAt.UTC().Format(time.RFC3339),
+			CreatedAt: status.CreatedAt.UTC().Format(time.RFC3339),
+			DeletedAt: util.TimePtrToStringPtr(status.DeletedAt, time.RFC3339),
+		})
+	}
+
+	for _, comment := range comments {
+		changes = append(changes, contract.Change{
+			Type:             "comment",
+			EntityID:         comment.ID,
+			TaskID:           comment.TaskID,
+			NoteID:           comment.NoteID,
+			Content:          util.ToPointer(comment.Content),
+			AuthorID:         util.ToPointer(comment.UserID),
+			MentionedUserIDs: util.ToPointer([]string(comment.MentionedUserIDs)),
+			EditedAt:         util.TimePtrToStringPtr(comment.EditedAt, time.RFC3339),
+			UpdatedAt:        comment.UpdatedAt.UTC().Format(time.RFC3339),
+			CreatedAt:        comment.CreatedAt.UTC().Format(time.RFC3339),
+			DeletedAt:        util.TimePtrToStringPtr(comment.DeletedAt, time.RFC3339),
+		})
+	}
+
+	// Only the personal space shows what is shared with the user on its own
+	if workspaceID == "" {
+		tombstones, err := r.sharedTombstones(removedMemberships, roles)
+		if err != nil {
+			logger.Log.Error("Failed to get tombstones of unshared items", zap.Error(err), zap.String("userID", userID))
+			return nil, err
+		}
+		changes = append(changes, tombstones...)
+	}
+
 	// sort changes by updated_at
 	sort.Slice(changes, func(i, j int) bool {
 		return changes[i].UpdatedAt < changes[j].UpdatedAt
@@ -120,11 +286,194 @@ func (r *SyncRepository) GetChanges(userID string, from string) (changes []contr
 	return changes, nil
 }
 
-func (r *SyncRepository) Sync(userID string, req *contract.SyncReq) (lastSyncTime time.Time, changes []contract.Change, err error) {
+// sharedChangesSQL matches, for the personal space, the user's own rows outside any workspace updated
+// since @from, and rows of the projects or collections shared with the user that were updated since,
+// or all of them when the membership started or changed since. column ties a row of table to its
+// project or collection, and memberColumn is the column of memberships that points at the same.
+func sharedChangesSQL(table, column, memberColumn string) string {
+	containers := memberTarget(memberColumn, "").table()
+	return `((` + table + `.user_id = @user_id AND ` + table + `.updated_at > @from AND (` + table + `.` + column + ` IS NULL OR ` + table + `.` + column + ` IN (
+		SELECT id FROM ` + containers + ` WHERE user_id = @user_id AND workspace_id IS NULL))) OR ` + table + `.` + column + ` IN (
+		SELECT m.` + memberColumn + ` FROM memberships m
+		WHERE m.user_id = @user_id AND m.removed_at IS NULL AND (m.updated_at > @from OR ` + table + `.updated_at > @from)))`
+}
+
+// workspaceChangesSQL matches the rows of the projects or collections of @workspace_id updated since
+// @from. Its arguments are those of sharedChangesSQL.
+func workspaceChangesSQL(table, column, memberColumn string) string {
+	containers := memberTarget(memberColumn, "").table()
+	return table + `.` + column + ` IN (SELECT id FROM ` + containers + ` WHERE workspace_id = @workspace_id) AND ` + table + `.updated_at > @from`
+}
+
+// workspaceScope returns the user's role on a workspace, or ErrWorkspaceAccess if they aren't a
+// member of it. Everything in it is sent again when the membership started or changed since @from.
+func (r *SyncRepository) workspaceScope(userID, workspaceID string, scope map[string]any) (string, error) {
+	access, err := memberAccess(r.db, userID, MemberTarget{WorkspaceID: &workspaceID})
+	if err != nil {
+		logger.Log.Error("Failed to get workspace access", zap.Error(err), zap.String("userID", userID), zap.String("workspaceID", workspaceID))
+		return "", err
+	}
+	if access == nil || access.Role == "" {
+		return "", ErrWorkspaceAccess
+	}
+
+	var changed int64
+	err = r.db.Model(&model.Membership{}).
+		Where("user_id = ? AND workspace_id = ? AND removed_at IS NULL AND updated_at > ?", userID, workspaceID, scope["from"]).
+		Count(&changed).Error
+	if err != nil {
+		logger.Log.Error("Failed to get workspace membership", zap.Error(err), zap.String("userID", userID), zap.String("workspaceID", workspaceID))
+		return "", err
+	}
+	if changed > 0 {
+		scope["from"] = time.Unix(0, 0).UTC()
+	}
+
+	return access.Role, nil
+}
+
+// sharedTombstones returns deletions of the projects and collections the user stopped being a member
+// of, and of everything in them, so the user's devices drop what is no longer shared. Targets the
+// user is a member of again, which have a role, are skipped.
+func (r *SyncRepository) sharedTombstones(removed []model.Membership, roles map[string]string) ([]contract.Change, error) {
+	tombstones := []contract.Change{}
+	seen := map[string]bool{}
+	for _, membership := range removed {
+		target := MemberTarget{ProjectID: membership.ProjectID, CollectionID: membership.CollectionID}
+		if _, ok := roles[target.id()]; ok || seen[target.id()] {
+			continue
+		}
+		seen[target.id()] = true
+		removedAt := membership.RemovedAt.UTC().Format(time.RFC3339)
+		tombstone := func(changeType, id string) contract.Change {
+			return contract.Change{Type: changeType, EntityID: id, UpdatedAt: removedAt, CreatedAt: removedAt, DeletedAt: &removedAt}
+		}
+
+		if target.ProjectID != nil {
+			tombstones = append(tombstones, tombstone("project", target.id()))
+			var statusIDs, taskIDs []string
+			if err := r.db.Model(&model.ProjectStatus{}).Where("project_id = ?", target.id()).Pluck("id", &statusIDs).Error; err != nil {
+				return nil, err
+			}
+			if err := r.db.Model(&model.Task{}).Where("project_id = ?", target.id()).Pluck("id", &taskIDs).Error; err != nil {
+				return nil, err
+			}
+			for _, id := range statusIDs {
+				tombstones = append(tombstones, tombstone("status", id))
+			}
+			for _, id := range taskIDs {
+				tombstones = append(tombstones, tombstone("task", id))
+			}
+			commentIDs, err := r.commentIDs("task_id", taskIDs)
+			if err != nil {
+				return nil, err
+			}
+			for _, id := range commentIDs {
+				tombstones = append(tombstones, tombstone("comment", id))
+			}
+			continue
+		}
+
+		tombstones = append(tombstones, tombstone("collection", target.id()))
+		var noteIDs []string
+		if err := r.db.Model(&model.Note{}).Where("collection_id = ?", target.id()).Pluck("id", &noteIDs).Error; err != nil {
+			return nil, err
+		}
+		for _, id := range noteIDs {
+			tombstones = append(tombstones, tombstone("note", id))
+		}
+		commentIDs, err := r.commentIDs("note_id", noteIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range commentIDs {
+			tombstones = append(tombstones, tombstone("comment", id))
+		}
+	}
+
+	return tombstones, nil
+}
+
+// commentIDs returns the comments on the tasks or notes with the given IDs; column is task_id or note_id
+func (r *SyncRepository) commentIDs(column string, ids []string) ([]string, error) {
+	commentIDs := []string{}
+	if len(ids) == 0 {
+		return commentIDs, nil
+	}
+	err := r.db.Model(&model.Comment{}).Where(column+" IN ?", ids).Pluck("id", &commentIDs).Error
+	return commentIDs, err
+}
+
+// Sync applies a batch of changes made in the personal space, for an empty workspaceID, or in a
+// workspace the user is a member of, and returns what changed there since the last sync
+//
+// A change the user may not make, such as an edit by a viewer, is left out and returned as rejected
+// while the rest of the batch is applied.
+func (r *SyncRepository) Sync(userID, workspaceID string, req *contract.SyncReq) (lastSyncTime time.Time, changes []contract.Change, rejected []contract.RejectedChange, err error) {
+	actor := ActivityActor{UserID: userID, Source: model.ActivitySourceSync, DeviceID: req.DeviceID}
+	rejected, _, err = r.commitChanges(userID, workspaceID, req.Changes, actor, true, nil)
+	if err != nil {
+		return lastSyncTime, changes, rejected, err
+	}
+	lastSyncTime = time.Now()
+
+	changes, err = r.GetChanges(userID, workspaceID, req.LastSyncTime)
+	if err != nil {
+		logger.Log.Error("Failed to get changes", zap.Error(err), zap.Any("req", req))
+		return lastSyncTime, changes, rejected, err
+	}
+
+	return lastSyncTime, changes, rejected, nil
+}
+
+// ApplyChanges applies changes made on the user's behalf by something other than a device, with
+// the same checks and effects as a sync. Unlike a sync, a change the user may not make fails the
+// whole batch. Returns the activity log entries.
+func (r *SyncRepository) ApplyChanges(userID, workspaceID string, changes []contract.Change, source string) ([]model.Activity, error) {
+	_, activities, err := r.commitChanges(userID, workspaceID, changes, ActivityActor{UserID: userID, Source: source}, false, nil)
+	return activities, err
+}
+
+// errRuleFired rolls back the changes of a rule that already fired under the trigger key
+var errRuleFired = errors.New("rule already fired")
+
+// ApplyRuleChanges applies the changes of a rule's firing like ApplyChanges, and logs the execution
+// in the same transaction, so the rule makes its changes once per trigger key even when it's run
+// again. Returns false without applying them when the rule already fired under the key.
+func (r *SyncRepository) ApplyRuleChanges(rule *model.Rule, execution *model.RuleExecution, changes []contract.Change) (bool, error) {
+	actor := ActivityActor{UserID: rule.UserID, Source: model.ActivitySourceRule}
+	_, _, err := r.commitChanges(rule.UserID, util.ToValue(rule.WorkspaceID), changes, actor, false, func(tx *gorm.DB) error {
+		created, err := createRuleExecution(tx, execution)
+		if err == nil && !created {
+			return errRuleFired
+		}
+		return err
+	})
+	if errors.Is(err, errRuleFired) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// isSyncPermissionError reports whether a change was refused because the user may not make it
+func isSyncPermissionError(err error) bool {
+	return errors.Is(err, ErrShareForbidden) ||
+		errors.Is(err, ErrShareMoveForbidden) ||
+		errors.Is(err, ErrCommentForbidden) ||
+		errors.Is(err, ErrWorkspaceMoveForbidden)
+}
 
+// commitChanges writes a batch of changes in one transaction and logs them as made by the actor.
+// With skipForbidden, changes the user may not make are left out and returned instead of failing the batch.
+// A non-nil prepare runs first in the transaction, and an error from it rolls the batch back.
+func (r *SyncRepository) commitChanges(userID, workspaceID string, batch []contract.Change, actor ActivityActor, skipForbidden bool, prepare func(tx *gorm.DB) error) (rejected []contract.RejectedChange, activities []model.Activity, err error) {
+	rejected = []contract.RejectedChange{}
 	tx := r.db.Begin()
 	if err = tx.Error; err != nil {
-		return lastSyncTime, changes, err
+		return nil, nil, err
 	}
 
 	defer func() {
@@ -133,83 +482,487 @@ func (r *SyncRepository) Sync(userID string, req *contract.SyncReq) (lastSyncTim
 		}
 	}()
 
-	for _, change := range req.Changes {
+	var workspace *syncWorkspace
+	if workspaceID != "" {
+		access, err := memberAccess(tx, userID, MemberTarget{WorkspaceID: &workspaceID})
+		if err != nil {
+			logger.Log.Error("Failed to get workspace access", zap.Error(err), zap.String("userID", userID), zap.String("workspaceID", workspaceID))
+			tx.Rollback()
+			return nil, nil, err
+		}
+		if access == nil || access.Role == "" {
+			tx.Rollback()
+			return nil, nil, ErrWorkspaceAccess
+		}
+		workspace = &syncWorkspace{ID: workspaceID, OwnerID: access.OwnerID, Role: access.Role}
+	}
+	if prepare != nil {
+		if err = prepare(tx); err != nil {
+			tx.Rollback()
+			return nil, nil, err
+		}
+	}
+
+	// Tags, projects and statuses go first so other changes in the same batch can reference them
+	ordered := slices.Clone(batch)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return syncTypeOrder(ordered[i].Type) < syncTypeOrder(ordered[j].Type)
+	})
+
+	// Items of shared projects and collections are written as their owner's, so the task
+	// follow-ups below run for each task's owner
+	taskOwners := map[string]string{}
+	touchedOwners := map[string]bool{}
+	applied := make([]contract.Change, 0, len(ordered))
+	log := newActivityLog(actor)
+	for _, change := range ordered {
+		ownerID, err := syncOwner(tx, userID, workspace, &change)
+		if err != nil {
+			logger.Log.Warn("Rejected change to shared item", zap.Error(err), zap.String("userID", userID), zap.Any("change", change))
+			if skipForbidden && isSyncPermissionError(err) {
+				rejected = append(rejected, contract.RejectedChange{Type: change.Type, EntityID: change.EntityID, Reason: err.Error()})
+				continue
+			}
+			tx.Rollback()
+			return nil, nil, err
+		}
+		// Tags are personal, so a member's tags never land on the owner's items
+		if ownerID != userID {
+			change.TagIDs = nil
+		}
+		if change.Type == "task" {
+			taskOwners[change.EntityID] = ownerID
+		}
+		if change.Type == "task" || change.Type == "status" {
+			touchedOwners[ownerID] = true
+		}
+
+		if err = log.track(tx, change.Type, change.EntityID); err != nil {
+			logger.Log.Error("Failed to snapshot entity for activity", zap.Error(err), zap.Any("change", change))
+			tx.Rollback()
+			return nil, nil, err
+		}
+
 		switch change.Type {
 		case "task":
-			err = r.syncTask(tx, userID, &change)
+			err = r.syncTask(tx, log, ownerID, &change)
 			if err != nil {
 				logger.Log.Error("Failed to sync task", zap.Error(err), zap.Any("change", change))
 				tx.Rollback()
-				return lastSyncTime, changes, err
+				return nil, nil, err
 			}
 		case "project":
-			err = r.syncProject(tx, userID, &change)
+			err = r.syncProject(tx, ownerID, &change)
 			if err != nil {
 				logger.Log.Error("Failed to sync project", zap.Error(err), zap.Any("change", change))
 				tx.Rollback()
-				return lastSyncTime, changes, err
+				return nil, nil, err
 			}
 		case "note":
-			err = r.syncNote(tx, userID, &change)
+			err = r.syncNote(tx, ownerID, &change)
 			if err != nil {
 				logger.Log.Error("Failed to sync note", zap.Error(err), zap.Any("change", change))
 				tx.Rollback()
-				return lastSyncTime, changes, err
+				return nil, nil, err
 			}
 		case "collection":
-			err = r.syncCollection(tx, userID, &change)
+			err = r.syncCollection(tx, ownerID, &change)
 			if err != nil {
 				logger.Log.Error("Failed to sync collection", zap.Error(err), zap.Any("change", change))
 				tx.Rollback()
-				return lastSyncTime, changes, err
+				return nil, nil, err
+			}
+		case "tag":
+			err = r.syncTag(tx, ownerID, &change)
+			if err != nil {
+				logger.Log.Error("Failed to sync tag", zap.Error(err), zap.Any("change", change))
+				tx.Rollback()
+				return nil, nil, err
+			}
+		case "status":
+			err = r.syncStatus(tx, log, ownerID, &change)
+			if err != nil {
+				logger.Log.Error("Failed to sync status", zap.Error(err), zap.Any("change", change))
+				tx.Rollback()
+				return nil, nil, err
+			}
+		case "comment":
+			err = r.syncComment(tx, ownerID, &change)
+			if err != nil {
+				logger.Log.Error("Failed to sync comment", zap.Error(err), zap.Any("change", change))
+				tx.Rollback()
+				return nil, nil, err
+			}
+		}
+
+		// The change is logged ahead of what its follow-ups did to other rows
+		if err = log.flush(tx); err != nil {
+			logger.Log.Error("Failed to record activity", zap.Error(err), zap.Any("change", change))
+			tx.Rollback()
+			return nil, nil, err
+		}
+		applied = append(applied, change)
+	}
+
+	// Dependencies go last so tasks in the same batch can block each other, and sort order
+	// clashes are settled once every task in the batch is in place
+	for _, change := range applied {
+		if change.Type != "task" || (util.ToValue(change.SortOrder) == "" && change.BlockedByTaskIDs == nil) {
+			continue
+		}
+		ownerID := taskOwners[change.EntityID]
+		if err = log.track(tx, "task", change.EntityID); err != nil {
+			logger.Log.Error("Failed to snapshot entity for activity", zap.Error(err), zap.Any("change", change))
+			tx.Rollback()
+			return nil, nil, err
+		}
+		if util.ToValue(change.SortOrder) != "" {
+			err = resolveDuplicateSortOrder(tx, ownerID, change.EntityID)
+			if err != nil {
+				logger.Log.Error("Failed to resolve duplicate sort order", zap.Error(err), zap.Any("change", change))
+				tx.Rollback()
+				return nil, nil, err
 			}
 		}
+		if change.BlockedByTaskIDs == nil {
+			continue
+		}
+		err = r.replaceTaskDependencies(tx, ownerID, change.EntityID, *change.BlockedByTaskIDs)
+		if err != nil {
+			logger.Log.Error("Failed to sync task dependencies", zap.Error(err), zap.Any("change", change))
+			tx.Rollback()
+			return nil, nil, err
+		}
+	}
+	for _, ownerID := range slices.Sorted(maps.Keys(touchedOwners)) {
+		if err = refreshBlockedTasks(tx, log, ownerID); err != nil {
+			logger.Log.Error("Failed to refresh blocked tasks", zap.Error(err), zap.String("userID", ownerID))
+			tx.Rollback()
+			return nil, nil, err
+		}
+	}
+	if err = log.flush(tx); err != nil {
+		logger.Log.Error("Failed to record activity", zap.Error(err))
+		tx.Rollback()
+		return nil, nil, err
 	}
 
 	if err = tx.Commit().Error; err != nil {
 		logger.Log.Error("Failed to commit transaction", zap.Error(err))
-		return lastSyncTime, changes, err
+		return nil, nil, err
 	}
-	lastSyncTime = time.Now()
 
-	changes, err = r.GetChanges(userID, req.LastSyncTime)
+	return rejected, log.activities, nil
+}
+
+// syncWorkspace is the workspace a batch is synced in and the user's role there
+type syncWorkspace struct {
+	ID      string
+	OwnerID string
+	Role    string
+}
+
+// id returns the ID of the workspace, or "" for the personal space
+func (w *syncWorkspace) id() string {
+	if w == nil {
+		return ""
+	}
+	return w.ID
+}
+
+// syncOwner returns whose rows a change writes. Items of a shared project or collection stay with
+// the user who owns it, so members with the editor role write the owner's rows. Viewers can't
+// write, only owners may delete a shared project or collection, and nothing moves between the
+// projects or collections of different users or between workspaces.
+//
+// In a workspace, which is nil for the personal space, new projects and collections join it under
+// its owner's account, and tasks, notes and statuses must be in one of its projects or collections.
+// Projects and collections of a workspace, and what is in them, are only written in that workspace.
+// Comments are always their author's, see syncCommentOwner.
+func syncOwner(tx *gorm.DB, userID string, workspace *syncWorkspace, change *contract.Change) (string, error) {
+	switch change.Type {
+	case "task":
+		return syncItemOwner(tx, userID, workspace, "tasks", "project_id", change.EntityID, change.ProjectID, true)
+	case "note":
+		return syncItemOwner(tx, userID, workspace, "notes", "collection_id", change.EntityID, change.CollectionID, true)
+	case "status":
+		return syncItemOwner(tx, userID, workspace, "project_statuses", "project_id", change.EntityID, change.ProjectID, false)
+	case "project":
+		return syncContainerOwner(tx, userID, workspace, "project_id", change)
+	case "collection":
+		return syncContainerOwner(tx, userID, workspace, "collection_id", change)
+	case "comment":
+		return syncCommentOwner(tx, userID, workspace, change)
+	}
+	return userID, nil
+}
+
+// syncItemOwner returns the owner of a task, note or status, which column ties to its project or
+// collection. A change to a movable item always says which one the item goes in, or nil for none.
+func syncItemOwner(tx *gorm.DB, userID string, workspace *syncWorkspace, table, column, entityID string, containerID *string, movable bool) (string, error) {
+	var existing []struct {
+		UserID      string
+		ContainerID *string
+		WorkspaceID *string
+	}
+	err := tx.Table(table+" t").
+		Select("t.user_id, t."+column+" AS container_id, c.workspace_id").
+		Joins("LEFT JOIN "+memberTarget(column, "").table()+" c ON c.id = t."+column).
+		Where("t.id = ?", entityID).
+		Limit(1).
+		Scan(&existing).Error
 	if err != nil {
-		logger.Log.Error("Failed to get changes", zap.Error(err), zap.Any("req", req))
-		return lastSyncTime, changes, err
+		return "", err
+	}
+
+	ownerID := userID
+	if len(existing) > 0 {
+		ownerID = existing[0].UserID
+		if ownerID != userID {
+			if err := requireSyncRole(tx, userID, column, existing[0].ContainerID, model.MemberRoleEditor); err != nil {
+				return "", err
+			}
+		}
+		if !movable {
+			if util.ToValue(existing[0].WorkspaceID) != workspace.id() {
+				return "", ErrWorkspaceItemOutside
+			}
+			return ownerID, nil
+		}
+	}
+
+	if containerID == nil || *containerID == "" {
+		if workspace != nil {
+			return "", ErrWorkspaceItemOutside
+		}
+		if ownerID != userID {
+			return "", ErrShareMoveForbidden
+		}
+		if len(existing) > 0 && existing[0].WorkspaceID != nil {
+			return "", ErrWorkspaceMoveForbidden
+		}
+		return ownerID, nil
+	}
+
+	access, err := memberAccess(tx, userID, memberTarget(column, *containerID))
+	if err != nil {
+		return "", err
+	}
+	// A missing project or collection is left to the foreign key
+	if access == nil {
+		return ownerID, nil
+	}
+	if util.ToValue(access.WorkspaceID) != workspace.id() {
+		return "", ErrWorkspaceItemOutside
+	}
+	if len(existing) > 0 && util.ToValue(access.WorkspaceID) != util.ToValue(existing[0].WorkspaceID) {
+		return "", ErrWorkspaceMoveForbidden
+	}
+	if len(existing) > 0 && access.OwnerID != ownerID {
+		return "", ErrShareMoveForbidden
 	}
+	if !model.MemberRoleAllows(access.Role, model.MemberRoleEditor) {
+		return "", ErrShareForbidden
+	}
+	return access.OwnerID, nil
+}
+
+// syncContainerOwner returns the owner of a project or collection; column is project_id or collection_id.
+// It sets the workspace of a new one, which the client can't choose.
+func syncContainerOwner(tx *gorm.DB, userID string, workspace *syncWorkspace, column string, change *contract.Change) (string, error) {
+	change.WorkspaceID = nil
+	target := memberTarget(column, change.EntityID)
+	var existing []struct {
+		UserID      string
+		WorkspaceID *string
+	}
+	err := tx.Table(target.table()).
+		Select("user_id, workspace_id").
+		Where("id = ?", change.EntityID).
+		Limit(1).
+		Scan(&existing).Error
+	if err != nil {
+		return "", err
+	}
+	if len(existing) == 0 {
+		if workspace == nil {
+			return userID, nil
+		}
+		if !model.MemberRoleAllows(workspace.Role, model.MemberRoleEditor) {
+			return "", ErrShareForbidden
+		}
+		change.WorkspaceID = &workspace.ID
+		return workspace.OwnerID, nil
+	}
+	if util.ToValue(existing[0].WorkspaceID) != workspace.id() {
+		return "", ErrWorkspaceItemOutside
+	}
+	if existing[0].UserID == userID {
+		return userID, nil
+	}
+
+	minimum := model.MemberRoleEditor
+	if change.DeletedAt != nil {
+		minimum = model.MemberRoleOwner
+	}
+	if err := requireSyncRole(tx, userID, column, &change.EntityID, minimum); err != nil {
+		return "", err
+	}
+	return existing[0].UserID, nil
+}
 
-	return lastSyncTime, changes, nil
+// requireSyncRole returns ErrShareForbidden unless the user has at least the minimum role on a live project or collection
+func requireSyncRole(tx *gorm.DB, userID, column string, containerID *string, minimum string) error {
+	if containerID == nil {
+		return ErrShareForbidden
+	}
+	access, err := memberAccess(tx, userID, memberTarget(column, *containerID))
+	if err != nil {
+		return err
+	}
+	if access == nil || !model.MemberRoleAllows(access.Role, minimum) {
+		return ErrShareForbidden
+	}
+	return nil
 }
 
-func (r *SyncRepository) syncTask(tx *gorm.DB, userID string, change *contract.Change) error {
+// syncTask writes a task change. What it does to other tasks, such as subtasks, parents and the
+// next instance of a recurring task, is tracked by the log.
+func (r *SyncRepository) syncTask(tx *gorm.DB, log *activityLog, userID string, change *contract.Change) error {
 	var dueDate *time.Time
 	if change.DueDate != nil {
 		dueDate = util.StringPtrToTimePtr(change.DueDate, time.RFC3339)
 	}
 
+	// "" unschedules and 0 clears the estimate
+	var startDate *time.Time
+	if change.StartDate != nil && *change.StartDate != "" {
+		startDate = util.StringPtrToTimePtr(change.StartDate, time.RFC3339)
+	}
+	var estimateMinutes *int
+	if change.EstimateMinutes != nil && *change.EstimateMinutes > 0 {
+		estimateMinutes = change.EstimateMinutes
+	}
+
+	sortOrder := syncSortOrder(change)
+
+	// Remember the current parent so it can be rolled up if the task moves away
+	var existing model.Task
+	if err := tx.Where("id = ? AND user_id = ?", change.EntityID, userID).
+		Limit(1).
+		Find(&existing).Error; err != nil {
+		return err
+	}
+	previousParentID := existing.ParentTaskID
+	wasCompleted := existing.ID != "" && existing.Status == model.TaskStatusCompleted
+	var previousStatus *int
+	if existing.ID != "" {
+		previousStatus = &existing.Status
+	}
+
+	// A custom status decides the category. A bare category change, or a move to another
+	// project, drops a custom status that no longer fits.
+	status := change.Status
+	var statusID *string
+	statusIDChanged := change.StatusID != nil
+	if change.StatusID != nil && *change.StatusID != "" {
+		projectStatus, err := r.findProjectStatus(tx, userID, *change.StatusID, change.ProjectID)
+		if err != nil {
+			return err
+		}
+		code, _ := model.StatusCategoryCode(projectStatus.Category)
+		status = &code
+		statusID = &projectStatus.ID
+	} else if change.StatusID == nil && existing.StatusID != nil {
+		projectStatus, err := r.findProjectStatus(tx, userID, *existing.StatusID, change.ProjectID)
+		switch {
+		case errors.Is(err, ErrStatusNotFound):
+			statusIDChanged = true
+		case err != nil:
+			return err
+		case status != nil && model.StatusCategoryOf(*status) != projectStatus.Category:
+			statusIDChanged = true
+			statusID, err = defaultProjectStatusID(tx, change.ProjectID, model.StatusCategoryOf(*status))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	var recurrenceRule *string
+	ruleChanged := false
+	if change.RecurrenceRule != nil {
+		if *change.RecurrenceRule != "" {
+			if err := recurrence.Validate(*change.RecurrenceRule); err != nil {
+				return errors.Join(ErrInvalidRecurrenceRule, err)
+			}
+			recurrenceRule = change.RecurrenceRule
+		}
+		ruleChanged = util.ToValue(recurrenceRule) != util.ToValue(existing.RecurrenceRule)
+	}
+
+	var parentTaskID *string
+	if change.ParentTaskID != nil && *change.ParentTaskID != "" {
+		parentTaskID = change.ParentTaskID
+		if err := r.validateTaskParent(tx, userID, change.EntityID, *parentTaskID); err != nil {
+			return err
+		}
+	}
+
 	// Prepare only non-falsy updates
 	updates := map[string]any{}
 
 	updates["project_id"] = change.ProjectID
 
+	if change.ParentTaskID != nil {
+		updates["parent_task_id"] = parentTaskID
+	}
 	if change.Title != nil {
 		updates["title"] = change.Title
 	}
 	if change.Description != nil {
 		updates["description"] = change.Description
 	}
-	if change.Status != nil {
-		updates["status"] = *change.Status
+	statusChanged := status != nil && util.ToValue(previousStatus) != *status
+	if status != nil {
+		updates["status"] = *status
+	}
+	if statusChanged {
+		updates["completed_at"] = completedAtFor(util.ToValue(status))
+	}
+	if statusIDChanged {
+		updates["status_id"] = statusID
 	}
 	if change.SortOrder != nil {
-		updates["sort_order"] = change.SortOrder
+		updates["sort_order"] = sortOrder
 	}
 	if dueDate != nil {
 		updates["due_date"] = dueDate
 	}
+	if change.StartDate != nil {
+		updates["start_date"] = startDate
+	}
+	if change.Priority != nil {
+		updates["priority"] = *change.Priority
+	}
+	if change.EstimateMinutes != nil {
+		updates["estimate_minutes"] = estimateMinutes
+	}
 	if change.DeletedAt != nil {
 		updates["deleted_at"] = change.DeletedAt
 	}
+	if ruleChanged {
+		// Re-anchor the rule so COUNT and UNTIL are counted from when it was set
+		updates["recurrence_rule"] = recurrenceRule
+		updates["recurrence_start"] = gorm.Expr("COALESCE(?, due_date, CURRENT_TIMESTAMP)", dueDate)
+		updates["recurrence_series_id"] = gorm.Expr("COALESCE(recurrence_series_id, id)")
+	}
+	if change.RecurrenceMode != nil {
+		updates["recurrence_mode"] = *change.RecurrenceMode
+	}
+	if change.ReminderOffsets != nil {
+		updates["reminder_offsets"] = datatypes.NewJSONSlice(*change.ReminderOffsets)
+	}
 
 	// Try update first
 	res := tx.Model(&model.Task{}).
@@ -222,26 +975,321 @@ func (r *SyncRepository) syncTask(tx *gorm.DB, userID string, change *contract.C
 
 	// If nothing updated → create
 	if res.RowsAffected == 0 {
-		var status int
-		if change.Status != nil {
-			status = *change.Status
-		}
 		task := &model.Task{
-			ID:          change.EntityID,
-			ProjectID:   change.ProjectID,
-			UserID:      userID,
-			Title:       change.Title,
-			Description: change.Description,
-			Status:      status,
-			SortOrder:   change.SortOrder,
-			DueDate:     dueDate,
+			ID:              change.EntityID,
+			ProjectID:       change.ProjectID,
+			UserID:          userID,
+			Title:           change.Title,
+			Description:     change.Description,
+			Status:          util.ToValue(status),
+			StatusID:        statusID,
+			SortOrder:       sortOrder,
+			DueDate:         dueDate,
+			StartDate:       startDate,
+			Priority:        util.ToValue(change.Priority),
+			EstimateMinutes: estimateMinutes,
+			ParentTaskID:    parentTaskID,
 		}
-		return tx.Create(task).Error
-	}
-
-	return nil
-}
-
+		if change.ReminderOffsets != nil {
+			task.ReminderOffsets = util.ToPointer(datatypes.NewJSONSlice(*change.ReminderOffsets))
+		}
+		if recurrenceRule != nil {
+			task.RecurrenceRule = recurrenceRule
+			task.RecurrenceMode = util.ToValue(change.RecurrenceMode)
+			task.RecurrenceStart = util.ToPointer(time.Now())
+			if dueDate != nil {
+				task.RecurrenceStart = dueDate
+			}
+			task.RecurrenceSeriesID = util.ToPointer(change.EntityID)
+		}
+		if status != nil && *status == model.TaskStatusCompleted {
+			task.CompletedAt = util.ToPointer(time.Now())
+		}
+		if err := tx.Create(task).Error; err != nil {
+			return err
+		}
+		statusChanged = true
+	}
+
+	if statusChanged {
+		var task model.Task
+		if err := tx.Where("id = ? AND user_id = ?", change.EntityID, userID).First(&task).Error; err != nil {
+			return err
+		}
+		if err := recordTaskStatusChange(tx, &task, previousStatus); err != nil {
+			return err
+		}
+	}
+
+	// Completing a recurring task moves it on to its next occurrence
+	if status != nil && *status == model.TaskStatusCompleted && !wasCompleted && change.DeletedAt == nil {
+		var task model.Task
+		if err := tx.Where("id = ? AND user_id = ?", change.EntityID, userID).First(&task).Error; err != nil {
+			return err
+		}
+		if task.RecurrenceRule != nil {
+			if err := advanceRecurringTask(tx, log, &task); err != nil {
+				return err
+			}
+		}
+	}
+
+	if change.DeletedAt != nil {
+		if err := r.deleteSubtasks(tx, log, userID, change.EntityID, *change.DeletedAt); err != nil {
+			return err
+		}
+	}
+	if status != nil && *status == model.TaskStatusCompleted && util.ToValue(change.CompleteSubtasks) {
+		if err := r.completeSubtasks(tx, log, userID, change.EntityID); err != nil {
+			return err
+		}
+	}
+
+	// Roll completion up to the old and new parents
+	currentParentID := previousParentID
+	if change.ParentTaskID != nil {
+		currentParentID = parentTaskID
+	}
+	if err := r.rollUpTaskStatus(tx, log, userID, currentParentID); err != nil {
+		return err
+	}
+	if previousParentID != nil && util.ToValue(previousParentID) != util.ToValue(currentParentID) {
+		if err := r.rollUpTaskStatus(tx, log, userID, previousParentID); err != nil {
+			return err
+		}
+	}
+
+	if change.TagIDs != nil {
+		return r.replaceTaskTags(tx, userID, change.EntityID, *change.TagIDs)
+	}
+
+	return nil
+}
+
+// MoveBoardTask moves a task of a project board to a column and a position in one transaction.
+// The status change goes through syncTask, so it has the same effects as one made on a device.
+// The new status and sort order reach every device through the change feed. userID owns the
+// task and actorID, who may be a member of a shared project, is who the activity log credits.
+func (r *SyncRepository) MoveBoardTask(ctx context.Context, actorID, userID, projectID, taskID string, column BoardColumn, afterTaskID, beforeTaskID *string) (*model.Task, error) {
+	var task model.Task
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("id = ? AND user_id = ? AND project_id = ? AND deleted_at IS NULL", taskID, userID, projectID).
+			Limit(1).
+			Find(&task).Error
+		if err != nil {
+			return err
+		}
+		if task.ID == "" {
+			return ErrTaskNotFound
+		}
+		log := newActivityLog(ActivityActor{UserID: actorID, Source: model.ActivitySourceBoard})
+		if err := log.track(tx, "task", taskID); err != nil {
+			return err
+		}
+
+		moved := util.ToValue(task.StatusID) != util.ToValue(column.StatusID) ||
+			(column.StatusID == nil && model.StatusCategoryOf(task.Status) != column.Category)
+		if moved {
+			change := &contract.Change{
+				Type:      "task",
+				EntityID:  task.ID,
+				ProjectID: task.ProjectID,
+				StatusID:  util.ToPointer(util.ToValue(column.StatusID)),
+			}
+			if column.StatusID == nil {
+				code, _ := model.StatusCategoryCode(column.Category)
+				change.Status = &code
+			}
+			if err := r.syncTask(tx, log, userID, change); err != nil {
+				return err
+			}
+			if err := refreshBlockedTasks(tx, log, userID); err != nil {
+				return err
+			}
+			if err := tx.Where("id = ?", taskID).First(&task).Error; err != nil {
+				return err
+			}
+		}
+
+		if _, err := placeTask(tx, log, &task, afterTaskID, beforeTaskID); err != nil {
+			return err
+		}
+		if err := log.flush(tx); err != nil {
+			return err
+		}
+
+		return tx.Preload("WorkflowStatus").
+			Preload("Tags", "deleted_at IS NULL").
+			Preload("BlockedBy").
+			Where("id = ?", taskID).
+			First(&task).Error
+	})
+	if err != nil {
+		logger.Log.Error("Failed to move board task", zap.Error(err), zap.String("taskID", taskID))
+		return nil, err
+	}
+
+	return &task, nil
+}
+
+// syncSortOrder returns the sort order sent with a change, "" clearing it. A key that isn't a
+// fractional index key, like one from a client that predates them, is dropped from the change
+// so the item keeps its current place instead of failing the whole batch.
+func syncSortOrder(change *contract.Change) *string {
+	if change.SortOrder == nil || *change.SortOrder == "" {
+		return nil
+	}
+	if err := fractional.Validate(*change.SortOrder); err != nil {
+		logger.Log.Warn("Ignoring invalid sort order", zap.Error(err), zap.String("entityID", change.EntityID), zap.String("sortOrder", *change.SortOrder))
+		change.SortOrder = nil
+		return nil
+	}
+	return change.SortOrder
+}
+
+// validateTaskParent rejects parents that are missing, would create a cycle, or exceed TaskMaxDepth
+func (r *SyncRepository) validateTaskParent(tx *gorm.DB, userID, taskID, parentTaskID string) error {
+	// Walk up from the parent; the chain length is the parent's depth
+	var ancestorIDs []string
+	err := tx.Raw(`
+		WITH RECURSIVE ancestors AS (
+			SELECT id, parent_task_id, 1 AS depth FROM tasks
+			WHERE id = ? AND user_id = ? AND deleted_at IS NULL
+			UNION ALL
+			SELECT t.id, t.parent_task_id, a.depth + 1 FROM tasks t
+			JOIN ancestors a ON t.id = a.parent_task_id
+			WHERE a.depth <= ?
+		)
+		SELECT id FROM ancestors`, parentTaskID, userID, model.TaskMaxDepth).
+		Scan(&ancestorIDs).Error
+	if err != nil {
+		return err
+	}
+	if len(ancestorIDs) == 0 {
+		return ErrTaskParentNotFound
+	}
+	for _, ancestorID := range ancestorIDs {
+		if ancestorID == taskID {
+			return ErrTaskCycle
+		}
+	}
+
+	// Walk down from the task; its subtree moves along with it
+	var subtreeHeight int
+	err = tx.Raw(`
+		WITH RECURSIVE descendants AS (
+			SELECT id, 0 AS depth FROM tasks WHERE id = ? AND user_id = ?
+			UNION ALL
+			SELECT t.id, d.depth + 1 FROM tasks t
+			JOIN descendants d ON t.parent_task_id = d.id
+			WHERE t.deleted_at IS NULL AND d.depth <= ?
+		)
+		SELECT COALESCE(MAX(depth), 0) FROM descendants`, taskID, userID, model.TaskMaxDepth).
+		Scan(&subtreeHeight).Error
+	if err != nil {
+		return err
+	}
+
+	if len(ancestorIDs)+1+subtreeHeight > model.TaskMaxDepth {
+		return ErrTaskTooDeep
+	}
+
+	return nil
+}
+
+// descendantTaskIDsSQL selects every descendant of a task, excluding the task itself
+const descendantTaskIDsSQL = `
+	WITH RECURSIVE descendants AS (
+		SELECT id FROM tasks WHERE parent_task_id = ? AND user_id = ?
+		UNION
+		SELECT t.id FROM tasks t JOIN descendants d ON t.parent_task_id = d.id
+	)
+	SELECT id FROM descendants`
+
+// deleteSubtasks soft-deletes the subtree of a deleted task
+func (r *SyncRepository) deleteSubtasks(tx *gorm.DB, log *activityLog, userID, taskID, deletedAt string) error {
+	var subtaskIDs []string
+	err := tx.Model(&model.Task{}).
+		Where("user_id = ? AND deleted_at IS NULL", userID).
+		Where("id IN ("+descendantTaskIDsSQL+")", taskID, userID).
+		Pluck("id", &subtaskIDs).Error
+	if err != nil || len(subtaskIDs) == 0 {
+		return err
+	}
+
+	if err := log.track(tx, "task", subtaskIDs...); err != nil {
+		return err
+	}
+	return tx.Model(&model.Task{}).
+		Where("id IN ?", subtaskIDs).
+		Updates(map[string]any{"deleted_at": deletedAt}).Error
+}
+
+// completeSubtasks completes the subtree of a task, leaving cancelled subtasks alone
+func (r *SyncRepository) completeSubtasks(tx *gorm.DB, log *activityLog, userID, taskID string) error {
+	var subtaskIDs []string
+	err := tx.Model(&model.Task{}).
+		Where("user_id = ? AND deleted_at IS NULL AND status NOT IN ?", userID, []int{model.TaskStatusCompleted, model.TaskStatusCancelled}).
+		Where("id IN ("+descendantTaskIDsSQL+")", taskID, userID).
+		Pluck("id", &subtaskIDs).Error
+	if err != nil {
+		return err
+	}
+
+	return setTaskStatus(tx, log, userID, subtaskIDs, model.TaskStatusCompleted)
+}
+
+// rollUpTaskStatus completes a parent once all its subtasks are done, reopens it
+// when a subtask is reopened, and repeats for each ancestor whose status changed
+func (r *SyncRepository) rollUpTaskStatus(tx *gorm.DB, log *activityLog, userID string, parentTaskID *string) error {
+	for depth := 0; parentTaskID != nil && depth < model.TaskMaxDepth; depth++ {
+		var parent model.Task
+		err := tx.Where("id = ? AND user_id = ? AND deleted_at IS NULL", *parentTaskID, userID).First(&parent).Error
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil
+			}
+			return err
+		}
+
+		var counts struct {
+			Total     int64
+			Done      int64
+			Completed int64
+		}
+		err = tx.Raw(`
+			SELECT
+				COUNT(*) AS total,
+				COUNT(*) FILTER (WHERE status IN (?, ?)) AS done,
+				COUNT(*) FILTER (WHERE status = ?) AS completed
+			FROM tasks
+			WHERE parent_task_id = ? AND user_id = ? AND deleted_at IS NULL`,
+			model.TaskStatusCompleted, model.TaskStatusCancelled, model.TaskStatusCompleted, parent.ID, userID).
+			Scan(&counts).Error
+		if err != nil {
+			return err
+		}
+
+		status := parent.Status
+		if counts.Total > 0 && counts.Done == counts.Total && counts.Completed > 0 {
+			status = model.TaskStatusCompleted
+		} else if parent.Status == model.TaskStatusCompleted && counts.Done < counts.Total {
+			status = model.TaskStatusInProgress
+		}
+		if status == parent.Status {
+			return nil
+		}
+
+		if err := setTaskStatus(tx, log, userID, []string{parent.ID}, status); err != nil {
+			return err
+		}
+
+		parentTaskID = parent.ParentTaskID
+	}
+
+	return nil
+}
+
 func (r *SyncRepository) syncProject(tx *gorm.DB, userID string, change *contract.Change) error {
 
 	// Prepare only non-falsy updates
@@ -277,6 +1325,7 @@ func (r *SyncRepository) syncProject(tx *gorm.DB, userID string, change *contrac
 			Title:       change.Title,
 			Description: change.Description,
 			Color:       change.Color,
+			WorkspaceID: change.WorkspaceID,
 		}
 		return tx.Create(project).Error
 	}
@@ -284,11 +1333,15 @@ func (r *SyncRepository) syncProject(tx *gorm.DB, userID string, change *contrac
 	return nil
 }
 
-func (r *SyncRepository) syncNote(tx *gorm.DB, userID string, change *contract.Change) (err error) {
-	var embedding *pgvector.Vector
+func (r *SyncRepository) syncNote(tx *gorm.DB, userID string, change *contract.Change) error {
+	embedding := r.generateNoteEmbedding(context.Background(), change.EntityID, util.ToValue(change.Title), util.ToValue(change.Content))
+	return r.saveNote(tx, userID, change, embedding)
+}
+
+// generateNoteEmbedding embeds a note's title and content. Returns nil when there is nothing
+// to embed or generation failed, so the note is still saved.
+func (r *SyncRepository) generateNoteEmbedding(ctx context.Context, noteID, title, content string) *pgvector.Vector {
 	var embeddingText string
-	title := util.ToValue(change.Title)
-	content := util.ToValue(change.Content)
 	if title != "" {
 		embeddingText = "Title: " + title
 	}
@@ -298,18 +1351,20 @@ func (r *SyncRepository) syncNote(tx *gorm.DB, userID string, change *contract.C
 	if content != "" {
 		embeddingText += "Content: " + content
 	}
+	if embeddingText == "" {
+		return nil
+	}
 
-	if embeddingText != "" {
-		ctx := context.Background()
-		emb, err := r.openaiClient.GenerateEmbedding(ctx, embeddingText)
-		if err != nil {
-			logger.Log.Error("Failed to generate embedding for note", zap.Error(err), zap.String("noteID", change.EntityID))
-			embedding = nil
-		} else {
-			embedding = util.ToPointer(pgvector.NewVector(emb))
-		}
+	emb, err := r.openaiClient.GenerateEmbedding(ctx, embeddingText)
+	if err != nil {
+		logger.Log.Error("Failed to generate embedding for note", zap.Error(err), zap.String("noteID", noteID))
+		return nil
 	}
+	return util.ToPointer(pgvector.NewVector(emb))
+}
 
+// saveNote applies a note change with an already generated embedding, which may be nil
+func (r *SyncRepository) saveNote(tx *gorm.DB, userID string, change *contract.Change, embedding *pgvector.Vector) error {
 	// Prepare only non-falsy updates
 	updates := map[string]any{}
 
@@ -350,12 +1405,395 @@ func (r *SyncRepository) syncNote(tx *gorm.DB, userID string, change *contract.C
 			Content:      change.Content,
 			Embedding:    embedding,
 		}
-		return tx.Create(note).Error
+		if err := tx.Create(note).Error; err != nil {
+			return err
+		}
+	}
+
+	if change.DeletedAt != nil {
+		if err := revokeShareLinks(tx, userID, "note_id", change.EntityID); err != nil {
+			return err
+		}
+	}
+	if change.Content != nil {
+		if err := r.syncNoteLinks(tx, userID, change.EntityID, *change.Content); err != nil {
+			return err
+		}
+	}
+	if change.Title != nil {
+		if err := r.resolveDanglingLinks(tx, userID, change.EntityID, *change.Title); err != nil {
+			return err
+		}
+	}
+
+	if change.TagIDs != nil {
+		return r.replaceNoteTags(tx, userID, change.EntityID, *change.TagIDs)
 	}
 
 	return nil
 }
 
+// ImportedNote is a note read from an import, with its collection and tags given by name
+type ImportedNote struct {
+	Title   string
+	Content string
+	// CollectionTitle is empty for notes outside any collection
+	CollectionTitle string
+	TagNames        []string
+}
+
+// ImportNote creates a note through the sync path, finding or creating its collection and tags by name.
+// Returns the note's ID. The embedding is left to EmbedNotes so imports don't wait on it.
+func (r *SyncRepository) ImportNote(ctx context.Context, userID string, imported *ImportedNote) (string, error) {
+	change := &contract.Change{
+		Type:     "note",
+		EntityID: uuid.New().String(),
+		Title:    &imported.Title,
+		Content:  &imported.Content,
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if imported.CollectionTitle != "" {
+			collectionID, err := r.collectionByTitle(tx, userID, imported.CollectionTitle)
+			if err != nil {
+				return err
+			}
+			change.CollectionID = &collectionID
+		}
+		if len(imported.TagNames) > 0 {
+			tagIDs, err := r.tagsByName(tx, userID, imported.TagNames)
+			if err != nil {
+				return err
+			}
+			change.TagIDs = &tagIDs
+		}
+		if err := r.saveNote(tx, userID, change, nil); err != nil {
+			return err
+		}
+		return recordImportActivity(tx, userID, "note", change.EntityID)
+	})
+	if err != nil {
+		logger.Log.Error("Failed to import note", zap.Error(err), zap.String("userID", userID))
+		return "", err
+	}
+
+	return change.EntityID, nil
+}
+
+// ResolveImportedLinks points the still dangling links of imported notes at the imported note
+// they name by file name or path, since that is how Obsidian links notes. Keys of noteIDsByName
+// are lowercased. Returns the number of links resolved.
+func (r *SyncRepository) ResolveImportedLinks(ctx context.Context, userID string, sourceNoteIDs []string, noteIDsByName map[string]string) (int, error) {
+	if len(sourceNoteIDs) == 0 {
+		return 0, nil
+	}
+
+	var links []model.NoteLink
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND target_note_id IS NULL AND source_note_id IN ?", userID, sourceNoteIDs).
+		Find(&links).Error
+	if err != nil {
+		logger.Log.Error("Failed to list imported links", zap.Error(err), zap.String("userID", userID))
+		return 0, err
+	}
+
+	resolved := 0
+	for _, link := range links {
+		noteID, ok := noteIDsByName[strings.ToLower(link.TargetTitle)]
+		if !ok {
+			continue
+		}
+		err := r.db.WithContext(ctx).Model(&model.NoteLink{}).
+			Where("id = ?", link.ID).
+			Update("target_note_id", noteID).Error
+		if err != nil {
+			logger.Log.Error("Failed to resolve imported link", zap.Error(err), zap.String("linkID", link.ID))
+			return resolved, err
+		}
+		resolved++
+	}
+
+	return resolved, nil
+}
+
+// EmbedNotes generates embeddings for the given notes that don't have one yet.
+// Returns the number of notes embedded.
+func (r *SyncRepository) EmbedNotes(ctx context.Context, noteIDs []string) (int, error) {
+	if len(noteIDs) == 0 {
+		return 0, nil
+	}
+
+	var notes []model.Note
+	err := r.db.WithContext(ctx).
+		Select("id, title, content").
+		Where("id IN ? AND embedding IS NULL AND deleted_at IS NULL", noteIDs).
+		Find(&notes).Error
+	if err != nil {
+		logger.Log.Error("Failed to list notes to embed", zap.Error(err))
+		return 0, err
+	}
+
+	embedded := 0
+	for _, note := range notes {
+		embedding := r.generateNoteEmbedding(ctx, note.ID, util.ToValue(note.Title), util.ToValue(note.Content))
+		if embedding == nil {
+			continue
+		}
+		// UpdateColumn leaves updated_at alone, so clients don't sync the note again for it
+		err := r.db.WithContext(ctx).Model(&model.Note{}).
+			Where("id = ?", note.ID).
+			UpdateColumn("embedding", embedding).Error
+		if err != nil {
+			logger.Log.Error("Failed to save note embedding", zap.Error(err), zap.String("noteID", note.ID))
+			return embedded, err
+		}
+		embedded++
+	}
+
+	return embedded, nil
+}
+
+// ImportedTask is a task read from an import, with its project, status and tags given by name
+type ImportedTask struct {
+	Title       string
+	Description *string
+	// ProjectTitle is empty for tasks outside any project
+	ProjectTitle string
+	// StatusName is matched against the custom statuses of the task's project. When none has that
+	// name, Category decides the status, and an empty Category means the name wasn't recognized.
+	StatusName      string
+	Category        string
+	Priority        int
+	DueDate         *time.Time
+	StartDate       *time.Time
+	EstimateMinutes *int
+	TagNames        []string
+}
+
+// ImportedTaskResult describes a task created by ImportTasks
+type ImportedTaskResult struct {
+	TaskID    string
+	ProjectID *string
+	// Set on the first task of a project the import created
+	ProjectCreated bool
+	StatusName     string
+	// False when the task's status name wasn't recognized and it was left to do
+	StatusMatched bool
+}
+
+// errImportDryRun rolls back a dry-run import once all of its tasks are in
+var errImportDryRun = errors.New("import dry run")
+
+// ImportTasks creates tasks through the sync path in one transaction, finding or creating their
+// projects and tags by name. Tasks go to the end of their project in the order given.
+// A dry run does all of it and then rolls back, so its results preview the import exactly.
+func (r *SyncRepository) ImportTasks(ctx context.Context, userID string, tasks []ImportedTask, dryRun bool) ([]ImportedTaskResult, error) {
+	results := make([]ImportedTaskResult, 0, len(tasks))
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		log := newActivityLog(ActivityActor{UserID: userID, Source: model.ActivitySourceImport})
+		for i := range tasks {
+			result, err := r.importTask(tx, log, userID, &tasks[i])
+			if err != nil {
+				return err
+			}
+			results = append(results, *result)
+		}
+		if dryRun {
+			return errImportDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errImportDryRun) {
+		logger.Log.Error("Failed to import tasks", zap.Error(err), zap.String("userID", userID))
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func (r *SyncRepository) importTask(tx *gorm.DB, log *activityLog, userID string, imported *ImportedTask) (*ImportedTaskResult, error) {
+	result := &ImportedTaskResult{TaskID: uuid.New().String()}
+	change := &contract.Change{
+		Type:            "task",
+		EntityID:        result.TaskID,
+		Title:           &imported.Title,
+		Description:     imported.Description,
+		Priority:        &imported.Priority,
+		EstimateMinutes: imported.EstimateMinutes,
+	}
+	if imported.DueDate != nil {
+		change.DueDate = util.ToPointer(imported.DueDate.Format(time.RFC3339))
+	}
+	if imported.StartDate != nil {
+		change.StartDate = util.ToPointer(imported.StartDate.Format(time.RFC3339))
+	}
+
+	if imported.ProjectTitle != "" {
+		projectID, created, err := r.projectByTitle(tx, userID, imported.ProjectTitle)
+		if err != nil {
+			return nil, err
+		}
+		change.ProjectID = &projectID
+		result.ProjectID = &projectID
+		result.ProjectCreated = created
+	}
+
+	category := imported.Category
+	result.StatusMatched = category != ""
+	if category == "" {
+		category = model.StatusCategoryTodo
+	}
+	code, _ := model.StatusCategoryCode(category)
+	change.Status = &code
+	result.StatusName = model.DefaultStatusName(category)
+
+	// A custom status of the project wins over the category, which otherwise lands
+	// the task in the project's first status of that category
+	if change.ProjectID != nil && imported.StatusName != "" {
+		var statuses []model.ProjectStatus
+		err := tx.Where("project_id = ? AND deleted_at IS NULL AND LOWER(name) = LOWER(?)", *change.ProjectID, imported.StatusName).
+			Order(sortOrderColumn + " ASC NULLS LAST, created_at ASC").
+			Limit(1).
+			Find(&statuses).Error
+		if err != nil {
+			return nil, err
+		}
+		if len(statuses) > 0 {
+			change.StatusID = &statuses[0].ID
+			result.StatusName = statuses[0].Name
+			result.StatusMatched = true
+		}
+	}
+	if change.ProjectID != nil && change.StatusID == nil {
+		statusID, err := defaultProjectStatusID(tx, change.ProjectID, category)
+		if err != nil {
+			return nil, err
+		}
+		if statusID != nil {
+			var status model.ProjectStatus
+			if err := tx.Where("id = ?", *statusID).First(&status).Error; err != nil {
+				return nil, err
+			}
+			change.StatusID = statusID
+			result.StatusName = status.Name
+		}
+	}
+
+	if len(imported.TagNames) > 0 {
+		tagIDs, err := r.tagsByName(tx, userID, imported.TagNames)
+		if err != nil {
+			return nil, err
+		}
+		change.TagIDs = &tagIDs
+	}
+
+	if err := log.track(tx, "task", result.TaskID); err != nil {
+		return nil, err
+	}
+	if err := r.syncTask(tx, log, userID, change); err != nil {
+		return nil, err
+	}
+	task := &model.Task{ID: result.TaskID, UserID: userID, ProjectID: change.ProjectID}
+	if _, err := placeTask(tx, log, task, nil, nil); err != nil {
+		return nil, err
+	}
+	if err := log.flush(tx); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// recordImportActivity logs the creation of an entity by an import of the user's
+func recordImportActivity(tx *gorm.DB, userID, entityType, entityID string) error {
+	_, err := recordActivity(tx, ActivityActor{UserID: userID, Source: model.ActivitySourceImport}, entityType, entityID, nil)
+	return err
+}
+
+// collectionByTitle returns the user's live collection with the given title, creating it if there is none
+func (r *SyncRepository) collectionByTitle(tx *gorm.DB, userID, title string) (string, error) {
+	var ids []string
+	err := tx.Model(&model.Collection{}).
+		Where("user_id = ? AND workspace_id IS NULL AND deleted_at IS NULL AND LOWER(title) = LOWER(?)", userID, title).
+		Order("created_at ASC").
+		Limit(1).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return "", err
+	}
+	if len(ids) > 0 {
+		return ids[0], nil
+	}
+
+	collectionID := uuid.New().String()
+	if err := r.syncCollection(tx, userID, &contract.Change{Type: "collection", EntityID: collectionID, Title: &title}); err != nil {
+		return "", err
+	}
+	return collectionID, recordImportActivity(tx, userID, "collection", collectionID)
+}
+
+// projectByTitle returns the user's live project with the given title, creating it if there is none.
+// Reports whether the project was created.
+func (r *SyncRepository) projectByTitle(tx *gorm.DB, userID, title string) (string, bool, error) {
+	var ids []string
+	err := tx.Model(&model.Project{}).
+		Where("user_id = ? AND workspace_id IS NULL AND deleted_at IS NULL AND LOWER(title) = LOWER(?)", userID, title).
+		Order("created_at ASC").
+		Limit(1).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return "", false, err
+	}
+	if len(ids) > 0 {
+		return ids[0], false, nil
+	}
+
+	projectID := uuid.New().String()
+	if err := r.syncProject(tx, userID, &contract.Change{Type: "project", EntityID: projectID, Title: &title}); err != nil {
+		return "", false, err
+	}
+	return projectID, true, recordImportActivity(tx, userID, "project", projectID)
+}
+
+// tagsByName returns the IDs of the user's live tags with the given names, creating the missing ones
+func (r *SyncRepository) tagsByName(tx *gorm.DB, userID string, names []string) ([]string, error) {
+	tagIDs := []string{}
+	seen := map[string]bool{}
+	for _, name := range names {
+		name = util.NormalizeTagName(name)
+		key := strings.ToLower(name)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		var ids []string
+		err := tx.Model(&model.Tag{}).
+			Where("user_id = ? AND deleted_at IS NULL AND LOWER(name) = ?", userID, key).
+			Order("created_at ASC").
+			Limit(1).
+			Pluck("id", &ids).Error
+		if err != nil {
+			return nil, err
+		}
+		if len(ids) > 0 {
+			tagIDs = append(tagIDs, ids[0])
+			continue
+		}
+
+		tagID := uuid.New().String()
+		if err := r.syncTag(tx, userID, &contract.Change{Type: "tag", EntityID: tagID, Name: &name}); err != nil {
+			return nil, err
+		}
+		if err := recordImportActivity(tx, userID, "tag", tagID); err != nil {
+			return nil, err
+		}
+		tagIDs = append(tagIDs, tagID)
+	}
+
+	return tagIDs, nil
+}
+
 func (r *SyncRepository) syncCollection(tx *gorm.DB, userID string, change *contract.Change) error {
 	var deletedAt *time.Time
 	if change.DeletedAt != nil {
@@ -400,10 +1838,461 @@ func (r *SyncRepository) syncCollection(tx *gorm.DB, userID string, change *cont
 			Title:       util.ToValue(change.Title),
 			Description: util.ToValue(change.Description),
 			Color:       change.Color,
+			WorkspaceID: change.WorkspaceID,
 			DeletedAt:   deletedAt,
 		}
 		return tx.Create(collection).Error
 	}
 
+	if deletedAt != nil {
+		return revokeShareLinks(tx, userID, "collection_id", change.EntityID)
+	}
+
+	return nil
+}
+
+func (r *SyncRepository) syncTag(tx *gorm.DB, userID string, change *contract.Change) error {
+	var name string
+	if change.Name != nil {
+		name = util.NormalizeTagName(*change.Name)
+		if name == "" {
+			return ErrTagNameRequired
+		}
+	}
+
+	// Prepare only non-falsy updates
+	updates := map[string]any{}
+
+	if change.Name != nil {
+		updates["name"] = name
+	}
+	if change.Color != nil {
+		updates["color"] = change.Color
+	}
+	if change.DeletedAt != nil {
+		updates["deleted_at"] = change.DeletedAt
+	}
+
+	// Try update first
+	res := tx.Model(&model.Tag{}).
+		Where("id = ? AND user_id = ?", change.EntityID, userID).
+		Updates(updates)
+
+	if res.Error != nil {
+		return res.Error
+	}
+
+	// If nothing updated → create
+	if res.RowsAffected == 0 {
+		if name == "" {
+			return ErrTagNameRequired
+		}
+		tag := &model.Tag{
+			ID:        change.EntityID,
+			UserID:    userID,
+			Name:      name,
+			Color:     change.Color,
+			DeletedAt: util.StringPtrToTimePtr(change.DeletedAt, time.RFC3339),
+		}
+		return tx.Create(tag).Error
+	}
+
 	return nil
 }
+
+// syncComment applies a comment change; userID is the comment's author. The task or note it is on
+// was set and checked by syncCommentOwner.
+func (r *SyncRepository) syncComment(tx *gorm.DB, userID string, change *contract.Change) error {
+	var content string
+	if change.Content != nil {
+		content = strings.TrimSpace(*change.Content)
+		if content == "" {
+			return ErrCommentIncomplete
+		}
+	}
+
+	// Prepare only non-falsy updates
+	updates := map[string]any{}
+
+	if change.Content != nil {
+		updates["content"] = content
+		updates["edited_at"] = gorm.Expr("CASE WHEN content <> ? THEN CURRENT_TIMESTAMP ELSE edited_at END", content)
+	}
+	if change.DeletedAt != nil {
+		updates["deleted_at"] = change.DeletedAt
+	}
+
+	// Try update first
+	res := tx.Model(&model.Comment{}).
+		Where("id = ? AND user_id = ?", change.EntityID, userID).
+		Updates(updates)
+
+	if res.Error != nil {
+		return res.Error
+	}
+
+	// If nothing updated → create
+	if res.RowsAffected == 0 {
+		comment := &model.Comment{
+			ID:        change.EntityID,
+			UserID:    userID,
+			TaskID:    change.TaskID,
+			NoteID:    change.NoteID,
+			Content:   content,
+			DeletedAt: util.StringPtrToTimePtr(change.DeletedAt, time.RFC3339),
+		}
+		if err := tx.Omit(clause.Associations).Create(comment).Error; err != nil {
+			return err
+		}
+	}
+
+	if change.Content == nil || change.DeletedAt != nil {
+		return nil
+	}
+	parent, err := findCommentParent(tx, change.TaskID, change.NoteID, true)
+	if err != nil || parent == nil {
+		return err
+	}
+	return syncCommentMentions(tx, userID, change.EntityID, content, parent)
+}
+
+// syncStatus writes a status change. What it does to the tasks in the status is tracked by the log.
+func (r *SyncRepository) syncStatus(tx *gorm.DB, log *activityLog, userID string, change *contract.Change) error {
+	var name string
+	if change.Name != nil {
+		name = strings.TrimSpace(*change.Name)
+	}
+
+	sortOrder := syncSortOrder(change)
+
+	// Prepare only non-falsy updates. A status can't move to another project.
+	updates := map[string]any{}
+
+	if change.Name != nil {
+		updates["name"] = name
+	}
+	if change.Category != nil {
+		updates["category"] = *change.Category
+	}
+	if change.SortOrder != nil {
+		updates["sort_order"] = sortOrder
+	}
+	if change.Color != nil {
+		updates["color"] = change.Color
+	}
+	if change.DeletedAt != nil {
+		updates["deleted_at"] = change.DeletedAt
+	}
+
+	// Try update first
+	res := tx.Model(&model.ProjectStatus{}).
+		Where("id = ? AND user_id = ?", change.EntityID, userID).
+		Updates(updates)
+
+	if res.Error != nil {
+		return res.Error
+	}
+
+	// If nothing updated → create
+	if res.RowsAffected == 0 {
+		if change.ProjectID == nil || name == "" || change.Category == nil {
+			return ErrStatusIncomplete
+		}
+		var projects int64
+		if err := tx.Model(&model.Project{}).
+			Where("id = ? AND user_id = ?", *change.ProjectID, userID).
+			Count(&projects).Error; err != nil {
+			return err
+		}
+		if projects == 0 {
+			return ErrStatusProjectNotFound
+		}
+
+		status := &model.ProjectStatus{
+			ID:        change.EntityID,
+			UserID:    userID,
+			ProjectID: *change.ProjectID,
+			Name:      name,
+			Category:  *change.Category,
+			SortOrder: sortOrder,
+			Color:     change.Color,
+			DeletedAt: util.StringPtrToTimePtr(change.DeletedAt, time.RFC3339),
+		}
+		return tx.Create(status).Error
+	}
+
+	// Tasks in a deleted status fall back to the built-in status of their category
+	if change.DeletedAt != nil {
+		var taskIDs []string
+		err := tx.Model(&model.Task{}).
+			Where("status_id = ? AND user_id = ?", change.EntityID, userID).
+			Pluck("id", &taskIDs).Error
+		if err != nil || len(taskIDs) == 0 {
+			return err
+		}
+		if err := log.track(tx, "task", taskIDs...); err != nil {
+			return err
+		}
+		return tx.Model(&model.Task{}).
+			Where("id IN ?", taskIDs).
+			Update("status_id", nil).Error
+	}
+
+	// Tasks follow their status into its new category
+	if change.Category != nil {
+		code, _ := model.StatusCategoryCode(*change.Category)
+		var taskIDs []string
+		err := tx.Model(&model.Task{}).
+			Where("status_id = ? AND user_id = ? AND status <> ?", change.EntityID, userID, code).
+			Pluck("id", &taskIDs).Error
+		if err != nil {
+			return err
+		}
+		return setTaskStatus(tx, log, userID, taskIDs, code)
+	}
+
+	return nil
+}
+
+// findProjectStatus returns a live status of the given project, or ErrStatusNotFound
+func (r *SyncRepository) findProjectStatus(tx *gorm.DB, userID, statusID string, projectID *string) (*model.ProjectStatus, error) {
+	if projectID == nil {
+		return nil, ErrStatusNotFound
+	}
+
+	var statuses []model.ProjectStatus
+	err := tx.Where("id = ? AND user_id = ? AND project_id = ? AND deleted_at IS NULL", statusID, userID, *projectID).
+		Limit(1).
+		Find(&statuses).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(statuses) == 0 {
+		return nil, ErrStatusNotFound
+	}
+
+	return &statuses[0], nil
+}
+
+// replaceTaskTags replaces the tags on a task, ignoring tags the user doesn't own
+func (r *SyncRepository) replaceTaskTags(tx *gorm.DB, userID, taskID string, tagIDs []string) error {
+	if err := tx.Where("task_id = ?", taskID).Delete(&model.TaskTag{}).Error; err != nil {
+		return err
+	}
+
+	ownedIDs, err := r.ownedTagIDs(tx, userID, tagIDs)
+	if err != nil || len(ownedIDs) == 0 {
+		return err
+	}
+
+	rows := make([]model.TaskTag, 0, len(ownedIDs))
+	for _, tagID := range ownedIDs {
+		rows = append(rows, model.TaskTag{TaskID: taskID, TagID: tagID})
+	}
+	return tx.Omit(clause.Associations).Create(&rows).Error
+}
+
+// replaceTaskDependencies replaces the tasks blocking a task, rejecting missing tasks and cycles.
+// Dependencies that stay keep their created_at, which marks which blocker completions count.
+func (r *SyncRepository) replaceTaskDependencies(tx *gorm.DB, userID, taskID string, blockedByTaskIDs []string) error {
+	if len(blockedByTaskIDs) == 0 {
+		return tx.Where("task_id = ?", taskID).Delete(&model.TaskDependency{}).Error
+	}
+	blockedByTaskIDs = slices.Compact(slices.Sorted(slices.Values(blockedByTaskIDs)))
+	err := tx.Where("task_id = ? AND blocked_by_task_id NOT IN ?", taskID, blockedByTaskIDs).Delete(&model.TaskDependency{}).Error
+	if err != nil {
+		return err
+	}
+
+	var found int64
+	err = tx.Model(&model.Task{}).
+		Where("id IN ? AND user_id = ? AND deleted_at IS NULL", blockedByTaskIDs, userID).
+		Count(&found).Error
+	if err != nil {
+		return err
+	}
+	if int(found) != len(blockedByTaskIDs) {
+		return ErrTaskDependencyNotFound
+	}
+
+	// Walk the blocking chain from the new blockers; reaching the task means a cycle
+	var cycles int64
+	err = tx.Raw(`
+		WITH RECURSIVE blockers AS (
+			SELECT id FROM tasks WHERE id IN ?
+			UNION
+			SELECT d.blocked_by_task_id FROM task_dependencies d
+			JOIN blockers b ON d.task_id = b.id
+		)
+		SELECT COUNT(*) FROM blockers WHERE id = ?`, blockedByTaskIDs, taskID).
+		Scan(&cycles).Error
+	if err != nil {
+		return err
+	}
+	if cycles > 0 {
+		return ErrTaskDependencyCycle
+	}
+
+	rows := make([]model.TaskDependency, 0, len(blockedByTaskIDs))
+	for _, blockedByTaskID := range blockedByTaskIDs {
+		rows = append(rows, model.TaskDependency{
+			TaskID:          taskID,
+			BlockedByTaskID: blockedByTaskID,
+			UserID:          userID,
+		})
+	}
+	return tx.Omit(clause.Associations).Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error
+}
+
+// replaceNoteTags replaces the tags on a note, ignoring tags the user doesn't own
+func (r *SyncRepository) replaceNoteTags(tx *gorm.DB, userID, noteID string, tagIDs []string) error {
+	if err := tx.Where("note_id = ?", noteID).Delete(&model.NoteTag{}).Error; err != nil {
+		return err
+	}
+
+	ownedIDs, err := r.ownedTagIDs(tx, userID, tagIDs)
+	if err != nil || len(ownedIDs) == 0 {
+		return err
+	}
+
+	rows := make([]model.NoteTag, 0, len(ownedIDs))
+	for _, tagID := range ownedIDs {
+		rows = append(rows, model.NoteTag{NoteID: noteID, TagID: tagID})
+	}
+	return tx.Omit(clause.Associations).Create(&rows).Error
+}
+
+// syncNoteLinks rebuilds the outgoing [[links]] of a note from its content. A link that still
+// points at a live note keeps its target rather than being looked up by title again, so a link
+// stays resolved after its target is renamed and the linking note is edited.
+func (r *SyncRepository) syncNoteLinks(tx *gorm.DB, userID, noteID, content string) error {
+	var resolved []model.NoteLink
+	err := tx.Model(&model.NoteLink{}).
+		Joins("JOIN notes ON notes.id = note_links.target_note_id AND notes.deleted_at IS NULL").
+		Where("note_links.source_note_id = ?", noteID).
+		Select("note_links.target_title, note_links.target_note_id").
+		Find(&resolved).Error
+	if err != nil {
+		return err
+	}
+	resolvedTargets := make(map[string]*string, len(resolved))
+	for _, link := range resolved {
+		resolvedTargets[strings.ToLower(link.TargetTitle)] = link.TargetNoteID
+	}
+
+	if err := tx.Where("source_note_id = ?", noteID).Delete(&model.NoteLink{}).Error; err != nil {
+		return err
+	}
+
+	targets := util.ParseWikiLinks(content)
+	if len(targets) == 0 {
+		return nil
+	}
+
+	links := make([]model.NoteLink, 0, len(targets))
+	for _, target := range targets {
+		targetNoteID, ok := resolvedTargets[strings.ToLower(target)]
+		if !ok {
+			targetNoteID, err = r.resolveLinkTarget(tx, userID, target)
+			if err != nil {
+				return err
+			}
+		}
+		links = append(links, model.NoteLink{
+			ID:           uuid.New().String(),
+			UserID:       userID,
+			SourceNoteID: noteID,
+			TargetNoteID: targetNoteID,
+			TargetTitle:  target,
+		})
+	}
+
+	return tx.Omit(clause.Associations).Create(&links).Error
+}
+
+// resolveLinkTarget finds the note a link points to, by ID first and then by title.
+// Returns nil when the link is dangling.
+func (r *SyncRepository) resolveLinkTarget(tx *gorm.DB, userID, target string) (*string, error) {
+	query := tx.Model(&model.Note{}).Where("user_id = ? AND deleted_at IS NULL", userID)
+	if _, err := uuid.Parse(target); err == nil {
+		query = query.Where("id = ?", target)
+	} else {
+		query = query.Where("LOWER(title) = LOWER(?)", target)
+	}
+
+	var ids []string
+	if err := query.Order("updated_at DESC").Limit(1).Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	return &ids[0], nil
+}
+
+// resolveDanglingLinks points dangling links whose title matches the note at it.
+// Links that already resolved keep their target, so renaming a note doesn't break them.
+func (r *SyncRepository) resolveDanglingLinks(tx *gorm.DB, userID, noteID, title string) error {
+	if title == "" {
+		return nil
+	}
+	return tx.Model(&model.NoteLink{}).
+		Where("user_id = ? AND target_note_id IS NULL AND LOWER(target_title) = LOWER(?)", userID, title).
+		Update("target_note_id", noteID).Error
+}
+
+func (r *SyncRepository) ownedTagIDs(tx *gorm.DB, userID string, tagIDs []string) (ownedIDs []string, err error) {
+	if len(tagIDs) == 0 {
+		return nil, nil
+	}
+	err = tx.Model(&model.Tag{}).
+		Where("id IN ? AND user_id = ?", tagIDs, userID).
+		Pluck("id", &ownedIDs).Error
+	return ownedIDs, err
+}
+
+func toReminderOffsets(offsets *datatypes.JSONSlice[int]) *[]int {
+	if offsets == nil {
+		return nil
+	}
+	return util.ToPointer([]int(*offsets))
+}
+
+func toBlockedByTaskIDs(dependencies []model.TaskDependency) *[]string {
+	ids := make([]string, 0, len(dependencies))
+	for _, dependency := range dependencies {
+		ids = append(ids, dependency.BlockedByTaskID)
+	}
+	return &ids
+}
+
+// ownTagIDs returns the IDs of an item's tags, or nil for another user's item since tags are personal
+func ownTagIDs(userID, ownerID string, tags []model.Tag) *[]string {
+	if ownerID != userID {
+		return nil
+	}
+	return toTagIDs(tags)
+}
+
+// memberRole returns the user's role on a project or collection shared with them or in the active
+// workspace, where the user has workspaceRole, or nil for their own
+func memberRole(roles map[string]string, workspaceRole, userID, ownerID, id string) *string {
+	if workspaceRole != "" {
+		return util.ToPointer(model.HigherMemberRole(workspaceRole, roles[id]))
+	}
+	if ownerID == userID {
+		return nil
+	}
+	if role, ok := roles[id]; ok {
+		return &role
+	}
+	return nil
+}
+
+func toTagIDs(tags []model.Tag) *[]string {
+	ids := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		ids = append(ids, tag.ID)
+	}
+	return &ids
+}
@@ -0,0 +1,517 @@
+package repository
+
+import (
+	"app/internal/contract"
+	"app/internal/model"
+	"app/pkg/openai"
+	"app/pkg/util"
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// newTestSyncRepository returns a sync repository whose note embeddings always fail, so notes are
+// saved without one
+func newTestSyncRepository(t *testing.T, db *gorm.DB) *SyncRepository {
+	t.Helper()
+
+	openaiClient, err := openai.NewOpenAIClient("test")
+	if err != nil {
+		t.Fatalf("failed to create OpenAI client: %v", err)
+	}
+	return NewSyncRepository(db, openaiClient)
+}
+
+// applyChanges syncs changes as the user in the personal space, failing the test on an error
+func applyChanges(t *testing.T, repo *SyncRepository, userID string, changes ...contract.Change) {
+	t.Helper()
+
+	if _, err := repo.ApplyChanges(userID, "", changes, model.ActivitySourceSync); err != nil {
+		t.Fatalf("failed to apply changes: %v", err)
+	}
+}
+
+func TestSyncTagRequiresName(t *testing.T) {
+	db := testDB(t)
+	repo := newTestSyncRepository(t, db)
+	userID := testUser(t, db)
+
+	_, err := repo.ApplyChanges(userID, "", []contract.Change{{Type: "tag", EntityID: uuid.NewString()}}, model.ActivitySourceSync)
+	if !errors.Is(err, ErrTagNameRequired) {
+		t.Fatalf("creating a tag without a name: got %v, want ErrTagNameRequired", err)
+	}
+
+	tagID := uuid.NewString()
+	applyChanges(t, repo, userID, contract.Change{Type: "tag", EntityID: tagID, Name: util.ToPointer("#work")})
+
+	_, err = repo.ApplyChanges(userID, "", []contract.Change{{Type: "tag", EntityID: tagID, Name: util.ToPointer(" # ")}}, model.ActivitySourceSync)
+	if !errors.Is(err, ErrTagNameRequired) {
+		t.Fatalf("renaming a tag to a blank name: got %v, want ErrTagNameRequired", err)
+	}
+
+	var tag model.Tag
+	if err := db.First(&tag, "id = ?", tagID).Error; err != nil {
+		t.Fatalf("failed to get tag: %v", err)
+	}
+	if tag.Name != "work" {
+		t.Errorf("tag name = %q, want %q", tag.Name, "work")
+	}
+}
+
+func TestNoteLinkSurvivesRenameThenEdit(t *testing.T) {
+	db := testDB(t)
+	repo := newTestSyncRepository(t, db)
+	userID := testUser(t, db)
+
+	targetID, sourceID := uuid.NewString(), uuid.NewString()
+	applyChanges(t, repo, userID,
+		contract.Change{Type: "note", EntityID: targetID, Title: util.ToPointer("Old Title"), Content: util.ToPointer("target")},
+		contract.Change{Type: "note", EntityID: sourceID, Title: util.ToPointer("Source"), Content: util.ToPointer("see [[Old Title]]")},
+	)
+
+	// Rename the target, then edit the source without touching its link text
+	applyChanges(t, repo, userID, contract.Change{Type: "note", EntityID: targetID, Title: util.ToPointer("New Title")})
+	applyChanges(t, repo, userID, contract.Change{Type: "note", EntityID: sourceID, Content: util.ToPointer("still see [[Old Title]]")})
+
+	var links []model.NoteLink
+	if err := db.Where("source_note_id = ?", sourceID).Find(&links).Error; err != nil {
+		t.Fatalf("failed to get links: %v", err)
+	}
+	if len(links) != 1 {
+		t.Fatalf("got %d links, want 1", len(links))
+	}
+	if got := util.ToValue(links[0].TargetNoteID); got != targetID {
+		t.Errorf("link target = %q, want the renamed note %q", got, targetID)
+	}
+
+	// A link to a title no note has stays dangling
+	applyChanges(t, repo, userID, contract.Change{Type: "note", EntityID: sourceID, Content: util.ToPointer("[[Old Title]] and [[Nowhere]]")})
+	var dangling int64
+	if err := db.Model(&model.NoteLink{}).Where("source_note_id = ? AND target_note_id IS NULL", sourceID).Count(&dangling).Error; err != nil {
+		t.Fatalf("failed to count dangling links: %v", err)
+	}
+	if dangling != 1 {
+		t.Errorf("got %d dangling links, want 1", dangling)
+	}
+}
+
+func TestGetChangesSendsDetachedParent(t *testing.T) {
+	db := testDB(t)
+	repo := newTestSyncRepository(t, db)
+	userID := testUser(t, db)
+
+	parentID, childID := uuid.NewString(), uuid.NewString()
+	applyChanges(t, repo, userID,
+		contract.Change{Type: "task", EntityID: parentID, Title: util.ToPointer("Parent")},
+		contract.Change{Type: "task", EntityID: childID, Title: util.ToPointer("Child"), ParentTaskID: util.ToPointer(parentID)},
+	)
+	applyChanges(t, repo, userID, contract.Change{Type: "task", EntityID: childID, ParentTaskID: util.ToPointer("")})
+
+	changes, err := repo.GetChanges(userID, "", "1970-01-01T00:00:00Z")
+	if err != nil {
+		t.Fatalf("failed to get changes: %v", err)
+	}
+	for _, change := range changes {
+		if change.EntityID != childID {
+			continue
+		}
+		if change.ParentTaskID == nil || *change.ParentTaskID != "" {
+			t.Errorf("parentTaskId = %v, want an explicit \"\"", change.ParentTaskID)
+		}
+		return
+	}
+	t.Fatalf("detached task missing from changes")
+}
+
+func TestInvalidSortOrderIsIgnoredPerChange(t *testing.T) {
+	db := testDB(t)
+	repo := newTestSyncRepository(t, db)
+	userID := testUser(t, db)
+
+	firstID, secondID := uuid.NewString(), uuid.NewString()
+	applyChanges(t, repo, userID, contract.Change{Type: "task", EntityID: firstID, Title: util.ToPointer("First"), SortOrder: util.ToPointer("a1")})
+
+	// A legacy key doesn't fail the batch or move the task
+	applyChanges(t, repo, userID,
+		contract.Change{Type: "task", EntityID: firstID, Title: util.ToPointer("First, renamed"), SortOrder: util.ToPointer("3.5")},
+		contract.Change{Type: "task", EntityID: secondID, Title: util.ToPointer("Second"), SortOrder: util.ToPointer("a2")},
+	)
+
+	var tasks []model.Task
+	if err := db.Where("user_id = ?", userID).Order("title").Find(&tasks).Error; err != nil {
+		t.Fatalf("failed to get tasks: %v", err)
+	}
+	if len(tasks) != 2 {
+		t.Fatalf("got %d tasks, want 2", len(tasks))
+	}
+	if util.ToValue(tasks[0].Title) != "First, renamed" || util.ToValue(tasks[0].SortOrder) != "a1" {
+		t.Errorf("first task = %q at %q, want it renamed and still at a1", util.ToValue(tasks[0].Title), util.ToValue(tasks[0].SortOrder))
+	}
+	if util.ToValue(tasks[1].SortOrder) != "a2" {
+		t.Errorf("second task sort order = %q, want a2", util.ToValue(tasks[1].SortOrder))
+	}
+}
+
+func TestSyncSortOrder(t *testing.T) {
+	tests := []struct {
+		name      string
+		sortOrder *string
+		want      *string
+		keep      bool
+	}{
+		{name: "not sent", sortOrder: nil, want: nil, keep: false},
+		{name: "cleared", sortOrder: util.ToPointer(""), want: nil, keep: true},
+		{name: "fractional key", sortOrder: util.ToPointer("a1"), want: util.ToPointer("a1"), keep: true},
+		{name: "trailing zero", sortOrder: util.ToPointer("a10"), want: nil, keep: false},
+		{name: "legacy decimal", sortOrder: util.ToPointer("3.5"), want: nil, keep: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			change := contract.Change{EntityID: uuid.NewString(), SortOrder: tt.sortOrder}
+			got := syncSortOrder(&change)
+			if util.ToValue(got) != util.ToValue(tt.want) || (got == nil) != (tt.want == nil) {
+				t.Errorf("syncSortOrder(%v) = %v, want %v", util.ToValue(tt.sortOrder), util.ToValue(got), util.ToValue(tt.want))
+			}
+			if (change.SortOrder != nil) != tt.keep {
+				t.Errorf("change keeps its sort order = %v, want %v", change.SortOrder != nil, tt.keep)
+			}
+		})
+	}
+}
+
+func TestSyncRejectsForbiddenChangesOneByOne(t *testing.T) {
+	db := testDB(t)
+	repo := newTestSyncRepository(t, db)
+	ownerID := testUser(t, db)
+	viewerID := testUser(t, db)
+
+	projectID, sharedTaskID := uuid.NewString(), uuid.NewString()
+	applyChanges(t, repo, ownerID,
+		contract.Change{Type: "project", EntityID: projectID, Title: util.ToPointer("Shared")},
+		contract.Change{Type: "task", EntityID: sharedTaskID, ProjectID: &projectID, Title: util.ToPointer("Theirs")},
+	)
+	membership := model.Membership{UserID: viewerID, ProjectID: &projectID, Role: model.MemberRoleViewer, InvitedBy: &ownerID}
+	if err := db.Create(&membership).Error; err != nil {
+		t.Fatalf("failed to create membership: %v", err)
+	}
+
+	ownTaskID := uuid.NewString()
+	batch := []contract.Change{
+		{Type: "task", EntityID: sharedTaskID, ProjectID: &projectID, Title: util.ToPointer("Renamed by a viewer")},
+		{Type: "task", EntityID: ownTaskID, Title: util.ToPointer("Mine")},
+	}
+
+	if _, err := repo.ApplyChanges(viewerID, "", batch, model.ActivitySourceRule); !errors.Is(err, ErrShareForbidden) {
+		t.Fatalf("applying the batch: got %v, want ErrShareForbidden", err)
+	}
+
+	req := &contract.SyncReq{Changes: batch, LastSyncTime: "1970-01-01T00:00:00Z"}
+	_, _, rejected, err := repo.Sync(viewerID, "", req)
+	if err != nil {
+		t.Fatalf("failed to sync: %v", err)
+	}
+	if len(rejected) != 1 || rejected[0].EntityID != sharedTaskID || rejected[0].Type != "task" {
+		t.Fatalf("rejected = %+v, want only the shared task", rejected)
+	}
+
+	var titles []string
+	if err := db.Model(&model.Task{}).Where("id IN ?", []string{sharedTaskID, ownTaskID}).Order("title").Pluck("title", &titles).Error; err != nil {
+		t.Fatalf("failed to get tasks: %v", err)
+	}
+	if !slices.Equal(titles, []string{"Mine", "Theirs"}) {
+		t.Errorf("task titles = %v, want the viewer's task created and the shared one unchanged", titles)
+	}
+}
+
+func TestPersonalSyncAfterLeavingWorkspace(t *testing.T) {
+	db := testDB(t)
+	repo := newTestSyncRepository(t, db)
+	ownerID := testUser(t, db)
+	memberID := testUser(t, db)
+
+	workspace := model.Workspace{UserID: ownerID, Title: "Team"}
+	if err := db.Create(&workspace).Error; err != nil {
+		t.Fatalf("failed to create workspace: %v", err)
+	}
+	membership := model.Membership{UserID: memberID, WorkspaceID: &workspace.ID, Role: model.MemberRoleEditor, InvitedBy: &ownerID}
+	if err := db.Create(&membership).Error; err != nil {
+		t.Fatalf("failed to create membership: %v", err)
+	}
+	removed, err := NewMembershipRepository(db).RemoveMember(t.Context(), MemberTarget{WorkspaceID: &workspace.ID}, memberID)
+	if err != nil || !removed {
+		t.Fatalf("failed to remove member: removed=%v err=%v", removed, err)
+	}
+
+	req := &contract.SyncReq{LastSyncTime: "1970-01-01T00:00:00Z"}
+	_, changes, _, err := repo.Sync(memberID, "", req)
+	if err != nil {
+		t.Fatalf("failed to sync: %v", err)
+	}
+	for _, change := range changes {
+		if change.DeletedAt != nil {
+			t.Errorf("got tombstone %s %s, want none for a workspace membership", change.Type, change.EntityID)
+		}
+	}
+}
+
+func TestPersonalSyncCantWriteWorkspaceItems(t *testing.T) {
+	db := testDB(t)
+	repo := newTestSyncRepository(t, db)
+	ownerID := testUser(t, db)
+
+	workspace := model.Workspace{UserID: ownerID, Title: "Team"}
+	if err := db.Create(&workspace).Error; err != nil {
+		t.Fatalf("failed to create workspace: %v", err)
+	}
+	projectID, statusID, taskID := uuid.NewString(), uuid.NewString(), uuid.NewString()
+	_, err := repo.ApplyChanges(ownerID, workspace.ID, []contract.Change{
+		{Type: "project", EntityID: projectID, Title: util.ToPointer("Team project")},
+		{Type: "status", EntityID: statusID, ProjectID: &projectID, Name: util.ToPointer("Review"), Category: util.ToPointer("doing")},
+		{Type: "task", EntityID: taskID, ProjectID: &projectID, Title: util.ToPointer("Team task")},
+	}, model.ActivitySourceSync)
+	if err != nil {
+		t.Fatalf("failed to sync the workspace: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		change contract.Change
+	}{
+		{name: "project", change: contract.Change{Type: "project", EntityID: projectID, Title: util.ToPointer("Renamed")}},
+		{name: "status", change: contract.Change{Type: "status", EntityID: statusID, Name: util.ToPointer("Renamed")}},
+		{name: "task", change: contract.Change{Type: "task", EntityID: taskID, ProjectID: &projectID, Title: util.ToPointer("Renamed")}},
+		{name: "new task", change: contract.Change{Type: "task", EntityID: uuid.NewString(), ProjectID: &projectID, Title: util.ToPointer("New")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := repo.ApplyChanges(ownerID, "", []contract.Change{tt.change}, model.ActivitySourceSync)
+			if !errors.Is(err, ErrWorkspaceItemOutside) {
+				t.Errorf("writing a workspace %s from the personal space: got %v, want ErrWorkspaceItemOutside", tt.name, err)
+			}
+		})
+	}
+}
+
+func TestTaskFollowsItsCustomStatus(t *testing.T) {
+	db := testDB(t)
+	repo := newTestSyncRepository(t, db)
+	userID := testUser(t, db)
+
+	projectID, statusID, taskID := uuid.NewString(), uuid.NewString(), uuid.NewString()
+	applyChanges(t, repo, userID,
+		contract.Change{Type: "project", EntityID: projectID, Title: util.ToPointer("Release")},
+		contract.Change{Type: "status", EntityID: statusID, ProjectID: &projectID, Name: util.ToPointer("QA"), Category: util.ToPointer(model.StatusCategoryDoing)},
+		contract.Change{Type: "task", EntityID: taskID, Title: util.ToPointer("Check build"), ProjectID: &projectID, StatusID: &statusID},
+	)
+
+	getTask := func() model.Task {
+		t.Helper()
+		var task model.Task
+		if err := db.First(&task, "id = ?", taskID).Error; err != nil {
+			t.Fatalf("failed to get task: %v", err)
+		}
+		return task
+	}
+
+	task := getTask()
+	if task.Status != model.TaskStatusInProgress || util.ToValue(task.StatusID) != statusID {
+		t.Fatalf("task has status %d in %v, want in progress in QA", task.Status, task.StatusID)
+	}
+
+	applyChanges(t, repo, userID, contract.Change{Type: "status", EntityID: statusID, Category: util.ToPointer(model.StatusCategoryDone)})
+	if task = getTask(); task.Status != model.TaskStatusCompleted {
+		t.Errorf("task status = %d after QA moved to done, want completed", task.Status)
+	}
+
+	applyChanges(t, repo, userID, contract.Change{Type: "status", EntityID: statusID, DeletedAt: util.ToPointer(time.Now().UTC().Format(time.RFC3339))})
+	task = getTask()
+	if task.StatusID != nil || task.Status != model.TaskStatusCompleted {
+		t.Errorf("task has status %d in %v after QA was deleted, want completed in the built-in status", task.Status, task.StatusID)
+	}
+}
+
+func TestSyncStatusErrors(t *testing.T) {
+	db := testDB(t)
+	repo := newTestSyncRepository(t, db)
+	userID := testUser(t, db)
+
+	projectID, otherProjectID, statusID := uuid.NewString(), uuid.NewString(), uuid.NewString()
+	applyChanges(t, repo, userID,
+		contract.Change{Type: "project", EntityID: projectID, Title: util.ToPointer("Release")},
+		contract.Change{Type: "project", EntityID: otherProjectID, Title: util.ToPointer("Ops")},
+		contract.Change{Type: "status", EntityID: statusID, ProjectID: &projectID, Name: util.ToPointer("QA"), Category: util.ToPointer(model.StatusCategoryDoing)},
+	)
+
+	tests := []struct {
+		name   string
+		change contract.Change
+		want   error
+	}{
+		{
+			name:   "status without a category",
+			change: contract.Change{Type: "status", EntityID: uuid.NewString(), ProjectID: &projectID, Name: util.ToPointer("Backlog")},
+			want:   ErrStatusIncomplete,
+		},
+		{
+			name:   "status in an unknown project",
+			change: contract.Change{Type: "status", EntityID: uuid.NewString(), ProjectID: util.ToPointer(uuid.NewString()), Name: util.ToPointer("Backlog"), Category: util.ToPointer(model.StatusCategoryTodo)},
+			want:   ErrStatusProjectNotFound,
+		},
+		{
+			name:   "task in another project's status",
+			change: contract.Change{Type: "task", EntityID: uuid.NewString(), Title: util.ToPointer("Rotate keys"), ProjectID: &otherProjectID, StatusID: &statusID},
+			want:   ErrStatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		_, err := repo.ApplyChanges(userID, "", []contract.Change{tt.change}, model.ActivitySourceSync)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestImportTasksDryRunSavesNothing(t *testing.T) {
+	db := testDB(t)
+	repo := newTestSyncRepository(t, db)
+	userID := testUser(t, db)
+
+	results, err := repo.ImportTasks(context.Background(), userID, []ImportedTask{
+		{Title: "Pack", ProjectTitle: "Trip", Category: model.StatusCategoryTodo, TagNames: []string{"travel"}},
+	}, true)
+	if err != nil {
+		t.Fatalf("failed to preview import: %v", err)
+	}
+	if len(results) != 1 || !results[0].ProjectCreated {
+		t.Fatalf("got results %v, want the task and its new project previewed", results)
+	}
+
+	for _, table := range []any{&model.Task{}, &model.Project{}, &model.Tag{}} {
+		var count int64
+		if err := db.Model(table).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+			t.Fatalf("failed to count %T: %v", table, err)
+		}
+		if count != 0 {
+			t.Errorf("a dry run saved %d of %T", count, table)
+		}
+	}
+}
+
+func TestImportTasksMatchesProjectsAndStatuses(t *testing.T) {
+	db := testDB(t)
+	repo := newTestSyncRepository(t, db)
+	userID := testUser(t, db)
+
+	projectID, qaID := uuid.NewString(), uuid.NewString()
+	applyChanges(t, repo, userID,
+		contract.Change{Type: "project", EntityID: projectID, Title: util.ToPointer("Release")},
+		contract.Change{Type: "status", EntityID: qaID, ProjectID: &projectID, Name: util.ToPointer("QA"), Category: util.ToPointer(model.StatusCategoryDoing)},
+	)
+
+	results, err := repo.ImportTasks(context.Background(), userID, []ImportedTask{
+		{Title: "Check build", ProjectTitle: "release", StatusName: "qa"},
+		{Title: "Write notes", ProjectTitle: "Docs", StatusName: "Waiting"},
+		{Title: "Publish", ProjectTitle: "Docs", StatusName: "done", Category: model.StatusCategoryDone},
+	}, false)
+	if err != nil {
+		t.Fatalf("failed to import: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("got %d results, want 3", len(results))
+	}
+
+	if util.ToValue(results[0].ProjectID) != projectID || results[0].ProjectCreated || results[0].StatusName != "QA" || !results[0].StatusMatched {
+		t.Errorf("first task = %+v, want it in the existing project's QA status", results[0])
+	}
+	if !results[1].ProjectCreated || results[1].StatusMatched || results[1].StatusName != "To do" {
+		t.Errorf("second task = %+v, want a new project and an unmatched status left to do", results[1])
+	}
+	if results[2].ProjectCreated || util.ToValue(results[2].ProjectID) != util.ToValue(results[1].ProjectID) {
+		t.Errorf("third task = %+v, want it in the project the second created", results[2])
+	}
+
+	var task model.Task
+	if err := db.First(&task, "id = ?", results[0].TaskID).Error; err != nil {
+		t.Fatalf("failed to get task: %v", err)
+	}
+	if task.Status != model.TaskStatusInProgress || util.ToValue(task.StatusID) != qaID {
+		t.Errorf("imported task has status %d in %v, want in progress in QA", task.Status, task.StatusID)
+	}
+}
+
+func TestSyncTaskParentErrors(t *testing.T) {
+	db := testDB(t)
+	repo := newTestSyncRepository(t, db)
+	userID := testUser(t, db)
+
+	rootID, childID, grandchildID, looseID := uuid.NewString(), uuid.NewString(), uuid.NewString(), uuid.NewString()
+	applyChanges(t, repo, userID,
+		contract.Change{Type: "task", EntityID: rootID, Title: util.ToPointer("Root")},
+		contract.Change{Type: "task", EntityID: childID, Title: util.ToPointer("Child"), ParentTaskID: &rootID},
+		contract.Change{Type: "task", EntityID: grandchildID, Title: util.ToPointer("Grandchild"), ParentTaskID: &childID},
+		contract.Change{Type: "task", EntityID: looseID, Title: util.ToPointer("Loose")},
+	)
+
+	tests := []struct {
+		name     string
+		taskID   string
+		parentID string
+		want     error
+	}{
+		{name: "under itself", taskID: rootID, parentID: rootID, want: ErrTaskCycle},
+		{name: "under its grandchild", taskID: rootID, parentID: grandchildID, want: ErrTaskCycle},
+		{name: "below the deepest level", taskID: looseID, parentID: grandchildID, want: ErrTaskTooDeep},
+		{name: "with its subtree too deep", taskID: rootID, parentID: looseID, want: ErrTaskTooDeep},
+		{name: "under a missing task", taskID: looseID, parentID: uuid.NewString(), want: ErrTaskParentNotFound},
+	}
+	for _, tt := range tests {
+		_, err := repo.ApplyChanges(userID, "", []contract.Change{
+			{Type: "task", EntityID: tt.taskID, ParentTaskID: &tt.parentID},
+		}, model.ActivitySourceSync)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestSubtasksRollUpToTheirParent(t *testing.T) {
+	db := testDB(t)
+	repo := newTestSyncRepository(t, db)
+	userID := testUser(t, db)
+
+	parentID, firstID, secondID := uuid.NewString(), uuid.NewString(), uuid.NewString()
+	applyChanges(t, repo, userID,
+		contract.Change{Type: "task", EntityID: parentID, Title: util.ToPointer("Trip")},
+		contract.Change{Type: "task", EntityID: firstID, Title: util.ToPointer("Book"), ParentTaskID: &parentID},
+		contract.Change{Type: "task", EntityID: secondID, Title: util.ToPointer("Pack"), ParentTaskID: &parentID},
+	)
+	parentStatus := func() int {
+		t.Helper()
+		var task model.Task
+		if err := db.First(&task, "id = ?", parentID).Error; err != nil {
+			t.Fatalf("failed to get parent: %v", err)
+		}
+		return task.Status
+	}
+
+	applyChanges(t, repo, userID, contract.Change{Type: "task", EntityID: firstID, ParentTaskID: &parentID, Status: util.ToPointer(model.TaskStatusCompleted)})
+	if status := parentStatus(); status != model.TaskStatusPending {
+		t.Errorf("parent status = %d with a subtask open, want pending", status)
+	}
+
+	applyChanges(t, repo, userID, contract.Change{Type: "task", EntityID: secondID, ParentTaskID: &parentID, Status: util.ToPointer(model.TaskStatusCancelled)})
+	if status := parentStatus(); status != model.TaskStatusCompleted {
+		t.Errorf("parent status = %d with every subtask done, want completed", status)
+	}
+
+	applyChanges(t, repo, userID, contract.Change{Type: "task", EntityID: firstID, ParentTaskID: &parentID, Status: util.ToPointer(model.TaskStatusPending)})
+	if status := parentStatus(); status != model.TaskStatusInProgress {
+		t.Errorf("parent status = %d after a subtask reopened, want in progress", status)
+	}
+}
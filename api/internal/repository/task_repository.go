@@ -0,0 +1,749 @@
+package repository
+
+import (
+	"app/internal/model"
+	"app/pkg/fractional"
+	"app/pkg/logger"
+	"app/pkg/recurrence"
+	"app/pkg/util"
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrInvalidRecurrenceRule = errors.New("invalid recurrence rule")
+
+	ErrProjectNotFound   = errors.New("project not found")
+	ErrTaskNotFound      = errors.New("task not found")
+	ErrTaskMoveNotInList = errors.New("tasks to move between must be in the same list as the moved task")
+	ErrTaskMoveOrder     = errors.New("the task to move after must come before the task to move before")
+)
+
+// sortOrderColumn orders by sort_order the way fractional keys compare, byte by byte
+const sortOrderColumn = `sort_order COLLATE "C"`
+
+type TaskRepository struct {
+	db *gorm.DB
+}
+
+func NewTaskRepository(db *gorm.DB) *TaskRepository {
+	return &TaskRepository{db: db}
+}
+
+// MaterializeRecurringTasks spawns instances of spawn-mode series until every
+// series has an instance due beyond the horizon, creating at most maxPerSeries per series
+// so a frequent rule catches up over several runs. Returns the number of tasks created.
+func (r *TaskRepository) MaterializeRecurringTasks(ctx context.Context, horizon time.Duration, maxPerSeries int) (int, error) {
+	limit := time.Now().Add(horizon)
+
+	var heads []model.Task
+	err := r.db.WithContext(ctx).
+		Where("recurrence_rule IS NOT NULL AND recurrence_mode = ? AND deleted_at IS NULL", model.RecurrenceModeSpawn).
+		Where("due_date IS NOT NULL AND due_date < ?", limit).
+		Find(&heads).Error
+	if err != nil {
+		logger.Log.Error("Failed to find recurring tasks to materialize", zap.Error(err))
+		return 0, err
+	}
+
+	created := 0
+	for _, head := range heads {
+		spawnedTasks := 0
+		err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			log := newActivityLog(ActivityActor{UserID: head.UserID, Source: model.ActivitySourceSystem})
+			current := head
+			for spawnedTasks < maxPerSeries && current.DueDate != nil && current.DueDate.Before(limit) {
+				next, err := nextOccurrence(&current, *current.DueDate)
+				if err != nil {
+					return err
+				}
+				if next == nil {
+					break
+				}
+				spawned, err := spawnRecurringTask(tx, log, &current, *next)
+				if err != nil {
+					return err
+				}
+				if spawned == nil {
+					break
+				}
+				spawnedTasks++
+				current = *spawned
+			}
+			return log.flush(tx)
+		})
+		if err == nil {
+			created += spawnedTasks
+		}
+		if err != nil {
+			logger.Log.Error("Failed to materialize recurring task", zap.Error(err), zap.String("taskID", head.ID))
+		}
+	}
+
+	return created, nil
+}
+
+// refreshBlockedTasks recomputes the blocked flag of the user's tasks that have, or had, open blockers.
+// A recurring blocker is done once one of its occurrences is completed after the dependency was
+// added, since roll mode reopens the task right away. Tasks whose flag changes get a new updated_at so
+// the change syncs, and are logged.
+func refreshBlockedTasks(tx *gorm.DB, log *activityLog, userID string) error {
+	var flippedIDs []string
+	err := tx.Raw(`
+		SELECT s.id FROM (
+			SELECT c.id, c.blocked, EXISTS (
+				SELECT 1 FROM task_dependencies d
+				JOIN tasks b ON b.id = d.blocked_by_task_id
+				WHERE d.task_id = c.id AND b.deleted_at IS NULL AND b.status NOT IN @done
+					AND NOT (b.recurrence_rule IS NOT NULL AND EXISTS (
+						SELECT 1 FROM task_status_changes h
+						WHERE h.task_id = b.id AND h.to_status = @completed AND h.changed_at >= d.created_at
+					))
+			) AS should_block
+			FROM tasks c
+			WHERE c.user_id = @user_id
+				AND (c.blocked OR c.id IN (SELECT task_id FROM task_dependencies WHERE user_id = @user_id))
+		) s
+		WHERE s.blocked <> s.should_block`, map[string]any{
+		"user_id":   userID,
+		"done":      []int{model.TaskStatusCompleted, model.TaskStatusCancelled},
+		"completed": model.TaskStatusCompleted,
+	}).Scan(&flippedIDs).Error
+	if err != nil || len(flippedIDs) == 0 {
+		return err
+	}
+
+	if err := log.track(tx, "task", flippedIDs...); err != nil {
+		return err
+	}
+	return tx.Model(&model.Task{}).
+		Where("id IN ?", flippedIDs).
+		Updates(map[string]any{
+			"blocked":    gorm.Expr("NOT blocked"),
+			"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+		}).Error
+}
+
+// advanceRecurringTask moves a just-completed recurring task on to its next occurrence:
+// roll mode reopens it with the next due date, spawn mode creates a new task for it
+func advanceRecurringTask(tx *gorm.DB, log *activityLog, task *model.Task) error {
+	// Completing late skips the occurrences that were missed
+	after := time.Now()
+	if task.DueDate != nil && task.DueDate.After(after) {
+		after = *task.DueDate
+	}
+
+	next, err := nextOccurrence(task, after)
+	if err != nil || next == nil {
+		return err
+	}
+
+	if task.RecurrenceMode == model.RecurrenceModeSpawn {
+		_, err = spawnRecurringTask(tx, log, task, *next)
+		return err
+	}
+
+	if err := log.track(tx, "task", task.ID); err != nil {
+		return err
+	}
+	statusID, err := defaultProjectStatusID(tx, task.ProjectID, model.StatusCategoryTodo)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Model(&model.Task{}).
+		Where("id = ?", task.ID).
+		Updates(map[string]any{
+			"due_date":   *next,
+			"start_date": shiftedStartDate(task, *next),
+			"status_id":  statusID,
+		}).Error
+	if err != nil {
+		return err
+	}
+
+	// Reopening goes through the history so the completion that came before it is kept
+	return setTaskStatus(tx, log, task.UserID, []string{task.ID}, model.TaskStatusPending)
+}
+
+// setTaskStatus moves tasks to a status category. Each task whose status actually changes
+// gets a row in task_status_changes, and its completed_at is stamped or cleared.
+func setTaskStatus(tx *gorm.DB, log *activityLog, userID string, taskIDs []string, status int) error {
+	if len(taskIDs) == 0 {
+		return nil
+	}
+	if err := log.track(tx, "task", taskIDs...); err != nil {
+		return err
+	}
+
+	// The joined row still holds the status from before the update
+	return tx.Exec(`
+		WITH changed AS (
+			UPDATE tasks SET
+				status = @status,
+				completed_at = @completed_at,
+				updated_at = CURRENT_TIMESTAMP
+			FROM tasks AS previous
+			WHERE tasks.id = previous.id
+				AND tasks.id IN @task_ids AND tasks.user_id = @user_id AND tasks.status <> @status
+			RETURNING tasks.id, tasks.user_id, previous.status AS from_status, tasks.status_id, tasks.due_date
+		)
+		INSERT INTO task_status_changes (task_id, user_id, from_status, to_status, status_id, due_date)
+		SELECT id, user_id, from_status, @status, status_id, due_date FROM changed`,
+		map[string]any{
+			"status":       status,
+			"completed_at": completedAtFor(status),
+			"task_ids":     taskIDs,
+			"user_id":      userID,
+		}).Error
+}
+
+// recordTaskStatusChange adds a status change made through a regular update or insert
+func recordTaskStatusChange(tx *gorm.DB, task *model.Task, fromStatus *int) error {
+	return tx.Create(&model.TaskStatusChange{
+		TaskID:     task.ID,
+		UserID:     task.UserID,
+		FromStatus: fromStatus,
+		ToStatus:   task.Status,
+		StatusID:   task.StatusID,
+		DueDate:    task.DueDate,
+	}).Error
+}
+
+// completedAtFor is the completed_at value for a task moving to the given status
+func completedAtFor(status int) any {
+	if status == model.TaskStatusCompleted {
+		return gorm.Expr("CURRENT_TIMESTAMP")
+	}
+	return nil
+}
+
+// defaultProjectStatusID returns the first custom status of a category in the project,
+// or nil if the project uses the built-in status for it
+func defaultProjectStatusID(tx *gorm.DB, projectID *string, category string) (*string, error) {
+	if projectID == nil {
+		return nil, nil
+	}
+
+	var statuses []model.ProjectStatus
+	err := tx.Where("project_id = ? AND category = ? AND deleted_at IS NULL", *projectID, category).
+		Order(sortOrderColumn + " ASC NULLS LAST, created_at ASC").
+		Limit(1).
+		Find(&statuses).Error
+	if err != nil || len(statuses) == 0 {
+		return nil, err
+	}
+
+	return &statuses[0].ID, nil
+}
+
+// shiftedStartDate keeps the gap between a task's start and due dates when it moves to a new due date
+func shiftedStartDate(task *model.Task, dueDate time.Time) *time.Time {
+	if task.StartDate == nil || task.DueDate == nil {
+		return task.StartDate
+	}
+	startDate := dueDate.Add(task.StartDate.Sub(*task.DueDate))
+	return &startDate
+}
+
+// nextOccurrence returns the task's first occurrence after the given time, or nil once its rule is exhausted
+func nextOccurrence(task *model.Task, after time.Time) (*time.Time, error) {
+	if task.RecurrenceRule == nil {
+		return nil, nil
+	}
+	next, err := recurrence.Next(*task.RecurrenceRule, task.RecurrenceAnchor(), after)
+	if err != nil {
+		return nil, errors.Join(ErrInvalidRecurrenceRule, err)
+	}
+	return next, nil
+}
+
+// spawnRecurringTask creates the series instance due at the given time and hands the rule over to it.
+// Returns nil if that instance already exists.
+func spawnRecurringTask(tx *gorm.DB, log *activityLog, task *model.Task, dueDate time.Time) (*model.Task, error) {
+	seriesID := task.ID
+	if task.RecurrenceSeriesID != nil {
+		seriesID = *task.RecurrenceSeriesID
+	}
+
+	var existing int64
+	err := tx.Model(&model.Task{}).
+		Where("recurrence_series_id = ? AND due_date = ? AND deleted_at IS NULL", seriesID, dueDate).
+		Count(&existing).Error
+	if err != nil || existing > 0 {
+		return nil, err
+	}
+
+	statusID, err := defaultProjectStatusID(tx, task.ProjectID, model.StatusCategoryTodo)
+	if err != nil {
+		return nil, err
+	}
+
+	// The next instance goes right after this one
+	sortOrder, err := sortOrderAfter(tx, task)
+	if err != nil {
+		return nil, err
+	}
+
+	recurrenceStart := task.RecurrenceAnchor()
+	next := &model.Task{
+		ID:                 uuid.New().String(),
+		ProjectID:          task.ProjectID,
+		ParentTaskID:       task.ParentTaskID,
+		UserID:             task.UserID,
+		Title:              task.Title,
+		Description:        task.Description,
+		Status:             model.TaskStatusPending,
+		StatusID:           statusID,
+		SortOrder:          sortOrder,
+		DueDate:            &dueDate,
+		StartDate:          shiftedStartDate(task, dueDate),
+		Priority:           task.Priority,
+		EstimateMinutes:    task.EstimateMinutes,
+		RecurrenceRule:     task.RecurrenceRule,
+		RecurrenceMode:     task.RecurrenceMode,
+		RecurrenceStart:    &recurrenceStart,
+		RecurrenceSeriesID: &seriesID,
+		ReminderOffsets:    task.ReminderOffsets,
+	}
+	if err := log.track(tx, "task", task.ID, next.ID); err != nil {
+		return nil, err
+	}
+	if err := tx.Omit(clause.Associations).Create(next).Error; err != nil {
+		return nil, err
+	}
+
+	err = tx.Exec("INSERT INTO task_tags (task_id, tag_id) SELECT ?, tag_id FROM task_tags WHERE task_id = ?", next.ID, task.ID).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the newest instance carries the rule, so the series is expanded once
+	err = tx.Model(&model.Task{}).
+		Where("id = ?", task.ID).
+		Updates(map[string]any{
+			"recurrence_rule":      nil,
+			"recurrence_series_id": seriesID,
+		}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return next, nil
+}
+
+// BoardColumn is a column of a project board: a custom status, or the built-in status of a category
+type BoardColumn struct {
+	// StatusID is nil for a built-in status
+	StatusID *string
+	Category string
+	Name     string
+	Color    *string
+}
+
+// ListBoardColumns returns the columns of a project's board, grouped by category in workflow order.
+// A category's built-in status gets a column, ahead of the custom ones, when the project has no
+// custom status for the category or when tasks still use the built-in one.
+func (r *TaskRepository) ListBoardColumns(ctx context.Context, userID, projectID string) ([]BoardColumn, error) {
+	var projects int64
+	err := r.db.WithContext(ctx).Model(&model.Project{}).
+		Where("id = ? AND user_id = ? AND deleted_at IS NULL", projectID, userID).
+		Count(&projects).Error
+	if err != nil {
+		logger.Log.Error("Failed to find board project", zap.Error(err), zap.String("projectID", projectID))
+		return nil, err
+	}
+	if projects == 0 {
+		return nil, ErrProjectNotFound
+	}
+
+	var statuses []model.ProjectStatus
+	err = r.db.WithContext(ctx).
+		Where("project_id = ? AND user_id = ? AND deleted_at IS NULL", projectID, userID).
+		Order(sortOrderColumn + " ASC NULLS LAST, created_at ASC").
+		Find(&statuses).Error
+	if err != nil {
+		logger.Log.Error("Failed to list board statuses", zap.Error(err), zap.String("projectID", projectID))
+		return nil, err
+	}
+
+	var builtInCodes []int
+	err = r.boardTasks(ctx, userID, projectID).
+		Where("status_id IS NULL").
+		Distinct("status").
+		Pluck("status", &builtInCodes).Error
+	if err != nil {
+		logger.Log.Error("Failed to list board built-in statuses", zap.Error(err), zap.String("projectID", projectID))
+		return nil, err
+	}
+
+	columns := []BoardColumn{}
+	for _, category := range model.StatusCategories {
+		code, _ := model.StatusCategoryCode(category)
+		custom := []BoardColumn{}
+		for _, status := range statuses {
+			if status.Category == category {
+				custom = append(custom, BoardColumn{
+					StatusID: util.ToPointer(status.ID),
+					Category: category,
+					Name:     status.Name,
+					Color:    status.Color,
+				})
+			}
+		}
+		if len(custom) == 0 || slices.Contains(builtInCodes, code) {
+			columns = append(columns, BoardColumn{Category: category, Name: model.DefaultStatusName(category)})
+		}
+		columns = append(columns, custom...)
+	}
+
+	return columns, nil
+}
+
+// ListBoardTasks returns a page of a board column's tasks in sort order, and how many tasks the column has.
+// Boards show the project's top-level tasks; subtasks stay with their parent.
+func (r *TaskRepository) ListBoardTasks(ctx context.Context, userID, projectID string, column BoardColumn, limit, offset int) ([]model.Task, int64, error) {
+	query := r.boardTasks(ctx, userID, projectID)
+	if column.StatusID != nil {
+		query = query.Where("status_id = ?", *column.StatusID)
+	} else {
+		code, _ := model.StatusCategoryCode(column.Category)
+		query = query.Where("status_id IS NULL AND status = ?", code)
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		logger.Log.Error("Failed to count board tasks", zap.Error(err), zap.String("projectID", projectID))
+		return nil, 0, err
+	}
+
+	var tasks []model.Task
+	err := query.Session(&gorm.Session{}).
+		Preload("Tags", "deleted_at IS NULL").
+		Preload("BlockedBy").
+		Order(sortOrderColumn + " ASC NULLS LAST, created_at ASC, id ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&tasks).Error
+	if err != nil {
+		logger.Log.Error("Failed to list board tasks", zap.Error(err), zap.String("projectID", projectID))
+		return nil, 0, err
+	}
+
+	return tasks, total, nil
+}
+
+// boardTasks scopes a query to the live top-level tasks of a project
+func (r *TaskRepository) boardTasks(ctx context.Context, userID, projectID string) *gorm.DB {
+	return r.db.WithContext(ctx).Model(&model.Task{}).
+		Where("user_id = ? AND project_id = ? AND parent_task_id IS NULL AND deleted_at IS NULL", userID, projectID)
+}
+
+// MoveTask places a task between two of its siblings and returns its new sort order.
+// A nil afterTaskID means right before beforeTaskID and vice versa; with neither the task goes last.
+func (r *TaskRepository) MoveTask(ctx context.Context, userID, taskID string, afterTaskID, beforeTaskID *string) (string, error) {
+	var sortOrder string
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var task model.Task
+		if err := tx.Where("id = ? AND user_id = ? AND deleted_at IS NULL", taskID, userID).
+			Limit(1).
+			Find(&task).Error; err != nil {
+			return err
+		}
+		if task.ID == "" {
+			return ErrTaskNotFound
+		}
+
+		var err error
+		sortOrder, err = placeTask(tx, nil, &task, afterTaskID, beforeTaskID)
+		return err
+	})
+	if err != nil {
+		logger.Log.Error("Failed to move task", zap.Error(err), zap.String("taskID", taskID))
+		return "", err
+	}
+
+	return sortOrder, nil
+}
+
+// RebalanceSortOrders rewrites the sort orders of every task list that has keys longer
+// than maxKeyLength, duplicated keys or malformed keys, keeping the lists' order.
+// Rewritten tasks get a new updated_at so the keys sync. Returns the number of tasks rewritten.
+func (r *TaskRepository) RebalanceSortOrders(ctx context.Context, maxKeyLength int) (int, error) {
+	var lists []taskList
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT user_id, project_id, parent_task_id FROM tasks
+		WHERE deleted_at IS NULL AND sort_order IS NOT NULL
+		GROUP BY user_id, project_id, parent_task_id
+		HAVING MAX(LENGTH(sort_order)) > @max_length
+			OR COUNT(DISTINCT sort_order) < COUNT(sort_order)
+			OR BOOL_OR(sort_order COLLATE "C" !~ '^[0-9A-Za-z]*[1-9A-Za-z]$')`, map[string]any{
+		"max_length": maxKeyLength,
+	}).Scan(&lists).Error
+	if err != nil {
+		logger.Log.Error("Failed to find task lists to rebalance", zap.Error(err))
+		return 0, err
+	}
+
+	rewritten := 0
+	for _, list := range lists {
+		err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			log := newActivityLog(ActivityActor{UserID: list.UserID, Source: model.ActivitySourceSystem})
+			n, err := rebalanceTaskList(tx, log, list)
+			if err != nil {
+				return err
+			}
+			rewritten += n
+			return log.flush(tx)
+		})
+		if err != nil {
+			logger.Log.Error("Failed to rebalance task list", zap.Error(err), zap.String("userID", list.UserID))
+		}
+	}
+
+	return rewritten, nil
+}
+
+// taskList identifies the tasks that are ordered together: a user's top-level tasks
+// in one project (or in none), or the subtasks of one task
+type taskList struct {
+	UserID       string
+	ProjectID    *string
+	ParentTaskID *string
+}
+
+func taskListOf(task *model.Task) taskList {
+	return taskList{UserID: task.UserID, ProjectID: task.ProjectID, ParentTaskID: task.ParentTaskID}
+}
+
+// tasks scopes a query to the list's live tasks
+func (l taskList) tasks(tx *gorm.DB) *gorm.DB {
+	return tx.Model(&model.Task{}).
+		Where("user_id = ? AND deleted_at IS NULL", l.UserID).
+		Where("project_id IS NOT DISTINCT FROM ? AND parent_task_id IS NOT DISTINCT FROM ?", l.ProjectID, l.ParentTaskID)
+}
+
+// placeTask gives a task a sort order between two of its siblings and returns it. Siblings
+// whose keys are rewritten on the way are tracked by the log, which may be nil.
+func placeTask(tx *gorm.DB, log *activityLog, task *model.Task, afterTaskID, beforeTaskID *string) (string, error) {
+	list := taskListOf(task)
+
+	// Keys that are missing, malformed, duplicated or too long to split get
+	// rewritten once, after which there is always room between two siblings
+	var sortOrder string
+	for attempt := 0; ; attempt++ {
+		a, b, err := moveBounds(tx, list, task.ID, afterTaskID, beforeTaskID)
+		if err == nil {
+			sortOrder, err = fractional.KeyBetween(a, b)
+		}
+		if err == nil {
+			err = fractional.Validate(sortOrder)
+		}
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, fractional.ErrInvalidKey) {
+			return "", err
+		}
+		if attempt > 0 {
+			return "", ErrTaskMoveOrder
+		}
+		if _, err := rebalanceTaskList(tx, log, list); err != nil {
+			return "", err
+		}
+	}
+
+	err := tx.Model(&model.Task{}).
+		Where("id = ?", task.ID).
+		Update("sort_order", sortOrder).Error
+	return sortOrder, err
+}
+
+// moveBounds returns the keys a task moved next to the given siblings has to fall between.
+// An empty key means the start or end of the list.
+func moveBounds(tx *gorm.DB, list taskList, taskID string, afterTaskID, beforeTaskID *string) (string, string, error) {
+	var a, b string
+	if afterTaskID != nil {
+		key, err := siblingSortOrder(tx, list, taskID, *afterTaskID)
+		if err != nil {
+			return "", "", err
+		}
+		a = key
+	}
+	if beforeTaskID != nil {
+		key, err := siblingSortOrder(tx, list, taskID, *beforeTaskID)
+		if err != nil {
+			return "", "", err
+		}
+		b = key
+	}
+
+	// With one neighbour given, the other is whichever sibling currently sits next to it.
+	// With neither, the task goes after the last sibling.
+	var keys []string
+	switch {
+	case afterTaskID == nil && beforeTaskID == nil:
+		err := list.tasks(tx).
+			Where("id <> ? AND sort_order IS NOT NULL", taskID).
+			Order(sortOrderColumn+" DESC").
+			Limit(1).
+			Pluck("sort_order", &keys).Error
+		if err != nil {
+			return "", "", err
+		}
+		if len(keys) > 0 {
+			a = keys[0]
+		}
+	case afterTaskID != nil && beforeTaskID == nil:
+		err := list.tasks(tx).
+			Where("id <> ? AND "+sortOrderColumn+" > ?", taskID, a).
+			Order(sortOrderColumn).
+			Limit(1).
+			Pluck("sort_order", &keys).Error
+		if err != nil {
+			return "", "", err
+		}
+		if len(keys) > 0 {
+			b = keys[0]
+		}
+	case afterTaskID == nil && beforeTaskID != nil:
+		err := list.tasks(tx).
+			Where("id <> ? AND "+sortOrderColumn+" < ?", taskID, b).
+			Order(sortOrderColumn+" DESC").
+			Limit(1).
+			Pluck("sort_order", &keys).Error
+		if err != nil {
+			return "", "", err
+		}
+		if len(keys) > 0 {
+			a = keys[0]
+		}
+	}
+
+	return a, b, nil
+}
+
+// siblingSortOrder returns the key of another task in the list. A sibling without a valid key
+// reports fractional.ErrInvalidKey so the list gets rebalanced.
+func siblingSortOrder(tx *gorm.DB, list taskList, taskID, siblingID string) (string, error) {
+	var siblings []model.Task
+	err := list.tasks(tx).
+		Where("id = ? AND id <> ?", siblingID, taskID).
+		Limit(1).
+		Find(&siblings).Error
+	if err != nil {
+		return "", err
+	}
+	if len(siblings) == 0 {
+		return "", ErrTaskMoveNotInList
+	}
+	if siblings[0].SortOrder == nil {
+		return "", fractional.ErrInvalidKey
+	}
+	return *siblings[0].SortOrder, fractional.Validate(*siblings[0].SortOrder)
+}
+
+// rebalanceTaskList spreads fresh, short keys over the list in its current order. Tasks without
+// a key keep their place at the end. Only tasks whose key changes are written, and tracked by the log.
+func rebalanceTaskList(tx *gorm.DB, log *activityLog, list taskList) (int, error) {
+	var tasks []model.Task
+	err := list.tasks(tx).
+		Select("id", "sort_order").
+		Order(sortOrderColumn + " ASC NULLS LAST, created_at ASC, id ASC").
+		Find(&tasks).Error
+	if err != nil {
+		return 0, err
+	}
+
+	keys, err := fractional.NKeysBetween("", "", len(tasks))
+	if err != nil {
+		return 0, err
+	}
+
+	rewritten := 0
+	for i, task := range tasks {
+		if util.ToValue(task.SortOrder) == keys[i] {
+			continue
+		}
+		if err := log.track(tx, "task", task.ID); err != nil {
+			return rewritten, err
+		}
+		err := tx.Model(&model.Task{}).
+			Where("id = ?", task.ID).
+			Update("sort_order", keys[i]).Error
+		if err != nil {
+			return rewritten, err
+		}
+		rewritten++
+	}
+
+	return rewritten, nil
+}
+
+// resolveDuplicateSortOrder moves a task just past any sibling that already has its key,
+// e.g. after two devices inserted at the same spot while offline
+func resolveDuplicateSortOrder(tx *gorm.DB, userID, taskID string) error {
+	var task model.Task
+	err := tx.Where("id = ? AND user_id = ? AND deleted_at IS NULL", taskID, userID).
+		Limit(1).
+		Find(&task).Error
+	if err != nil || task.ID == "" || task.SortOrder == nil {
+		return err
+	}
+
+	var duplicates int64
+	err = taskListOf(&task).tasks(tx).
+		Where("id <> ? AND sort_order = ?", task.ID, *task.SortOrder).
+		Count(&duplicates).Error
+	if err != nil || duplicates == 0 {
+		return err
+	}
+
+	sortOrder, err := sortOrderAfter(tx, &task)
+	if err != nil {
+		return err
+	}
+	if sortOrder == nil {
+		// Malformed neighbours; leave it to the next rebalance
+		return nil
+	}
+
+	return tx.Model(&model.Task{}).
+		Where("id = ?", task.ID).
+		Update("sort_order", *sortOrder).Error
+}
+
+// sortOrderAfter returns a key between the task's and the next greater key in its list.
+// Returns nil if the task has no key or the keys around it are malformed.
+func sortOrderAfter(tx *gorm.DB, task *model.Task) (*string, error) {
+	if task.SortOrder == nil {
+		return nil, nil
+	}
+
+	var keys []string
+	err := taskListOf(task).tasks(tx).
+		Where(sortOrderColumn+" > ?", *task.SortOrder).
+		Order(sortOrderColumn).
+		Limit(1).
+		Pluck("sort_order", &keys).Error
+	if err != nil {
+		return nil, err
+	}
+
+	next := ""
+	if len(keys) > 0 {
+		next = keys[0]
+	}
+	key, err := fractional.KeyBetween(*task.SortOrder, next)
+	if err != nil || fractional.Validate(key) != nil {
+		return nil, nil
+	}
+	return &key, nil
+}
@@ -0,0 +1,337 @@
+package repository
+
+import (
+	"app/internal/contract"
+	"app/internal/model"
+	"app/pkg/util"
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestMaterializeRecurringTasksCapsEachSeries(t *testing.T) {
+	db := testDB(t)
+	syncRepo := newTestSyncRepository(t, db)
+	taskRepo := NewTaskRepository(db)
+	userID := testUser(t, db)
+
+	seriesID := uuid.NewString()
+	applyChanges(t, syncRepo, userID, contract.Change{
+		Type:           "task",
+		EntityID:       seriesID,
+		Title:          util.ToPointer("Water plants"),
+		DueDate:        util.ToPointer(time.Now().AddDate(0, 0, -60).Format(time.RFC3339)),
+		RecurrenceRule: util.ToPointer("FREQ=DAILY"),
+		RecurrenceMode: util.ToPointer(model.RecurrenceModeSpawn),
+	})
+
+	if _, err := taskRepo.MaterializeRecurringTasks(context.Background(), 14*24*time.Hour, 5); err != nil {
+		t.Fatalf("failed to materialize: %v", err)
+	}
+
+	var spawned int64
+	if err := db.Model(&model.Task{}).Where("recurrence_series_id = ? AND id <> ?", seriesID, seriesID).Count(&spawned).Error; err != nil {
+		t.Fatalf("failed to count spawned tasks: %v", err)
+	}
+	if spawned != 5 {
+		t.Errorf("spawned %d tasks, want the cap of 5", spawned)
+	}
+}
+
+func TestCompletedRollOccurrenceUnblocksDependents(t *testing.T) {
+	db := testDB(t)
+	repo := newTestSyncRepository(t, db)
+	userID := testUser(t, db)
+
+	blockerID, dependentID := uuid.NewString(), uuid.NewString()
+	applyChanges(t, repo, userID,
+		contract.Change{
+			Type:           "task",
+			EntityID:       blockerID,
+			Title:          util.ToPointer("Weekly report"),
+			DueDate:        util.ToPointer(time.Now().Format(time.RFC3339)),
+			RecurrenceRule: util.ToPointer("FREQ=WEEKLY"),
+			RecurrenceMode: util.ToPointer(model.RecurrenceModeRoll),
+		},
+		contract.Change{Type: "task", EntityID: dependentID, Title: util.ToPointer("Send report"), BlockedByTaskIDs: &[]string{blockerID}},
+	)
+
+	var dependent model.Task
+	if err := db.First(&dependent, "id = ?", dependentID).Error; err != nil {
+		t.Fatalf("failed to get task: %v", err)
+	}
+	if !dependent.Blocked {
+		t.Fatal("dependent isn't blocked before the blocker is completed")
+	}
+
+	applyChanges(t, repo, userID, contract.Change{Type: "task", EntityID: blockerID, Status: util.ToPointer(model.TaskStatusCompleted)})
+
+	var blocker model.Task
+	if err := db.First(&blocker, "id = ?", blockerID).Error; err != nil {
+		t.Fatalf("failed to get task: %v", err)
+	}
+	if blocker.Status != model.TaskStatusPending {
+		t.Fatalf("roll-mode blocker status = %d, want it reopened", blocker.Status)
+	}
+	if err := db.First(&dependent, "id = ?", dependentID).Error; err != nil {
+		t.Fatalf("failed to get task: %v", err)
+	}
+	if dependent.Blocked {
+		t.Error("dependent is still blocked after an occurrence of its blocker was completed")
+	}
+
+	// Resending the same dependencies keeps the completion counting
+	applyChanges(t, repo, userID, contract.Change{Type: "task", EntityID: dependentID, BlockedByTaskIDs: &[]string{blockerID}})
+	if err := db.First(&dependent, "id = ?", dependentID).Error; err != nil {
+		t.Fatalf("failed to get task: %v", err)
+	}
+	if dependent.Blocked {
+		t.Error("resending the dependencies blocked the task again")
+	}
+}
+
+func TestSyncTaskDependencyErrors(t *testing.T) {
+	db := testDB(t)
+	repo := newTestSyncRepository(t, db)
+	userID, otherID := testUser(t, db), testUser(t, db)
+
+	firstID, secondID, thirdID, othersID := uuid.NewString(), uuid.NewString(), uuid.NewString(), uuid.NewString()
+	applyChanges(t, repo, otherID, contract.Change{Type: "task", EntityID: othersID, Title: util.ToPointer("Not yours")})
+	applyChanges(t, repo, userID,
+		contract.Change{Type: "task", EntityID: firstID, Title: util.ToPointer("First")},
+		contract.Change{Type: "task", EntityID: secondID, Title: util.ToPointer("Second"), BlockedByTaskIDs: &[]string{firstID}},
+		contract.Change{Type: "task", EntityID: thirdID, Title: util.ToPointer("Third"), BlockedByTaskIDs: &[]string{secondID}},
+	)
+
+	tests := []struct {
+		name      string
+		taskID    string
+		blockedBy []string
+		want      error
+	}{
+		{name: "by itself", taskID: firstID, blockedBy: []string{firstID}, want: ErrTaskDependencyCycle},
+		{name: "by a task it blocks through another", taskID: firstID, blockedBy: []string{thirdID}, want: ErrTaskDependencyCycle},
+		{name: "by a missing task", taskID: firstID, blockedBy: []string{uuid.NewString()}, want: ErrTaskDependencyNotFound},
+		{name: "by another user's task", taskID: firstID, blockedBy: []string{othersID}, want: ErrTaskDependencyNotFound},
+	}
+	for _, tt := range tests {
+		_, err := repo.ApplyChanges(userID, "", []contract.Change{
+			{Type: "task", EntityID: tt.taskID, BlockedByTaskIDs: &tt.blockedBy},
+		}, model.ActivitySourceSync)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestBlockedFollowsEveryBlocker(t *testing.T) {
+	db := testDB(t)
+	repo := newTestSyncRepository(t, db)
+	userID := testUser(t, db)
+
+	firstID, secondID, dependentID := uuid.NewString(), uuid.NewString(), uuid.NewString()
+	applyChanges(t, repo, userID,
+		contract.Change{Type: "task", EntityID: firstID, Title: util.ToPointer("Draft")},
+		contract.Change{Type: "task", EntityID: secondID, Title: util.ToPointer("Review")},
+		contract.Change{Type: "task", EntityID: dependentID, Title: util.ToPointer("Publish"), BlockedByTaskIDs: &[]string{firstID, secondID}},
+	)
+	blocked := func() bool {
+		t.Helper()
+		var task model.Task
+		if err := db.First(&task, "id = ?", dependentID).Error; err != nil {
+			t.Fatalf("failed to get task: %v", err)
+		}
+		return task.Blocked
+	}
+
+	applyChanges(t, repo, userID, contract.Change{Type: "task", EntityID: firstID, Status: util.ToPointer(model.TaskStatusCompleted)})
+	if !blocked() {
+		t.Error("task isn't blocked while one of its blockers is open")
+	}
+	applyChanges(t, repo, userID, contract.Change{Type: "task", EntityID: secondID, Status: util.ToPointer(model.TaskStatusCancelled)})
+	if blocked() {
+		t.Error("task is still blocked with every blocker done")
+	}
+	applyChanges(t, repo, userID, contract.Change{Type: "task", EntityID: firstID, Status: util.ToPointer(model.TaskStatusPending)})
+	if !blocked() {
+		t.Error("task isn't blocked again after a blocker reopened")
+	}
+	applyChanges(t, repo, userID, contract.Change{Type: "task", EntityID: dependentID, BlockedByTaskIDs: &[]string{}})
+	if blocked() {
+		t.Error("task is still blocked with its dependencies cleared")
+	}
+}
+
+func TestShiftedStartDate(t *testing.T) {
+	dueDate := time.Date(2026, 1, 10, 9, 0, 0, 0, time.UTC)
+	startDate := dueDate.Add(-48 * time.Hour)
+	nextDueDate := dueDate.AddDate(0, 0, 7)
+
+	got := shiftedStartDate(&model.Task{DueDate: &dueDate, StartDate: &startDate}, nextDueDate)
+	if got == nil || !got.Equal(nextDueDate.Add(-48*time.Hour)) {
+		t.Errorf("shiftedStartDate = %v, want two days before the next due date", got)
+	}
+	if got := shiftedStartDate(&model.Task{StartDate: &startDate}, nextDueDate); got != &startDate {
+		t.Errorf("shiftedStartDate without a due date = %v, want the start date kept", got)
+	}
+	if got := shiftedStartDate(&model.Task{DueDate: &dueDate}, nextDueDate); got != nil {
+		t.Errorf("shiftedStartDate without a start date = %v, want nil", got)
+	}
+}
+
+func TestSyncClearsStartDateAndEstimate(t *testing.T) {
+	db := testDB(t)
+	repo := newTestSyncRepository(t, db)
+	userID := testUser(t, db)
+
+	taskID := uuid.NewString()
+	applyChanges(t, repo, userID, contract.Change{
+		Type:            "task",
+		EntityID:        taskID,
+		Title:           util.ToPointer("Plan sprint"),
+		StartDate:       util.ToPointer("2026-02-02T09:00:00Z"),
+		Priority:        util.ToPointer(model.TaskPriorityHigh),
+		EstimateMinutes: util.ToPointer(90),
+	})
+
+	var task model.Task
+	if err := db.First(&task, "id = ?", taskID).Error; err != nil {
+		t.Fatalf("failed to get task: %v", err)
+	}
+	if task.StartDate == nil || task.Priority != model.TaskPriorityHigh || util.ToValue(task.EstimateMinutes) != 90 {
+		t.Fatalf("task has start %v, priority %d and estimate %v, want them as synced", task.StartDate, task.Priority, task.EstimateMinutes)
+	}
+
+	applyChanges(t, repo, userID, contract.Change{
+		Type:            "task",
+		EntityID:        taskID,
+		StartDate:       util.ToPointer(""),
+		EstimateMinutes: util.ToPointer(0),
+	})
+
+	task = model.Task{}
+	if err := db.First(&task, "id = ?", taskID).Error; err != nil {
+		t.Fatalf("failed to get task: %v", err)
+	}
+	if task.StartDate != nil || task.EstimateMinutes != nil {
+		t.Errorf("task has start %v and estimate %v, want both cleared", task.StartDate, task.EstimateMinutes)
+	}
+	if task.Priority != model.TaskPriorityHigh {
+		t.Errorf("priority = %d, want it kept when not sent", task.Priority)
+	}
+}
+
+func TestSpawnedOccurrenceKeepsPlanning(t *testing.T) {
+	db := testDB(t)
+	repo := newTestSyncRepository(t, db)
+	userID := testUser(t, db)
+
+	taskID := uuid.NewString()
+	applyChanges(t, repo, userID, contract.Change{
+		Type:            "task",
+		EntityID:        taskID,
+		Title:           util.ToPointer("Review budget"),
+		DueDate:         util.ToPointer("2026-01-09T17:00:00Z"),
+		StartDate:       util.ToPointer("2026-01-08T09:00:00Z"),
+		Priority:        util.ToPointer(model.TaskPriorityUrgent),
+		EstimateMinutes: util.ToPointer(45),
+		RecurrenceRule:  util.ToPointer("FREQ=WEEKLY"),
+		RecurrenceMode:  util.ToPointer(model.RecurrenceModeSpawn),
+	})
+	applyChanges(t, repo, userID, contract.Change{Type: "task", EntityID: taskID, Status: util.ToPointer(model.TaskStatusCompleted)})
+
+	var next model.Task
+	if err := db.Where("recurrence_series_id = ? AND id <> ?", taskID, taskID).Take(&next).Error; err != nil {
+		t.Fatalf("failed to get spawned task: %v", err)
+	}
+	if next.Priority != model.TaskPriorityUrgent || util.ToValue(next.EstimateMinutes) != 45 {
+		t.Errorf("spawned task has priority %d and estimate %v, want them copied", next.Priority, next.EstimateMinutes)
+	}
+	if next.StartDate == nil || next.DueDate == nil || next.DueDate.Sub(*next.StartDate) != 32*time.Hour {
+		t.Errorf("spawned task starts %v and is due %v, want the same lead time", next.StartDate, next.DueDate)
+	}
+}
+
+func TestListBoardColumns(t *testing.T) {
+	db := testDB(t)
+	syncRepo := newTestSyncRepository(t, db)
+	taskRepo := NewTaskRepository(db)
+	userID := testUser(t, db)
+
+	projectID, qaID := uuid.NewString(), uuid.NewString()
+	applyChanges(t, syncRepo, userID,
+		contract.Change{Type: "project", EntityID: projectID, Title: util.ToPointer("Release")},
+		contract.Change{Type: "task", EntityID: uuid.NewString(), Title: util.ToPointer("Started before QA"), ProjectID: &projectID, Status: util.ToPointer(model.TaskStatusInProgress)},
+		contract.Change{Type: "status", EntityID: qaID, ProjectID: &projectID, Name: util.ToPointer("QA"), Category: util.ToPointer(model.StatusCategoryDoing)},
+	)
+
+	columns, err := taskRepo.ListBoardColumns(context.Background(), userID, projectID)
+	if err != nil {
+		t.Fatalf("failed to list board columns: %v", err)
+	}
+
+	names := make([]string, 0, len(columns))
+	for _, column := range columns {
+		names = append(names, column.Name)
+	}
+	want := []string{"To do", "In progress", "QA", "Done", "Cancelled"}
+	if !slices.Equal(names, want) {
+		t.Errorf("columns = %v, want %v", names, want)
+	}
+	if util.ToValue(columns[2].StatusID) != qaID {
+		t.Errorf("QA column has status %v, want %s", columns[2].StatusID, qaID)
+	}
+
+	_, err = taskRepo.ListBoardColumns(context.Background(), testUser(t, db), projectID)
+	if !errors.Is(err, ErrProjectNotFound) {
+		t.Errorf("listing another user's board: got %v, want ErrProjectNotFound", err)
+	}
+}
+
+func TestMoveBoardTask(t *testing.T) {
+	db := testDB(t)
+	syncRepo := newTestSyncRepository(t, db)
+	taskRepo := NewTaskRepository(db)
+	userID := testUser(t, db)
+	ctx := context.Background()
+
+	projectID, qaID := uuid.NewString(), uuid.NewString()
+	firstID, secondID, movedID := uuid.NewString(), uuid.NewString(), uuid.NewString()
+	applyChanges(t, syncRepo, userID,
+		contract.Change{Type: "project", EntityID: projectID, Title: util.ToPointer("Release")},
+		contract.Change{Type: "status", EntityID: qaID, ProjectID: &projectID, Name: util.ToPointer("QA"), Category: util.ToPointer(model.StatusCategoryDoing)},
+		contract.Change{Type: "task", EntityID: firstID, Title: util.ToPointer("First"), ProjectID: &projectID, StatusID: &qaID, SortOrder: util.ToPointer("a1")},
+		contract.Change{Type: "task", EntityID: secondID, Title: util.ToPointer("Second"), ProjectID: &projectID, StatusID: &qaID, SortOrder: util.ToPointer("a2")},
+		contract.Change{Type: "task", EntityID: movedID, Title: util.ToPointer("Moved"), ProjectID: &projectID},
+	)
+
+	column := BoardColumn{StatusID: &qaID, Category: model.StatusCategoryDoing, Name: "QA"}
+	task, err := syncRepo.MoveBoardTask(ctx, userID, userID, projectID, movedID, column, &firstID, &secondID)
+	if err != nil {
+		t.Fatalf("failed to move task: %v", err)
+	}
+	if task.Status != model.TaskStatusInProgress || util.ToValue(task.StatusID) != qaID {
+		t.Errorf("moved task has status %d in %v, want in progress in QA", task.Status, task.StatusID)
+	}
+
+	tasks, total, err := taskRepo.ListBoardTasks(ctx, userID, projectID, column, 10, 0)
+	if err != nil {
+		t.Fatalf("failed to list board tasks: %v", err)
+	}
+	ids := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+	}
+	if total != 3 || !slices.Equal(ids, []string{firstID, movedID, secondID}) {
+		t.Errorf("QA column holds %v of %d, want the moved task between the others", ids, total)
+	}
+
+	_, err = syncRepo.MoveBoardTask(ctx, userID, userID, uuid.NewString(), movedID, column, nil, nil)
+	if !errors.Is(err, ErrTaskNotFound) {
+		t.Errorf("moving a task on another project's board: got %v, want ErrTaskNotFound", err)
+	}
+}
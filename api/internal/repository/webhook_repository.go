@@ -0,0 +1,416 @@
+package repository
+
+import (
+	"app/internal/contract"
+	"app/internal/model"
+	"app/pkg/logger"
+	"app/pkg/util"
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrWebhookNotFound         = errors.New("webhook not found")
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+	ErrWebhookInactive         = errors.New("webhook is turned off")
+)
+
+// WebhookDeliveryFilters represents filters for a webhook's delivery log
+type WebhookDeliveryFilters struct {
+	Status *string
+	Limit  int
+	Offset int
+}
+
+// WebhookAttempt is the outcome of posting a delivery
+type WebhookAttempt struct {
+	Status         string
+	LastError      *string
+	ResponseStatus *int
+	ResponseBody   *string
+	DurationMs     *int
+	NextAttemptAt  *time.Time
+}
+
+type WebhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+func (r *WebhookRepository) CreateWebhook(ctx context.Context, webhook *model.Webhook) error {
+	if err := r.db.WithContext(ctx).Create(webhook).Error; err != nil {
+		logger.Log.Error("Failed to create webhook", zap.Error(err), zap.String("userID", webhook.UserID))
+		return err
+	}
+
+	return nil
+}
+
+// ListWebhooks returns the user's live webhooks, oldest first
+func (r *WebhookRepository) ListWebhooks(ctx context.Context, userID string) ([]model.Webhook, error) {
+	var webhooks []model.Webhook
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND deleted_at IS NULL", userID).
+		Order("created_at ASC").
+		Find(&webhooks).Error
+	if err != nil {
+		logger.Log.Error("Failed to list webhooks", zap.Error(err), zap.String("userID", userID))
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+// GetWebhook returns a live webhook of the user's, or nil if there is none
+func (r *WebhookRepository) GetWebhook(ctx context.Context, userID, webhookID string) (*model.Webhook, error) {
+	var webhooks []model.Webhook
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ? AND deleted_at IS NULL", webhookID, userID).
+		Limit(1).
+		Find(&webhooks).Error
+	if err != nil {
+		logger.Log.Error("Failed to get webhook", zap.Error(err), zap.String("webhookID", webhookID))
+		return nil, err
+	}
+	if len(webhooks) == 0 {
+		return nil, nil
+	}
+
+	return &webhooks[0], nil
+}
+
+// UpdateWebhook changes the given columns of a live webhook of the user's. Turning it off cancels
+// the deliveries it still had to make.
+func (r *WebhookRepository) UpdateWebhook(ctx context.Context, userID, webhookID string, updates map[string]any) error {
+	updates["updated_at"] = gorm.Expr("CURRENT_TIMESTAMP")
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&model.Webhook{}).
+			Where("id = ? AND user_id = ? AND deleted_at IS NULL", webhookID, userID).
+			Updates(updates)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrWebhookNotFound
+		}
+
+		if active, ok := updates["active"].(bool); ok && !active {
+			return cancelWebhookDeliveries(tx, webhookID, "webhook was turned off")
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, ErrWebhookNotFound) {
+		logger.Log.Error("Failed to update webhook", zap.Error(err), zap.String("webhookID", webhookID))
+	}
+
+	return err
+}
+
+// DeleteWebhook deletes a webhook of the user's and cancels the deliveries it still had to make.
+// Its delivery log stays until the webhook is purged with its user.
+func (r *WebhookRepository) DeleteWebhook(ctx context.Context, userID, webhookID string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&model.Webhook{}).
+			Where("id = ? AND user_id = ? AND deleted_at IS NULL", webhookID, userID).
+			Updates(map[string]any{
+				"deleted_at": gorm.Expr("CURRENT_TIMESTAMP"),
+				"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrWebhookNotFound
+		}
+
+		return cancelWebhookDeliveries(tx, webhookID, "webhook was deleted")
+	})
+	if err != nil && !errors.Is(err, ErrWebhookNotFound) {
+		logger.Log.Error("Failed to delete webhook", zap.Error(err), zap.String("webhookID", webhookID))
+	}
+
+	return err
+}
+
+// ListDeliveries returns the delivery log of a webhook, newest first
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, webhookID string, filters WebhookDeliveryFilters) ([]model.WebhookDelivery, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.WebhookDelivery{}).Where("webhook_id = ?", webhookID)
+	if filters.Status != nil {
+		query = query.Where("status = ?", *filters.Status)
+	}
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		logger.Log.Error("Failed to count webhook deliveries", zap.Error(err), zap.String("webhookID", webhookID))
+		return nil, 0, err
+	}
+
+	var deliveries []model.WebhookDelivery
+	err := query.
+		Order("created_at DESC, id ASC").
+		Limit(filters.Limit).
+		Offset(filters.Offset).
+		Find(&deliveries).Error
+	if err != nil {
+		logger.Log.Error("Failed to list webhook deliveries", zap.Error(err), zap.String("webhookID", webhookID))
+		return nil, 0, err
+	}
+
+	return deliveries, total, nil
+}
+
+// Redeliver queues the event of a past delivery again as a new delivery, so the log keeps both
+func (r *WebhookRepository) Redeliver(ctx context.Context, webhook *model.Webhook, deliveryID string) (*model.WebhookDelivery, error) {
+	if !webhook.Active {
+		return nil, ErrWebhookInactive
+	}
+
+	var original model.WebhookDelivery
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND webhook_id = ?", deliveryID, webhook.ID).
+		Limit(1).
+		Find(&original).Error
+	if err != nil {
+		logger.Log.Error("Failed to get webhook delivery", zap.Error(err), zap.String("deliveryID", deliveryID))
+		return nil, err
+	}
+	if original.ID == "" {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+
+	delivery := model.WebhookDelivery{
+		WebhookID:    webhook.ID,
+		ActivityID:   original.ActivityID,
+		EventType:    original.EventType,
+		Payload:      original.Payload,
+		RedeliveryOf: &original.ID,
+	}
+	if err := r.db.WithContext(ctx).Create(&delivery).Error; err != nil {
+		logger.Log.Error("Failed to redeliver webhook delivery", zap.Error(err), zap.String("deliveryID", deliveryID))
+		return nil, err
+	}
+
+	return &delivery, nil
+}
+
+// ClaimDeliveries marks up to limit deliveries that are due as sending and returns them with their
+// webhooks, so concurrent workers never post the same one twice
+func (r *WebhookRepository) ClaimDeliveries(ctx context.Context, limit int) ([]model.WebhookDelivery, error) {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = @sending, attempts = attempts + 1, updated_at = CURRENT_TIMESTAMP
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status IN @ready AND attempts < @max_attempts AND next_attempt_at <= CURRENT_TIMESTAMP
+			ORDER BY created_at
+			LIMIT @limit
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`
+
+	var deliveries []model.WebhookDelivery
+	err := r.db.WithContext(ctx).Raw(query, map[string]any{
+		"sending":      model.WebhookStatusSending,
+		"ready":        []string{model.WebhookStatusPending, model.WebhookStatusFailed},
+		"max_attempts": model.WebhookMaxAttempts,
+		"limit":        limit,
+	}).Scan(&deliveries).Error
+	if err != nil {
+		logger.Log.Error("Failed to claim webhook deliveries", zap.Error(err))
+		return nil, err
+	}
+	if len(deliveries) == 0 {
+		return deliveries, nil
+	}
+
+	webhookIDs := []string{}
+	for _, delivery := range deliveries {
+		if !slices.Contains(webhookIDs, delivery.WebhookID) {
+			webhookIDs = append(webhookIDs, delivery.WebhookID)
+		}
+	}
+	var webhooks []model.Webhook
+	if err := r.db.WithContext(ctx).Where("id IN ?", webhookIDs).Find(&webhooks).Error; err != nil {
+		logger.Log.Error("Failed to get webhooks of claimed deliveries", zap.Error(err))
+		return nil, err
+	}
+	for i := range deliveries {
+		for j := range webhooks {
+			if webhooks[j].ID == deliveries[i].WebhookID {
+				deliveries[i].Webhook = &webhooks[j]
+			}
+		}
+	}
+
+	return deliveries, nil
+}
+
+// AbandonStaleDeliveries gives up on deliveries left in sending by a worker that stopped mid-post.
+// They are not retried because the endpoint may already have received them; they can be redelivered.
+func (r *WebhookRepository) AbandonStaleDeliveries(ctx context.Context, olderThan time.Duration) (int64, error) {
+	res := r.db.WithContext(ctx).
+		Model(&model.WebhookDelivery{}).
+		Where("status = ? AND updated_at < ?", model.WebhookStatusSending, time.Now().Add(-olderThan)).
+		Updates(map[string]any{
+			"status":     model.WebhookStatusFailed,
+			"attempts":   model.WebhookMaxAttempts,
+			"last_error": "delivery was interrupted and may or may not have been received",
+			"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+		})
+	if res.Error != nil {
+		logger.Log.Error("Failed to abandon stale webhook deliveries", zap.Error(res.Error))
+		return 0, res.Error
+	}
+
+	return res.RowsAffected, nil
+}
+
+// MarkDelivery records the outcome of a delivery attempt
+func (r *WebhookRepository) MarkDelivery(ctx context.Context, id string, attempt WebhookAttempt) error {
+	updates := map[string]any{
+		"status":          attempt.Status,
+		"last_error":      attempt.LastError,
+		"response_status": attempt.ResponseStatus,
+		"response_body":   attempt.ResponseBody,
+		"duration_ms":     attempt.DurationMs,
+		"updated_at":      gorm.Expr("CURRENT_TIMESTAMP"),
+	}
+	if attempt.Status == model.WebhookStatusSent {
+		updates["sent_at"] = gorm.Expr("CURRENT_TIMESTAMP")
+	}
+	if attempt.NextAttemptAt != nil {
+		updates["next_attempt_at"] = *attempt.NextAttemptAt
+	}
+
+	err := r.db.WithContext(ctx).
+		Model(&model.WebhookDelivery{}).
+		Where("id = ?", id).
+		Updates(updates).Error
+	if err != nil {
+		logger.Log.Error("Failed to mark webhook delivery", zap.Error(err), zap.String("deliveryID", id), zap.String("status", attempt.Status))
+		return err
+	}
+
+	return nil
+}
+
+// cancelWebhookDeliveries cancels the deliveries a webhook still had to make
+func cancelWebhookDeliveries(tx *gorm.DB, webhookID, reason string) error {
+	return tx.Model(&model.WebhookDelivery{}).
+		Where("webhook_id = ? AND status IN ?", webhookID, []string{model.WebhookStatusPending, model.WebhookStatusFailed}).
+		Updates(map[string]any{
+			"status":     model.WebhookStatusCancelled,
+			"last_error": reason,
+			"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+		}).Error
+}
+
+// webhookSeesActivity reports whether a webhook may be told about an entry of the activity log.
+// One limited to a project needs its user to still be a member; the others get changes to their
+// user's items and changes their user makes.
+func webhookSeesActivity(tx *gorm.DB, webhook *model.Webhook, activity *model.Activity) (bool, error) {
+	if webhook.ProjectID == nil {
+		return webhook.UserID == activity.OwnerID || webhook.UserID == activity.ActorID, nil
+	}
+	if util.ToValue(activity.ProjectID) != *webhook.ProjectID {
+		return false, nil
+	}
+
+	access, err := memberAccess(tx, webhook.UserID, MemberTarget{ProjectID: webhook.ProjectID})
+	if err != nil {
+		return false, err
+	}
+	return access != nil && access.Role != "", nil
+}
+
+// enqueueWebhooks queues a delivery of each event an entry of the activity log raises to every
+// active webhook that subscribes to it, in the transaction that made the change
+func enqueueWebhooks(tx *gorm.DB, activity *model.Activity, entity map[string]any) error {
+	users := []string{activity.OwnerID, activity.ActorID}
+	query := tx.Where("deleted_at IS NULL AND active")
+	if activity.ProjectID != nil {
+		query = query.Where("(project_id IS NULL AND user_id IN ?) OR project_id = ?", users, *activity.ProjectID)
+	} else {
+		query = query.Where("project_id IS NULL AND user_id IN ?", users)
+	}
+	var webhooks []model.Webhook
+	if err := query.Find(&webhooks).Error; err != nil {
+		return err
+	}
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	changes := activity.Changes.Data()
+	changesRes := map[string]contract.ActivityChangeRes{}
+	for column, change := range changes {
+		changesRes[column] = contract.ActivityChangeRes{From: change.From, To: change.To}
+	}
+
+	deliveries := []model.WebhookDelivery{}
+	for _, eventType := range model.ActivityEventTypes(activity) {
+		var payload []byte
+		for i := range webhooks {
+			if !slices.Contains(webhooks[i].EventTypes, eventType) {
+				continue
+			}
+			sees, err := webhookSeesActivity(tx, &webhooks[i], activity)
+			if err != nil {
+				return err
+			}
+			if !sees {
+				continue
+			}
+
+			// Every webhook gets the same event, so a consumer can tell repeats apart by its ID
+			if payload == nil {
+				var err error
+				payload, err = json.Marshal(contract.WebhookEvent{
+					ID:           uuid.New().String(),
+					Type:         eventType,
+					CreatedAt:    activity.CreatedAt.UTC().Format(time.RFC3339),
+					EntityType:   activity.EntityType,
+					EntityID:     activity.EntityID,
+					Action:       activity.Action,
+					Source:       activity.Source,
+					ActorID:      activity.ActorID,
+					OwnerID:      activity.OwnerID,
+					DeviceID:     activity.DeviceID,
+					Changes:      changesRes,
+					ProjectID:    activity.ProjectID,
+					CollectionID: activity.CollectionID,
+					TaskID:       activity.TaskID,
+					NoteID:       activity.NoteID,
+					Entity:       entity,
+				})
+				if err != nil {
+					return err
+				}
+			}
+
+			deliveries = append(deliveries, model.WebhookDelivery{
+				WebhookID:  webhooks[i].ID,
+				ActivityID: &activity.ID,
+				EventType:  eventType,
+				Payload:    datatypes.JSON(payload),
+			})
+		}
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	return tx.Create(&deliveries).Error
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"app/internal/model"
+	"app/pkg/logger"
+	"context"
+	"errors"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrWorkspaceNotFound = errors.New("workspace not found")
+	ErrWorkspaceNotEmpty = errors.New("delete the workspace's projects and collections first")
+
+	ErrWorkspaceAccess        = errors.New("you aren't a member of this workspace")
+	ErrWorkspaceMoveForbidden = errors.New("items can't move between workspaces")
+	ErrWorkspaceItemOutside   = errors.New("items in a workspace must be in one of its projects or collections and are only synced there")
+)
+
+// UserWorkspace is a workspace with the user's role on it
+type UserWorkspace struct {
+	model.Workspace
+	Role string
+}
+
+type WorkspaceRepository struct {
+	db *gorm.DB
+}
+
+func NewWorkspaceRepository(db *gorm.DB) *WorkspaceRepository {
+	return &WorkspaceRepository{db: db}
+}
+
+// CreateWorkspace creates a workspace owned by its user
+func (r *WorkspaceRepository) CreateWorkspace(ctx context.Context, workspace *model.Workspace) error {
+	if err := r.db.WithContext(ctx).Create(workspace).Error; err != nil {
+		logger.Log.Error("Failed to create workspace", zap.Error(err), zap.String("userID", workspace.UserID))
+		return err
+	}
+
+	return nil
+}
+
+// ListWorkspaces returns the live workspaces the user owns or is a member of, oldest first
+func (r *WorkspaceRepository) ListWorkspaces(ctx context.Context, userID string) ([]UserWorkspace, error) {
+	var workspaces []UserWorkspace
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT w.*, CASE WHEN w.user_id = @user_id THEN @owner ELSE m.role END AS role
+		FROM workspaces w
+		LEFT JOIN memberships m ON m.workspace_id = w.id AND m.user_id = @user_id AND m.removed_at IS NULL
+		WHERE w.deleted_at IS NULL AND (w.user_id = @user_id OR m.id IS NOT NULL)
+		ORDER BY w.created_at ASC`,
+		map[string]any{
+			"user_id": userID,
+			"owner":   model.MemberRoleOwner,
+		}).Scan(&workspaces).Error
+	if err != nil {
+		logger.Log.Error("Failed to list workspaces", zap.Error(err), zap.String("userID", userID))
+		return nil, err
+	}
+
+	return workspaces, nil
+}
+
+// RenameWorkspace changes the title of a live workspace
+func (r *WorkspaceRepository) RenameWorkspace(ctx context.Context, workspaceID, title string) error {
+	res := r.db.WithContext(ctx).
+		Model(&model.Workspace{}).
+		Where("id = ? AND deleted_at IS NULL", workspaceID).
+		Updates(map[string]any{
+			"title":      title,
+			"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+		})
+	if res.Error != nil {
+		logger.Log.Error("Failed to rename workspace", zap.Error(res.Error), zap.String("workspaceID", workspaceID))
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrWorkspaceNotFound
+	}
+
+	return nil
+}
+
+// DeleteWorkspace deletes a workspace that has no live projects or collections left, and revokes
+// its pending invitations
+func (r *WorkspaceRepository) DeleteWorkspace(ctx context.Context, workspaceID string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var live int64
+		err := tx.Raw(`
+			SELECT (SELECT COUNT(*) FROM projects WHERE workspace_id = @id AND deleted_at IS NULL)
+				+ (SELECT COUNT(*) FROM collections WHERE workspace_id = @id AND deleted_at IS NULL)`,
+			map[string]any{"id": workspaceID}).Scan(&live).Error
+		if err != nil {
+			return err
+		}
+		if live > 0 {
+			return ErrWorkspaceNotEmpty
+		}
+
+		res := tx.Model(&model.Workspace{}).
+			Where("id = ? AND deleted_at IS NULL", workspaceID).
+			Updates(map[string]any{
+				"deleted_at": gorm.Expr("CURRENT_TIMESTAMP"),
+				"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrWorkspaceNotFound
+		}
+
+		return tx.Model(&model.Invitation{}).
+			Where("workspace_id = ? AND "+pendingInvitationSQL, workspaceID).
+			Updates(map[string]any{
+				"revoked_at": gorm.Expr("CURRENT_TIMESTAMP"),
+				"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+			}).Error
+	})
+	if err != nil {
+		if !errors.Is(err, ErrWorkspaceNotEmpty) && !errors.Is(err, ErrWorkspaceNotFound) {
+			logger.Log.Error("Failed to delete workspace", zap.Error(err), zap.String("workspaceID", workspaceID))
+		}
+		return err
+	}
+
+	return nil
+}
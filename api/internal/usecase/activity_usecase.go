@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"app/internal/contract"
+	"app/internal/model"
+	"app/internal/repository"
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ActivityUsecase struct {
+	activityRepo   *repository.ActivityRepository
+	membershipRepo *repository.MembershipRepository
+}
+
+func NewActivityUsecase(activityRepo *repository.ActivityRepository, membershipRepo *repository.MembershipRepository) *ActivityUsecase {
+	return &ActivityUsecase{
+		activityRepo:   activityRepo,
+		membershipRepo: membershipRepo,
+	}
+}
+
+// ListUserActivity returns the changes the user made and the changes others made to the user's items
+func (u *ActivityUsecase) ListUserActivity(ctx context.Context, userID string, req *contract.ActivityReq) ([]contract.ActivityRes, int64, error) {
+	return u.list(ctx, repository.ActivityFilters{UserID: &userID}, req)
+}
+
+// ListProjectActivity returns the changes to a project, its statuses and tasks and their comments.
+// Any member of the project may read it.
+func (u *ActivityUsecase) ListProjectActivity(ctx context.Context, userID, projectID string, req *contract.ActivityReq) ([]contract.ActivityRes, int64, error) {
+	access, err := u.membershipRepo.Access(ctx, userID, repository.MemberTarget{ProjectID: &projectID})
+	if err != nil {
+		return nil, 0, err
+	}
+	if access == nil {
+		return nil, 0, fiber.NewError(fiber.StatusNotFound, repository.ErrProjectNotFound.Error())
+	}
+
+	return u.list(ctx, repository.ActivityFilters{ProjectID: &projectID}, req)
+}
+
+// ListNoteActivity returns the changes to a note and its comments. The note's owner and the members
+// of its collection may read it, also once the note is deleted.
+func (u *ActivityUsecase) ListNoteActivity(ctx context.Context, userID, noteID string, req *contract.ActivityReq) ([]contract.ActivityRes, int64, error) {
+	ok, err := u.activityRepo.NoteAccess(ctx, userID, noteID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !ok {
+		return nil, 0, fiber.NewError(fiber.StatusNotFound, "Note not found")
+	}
+
+	return u.list(ctx, repository.ActivityFilters{NoteID: &noteID}, req)
+}
+
+func (u *ActivityUsecase) list(ctx context.Context, filters repository.ActivityFilters, req *contract.ActivityReq) ([]contract.ActivityRes, int64, error) {
+	filters.Limit = req.Limit
+	filters.Offset = (req.Page - 1) * req.Limit
+	activities, total, err := u.activityRepo.ListActivities(ctx, filters)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	items := make([]contract.ActivityRes, 0, len(activities))
+	for i := range activities {
+		items = append(items, toActivityRes(&activities[i]))
+	}
+	return items, total, nil
+}
+
+func toActivityRes(activity *model.Activity) contract.ActivityRes {
+	changes := map[string]contract.ActivityChangeRes{}
+	for column, change := range activity.Changes.Data() {
+		changes[column] = contract.ActivityChangeRes{From: change.From, To: change.To}
+	}
+
+	res := contract.ActivityRes{
+		ID:           activity.ID,
+		EntityType:   activity.EntityType,
+		EntityID:     activity.EntityID,
+		Action:       activity.Action,
+		Source:       activity.Source,
+		DeviceID:     activity.DeviceID,
+		ActorID:      activity.ActorID,
+		OwnerID:      activity.OwnerID,
+		Changes:      changes,
+		ProjectID:    activity.ProjectID,
+		CollectionID: activity.CollectionID,
+		TaskID:       activity.TaskID,
+		NoteID:       activity.NoteID,
+		CreatedAt:    activity.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if activity.Actor != nil {
+		res.ActorName = activity.Actor.Name
+	}
+	return res
+}
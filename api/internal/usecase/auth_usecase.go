@@ -45,7 +45,7 @@ func (u *AuthUsecase) LoginGoogleUser(c *fiber.Ctx, req *contract.GoogleLoginReq
 			return nil, err
 		}
 
-		tokens, err := u.tokenUsecase.GenerateTokenPair(user.ID)
+		tokens, err := u.tokenUsecase.GenerateTokenPair(user.ID, "")
 		if err != nil {
 			logger.Log.Error("Failed to generate token pair", zap.Error(err), zap.String("userID", user.ID))
 			return nil, err
@@ -68,7 +68,7 @@ func (u *AuthUsecase) LoginGoogleUser(c *fiber.Ctx, req *contract.GoogleLoginReq
 		return nil, err
 	}
 
-	tokens, err := u.tokenUsecase.GenerateTokenPair(userFromDB.ID)
+	tokens, err := u.tokenUsecase.GenerateTokenPair(userFromDB.ID, "")
 	if err != nil {
 		logger.Log.Error("Failed to generate token pair", zap.Error(err), zap.String("userID", userFromDB.ID))
 		return nil, err
@@ -103,7 +103,9 @@ func (u *AuthUsecase) RefreshToken(c *fiber.Ctx, req *contract.RefreshTokenReq)
 		return fiber.NewError(fiber.StatusNotFound, "User not found")
 	}
 
-	tokens, err := u.tokenUsecase.GenerateTokenPair(user.ID)
+	// The workspace stays active across refreshes. Membership is checked wherever it is used, so a
+	// user removed from it gets 403 there and switches back.
+	tokens, err := u.tokenUsecase.GenerateTokenPair(user.ID, claims.WorkspaceID)
 	if err != nil {
 		logger.Log.Error("Failed to generate token pair", zap.Error(err), zap.String("userID", user.ID))
 		return fiber.NewError(fiber.StatusInternalServerError)
@@ -126,6 +128,28 @@ func (u *AuthUsecase) GetUserByID(id string) (*contract.UserRes, error) {
 	return u.buildUserRes(user), nil
 }
 
+// UpdateUser changes the current user's settings
+func (u *AuthUsecase) UpdateUser(id string, req *contract.UpdateUserReq) (*contract.UserRes, error) {
+	user, err := u.userRepo.GetUserByID(id)
+	if err != nil {
+		logger.Log.Error("Failed to get user by ID", zap.Error(err), zap.String("userID", id))
+		return nil, fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+	if user == nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, "User not found")
+	}
+
+	if req.Timezone != nil {
+		user.Timezone = *req.Timezone
+	}
+	if err := u.userRepo.UpdateUser(user); err != nil {
+		logger.Log.Error("Failed to update user", zap.Error(err), zap.String("userID", id))
+		return nil, err
+	}
+
+	return u.buildUserRes(user), nil
+}
+
 func (u *AuthUsecase) buildUserRes(user *model.User) *contract.UserRes {
 	if user == nil {
 		return nil
@@ -140,6 +164,7 @@ func (u *AuthUsecase) buildUserRes(user *model.User) *contract.UserRes {
 		Name:        user.Name,
 		GoogleImage: googleImage,
 		IsVerified:  user.IsVerified,
+		Timezone:    user.Timezone,
 		CreatedAt:   user.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:   user.UpdatedAt.Format(time.RFC3339),
 	}
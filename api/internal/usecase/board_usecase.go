@@ -0,0 +1,160 @@
+package usecase
+
+import (
+	"app/internal/contract"
+	"app/internal/model"
+	"app/internal/repository"
+	"app/pkg/util"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type BoardUsecase struct {
+	taskRepo       *repository.TaskRepository
+	syncRepo       *repository.SyncRepository
+	membershipRepo *repository.MembershipRepository
+}
+
+func NewBoardUsecase(taskRepo *repository.TaskRepository, syncRepo *repository.SyncRepository, membershipRepo *repository.MembershipRepository) *BoardUsecase {
+	return &BoardUsecase{
+		taskRepo:       taskRepo,
+		syncRepo:       syncRepo,
+		membershipRepo: membershipRepo,
+	}
+}
+
+// GetBoard returns a page of each column of a project's board, or of the one column asked for.
+// Members of a shared project see its owner's board.
+func (u *BoardUsecase) GetBoard(ctx context.Context, userID, projectID string, req *contract.BoardReq) (*contract.BoardRes, error) {
+	access, err := u.boardAccess(ctx, userID, projectID, model.MemberRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	columns, err := u.taskRepo.ListBoardColumns(ctx, access.OwnerID, projectID)
+	if errors.Is(err, repository.ErrProjectNotFound) {
+		return nil, fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res := &contract.BoardRes{ProjectID: projectID, Columns: []contract.BoardColumnRes{}}
+	for _, column := range columns {
+		if !boardColumnMatches(column, req.StatusID, req.Category) {
+			continue
+		}
+
+		tasks, total, err := u.taskRepo.ListBoardTasks(ctx, access.OwnerID, projectID, column, req.Limit, (req.Page-1)*req.Limit)
+		if err != nil {
+			return nil, err
+		}
+		items := make([]contract.BoardTaskRes, 0, len(tasks))
+		for i := range tasks {
+			items = append(items, toBoardTaskRes(userID, &tasks[i]))
+		}
+
+		res.Columns = append(res.Columns, contract.BoardColumnRes{
+			StatusID: column.StatusID,
+			Category: column.Category,
+			Name:     column.Name,
+			Color:    column.Color,
+			Tasks:    util.ToPaginatedData(items, req.Page, req.Limit, total),
+		})
+	}
+
+	if len(res.Columns) == 0 && (req.StatusID != nil || req.Category != nil) {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Column not found")
+	}
+
+	return res, nil
+}
+
+// MoveBoardTask moves a task to a column and a position on its project's board. Members of a
+// shared project need the editor role.
+func (u *BoardUsecase) MoveBoardTask(ctx context.Context, userID, projectID string, req *contract.MoveBoardTaskReq) (*contract.BoardTaskRes, error) {
+	access, err := u.boardAccess(ctx, userID, projectID, model.MemberRoleEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	column := repository.BoardColumn{StatusID: req.StatusID}
+	if req.StatusID == nil {
+		column.Category = util.ToValue(req.Category)
+	}
+
+	task, err := u.syncRepo.MoveBoardTask(ctx, userID, access.OwnerID, projectID, req.TaskID, column, req.AfterTaskID, req.BeforeTaskID)
+	switch {
+	case errors.Is(err, repository.ErrTaskNotFound):
+		return nil, fiber.NewError(fiber.StatusNotFound, err.Error())
+	case errors.Is(err, repository.ErrStatusNotFound),
+		errors.Is(err, repository.ErrTaskMoveNotInList),
+		errors.Is(err, repository.ErrTaskMoveOrder):
+		return nil, fiber.NewError(fiber.StatusBadRequest, err.Error())
+	case err != nil:
+		return nil, err
+	}
+
+	res := toBoardTaskRes(userID, task)
+	return &res, nil
+}
+
+// boardAccess returns the user's access to a project, which must allow at least the minimum role
+func (u *BoardUsecase) boardAccess(ctx context.Context, userID, projectID, minimum string) (*repository.MemberAccess, error) {
+	access, err := u.membershipRepo.Access(ctx, userID, repository.MemberTarget{ProjectID: &projectID})
+	if err != nil {
+		return nil, err
+	}
+	if access == nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, repository.ErrProjectNotFound.Error())
+	}
+	if !model.MemberRoleAllows(access.Role, minimum) {
+		return nil, fiber.NewError(fiber.StatusForbidden, repository.ErrShareForbidden.Error())
+	}
+	return access, nil
+}
+
+// boardColumnMatches reports whether a column is the one picked by a status ID, or by a category alone
+func boardColumnMatches(column repository.BoardColumn, statusID, category *string) bool {
+	if statusID != nil {
+		return util.ToValue(column.StatusID) == *statusID
+	}
+	if category != nil {
+		return column.StatusID == nil && column.Category == *category
+	}
+	return true
+}
+
+// toBoardTaskRes leaves out the tags of another user's task, since tags are personal
+func toBoardTaskRes(userID string, task *model.Task) contract.BoardTaskRes {
+	blockedBy := make([]string, 0, len(task.BlockedBy))
+	for _, dependency := range task.BlockedBy {
+		blockedBy = append(blockedBy, dependency.BlockedByTaskID)
+	}
+	tagIDs := make([]string, 0, len(task.Tags))
+	for _, tag := range task.Tags {
+		if task.UserID == userID {
+			tagIDs = append(tagIDs, tag.ID)
+		}
+	}
+
+	return contract.BoardTaskRes{
+		ID:               task.ID,
+		Title:            task.Title,
+		Description:      task.Description,
+		Status:           task.Status,
+		StatusID:         task.StatusID,
+		SortOrder:        task.SortOrder,
+		DueDate:          util.TimePtrToStringPtr(task.DueDate, time.RFC3339),
+		StartDate:        util.TimePtrToStringPtr(task.StartDate, time.RFC3339),
+		Priority:         task.Priority,
+		EstimateMinutes:  task.EstimateMinutes,
+		Blocked:          task.Blocked,
+		BlockedByTaskIDs: blockedBy,
+		TagIDs:           tagIDs,
+		UpdatedAt:        task.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
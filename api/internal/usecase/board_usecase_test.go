@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"app/internal/model"
+	"app/internal/repository"
+	"app/pkg/util"
+	"testing"
+)
+
+func TestBoardColumnMatches(t *testing.T) {
+	builtIn := repository.BoardColumn{Category: model.StatusCategoryDoing, Name: "In progress"}
+	custom := repository.BoardColumn{StatusID: util.ToPointer("qa"), Category: model.StatusCategoryDoing, Name: "QA"}
+
+	tests := []struct {
+		name     string
+		column   repository.BoardColumn
+		statusID *string
+		category *string
+		want     bool
+	}{
+		{"status ID picks the custom status", custom, util.ToPointer("qa"), nil, true},
+		{"status ID skips another status", custom, util.ToPointer("review"), nil, false},
+		{"status ID skips the built-in status", builtIn, util.ToPointer("qa"), nil, false},
+		{"category picks the built-in status", builtIn, nil, util.ToPointer(model.StatusCategoryDoing), true},
+		{"category skips custom statuses", custom, nil, util.ToPointer(model.StatusCategoryDoing), false},
+		{"category skips other categories", builtIn, nil, util.ToPointer(model.StatusCategoryDone), false},
+		{"nothing picks every column", custom, nil, nil, true},
+	}
+	for _, tt := range tests {
+		if got := boardColumnMatches(tt.column, tt.statusID, tt.category); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,437 @@
+package usecase
+
+import (
+	"app/internal/config"
+	"app/internal/contract"
+	"app/internal/model"
+	"app/internal/repository"
+	"app/pkg/ical"
+	"app/pkg/logger"
+	"app/pkg/recurrence"
+	"app/pkg/util"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+const (
+	calendarDateLayout   = "2006-01-02"
+	calendarMaxDays      = 62
+	calendarOverdueLimit = 200
+
+	// calendarFeedHistory is how far back the feed reaches; everything due later is included
+	calendarFeedHistory  = 90 * 24 * time.Hour
+	calendarFeedLimit    = 5000
+	calendarFeedEventLen = 30 * time.Minute
+)
+
+// icalTodoStatuses maps Task.Status codes to VTODO statuses
+var icalTodoStatuses = map[int]string{
+	model.TaskStatusPending:    "NEEDS-ACTION",
+	model.TaskStatusInProgress: "IN-PROCESS",
+	model.TaskStatusCompleted:  "COMPLETED",
+	model.TaskStatusCancelled:  "CANCELLED",
+}
+
+// icalPriorities maps task priorities to iCalendar ones, where 1 is the highest and 9 the lowest
+var icalPriorities = map[int]int{
+	model.TaskPriorityLow:    9,
+	model.TaskPriorityMedium: 5,
+	model.TaskPriorityHigh:   3,
+	model.TaskPriorityUrgent: 1,
+}
+
+type CalendarUsecase struct {
+	userRepo     *repository.UserRepository
+	calendarRepo *repository.CalendarRepository
+}
+
+func NewCalendarUsecase(userRepo *repository.UserRepository, calendarRepo *repository.CalendarRepository) *CalendarUsecase {
+	return &CalendarUsecase{
+		userRepo:     userRepo,
+		calendarRepo: calendarRepo,
+	}
+}
+
+// calendarItem is a task, or an occurrence of one, placed at a time
+type calendarItem struct {
+	at  time.Time
+	res contract.CalendarTaskRes
+}
+
+// GetCalendar returns the tasks of a space due on each day of a date range in the user's time zone,
+// with later occurrences of recurring tasks and the tasks that are overdue
+func (u *CalendarUsecase) GetCalendar(ctx context.Context, userID, workspaceID string, req *contract.CalendarReq) (*contract.CalendarRes, error) {
+	loc, err := resolveLocation(u.userRepo, userID, req.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	from, end, err := parseDateRange(req.From, req.To, loc, calendarMaxDays)
+	if err != nil {
+		return nil, err
+	}
+
+	filters := repository.CalendarFilters{
+		WorkspaceID:      workspaceID,
+		ProjectID:        req.ProjectID,
+		IncludeCompleted: req.IncludeCompleted,
+	}
+	now := time.Now()
+
+	tasks, err := u.calendarRepo.ListDueTasks(ctx, userID, from, end, filters)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]calendarItem, 0, len(tasks))
+	for i := range tasks {
+		items = append(items, calendarItem{at: *tasks[i].DueDate, res: toCalendarTaskRes(&tasks[i], *tasks[i].DueDate, loc, now)})
+	}
+
+	occurrences, err := u.expandOccurrences(ctx, userID, from, end, filters, loc, now)
+	if err != nil {
+		return nil, err
+	}
+	items = append(items, occurrences...)
+
+	days := []contract.CalendarDayRes{}
+	dayIndex := map[string]int{}
+	for day := from; day.Before(end); day = day.AddDate(0, 0, 1) {
+		date := day.Format(calendarDateLayout)
+		dayIndex[date] = len(days)
+		days = append(days, contract.CalendarDayRes{Date: date, Tasks: []contract.CalendarTaskRes{}})
+	}
+
+	// Within a day: earliest first, then most important
+	sort.SliceStable(items, func(i, j int) bool {
+		if !items[i].at.Equal(items[j].at) {
+			return items[i].at.Before(items[j].at)
+		}
+		return items[i].res.Priority > items[j].res.Priority
+	})
+	for _, item := range items {
+		if i, ok := dayIndex[item.at.In(loc).Format(calendarDateLayout)]; ok {
+			days[i].Tasks = append(days[i].Tasks, item.res)
+		}
+	}
+
+	res := &contract.CalendarRes{
+		From:     req.From,
+		To:       req.To,
+		Timezone: loc.String(),
+		Overdue:  []contract.CalendarTaskRes{},
+		Days:     days,
+	}
+
+	// Tasks overdue within the range already show on their day
+	if end.After(now) {
+		before := from
+		if now.Before(before) {
+			before = now
+		}
+		overdue, total, err := u.calendarRepo.ListOverdueTasks(ctx, userID, before, filters, calendarOverdueLimit)
+		if err != nil {
+			return nil, err
+		}
+		for i := range overdue {
+			res.Overdue = append(res.Overdue, toCalendarTaskRes(&overdue[i], *overdue[i].DueDate, loc, now))
+		}
+		res.OverdueTotal = total
+	}
+
+	return res, nil
+}
+
+// expandOccurrences returns the occurrences of open recurring tasks within [from, end) that come after
+// each task's current due date. Occurrences already past are left out, since completing a task late skips them.
+func (u *CalendarUsecase) expandOccurrences(ctx context.Context, userID string, from, end time.Time, filters repository.CalendarFilters, loc *time.Location, now time.Time) ([]calendarItem, error) {
+	tasks, err := u.calendarRepo.ListRecurringTasks(ctx, userID, end, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	items := []calendarItem{}
+	for i := range tasks {
+		task := &tasks[i]
+		after := now
+		if task.DueDate != nil && task.DueDate.After(after) {
+			after = *task.DueDate
+		}
+		if !after.Before(end) {
+			continue
+		}
+		start := from
+		if after.After(start) {
+			start = after
+		}
+
+		occurrences, err := recurrence.Between(*task.RecurrenceRule, task.RecurrenceAnchor(), start, end)
+		if err != nil {
+			logger.Log.Warn("Failed to expand recurrence rule", zap.Error(err), zap.String("taskID", task.ID))
+			continue
+		}
+		for _, occurrence := range occurrences {
+			if !occurrence.After(after) || !occurrence.Before(end) {
+				continue
+			}
+			res := toCalendarTaskRes(task, occurrence, loc, now)
+			res.Status = model.TaskStatusPending
+			res.StatusName = model.DefaultStatusName(model.StatusCategoryTodo)
+			res.StatusID = nil
+			res.Blocked = false
+			res.Overdue = false
+			res.Occurrence = true
+			if task.StartDate != nil && task.DueDate != nil {
+				startDate := occurrence.Add(task.StartDate.Sub(*task.DueDate)).In(loc)
+				res.StartDate = util.TimePtrToStringPtr(&startDate, time.RFC3339)
+			}
+			items = append(items, calendarItem{at: occurrence, res: res})
+		}
+	}
+
+	return items, nil
+}
+
+// resolveLocation resolves the time zone asked for, or the user's own
+func resolveLocation(userRepo *repository.UserRepository, userID string, timezone *string) (*time.Location, error) {
+	if timezone != nil && *timezone != "" {
+		loc, err := time.LoadLocation(*timezone)
+		if err != nil {
+			return nil, fiber.NewError(fiber.StatusBadRequest, "Unknown time zone")
+		}
+		return loc, nil
+	}
+
+	user, err := userRepo.GetUserByID(userID)
+	if err != nil {
+		logger.Log.Error("Failed to get user", zap.Error(err), zap.String("userID", userID))
+		return nil, err
+	}
+	if user == nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, "User not found")
+	}
+	return user.Location(), nil
+}
+
+// parseDateRange turns an inclusive range of YYYY-MM-DD days into [start, end) in loc.
+// The dates must already be validated.
+func parseDateRange(from, to string, loc *time.Location, maxDays int) (time.Time, time.Time, error) {
+	start, _ := time.ParseInLocation(calendarDateLayout, from, loc)
+	last, _ := time.ParseInLocation(calendarDateLayout, to, loc)
+	if last.Before(start) {
+		return time.Time{}, time.Time{}, fiber.NewError(fiber.StatusBadRequest, "to must not be before from")
+	}
+	// AddDate keeps days whole across DST changes
+	end := last.AddDate(0, 0, 1)
+	if start.AddDate(0, 0, maxDays).Before(end) {
+		return time.Time{}, time.Time{}, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("the range must not be longer than %d days", maxDays))
+	}
+	return start, end, nil
+}
+
+// GetFeed returns the state of the user's calendar feed
+func (u *CalendarUsecase) GetFeed(ctx context.Context, userID string) (*contract.CalendarFeedRes, error) {
+	feed, err := u.calendarRepo.GetFeed(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return toCalendarFeedRes(feed), nil
+}
+
+// RotateFeed turns the user's calendar feed on with a new secret URL. Any previous URL stops working.
+func (u *CalendarUsecase) RotateFeed(ctx context.Context, userID string) (*contract.CalendarFeedRes, error) {
+	token, err := newSecretToken()
+	if err != nil {
+		logger.Log.Error("Failed to generate calendar feed token", zap.Error(err))
+		return nil, err
+	}
+
+	if err := u.calendarRepo.SaveFeedToken(ctx, userID, hashSecretToken(token)); err != nil {
+		return nil, err
+	}
+
+	res, err := u.GetFeed(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	res.URL = util.ToPointer(fmt.Sprintf("%s/v1/calendar/feed/%s.ics", strings.TrimRight(config.Env.App.BaseURL, "/"), token))
+	return res, nil
+}
+
+// DeleteFeed turns the user's calendar feed off
+func (u *CalendarUsecase) DeleteFeed(ctx context.Context, userID string) error {
+	return u.calendarRepo.DeleteFeed(ctx, userID)
+}
+
+// RenderFeed returns the iCalendar document of the feed with the given token
+func (u *CalendarUsecase) RenderFeed(ctx context.Context, token string, query *contract.CalendarFeedQuery) (string, error) {
+	feed, err := u.calendarRepo.AccessFeed(ctx, hashSecretToken(token))
+	if err != nil {
+		return "", err
+	}
+	if feed == nil {
+		return "", fiber.NewError(fiber.StatusNotFound, "Calendar feed not found")
+	}
+
+	tasks, err := u.calendarRepo.ListFeedTasks(ctx, feed.UserID, time.Now().Add(-calendarFeedHistory), query.ProjectID, calendarFeedLimit)
+	if err != nil {
+		return "", err
+	}
+
+	name := "Memr"
+	if query.ProjectID != nil {
+		for _, task := range tasks {
+			if task.Project != nil && task.Project.Title != nil {
+				name += " · " + *task.Project.Title
+				break
+			}
+		}
+	}
+
+	w := ical.NewCalendar("-//Memr//Tasks//EN", name)
+	w.Line("REFRESH-INTERVAL;VALUE=DURATION", "PT15M")
+	w.Line("X-PUBLISHED-TTL", "PT15M")
+	for i := range tasks {
+		if query.Kind == "todo" {
+			writeFeedTodo(w, &tasks[i])
+		} else {
+			writeFeedEvent(w, &tasks[i])
+		}
+	}
+
+	return w.String(), nil
+}
+
+// writeFeedTodo writes a task as a VTODO
+func writeFeedTodo(w *ical.Writer, task *model.Task) {
+	w.Line("BEGIN", "VTODO")
+	writeFeedCommon(w, task)
+	if task.StartDate != nil && task.StartDate.Before(*task.DueDate) {
+		w.Time("DTSTART", *task.StartDate)
+	}
+	w.Time("DUE", *task.DueDate)
+	w.Line("STATUS", icalTodoStatuses[task.Status])
+	if task.CompletedAt != nil {
+		w.Time("COMPLETED", *task.CompletedAt)
+	}
+	w.Line("END", "VTODO")
+}
+
+// writeFeedEvent writes a task as a VEVENT that ends when the task is due and lasts its estimate.
+// Events don't block time in the calendar.
+func writeFeedEvent(w *ical.Writer, task *model.Task) {
+	length := calendarFeedEventLen
+	if task.EstimateMinutes != nil {
+		length = time.Duration(*task.EstimateMinutes) * time.Minute
+	}
+
+	w.Line("BEGIN", "VEVENT")
+	writeFeedCommon(w, task)
+	w.Time("DTSTART", task.DueDate.Add(-length))
+	w.Time("DTEND", *task.DueDate)
+	if task.Status == model.TaskStatusCancelled {
+		w.Line("STATUS", "CANCELLED")
+	} else {
+		w.Line("STATUS", "CONFIRMED")
+	}
+	w.Line("TRANSP", "TRANSPARENT")
+	w.Line("END", "VEVENT")
+}
+
+// writeFeedCommon writes the properties VTODO and VEVENT share
+func writeFeedCommon(w *ical.Writer, task *model.Task) {
+	title := "Untitled task"
+	if task.Title != nil && *task.Title != "" {
+		title = *task.Title
+	}
+	if task.Status == model.TaskStatusCompleted {
+		title = "✓ " + title
+	}
+
+	// Calendar apps show the description, so the project and status go there too
+	description := "Status: " + task.StatusName()
+	if task.Project != nil && task.Project.Title != nil {
+		description = "Project: " + *task.Project.Title + "\n" + description
+		w.Text("CATEGORIES", *task.Project.Title)
+	}
+	if task.Description != nil && *task.Description != "" {
+		description += "\n\n" + *task.Description
+	}
+
+	w.Line("UID", task.ID+"@memr")
+	w.Time("DTSTAMP", task.UpdatedAt)
+	w.Time("CREATED", task.CreatedAt)
+	w.Time("LAST-MODIFIED", task.UpdatedAt)
+	w.Text("SUMMARY", title)
+	w.Text("DESCRIPTION", description)
+	if priority, ok := icalPriorities[task.Priority]; ok {
+		w.Line("PRIORITY", fmt.Sprint(priority))
+	}
+}
+
+// newSecretToken returns a random URL-safe token for a secret link
+func newSecretToken() (string, error) {
+	tokenBytes := make([]byte, 32)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(tokenBytes), nil
+}
+
+// hashSecretToken returns the hex SHA-256 of a secret link token, which is what gets stored
+func hashSecretToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func toCalendarFeedRes(feed *model.CalendarFeed) *contract.CalendarFeedRes {
+	if feed == nil {
+		return &contract.CalendarFeedRes{Enabled: false}
+	}
+	return &contract.CalendarFeedRes{
+		Enabled:        true,
+		CreatedAt:      util.ToPointer(feed.CreatedAt.UTC().Format(time.RFC3339)),
+		RotatedAt:      util.ToPointer(feed.UpdatedAt.UTC().Format(time.RFC3339)),
+		LastAccessedAt: util.TimePtrToStringPtr(feed.LastAccessedAt, time.RFC3339),
+	}
+}
+
+func toCalendarTaskRes(task *model.Task, dueDate time.Time, loc *time.Location, now time.Time) contract.CalendarTaskRes {
+	tagIDs := make([]string, 0, len(task.Tags))
+	for _, tag := range task.Tags {
+		tagIDs = append(tagIDs, tag.ID)
+	}
+
+	var startDate *time.Time
+	if task.StartDate != nil {
+		startDate = util.ToPointer(task.StartDate.In(loc))
+	}
+	open := task.Status == model.TaskStatusPending || task.Status == model.TaskStatusInProgress
+
+	return contract.CalendarTaskRes{
+		ID:              task.ID,
+		Title:           task.Title,
+		ProjectID:       task.ProjectID,
+		ParentTaskID:    task.ParentTaskID,
+		Status:          task.Status,
+		StatusID:        task.StatusID,
+		StatusName:      task.StatusName(),
+		DueDate:         dueDate.In(loc).Format(time.RFC3339),
+		StartDate:       util.TimePtrToStringPtr(startDate, time.RFC3339),
+		Priority:        task.Priority,
+		EstimateMinutes: task.EstimateMinutes,
+		Blocked:         task.Blocked,
+		TagIDs:          tagIDs,
+		Recurring:       task.RecurrenceRule != nil,
+		Overdue:         open && dueDate.Before(now),
+	}
+}
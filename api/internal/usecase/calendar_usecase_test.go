@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"app/internal/model"
+	"app/pkg/ical"
+	"app/pkg/util"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseDateRange(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("failed to load time zone: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		from    string
+		to      string
+		wantLen time.Duration
+		wantErr bool
+	}{
+		{name: "one day", from: "2026-06-01", to: "2026-06-01", wantLen: 24 * time.Hour},
+		{name: "day clocks go forward", from: "2026-03-08", to: "2026-03-08", wantLen: 23 * time.Hour},
+		{name: "longest range", from: "2026-01-01", to: "2026-03-03", wantLen: 62 * 24 * time.Hour},
+		{name: "too long", from: "2026-01-01", to: "2026-03-04", wantErr: true},
+		{name: "backwards", from: "2026-06-02", to: "2026-06-01", wantErr: true},
+	}
+	for _, tt := range tests {
+		start, end, err := parseDateRange(tt.from, tt.to, newYork, calendarMaxDays)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: got no error, want one", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: got error %v", tt.name, err)
+			continue
+		}
+		if start.Format(calendarDateLayout) != tt.from || start.Hour() != 0 {
+			t.Errorf("%s: start = %v, want midnight on %s", tt.name, start, tt.from)
+		}
+		if got := end.Sub(start); got != tt.wantLen {
+			t.Errorf("%s: range is %v long, want %v", tt.name, got, tt.wantLen)
+		}
+	}
+}
+
+func TestToCalendarTaskRes(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Fatalf("failed to load time zone: %v", err)
+	}
+	now := time.Date(2026, 6, 10, 12, 0, 0, 0, time.UTC)
+	dueDate := time.Date(2026, 6, 9, 20, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		status      int
+		dueDate     time.Time
+		wantOverdue bool
+	}{
+		{"open and past due", model.TaskStatusPending, dueDate, true},
+		{"in progress and past due", model.TaskStatusInProgress, dueDate, true},
+		{"completed and past due", model.TaskStatusCompleted, dueDate, false},
+		{"open and due later", model.TaskStatusPending, now.Add(time.Hour), false},
+	}
+	for _, tt := range tests {
+		task := &model.Task{ID: "task", Status: tt.status, DueDate: &tt.dueDate}
+		res := toCalendarTaskRes(task, tt.dueDate, jakarta, now)
+		if res.Overdue != tt.wantOverdue {
+			t.Errorf("%s: overdue = %v, want %v", tt.name, res.Overdue, tt.wantOverdue)
+		}
+	}
+
+	res := toCalendarTaskRes(&model.Task{Status: model.TaskStatusPending}, dueDate, jakarta, now)
+	if res.DueDate != "2026-06-10T03:00:00+07:00" {
+		t.Errorf("due date = %s, want it in the user's time zone", res.DueDate)
+	}
+	if res.StatusName != "To do" {
+		t.Errorf("status name = %q, want the built-in name", res.StatusName)
+	}
+}
+
+func TestWriteFeedTask(t *testing.T) {
+	dueDate := time.Date(2026, 6, 10, 9, 0, 0, 0, time.UTC)
+	task := &model.Task{
+		ID:              "task-1",
+		Title:           util.ToPointer("Pay rent, bills"),
+		Status:          model.TaskStatusCompleted,
+		Priority:        model.TaskPriorityUrgent,
+		DueDate:         &dueDate,
+		EstimateMinutes: util.ToPointer(45),
+		Project:         &model.Project{Title: util.ToPointer("Home")},
+	}
+
+	tests := []struct {
+		name  string
+		write func(w *ical.Writer, task *model.Task)
+		want  []string
+	}{
+		{
+			name:  "todo",
+			write: writeFeedTodo,
+			want:  []string{"BEGIN:VTODO", "DUE:20260610T090000Z", "STATUS:COMPLETED", "PRIORITY:1"},
+		},
+		{
+			name:  "event",
+			write: writeFeedEvent,
+			want:  []string{"BEGIN:VEVENT", "DTSTART:20260610T081500Z", "DTEND:20260610T090000Z", "TRANSP:TRANSPARENT"},
+		},
+	}
+	for _, tt := range tests {
+		w := ical.NewCalendar("-//Test//EN", "")
+		tt.write(w, task)
+		doc := w.String()
+
+		want := append(tt.want, `SUMMARY:✓ Pay rent\, bills`, "CATEGORIES:Home", "UID:task-1@memr")
+		for _, line := range want {
+			if !strings.Contains(doc, line+"\r\n") {
+				t.Errorf("%s: document lacks %q:\n%s", tt.name, line, doc)
+			}
+		}
+	}
+}
+
+func TestSecretToken(t *testing.T) {
+	first, err := newSecretToken()
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+	second, err := newSecretToken()
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+	if first == second || len(first) != 43 {
+		t.Errorf("tokens %q and %q, want distinct 32-byte tokens", first, second)
+	}
+	if strings.ContainsAny(first, "+/=") {
+		t.Errorf("token %q isn't URL-safe", first)
+	}
+	if hashSecretToken(first) != hashSecretToken(first) || hashSecretToken(first) == hashSecretToken(second) {
+		t.Error("hashes aren't stable per token")
+	}
+}
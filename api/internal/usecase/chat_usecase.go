@@ -33,8 +33,9 @@ func NewChatUsecase(userRepo *repository.UserRepository, chatRepo *repository.Ch
 	}
 }
 
-func (u *ChatUsecase) StartChat(ctx context.Context, userID string) (*contract.ChatStartRes, error) {
-	chat, err := u.chatRepo.CreateChat(ctx, userID)
+// StartChat starts a chat in the active workspace, which the agent then answers from
+func (u *ChatUsecase) StartChat(ctx context.Context, userID, workspaceID string) (*contract.ChatStartRes, error) {
+	chat, err := u.chatRepo.CreateChat(ctx, userID, workspaceID)
 	if err != nil {
 		logger.Log.Error("Failed to create chat", zap.Error(err), zap.String("userID", userID))
 		return nil, err
@@ -45,7 +46,7 @@ func (u *ChatUsecase) StartChat(ctx context.Context, userID string) (*contract.C
 	}, nil
 }
 
-func (u *ChatUsecase) SendMessage(ctx context.Context, chatID, userID, message string) (*contract.ChatSendRes, error) {
+func (u *ChatUsecase) SendMessage(ctx context.Context, chatID, userID, workspaceID, message string) (*contract.ChatSendRes, error) {
 	// Verify chat belongs to user
 	user, err := u.userRepo.GetUserByID(userID)
 	if err != nil {
@@ -56,7 +57,7 @@ func (u *ChatUsecase) SendMessage(ctx context.Context, chatID, userID, message s
 		return nil, fiber.NewError(fiber.StatusNotFound, "User not found")
 	}
 
-	chat, err := u.chatRepo.GetChatByID(ctx, chatID, userID)
+	chat, err := u.chatRepo.GetChatByID(ctx, chatID, userID, workspaceID)
 	if err != nil {
 		logger.Log.Error("Failed to get chat", zap.Error(err), zap.String("chatID", chatID))
 		return nil, err
@@ -152,7 +153,7 @@ func (u *ChatUsecase) SendMessage(ctx context.Context, chatID, userID, message s
 	}
 
 	// Process message with agent
-	assistantResponse, err := u.agent.ProcessMessage(ctx, userID, systemPrompt, openaiMessages)
+	assistantResponse, err := u.agent.ProcessMessage(ctx, userID, workspaceID, systemPrompt, openaiMessages)
 	if err != nil {
 		logger.Log.Error("Failed to process message", zap.Error(err))
 		return nil, err
@@ -179,7 +180,7 @@ type StreamWriter interface {
 }
 
 // SendMessageStream sends a message and streams the response
-func (u *ChatUsecase) SendMessageStream(ctx context.Context, chatID, userID, message string, writer StreamWriter) error {
+func (u *ChatUsecase) SendMessageStream(ctx context.Context, chatID, userID, workspaceID, message string, writer StreamWriter) error {
 	// Verify chat belongs to user
 	user, err := u.userRepo.GetUserByID(userID)
 	if err != nil {
@@ -190,7 +191,7 @@ func (u *ChatUsecase) SendMessageStream(ctx context.Context, chatID, userID, mes
 		return fiber.NewError(fiber.StatusNotFound, "User not found")
 	}
 
-	chat, err := u.chatRepo.GetChatByID(ctx, chatID, userID)
+	chat, err := u.chatRepo.GetChatByID(ctx, chatID, userID, workspaceID)
 	if err != nil {
 		logger.Log.Error("Failed to get chat", zap.Error(err), zap.String("chatID", chatID))
 		return err
@@ -293,7 +294,7 @@ func (u *ChatUsecase) SendMessageStream(ctx context.Context, chatID, userID, mes
 
 	// Process message with agent (streaming)
 	var finalContent string
-	assistantResponse, err := u.agent.ProcessMessageStream(ctx, userID, systemPrompt, openaiMessages, func(deltaContent string) error {
+	assistantResponse, err := u.agent.ProcessMessageStream(ctx, userID, workspaceID, systemPrompt, openaiMessages, func(deltaContent string) error {
 		// Write delta chunk
 		chunk := contract.ChatStreamChunk{
 			Content: deltaContent,
@@ -343,9 +344,9 @@ func (u *ChatUsecase) SendMessageStream(ctx context.Context, chatID, userID, mes
 }
 
 // GetChatHistory retrieves the chat history
-func (u *ChatUsecase) GetChatHistory(ctx context.Context, chatID, userID string) (*contract.ChatHistoryRes, error) {
+func (u *ChatUsecase) GetChatHistory(ctx context.Context, chatID, userID, workspaceID string) (*contract.ChatHistoryRes, error) {
 	// Verify chat belongs to user
-	chat, err := u.chatRepo.GetChatByID(ctx, chatID, userID)
+	chat, err := u.chatRepo.GetChatByID(ctx, chatID, userID, workspaceID)
 	if err != nil {
 		logger.Log.Error("Failed to get chat", zap.Error(err), zap.String("chatID", chatID))
 		return nil, err
@@ -399,8 +400,8 @@ func (u *ChatUsecase) GetChatHistory(ctx context.Context, chatID, userID string)
 }
 
 // ListChats retrieves chats for a user with pagination
-func (u *ChatUsecase) ListChats(ctx context.Context, userID string, page, limit int) (chats []contract.ChatRes, total int64, err error) {
-	chatsDB, total, err := u.chatRepo.ListChatsByUserID(ctx, userID, page, limit)
+func (u *ChatUsecase) ListChats(ctx context.Context, userID, workspaceID string, page, limit int) (chats []contract.ChatRes, total int64, err error) {
+	chatsDB, total, err := u.chatRepo.ListChatsByUserID(ctx, userID, workspaceID, page, limit)
 	if err != nil {
 		logger.Log.Error("Failed to list chats", zap.Error(err), zap.String("userID", userID))
 		return nil, 0, err
@@ -475,6 +476,7 @@ func (u *ChatUsecase) getSystemPrompt(userName, userEmail string) (string, error
 	prompt := string(content)
 	prompt = strings.ReplaceAll(prompt, "{{user_name}}", userName)
 	prompt = strings.ReplaceAll(prompt, "{{user_email}}", userEmail)
+	prompt = strings.ReplaceAll(prompt, "{{current_time}}", time.Now().UTC().Format(time.RFC3339))
 
 	return prompt, nil
 }
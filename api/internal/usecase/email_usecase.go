@@ -63,3 +63,16 @@ To verify your email, click on this link: %s
 If you did not create an account, then ignore this email.`, verificationEmailURL)
 	return s.SendEmail(to, subject, body)
 }
+
+func (s *EmailUsecase) SendInvitationEmail(to, inviterName, title, role, token string) error {
+	subject := fmt.Sprintf("%s invited you to %s", inviterName, title)
+
+	// TODO: replace this url with the link to the invitation page of your front-end app
+	invitationURL := fmt.Sprintf("http://link-to-app/invitations?token=%s", token)
+	body := fmt.Sprintf(`Dear user,
+
+%s invited you to join "%s" as %s. To accept, click on this link: %s
+
+If you don't know %s, then ignore this email.`, inviterName, title, role, invitationURL, inviterName)
+	return s.SendEmail(to, subject, body)
+}
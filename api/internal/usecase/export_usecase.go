@@ -0,0 +1,482 @@
+package usecase
+
+import (
+	"app/internal/config"
+	"app/internal/contract"
+	"app/internal/model"
+	"app/internal/repository"
+	"app/pkg/logger"
+	"app/pkg/util"
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	exportListLimit = 10
+	// exportStaleAfter is how long an export may stay running before it is abandoned
+	exportStaleAfter = 30 * time.Minute
+	// exportMaxArchiveBytes stops one account from filling the export directory
+	exportMaxArchiveBytes = 512 << 20
+	exportMaxNameLength   = 100
+)
+
+var errExportTooLarge = errors.New("the export is too large to download as one archive")
+
+type ExportUsecase struct {
+	exportRepo *repository.ExportRepository
+	archiveDir string
+}
+
+func NewExportUsecase(exportRepo *repository.ExportRepository) *ExportUsecase {
+	return &ExportUsecase{exportRepo: exportRepo, archiveDir: config.Env.Export.Directory}
+}
+
+// RequestExport queues an export of the user's account and starts building it in the background.
+// If an export is already queued or running, that one is returned instead.
+func (u *ExportUsecase) RequestExport(ctx context.Context, userID string) (*contract.ExportJobRes, error) {
+	job, err := u.exportRepo.CreateJob(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The cron picks the job up too, should this run not get to it
+	go func() {
+		if _, err := u.ProcessPendingExports(context.Background()); err != nil {
+			logger.Log.Error("Failed to process exports", zap.Error(err))
+		}
+	}()
+
+	return toExportJobRes(job), nil
+}
+
+// GetExport returns one of the user's exports
+func (u *ExportUsecase) GetExport(ctx context.Context, userID, jobID string) (*contract.ExportJobRes, error) {
+	job, err := u.exportRepo.GetJob(ctx, userID, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Export not found")
+	}
+
+	return toExportJobRes(job), nil
+}
+
+// ListExports returns the user's most recent exports, newest first
+func (u *ExportUsecase) ListExports(ctx context.Context, userID string) ([]contract.ExportJobRes, error) {
+	jobs, err := u.exportRepo.ListJobs(ctx, userID, exportListLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]contract.ExportJobRes, 0, len(jobs))
+	for i := range jobs {
+		items = append(items, *toExportJobRes(&jobs[i]))
+	}
+	return items, nil
+}
+
+// DownloadExport returns the file name of a completed export and the path of its archive
+func (u *ExportUsecase) DownloadExport(ctx context.Context, userID, jobID string) (string, string, error) {
+	job, err := u.exportRepo.GetArchive(ctx, userID, jobID)
+	if err != nil {
+		return "", "", err
+	}
+	if job == nil || job.ArchivePath == nil {
+		return "", "", fiber.NewError(fiber.StatusNotFound, "Export not found, not finished yet or expired")
+	}
+
+	return fmt.Sprintf("memr-export-%s.zip", job.CreatedAt.UTC().Format("2006-01-02")), *job.ArchivePath, nil
+}
+
+// ProcessPendingExports builds every queued export, one at a time. Returns the number of exports processed.
+func (u *ExportUsecase) ProcessPendingExports(ctx context.Context) (int, error) {
+	if _, err := u.exportRepo.AbandonStaleJobs(ctx, exportStaleAfter); err != nil {
+		return 0, err
+	}
+	expired, err := u.exportRepo.DeleteExpiredJobs(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for _, job := range expired {
+		removeExportArchive(job.ArchivePath)
+	}
+
+	processed := 0
+	for {
+		job, err := u.exportRepo.ClaimJob(ctx)
+		if err != nil || job == nil {
+			return processed, err
+		}
+		u.runExport(ctx, job)
+		processed++
+	}
+}
+
+// runExport builds a claimed export and records the outcome
+func (u *ExportUsecase) runExport(ctx context.Context, job *model.ExportJob) {
+	data, err := u.exportRepo.GetExportData(ctx, job.UserID)
+	if err != nil {
+		_ = u.exportRepo.FailJob(ctx, job.ID, "the account data could not be loaded")
+		return
+	}
+
+	archivePath, size, err := u.writeExportArchive(job.ID, data)
+	if err != nil {
+		logger.Log.Error("Failed to build export archive", zap.Error(err), zap.String("jobID", job.ID))
+		reason := "the archive could not be built"
+		if errors.Is(err, errExportTooLarge) {
+			reason = err.Error()
+		}
+		_ = u.exportRepo.FailJob(ctx, job.ID, reason)
+		return
+	}
+
+	if err := u.exportRepo.CompleteJob(ctx, job.ID, archivePath, size); err != nil {
+		removeExportArchive(&archivePath)
+		_ = u.exportRepo.FailJob(ctx, job.ID, "the archive could not be saved")
+		return
+	}
+	logger.Log.Info("Exported account", zap.String("jobID", job.ID), zap.String("userID", job.UserID), zap.Int64("bytes", size))
+}
+
+// writeExportArchive builds the archive into a file of the export directory and returns its path and size.
+// The file only appears under its final name once it is complete.
+func (u *ExportUsecase) writeExportArchive(jobID string, data *repository.ExportData) (string, int64, error) {
+	if err := os.MkdirAll(u.archiveDir, 0o700); err != nil {
+		return "", 0, err
+	}
+	file, err := os.CreateTemp(u.archiveDir, jobID+"-*.zip.tmp")
+	if err != nil {
+		return "", 0, err
+	}
+	defer os.Remove(file.Name())
+
+	size, err := buildExportArchive(file, data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", 0, err
+	}
+
+	archivePath := filepath.Join(u.archiveDir, jobID+".zip")
+	if err := os.Rename(file.Name(), archivePath); err != nil {
+		return "", 0, err
+	}
+	return archivePath, size, nil
+}
+
+// removeExportArchive deletes an archive file, if the export has one
+func removeExportArchive(archivePath *string) {
+	if archivePath == nil {
+		return
+	}
+	if err := os.Remove(*archivePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		logger.Log.Warn("Failed to remove export archive", zap.Error(err), zap.String("path", *archivePath))
+	}
+}
+
+// noteFrontMatter is the YAML front-matter of an exported note, readable by the Markdown import
+type noteFrontMatter struct {
+	ID      string   `yaml:"id"`
+	Title   string   `yaml:"title,omitempty"`
+	Tags    []string `yaml:"tags,omitempty"`
+	Created string   `yaml:"created"`
+	Updated string   `yaml:"updated"`
+}
+
+// buildExportArchive writes one Markdown file per note under notes/, in a folder per collection,
+// and a JSON file per other entity type, to out. Returns the size of the archive.
+func buildExportArchive(out io.Writer, data *repository.ExportData) (int64, error) {
+	counter := &exportSizeWriter{w: out}
+	w := zip.NewWriter(counter)
+	usedPaths := map[string]bool{}
+
+	collections := make([]contract.ExportCollection, 0, len(data.Collections))
+	folders := map[string]string{}
+	for _, collection := range data.Collections {
+		folder := uniqueExportPath(usedPaths, "notes/"+exportFolderName(collection.Title), "")
+		folders[collection.ID] = folder
+		collections = append(collections, contract.ExportCollection{
+			ID:          collection.ID,
+			Title:       collection.Title,
+			Description: collection.Description,
+			Color:       collection.Color,
+			Path:        folder,
+			CreatedAt:   collection.CreatedAt.UTC().Format(time.RFC3339),
+			UpdatedAt:   collection.UpdatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+
+	for _, note := range data.Notes {
+		folder := "notes"
+		if note.CollectionID != nil && folders[*note.CollectionID] != "" {
+			folder = folders[*note.CollectionID]
+		}
+		filePath := uniqueExportPath(usedPaths, folder+"/"+exportFileName(util.ToValue(note.Title)), ".md")
+
+		matter, err := yaml.Marshal(noteFrontMatter{
+			ID:      note.ID,
+			Title:   util.ToValue(note.Title),
+			Tags:    exportTagNames(note.Tags),
+			Created: note.CreatedAt.UTC().Format(time.RFC3339),
+			Updated: note.UpdatedAt.UTC().Format(time.RFC3339),
+		})
+		if err != nil {
+			return 0, err
+		}
+		content := "---\n" + string(matter) + "---\n" + util.ToValue(note.Content)
+		if err := writeExportFile(w, filePath, []byte(content), note.UpdatedAt); err != nil {
+			return 0, err
+		}
+	}
+
+	files := []struct {
+		name  string
+		value any
+	}{
+		{"tasks.json", toExportTasks(data.Tasks)},
+		{"projects.json", toExportProjects(data.Projects)},
+		{"collections.json", collections},
+		{"tags.json", toExportTags(data.Tags)},
+		{"chats.json", toExportChats(data.Chats)},
+	}
+	now := time.Now()
+	for _, file := range files {
+		content, err := json.MarshalIndent(file.value, "", "  ")
+		if err != nil {
+			return 0, err
+		}
+		if err := writeExportFile(w, file.name, content, now); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return 0, err
+	}
+	return counter.written, nil
+}
+
+// exportSizeWriter counts what is written through it and fails once an archive grows past exportMaxArchiveBytes
+type exportSizeWriter struct {
+	w       io.Writer
+	written int64
+}
+
+func (e *exportSizeWriter) Write(p []byte) (int, error) {
+	if e.written+int64(len(p)) > exportMaxArchiveBytes {
+		return 0, errExportTooLarge
+	}
+	n, err := e.w.Write(p)
+	e.written += int64(n)
+	return n, err
+}
+
+func writeExportFile(w *zip.Writer, name string, content []byte, modified time.Time) error {
+	f, err := w.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modified})
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(content)
+	return err
+}
+
+// exportFolderName turns a collection title into a folder path. Slashes nest folders,
+// mirroring how the Markdown import names collections after folders.
+func exportFolderName(title string) string {
+	segments := []string{}
+	for _, segment := range strings.Split(title, "/") {
+		if segment = strings.TrimSpace(segment); segment != "" {
+			segments = append(segments, exportFileName(segment))
+		}
+	}
+	if len(segments) == 0 {
+		return "Untitled"
+	}
+	return strings.Join(segments, "/")
+}
+
+// exportFileName makes a title safe to use as a file name on common file systems
+func exportFileName(title string) string {
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '-'
+		}
+		return r
+	}, title)
+	name = strings.Trim(strings.TrimSpace(name), ".")
+	if runes := []rune(name); len(runes) > exportMaxNameLength {
+		name = strings.TrimSpace(string(runes[:exportMaxNameLength]))
+	}
+	if name == "" {
+		return "Untitled"
+	}
+	return name
+}
+
+// uniqueExportPath numbers a path that is already taken, ignoring case as some file systems do
+func uniqueExportPath(used map[string]bool, base, ext string) string {
+	candidate := base + ext
+	for n := 2; used[strings.ToLower(candidate)]; n++ {
+		candidate = fmt.Sprintf("%s (%d)%s", base, n, ext)
+	}
+	used[strings.ToLower(candidate)] = true
+	return candidate
+}
+
+func exportTagNames(tags []model.Tag) []string {
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	return names
+}
+
+func exportTagIDs(tags []model.Tag) []string {
+	ids := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		ids = append(ids, tag.ID)
+	}
+	return ids
+}
+
+func toExportTasks(tasks []model.Task) []contract.ExportTask {
+	items := make([]contract.ExportTask, 0, len(tasks))
+	for _, task := range tasks {
+		blockedBy := make([]string, 0, len(task.BlockedBy))
+		for _, dependency := range task.BlockedBy {
+			blockedBy = append(blockedBy, dependency.BlockedByTaskID)
+		}
+		var reminderOffsets *[]int
+		if task.ReminderOffsets != nil {
+			reminderOffsets = util.ToPointer([]int(*task.ReminderOffsets))
+		}
+
+		items = append(items, contract.ExportTask{
+			ID:                 task.ID,
+			ProjectID:          task.ProjectID,
+			ParentTaskID:       task.ParentTaskID,
+			Title:              task.Title,
+			Description:        task.Description,
+			Status:             model.StatusCategoryOf(task.Status),
+			StatusID:           task.StatusID,
+			SortOrder:          task.SortOrder,
+			DueDate:            util.TimePtrToStringPtr(task.DueDate, time.RFC3339),
+			StartDate:          util.TimePtrToStringPtr(task.StartDate, time.RFC3339),
+			Priority:           task.Priority,
+			EstimateMinutes:    task.EstimateMinutes,
+			CompletedAt:        util.TimePtrToStringPtr(task.CompletedAt, time.RFC3339),
+			RecurrenceRule:     task.RecurrenceRule,
+			RecurrenceMode:     task.RecurrenceMode,
+			RecurrenceSeriesID: task.RecurrenceSeriesID,
+			ReminderOffsets:    reminderOffsets,
+			TagIDs:             exportTagIDs(task.Tags),
+			BlockedByTaskIDs:   blockedBy,
+			CreatedAt:          task.CreatedAt.UTC().Format(time.RFC3339),
+			UpdatedAt:          task.UpdatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	return items
+}
+
+func toExportProjects(projects []model.Project) []contract.ExportProject {
+	items := make([]contract.ExportProject, 0, len(projects))
+	for _, project := range projects {
+		statuses := make([]contract.ExportProjectStatus, 0, len(project.Statuses))
+		for _, status := range project.Statuses {
+			statuses = append(statuses, contract.ExportProjectStatus{
+				ID:        status.ID,
+				Name:      status.Name,
+				Category:  status.Category,
+				Color:     status.Color,
+				SortOrder: status.SortOrder,
+			})
+		}
+
+		items = append(items, contract.ExportProject{
+			ID:          project.ID,
+			Title:       project.Title,
+			Description: project.Description,
+			Color:       project.Color,
+			Statuses:    statuses,
+			CreatedAt:   project.CreatedAt.UTC().Format(time.RFC3339),
+			UpdatedAt:   project.UpdatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	return items
+}
+
+func toExportTags(tags []model.Tag) []contract.ExportTag {
+	items := make([]contract.ExportTag, 0, len(tags))
+	for _, tag := range tags {
+		items = append(items, contract.ExportTag{ID: tag.ID, Name: tag.Name, Color: tag.Color})
+	}
+	return items
+}
+
+func toExportChats(chats []model.Chat) []contract.ExportChat {
+	items := make([]contract.ExportChat, 0, len(chats))
+	for _, chat := range chats {
+		messages := make([]contract.ExportMessage, 0, len(chat.Messages))
+		for _, message := range chat.Messages {
+			toolCalls := make([]contract.ExportToolCall, 0, len(message.ToolCalls))
+			for _, call := range message.ToolCalls {
+				arguments := json.RawMessage(call.GetArgumentsBytes())
+				if len(arguments) == 0 {
+					arguments = json.RawMessage("null")
+				}
+				toolCalls = append(toolCalls, contract.ExportToolCall{
+					ID:        call.ID,
+					Name:      call.Name,
+					Arguments: arguments,
+					CreatedAt: call.CreatedAt.UTC().Format(time.RFC3339),
+				})
+			}
+			messages = append(messages, contract.ExportMessage{
+				ID:        message.ID,
+				Role:      message.Role,
+				Content:   message.Content,
+				ToolCalls: toolCalls,
+				CreatedAt: message.CreatedAt.UTC().Format(time.RFC3339),
+			})
+		}
+
+		items = append(items, contract.ExportChat{
+			ID:        chat.ID,
+			CreatedAt: chat.CreatedAt.UTC().Format(time.RFC3339),
+			Messages:  messages,
+		})
+	}
+	return items
+}
+
+func toExportJobRes(job *model.ExportJob) *contract.ExportJobRes {
+	res := &contract.ExportJobRes{
+		ID:          job.ID,
+		Status:      job.Status,
+		Error:       job.Error,
+		SizeBytes:   job.SizeBytes,
+		CreatedAt:   job.CreatedAt.UTC().Format(time.RFC3339),
+		CompletedAt: util.TimePtrToStringPtr(job.CompletedAt, time.RFC3339),
+		ExpiresAt:   util.TimePtrToStringPtr(job.ExpiresAt, time.RFC3339),
+	}
+	if job.Status == model.ExportStatusCompleted && job.ExpiresAt != nil && job.ExpiresAt.After(time.Now()) {
+		res.DownloadURL = util.ToPointer(fmt.Sprintf("%s/v1/exports/%s/download", strings.TrimRight(config.Env.App.BaseURL, "/"), job.ID))
+	}
+	return res
+}
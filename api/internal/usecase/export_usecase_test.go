@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"app/internal/model"
+	"app/internal/repository"
+	"app/pkg/util"
+	"archive/zip"
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteExportArchive(t *testing.T) {
+	u := &ExportUsecase{archiveDir: filepath.Join(t.TempDir(), "exports")}
+	data := &repository.ExportData{
+		Notes: []model.Note{{ID: "n1", Title: util.ToPointer("Groceries"), Content: util.ToPointer("milk")}},
+	}
+
+	archivePath, size, err := u.writeExportArchive("job", data)
+	if err != nil {
+		t.Fatalf("writeExportArchive: %v", err)
+	}
+	if archivePath != filepath.Join(u.archiveDir, "job.zip") {
+		t.Errorf("archive path = %q, want job.zip in the export directory", archivePath)
+	}
+
+	info, err := os.Stat(archivePath)
+	if err != nil {
+		t.Fatalf("archive file: %v", err)
+	}
+	if info.Size() != size {
+		t.Errorf("reported size %d, file has %d bytes", size, info.Size())
+	}
+
+	r, err := zip.OpenReader(archivePath)
+	if err != nil {
+		t.Fatalf("archive isn't a readable zip: %v", err)
+	}
+	defer r.Close()
+	names := map[string]bool{}
+	for _, f := range r.File {
+		names[f.Name] = true
+	}
+	for _, name := range []string{"notes/Groceries.md", "tasks.json", "chats.json"} {
+		if !names[name] {
+			t.Errorf("archive is missing %s", name)
+		}
+	}
+
+	// Only the finished archive is left behind
+	entries, err := os.ReadDir(u.archiveDir)
+	if err != nil {
+		t.Fatalf("failed to list the export directory: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("export directory has %d files, want only the archive", len(entries))
+	}
+}
+
+func TestExportSizeWriterStopsAtTheCap(t *testing.T) {
+	var out bytes.Buffer
+	w := &exportSizeWriter{w: &out}
+
+	if _, err := w.Write(make([]byte, exportMaxArchiveBytes)); err != nil {
+		t.Fatalf("writing up to the cap: %v", err)
+	}
+	if _, err := w.Write([]byte{0}); !errors.Is(err, errExportTooLarge) {
+		t.Errorf("writing past the cap: got %v, want errExportTooLarge", err)
+	}
+	if w.written != exportMaxArchiveBytes {
+		t.Errorf("written = %d, want %d", w.written, exportMaxArchiveBytes)
+	}
+}
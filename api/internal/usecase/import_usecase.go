@@ -0,0 +1,771 @@
+package usecase
+
+import (
+	"app/internal/contract"
+	"app/internal/model"
+	"app/internal/repository"
+	"app/pkg/logger"
+	"app/pkg/util"
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+const (
+	importStatusCreated = "created"
+	importStatusSkipped = "skipped"
+	importStatusFailed  = "failed"
+
+	importMaxFiles     = 2000
+	importMaxTasks     = 5000
+	importMaxNoteBytes = 1 << 20
+	// importMaxTotalBytes caps the uncompressed size of an archive, so a zip bomb can't exhaust memory
+	importMaxTotalBytes = 64 << 20
+)
+
+type ImportUsecase struct {
+	syncRepo *repository.SyncRepository
+	userRepo *repository.UserRepository
+}
+
+func NewImportUsecase(syncRepo *repository.SyncRepository, userRepo *repository.UserRepository) *ImportUsecase {
+	return &ImportUsecase{syncRepo: syncRepo, userRepo: userRepo}
+}
+
+// markdownFile is a file of an imported archive, with the wrapping folder of the vault removed from its path
+type markdownFile struct {
+	file *zip.File
+	path string
+}
+
+// ImportMarkdown creates a note for every Markdown file in a zip archive, such as an Obsidian vault.
+// Folders become collections and front-matter titles and tags become the note's title and tags.
+// Embeddings are generated in the background once the notes are in.
+func (u *ImportUsecase) ImportMarkdown(ctx context.Context, userID string, archive *zip.Reader) (*contract.ImportMarkdownRes, error) {
+	files := importableFiles(archive)
+	if len(files) == 0 {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "The archive has no files to import")
+	}
+	if len(files) > importMaxFiles {
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("The archive must not have more than %d files", importMaxFiles))
+	}
+
+	res := &contract.ImportMarkdownRes{Files: make([]contract.ImportFileRes, 0, len(files))}
+	noteIDs := []string{}
+	noteIDsByName := map[string]string{}
+	var totalBytes int64
+	for _, f := range files {
+		result := contract.ImportFileRes{Path: f.path, Status: importStatusFailed}
+
+		if !strings.EqualFold(path.Ext(f.path), ".md") {
+			result.Status = importStatusSkipped
+			result.Message = util.ToPointer("not a Markdown file")
+			res.Files = append(res.Files, result)
+			continue
+		}
+
+		content, err := readImportFile(f.file, importMaxTotalBytes-totalBytes)
+		totalBytes += int64(len(content))
+		if err != nil {
+			result.Message = util.ToPointer(err.Error())
+			res.Files = append(res.Files, result)
+			continue
+		}
+
+		imported, message := parseMarkdownNote(f.path, content)
+		result.Title = &imported.Title
+		result.Tags = imported.TagNames
+		result.Message = message
+		if imported.CollectionTitle != "" {
+			result.CollectionTitle = &imported.CollectionTitle
+		}
+
+		noteID, err := u.syncRepo.ImportNote(ctx, userID, imported)
+		if err != nil {
+			result.Message = util.ToPointer("the note could not be saved")
+			res.Files = append(res.Files, result)
+			continue
+		}
+		result.Status = importStatusCreated
+		result.NoteID = &noteID
+		res.Files = append(res.Files, result)
+
+		// Obsidian links by file name, or by path when names are ambiguous
+		noteIDs = append(noteIDs, noteID)
+		withoutExt := strings.ToLower(strings.TrimSuffix(f.path, path.Ext(f.path)))
+		for _, name := range []string{path.Base(withoutExt), withoutExt} {
+			if _, ok := noteIDsByName[name]; !ok {
+				noteIDsByName[name] = noteID
+			}
+		}
+	}
+
+	for _, result := range res.Files {
+		switch result.Status {
+		case importStatusCreated:
+			res.Created++
+		case importStatusSkipped:
+			res.Skipped++
+		default:
+			res.Failed++
+		}
+	}
+
+	resolved, err := u.syncRepo.ResolveImportedLinks(ctx, userID, noteIDs, noteIDsByName)
+	if err != nil {
+		return nil, err
+	}
+	res.LinksResolved = resolved
+
+	go u.embedImportedNotes(userID, noteIDs)
+
+	return res, nil
+}
+
+// embedImportedNotes generates embeddings for imported notes, outliving the request
+func (u *ImportUsecase) embedImportedNotes(userID string, noteIDs []string) {
+	embedded, err := u.syncRepo.EmbedNotes(context.Background(), noteIDs)
+	if err != nil {
+		logger.Log.Error("Failed to embed imported notes", zap.Error(err), zap.String("userID", userID))
+		return
+	}
+	logger.Log.Info("Embedded imported notes", zap.String("userID", userID), zap.Int("embedded", embedded), zap.Int("notes", len(noteIDs)))
+}
+
+// importableFiles lists the archive's files in path order, leaving out hidden files and folders such as
+// .obsidian and __MACOSX. When everything sits in one folder, as when a vault folder is zipped, that
+// folder is dropped from the paths so it doesn't become a collection.
+func importableFiles(archive *zip.Reader) []markdownFile {
+	files := []markdownFile{}
+	for _, file := range archive.File {
+		name := path.Clean(strings.ReplaceAll(file.Name, "\\", "/"))
+		if file.FileInfo().IsDir() || strings.HasPrefix(name, "../") || path.IsAbs(name) {
+			continue
+		}
+		hidden := false
+		for _, segment := range strings.Split(name, "/") {
+			if strings.HasPrefix(segment, ".") || segment == "__MACOSX" {
+				hidden = true
+				break
+			}
+		}
+		if !hidden {
+			files = append(files, markdownFile{file: file, path: name})
+		}
+	}
+
+	wrapped := len(files) > 0
+	root := ""
+	if wrapped {
+		root, _, _ = strings.Cut(files[0].path, "/")
+	}
+	for _, f := range files {
+		if !strings.HasPrefix(f.path, root+"/") {
+			wrapped = false
+			break
+		}
+	}
+	if wrapped {
+		for i := range files {
+			files[i].path = strings.TrimPrefix(files[i].path, root+"/")
+		}
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].path < files[j].path })
+	return files
+}
+
+// readImportFile reads a note's content, enforcing the per-note and remaining archive size limits
+func readImportFile(file *zip.File, remaining int64) (string, error) {
+	limit := min(int64(importMaxNoteBytes), remaining)
+	reader, err := file.Open()
+	if err != nil {
+		return "", errors.New("the file could not be read")
+	}
+	defer reader.Close()
+
+	data, err := io.ReadAll(io.LimitReader(reader, limit+1))
+	if err != nil {
+		return "", errors.New("the file could not be read")
+	}
+	if int64(len(data)) > limit {
+		if limit < importMaxNoteBytes {
+			return "", fmt.Errorf("the archive is larger than %d MB uncompressed", importMaxTotalBytes>>20)
+		}
+		return "", fmt.Errorf("the file is larger than %d MB", importMaxNoteBytes>>20)
+	}
+	if !utf8.Valid(data) {
+		return "", errors.New("the file is not valid UTF-8")
+	}
+	return string(data), nil
+}
+
+// parseMarkdownNote turns a Markdown file into a note. The title comes from the front-matter or the
+// file name and the collection from the folder. Wikilinks are left in the content as they are.
+// Returns a message when the front-matter couldn't be used.
+func parseMarkdownNote(filePath, content string) (*repository.ImportedNote, *string) {
+	imported := &repository.ImportedNote{
+		Title:   strings.TrimSuffix(path.Base(filePath), path.Ext(filePath)),
+		Content: content,
+	}
+	if dir := path.Dir(filePath); dir != "." {
+		imported.CollectionTitle = dir
+	}
+
+	matter, body, err := util.ParseFrontMatter(content)
+	if err != nil {
+		return imported, util.ToPointer("the front-matter is not valid YAML and was kept in the content")
+	}
+	imported.Content = body
+	if matter.Title != "" {
+		imported.Title = matter.Title
+	}
+	imported.TagNames = matter.Tags
+	return imported, nil
+}
+
+// importTaskFields are the fields of an imported task, as named in JSON files and CSV mappings
+var importTaskFields = []string{"title", "description", "project", "status", "priority", "dueDate", "startDate", "estimateMinutes", "tags"}
+
+// importFieldAliases maps normalized column headers of other tools' exports to task fields
+var importFieldAliases = map[string]string{
+	"name":          "title",
+	"task":          "title",
+	"taskname":      "title",
+	"content":       "title",
+	"summary":       "title",
+	"subject":       "title",
+	"notes":         "description",
+	"note":          "description",
+	"details":       "description",
+	"projectname":   "project",
+	"list":          "project",
+	"listname":      "project",
+	"state":         "status",
+	"column":        "status",
+	"due":           "dueDate",
+	"deadline":      "dueDate",
+	"dueat":         "dueDate",
+	"start":         "startDate",
+	"scheduled":     "startDate",
+	"scheduleddate": "startDate",
+	"estimate":      "estimateMinutes",
+	"duration":      "estimateMinutes",
+	"timeestimate":  "estimateMinutes",
+	"tag":           "tags",
+	"labels":        "tags",
+	"label":         "tags",
+}
+
+// importStatusCategories maps common status names of other tools to status categories
+var importStatusCategories = map[string]string{
+	"todo":         model.StatusCategoryTodo,
+	"to do":        model.StatusCategoryTodo,
+	"open":         model.StatusCategoryTodo,
+	"not started":  model.StatusCategoryTodo,
+	"backlog":      model.StatusCategoryTodo,
+	"pending":      model.StatusCategoryTodo,
+	"new":          model.StatusCategoryTodo,
+	"incomplete":   model.StatusCategoryTodo,
+	"needs action": model.StatusCategoryTodo,
+	"false":        model.StatusCategoryTodo,
+	"no":           model.StatusCategoryTodo,
+	"doing":        model.StatusCategoryDoing,
+	"in progress":  model.StatusCategoryDoing,
+	"inprogress":   model.StatusCategoryDoing,
+	"started":      model.StatusCategoryDoing,
+	"active":       model.StatusCategoryDoing,
+	"ongoing":      model.StatusCategoryDoing,
+	"in review":    model.StatusCategoryDoing,
+	"review":       model.StatusCategoryDoing,
+	"wip":          model.StatusCategoryDoing,
+	"done":         model.StatusCategoryDone,
+	"completed":    model.StatusCategoryDone,
+	"complete":     model.StatusCategoryDone,
+	"closed":       model.StatusCategoryDone,
+	"finished":     model.StatusCategoryDone,
+	"resolved":     model.StatusCategoryDone,
+	"true":         model.StatusCategoryDone,
+	"yes":          model.StatusCategoryDone,
+	"x":            model.StatusCategoryDone,
+	"cancelled":    model.StatusCategoryCancelled,
+	"canceled":     model.StatusCategoryCancelled,
+	"won't do":     model.StatusCategoryCancelled,
+	"wont do":      model.StatusCategoryCancelled,
+	"abandoned":    model.StatusCategoryCancelled,
+	"dropped":      model.StatusCategoryCancelled,
+}
+
+// importPriorities maps priority names to priorities. p1 to p4 follow Todoist, where p4 is no priority.
+var importPriorities = map[string]int{
+	"":            0,
+	"none":        0,
+	"no priority": 0,
+	"p4":          0,
+	"low":         1,
+	"lowest":      1,
+	"medium":      2,
+	"normal":      2,
+	"p3":          2,
+	"high":        3,
+	"p2":          3,
+	"urgent":      4,
+	"highest":     4,
+	"critical":    4,
+	"p1":          4,
+}
+
+// importDateLayouts are the numeric date layouts of each date order, tried after ISO 8601
+var importDateLayouts = map[string][]string{
+	"mdy": {"1/2/2006", "1/2/06", "1-2-2006", "1.2.2006"},
+	"dmy": {"2/1/2006", "2/1/06", "2-1-2006", "2.1.2006"},
+	"ymd": {"2006/1/2", "2006.1.2"},
+}
+
+// importClockLayouts are the times that may follow a date. Dates without one are all-day.
+var importClockLayouts = []string{"", "T15:04:05", "T15:04", " 15:04:05", " 15:04", " 3:04 PM", " 3:04PM"}
+
+// importRow is one task of an import file, with its values keyed by task field
+type importRow struct {
+	line   int
+	values map[string]string
+}
+
+// ImportTasks creates tasks from a CSV or JSON file exported from another task manager. Projects and
+// tags are found or created by name, dates without a UTC offset are read in the user's time zone and
+// status names are matched to the project's custom statuses or to status categories.
+// A dry run reports the same results without saving anything.
+func (u *ImportUsecase) ImportTasks(ctx context.Context, userID string, req *contract.ImportTasksReq, fileName string, data []byte) (*contract.ImportTasksRes, error) {
+	loc, err := resolveLocation(u.userRepo, userID, req.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	format := req.Format
+	if format == "" {
+		switch strings.ToLower(path.Ext(fileName)) {
+		case ".csv", ".tsv", ".txt":
+			format = "csv"
+		case ".json":
+			format = "json"
+		default:
+			return nil, fiber.NewError(fiber.StatusBadRequest, "format is required for files that aren't .csv or .json")
+		}
+	}
+	dateOrder := req.DateOrder
+	if dateOrder == "" {
+		dateOrder = "mdy"
+	}
+
+	var rows []importRow
+	if format == "json" {
+		rows, err = readTaskJSON(data)
+	} else {
+		rows, err = readTaskCSV(data, req.Mapping)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "The file has no tasks to import")
+	}
+	if len(rows) > importMaxTasks {
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("The file must not have more than %d tasks", importMaxTasks))
+	}
+
+	res := &contract.ImportTasksRes{
+		DryRun:          req.DryRun,
+		ProjectsCreated: []string{},
+		Rows:            make([]contract.ImportTaskRowRes, 0, len(rows)),
+	}
+	tasks := []repository.ImportedTask{}
+	// rowOfTask is the index in res.Rows of each task to create
+	rowOfTask := []int{}
+	for _, row := range rows {
+		imported, result := parseImportedTask(row, loc, dateOrder)
+		if imported != nil {
+			tasks = append(tasks, *imported)
+			rowOfTask = append(rowOfTask, len(res.Rows))
+		}
+		res.Rows = append(res.Rows, result)
+	}
+
+	results, err := u.syncRepo.ImportTasks(ctx, userID, tasks, req.DryRun)
+	if err != nil {
+		return nil, err
+	}
+	for i, created := range results {
+		row := &res.Rows[rowOfTask[i]]
+		row.Status = importStatusCreated
+		row.StatusName = util.ToPointer(created.StatusName)
+		if !req.DryRun {
+			row.TaskID = util.ToPointer(created.TaskID)
+		}
+		if !created.StatusMatched {
+			row.Messages = append(row.Messages, fmt.Sprintf("unknown status %q, imported as %s", tasks[i].StatusName, created.StatusName))
+		}
+		if created.ProjectCreated {
+			res.ProjectsCreated = append(res.ProjectsCreated, tasks[i].ProjectTitle)
+		}
+	}
+
+	for _, row := range res.Rows {
+		if row.Status == importStatusCreated {
+			res.Created++
+		} else {
+			res.Failed++
+		}
+	}
+
+	return res, nil
+}
+
+// readTaskCSV reads the rows of a CSV file. The delimiter may be a comma, semicolon or tab, as
+// spreadsheets export with all three. Fields are taken from the columns named in the mapping,
+// then from columns named like a field. Blank rows are skipped.
+func readTaskCSV(data []byte, mapping string) ([]importRow, error) {
+	text := strings.TrimPrefix(string(data), "\ufeff")
+	if !utf8.ValidString(text) {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "The file is not valid UTF-8")
+	}
+
+	reader := csv.NewReader(strings.NewReader(text))
+	reader.Comma = csvDelimiter(text)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "The file has no tasks to import")
+	}
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "The file is not valid CSV: "+err.Error())
+	}
+	columns, err := importColumns(header, mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []importRow{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fiber.NewError(fiber.StatusBadRequest, "The file is not valid CSV: "+err.Error())
+		}
+
+		line, _ := reader.FieldPos(0)
+		row := importRow{line: line, values: map[string]string{}}
+		for field, index := range columns {
+			if index < len(record) && strings.TrimSpace(record[index]) != "" {
+				row.values[field] = strings.TrimSpace(record[index])
+			}
+		}
+		if len(row.values) > 0 {
+			rows = append(rows, row)
+		}
+	}
+
+	return rows, nil
+}
+
+// csvDelimiter picks the comma, semicolon or tab, whichever the header line has most of
+func csvDelimiter(text string) rune {
+	header, _, _ := strings.Cut(text, "\n")
+	delimiter := ','
+	for _, candidate := range []rune{';', '\t'} {
+		if strings.Count(header, string(candidate)) > strings.Count(header, string(delimiter)) {
+			delimiter = candidate
+		}
+	}
+	return delimiter
+}
+
+// importColumns returns the column index of each task field. The mapping names the column of a
+// field by its header, or "" to leave the field out, and overrides the columns found by name.
+func importColumns(header []string, mapping string) (map[string]int, error) {
+	columns := matchImportFields(header)
+
+	if mapping != "" {
+		var columnOf map[string]string
+		if err := json.Unmarshal([]byte(mapping), &columnOf); err != nil {
+			return nil, fiber.NewError(fiber.StatusBadRequest, "mapping must be a JSON object of task fields to column headers")
+		}
+		for field, column := range columnOf {
+			if !slices.Contains(importTaskFields, field) {
+				return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("mapping has an unknown field %q", field))
+			}
+			if column == "" {
+				delete(columns, field)
+				continue
+			}
+			index := slices.IndexFunc(header, func(h string) bool {
+				return strings.EqualFold(strings.TrimSpace(h), strings.TrimSpace(column))
+			})
+			if index < 0 {
+				return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("The file has no %q column", column))
+			}
+			columns[field] = index
+		}
+	}
+
+	if _, ok := columns["title"]; !ok {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "The file has no title column, map one in mapping")
+	}
+	return columns, nil
+}
+
+// matchImportFields returns the index of the key that holds each task field. Keys are compared
+// ignoring case, spaces, underscores and dashes. A key named like the field wins over an alias.
+func matchImportFields(keys []string) map[string]int {
+	normalize := func(key string) string {
+		return strings.ToLower(strings.NewReplacer(" ", "", "_", "", "-", "").Replace(key))
+	}
+
+	fields := map[string]int{}
+	for i, key := range keys {
+		for _, field := range importTaskFields {
+			if _, ok := fields[field]; !ok && normalize(key) == strings.ToLower(field) {
+				fields[field] = i
+			}
+		}
+	}
+	for i, key := range keys {
+		if field, ok := importFieldAliases[normalize(key)]; ok {
+			if _, taken := fields[field]; !taken {
+				fields[field] = i
+			}
+		}
+	}
+	return fields
+}
+
+// readTaskJSON reads the tasks of a JSON file, which is an array of tasks or an object with a "tasks" array
+func readTaskJSON(data []byte) ([]importRow, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var file any
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "The file is not valid JSON")
+	}
+
+	items, ok := file.([]any)
+	if object, isObject := file.(map[string]any); isObject {
+		items, ok = object["tasks"].([]any)
+	}
+	if !ok {
+		return nil, fiber.NewError(fiber.StatusBadRequest, `The file must be an array of tasks or an object with a "tasks" array`)
+	}
+
+	rows := make([]importRow, 0, len(items))
+	for i, item := range items {
+		row := importRow{line: i + 1, values: map[string]string{}}
+		task, _ := item.(map[string]any)
+		keys := make([]string, 0, len(task))
+		for key := range task {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for field, index := range matchImportFields(keys) {
+			if value := jsonImportValue(task[keys[index]]); value != "" {
+				row.values[field] = value
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// jsonImportValue reads a JSON value as it would appear in a CSV cell. Arrays become comma separated lists.
+func jsonImportValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(v)
+	case []any:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, jsonImportValue(item))
+		}
+		return strings.Join(items, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// parseImportedTask reads a row into a task. Returns a nil task when the row can't be imported,
+// with the reasons in the result's messages.
+func parseImportedTask(row importRow, loc *time.Location, dateOrder string) (*repository.ImportedTask, contract.ImportTaskRowRes) {
+	values := row.values
+	result := contract.ImportTaskRowRes{Row: row.line, Status: importStatusFailed}
+	imported := &repository.ImportedTask{
+		Title:        values["title"],
+		ProjectTitle: values["project"],
+		StatusName:   values["status"],
+	}
+	valid := true
+	invalid := func(message string) {
+		result.Messages = append(result.Messages, message)
+		valid = false
+	}
+
+	if imported.Title != "" {
+		result.Title = &imported.Title
+	} else {
+		invalid("title is required")
+	}
+	if description := values["description"]; description != "" {
+		imported.Description = &description
+	}
+	if imported.ProjectTitle != "" {
+		result.Project = &imported.ProjectTitle
+	}
+
+	// An unknown status may still be the name of a custom status of the project
+	imported.Category = importStatusCategory(imported.StatusName)
+
+	priority, ok := importPriority(values["priority"])
+	if !ok {
+		result.Messages = append(result.Messages, fmt.Sprintf("unknown priority %q, imported as none", values["priority"]))
+	}
+	imported.Priority = priority
+	result.Priority = &priority
+
+	dueDate, err := parseImportDate(values["dueDate"], loc, dateOrder, true)
+	if err != nil {
+		invalid(fmt.Sprintf("dueDate %q is not a date", values["dueDate"]))
+	}
+	startDate, err := parseImportDate(values["startDate"], loc, dateOrder, false)
+	if err != nil {
+		invalid(fmt.Sprintf("startDate %q is not a date", values["startDate"]))
+	}
+	imported.DueDate = dueDate
+	imported.StartDate = startDate
+	if dueDate != nil {
+		result.DueDate = util.ToPointer(dueDate.In(loc).Format(time.RFC3339))
+	}
+	if startDate != nil {
+		result.StartDate = util.ToPointer(startDate.In(loc).Format(time.RFC3339))
+	}
+
+	estimateMinutes, err := parseImportEstimate(values["estimateMinutes"])
+	if err != nil {
+		invalid(fmt.Sprintf("estimateMinutes %q is not a number of minutes up to 100000", values["estimateMinutes"]))
+	}
+	imported.EstimateMinutes = estimateMinutes
+	result.EstimateMinutes = estimateMinutes
+
+	for _, name := range strings.FieldsFunc(values["tags"], func(r rune) bool { return r == ',' || r == ';' }) {
+		if name = util.NormalizeTagName(name); name != "" {
+			imported.TagNames = append(imported.TagNames, name)
+		}
+	}
+	result.Tags = imported.TagNames
+
+	if !valid {
+		return nil, result
+	}
+	return imported, result
+}
+
+// importStatusCategory returns the category of a status name or Task.Status code.
+// Returns "" for names it doesn't know. Tasks without a status are to do.
+func importStatusCategory(name string) string {
+	name = strings.Join(strings.Fields(strings.ToLower(strings.ReplaceAll(name, "_", " "))), " ")
+	if name == "" {
+		return model.StatusCategoryTodo
+	}
+	if code, err := strconv.Atoi(name); err == nil {
+		if code < model.TaskStatusCancelled || code > model.TaskStatusCompleted {
+			return ""
+		}
+		return model.StatusCategoryOf(code)
+	}
+	if category, ok := importStatusCategories[name]; ok {
+		return category
+	}
+	return importStatusCategories[strings.ReplaceAll(name, "-", " ")]
+}
+
+// importPriority returns the priority of a name or of a number from 0 to 4.
+// Unknown priorities are none, with ok false.
+func importPriority(value string) (int, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if priority, err := strconv.Atoi(value); err == nil {
+		if priority < 0 || priority > 4 {
+			return 0, false
+		}
+		return priority, true
+	}
+	priority, ok := importPriorities[value]
+	return priority, ok
+}
+
+// parseImportDate reads an ISO 8601 date or time, or a numeric date in the given order. Times without
+// a UTC offset are in loc. An all-day due date is due at the end of its day, an all-day start date
+// starts at the beginning of it.
+func parseImportDate(value string, loc *time.Location, dateOrder string, endOfDay bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+
+	value = strings.ToUpper(value)
+	for _, date := range append([]string{"2006-01-02"}, importDateLayouts[dateOrder]...) {
+		for _, clock := range importClockLayouts {
+			t, err := time.ParseInLocation(date+clock, value, loc)
+			if err != nil {
+				continue
+			}
+			if clock == "" && endOfDay {
+				t = time.Date(t.Year(), t.Month(), t.Day(), 23, 59, 0, 0, loc)
+			}
+			return &t, nil
+		}
+	}
+	return nil, errors.New("unknown date format")
+}
+
+// parseImportEstimate reads an estimate in minutes, or as a duration such as "1h30m". Zero means none.
+func parseImportEstimate(value string) (*int, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	minutes, err := strconv.Atoi(value)
+	if err != nil {
+		duration, durationErr := time.ParseDuration(strings.ReplaceAll(value, " ", ""))
+		if durationErr != nil {
+			return nil, durationErr
+		}
+		minutes = int(duration.Round(time.Minute).Minutes())
+	}
+	if minutes < 0 || minutes > 100000 {
+		return nil, errors.New("estimate out of range")
+	}
+	if minutes == 0 {
+		return nil, nil
+	}
+	return &minutes, nil
+}
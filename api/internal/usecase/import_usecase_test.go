@@ -0,0 +1,348 @@
+package usecase
+
+import (
+	"app/internal/model"
+	"archive/zip"
+	"bytes"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadTaskCSV(t *testing.T) {
+	data := "\ufeffName;Due;Labels;Notes\n" +
+		"Pay rent;2026-06-01;home, bills;\n" +
+		";;;\n" +
+		"\"Call the bank; ask about fees\";;;Before noon\n"
+
+	rows, err := readTaskCSV([]byte(data), "")
+	if err != nil {
+		t.Fatalf("failed to read CSV: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2 without the blank one", len(rows))
+	}
+
+	want := map[string]string{"title": "Pay rent", "dueDate": "2026-06-01", "tags": "home, bills"}
+	if !maps.Equal(rows[0].values, want) || rows[0].line != 2 {
+		t.Errorf("first row = %v on line %d, want %v on line 2", rows[0].values, rows[0].line, want)
+	}
+	want = map[string]string{"title": "Call the bank; ask about fees", "description": "Before noon"}
+	if !maps.Equal(rows[1].values, want) || rows[1].line != 4 {
+		t.Errorf("second row = %v on line %d, want %v on line 4", rows[1].values, rows[1].line, want)
+	}
+}
+
+func TestImportColumns(t *testing.T) {
+	header := []string{"Task Name", "Title", "Project", "Due Date", "Deadline"}
+
+	tests := []struct {
+		name    string
+		mapping string
+		want    map[string]int
+		wantErr bool
+	}{
+		{
+			name: "field names win over aliases",
+			want: map[string]int{"title": 1, "project": 2, "dueDate": 3},
+		},
+		{
+			name:    "mapping overrides and drops columns",
+			mapping: `{"title": "task name", "dueDate": "Deadline", "project": ""}`,
+			want:    map[string]int{"title": 0, "dueDate": 4},
+		},
+		{name: "unknown field", mapping: `{"assignee": "Title"}`, wantErr: true},
+		{name: "missing column", mapping: `{"tags": "Labels"}`, wantErr: true},
+		{name: "no title", mapping: `{"title": ""}`, wantErr: true},
+		{name: "not JSON", mapping: `title=Title`, wantErr: true},
+	}
+	for _, tt := range tests {
+		columns, err := importColumns(header, tt.mapping)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: got columns %v, want an error", tt.name, columns)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: got error %v", tt.name, err)
+			continue
+		}
+		if !maps.Equal(columns, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, columns, tt.want)
+		}
+	}
+}
+
+func TestReadTaskJSON(t *testing.T) {
+	data := `{"tasks": [
+		{"content": "Renew passport", "priority": 4, "labels": ["travel", "errands"], "done": false},
+		{"title": "Book flights", "name": "ignored", "estimate": "1h30m"}
+	]}`
+
+	rows, err := readTaskJSON([]byte(data))
+	if err != nil {
+		t.Fatalf("failed to read JSON: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2", len(rows))
+	}
+
+	want := map[string]string{"title": "Renew passport", "priority": "4", "tags": "travel,errands"}
+	if !maps.Equal(rows[0].values, want) {
+		t.Errorf("first row = %v, want %v", rows[0].values, want)
+	}
+	want = map[string]string{"title": "Book flights", "estimateMinutes": "1h30m"}
+	if !maps.Equal(rows[1].values, want) {
+		t.Errorf("second row = %v, want %v", rows[1].values, want)
+	}
+
+	if _, err := readTaskJSON([]byte(`{"items": []}`)); err == nil {
+		t.Error("an object without a tasks array was read")
+	}
+}
+
+func TestImportStatusCategory(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"", model.StatusCategoryTodo},
+		{"Not Started", model.StatusCategoryTodo},
+		{"IN_PROGRESS", model.StatusCategoryDoing},
+		{"in-progress", model.StatusCategoryDoing},
+		{"x", model.StatusCategoryDone},
+		{"2", model.StatusCategoryDone},
+		{"-1", model.StatusCategoryCancelled},
+		{"Won't do", model.StatusCategoryCancelled},
+		{"5", ""},
+		{"Waiting on legal", ""},
+	}
+	for _, tt := range tests {
+		if got := importStatusCategory(tt.name); got != tt.want {
+			t.Errorf("importStatusCategory(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestImportPriority(t *testing.T) {
+	tests := []struct {
+		value  string
+		want   int
+		wantOK bool
+	}{
+		{"", 0, true},
+		{"High", 3, true},
+		{"p1", 4, true},
+		{"p4", 0, true},
+		{"2", 2, true},
+		{"7", 0, false},
+		{"asap", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := importPriority(tt.value)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("importPriority(%q) = %d, %v, want %d, %v", tt.value, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestParseImportDate(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("failed to load time zone: %v", err)
+	}
+
+	tests := []struct {
+		value     string
+		dateOrder string
+		endOfDay  bool
+		want      string
+	}{
+		{"2026-06-01T09:00:00Z", "mdy", true, "2026-06-01T09:00:00Z"},
+		{"2026-06-01", "mdy", true, "2026-06-01T23:59:00+02:00"},
+		{"2026-06-01", "mdy", false, "2026-06-01T00:00:00+02:00"},
+		{"6/1/2026", "mdy", true, "2026-06-01T23:59:00+02:00"},
+		{"1/6/2026", "dmy", true, "2026-06-01T23:59:00+02:00"},
+		{"2026/6/1 3:30 pm", "ymd", true, "2026-06-01T15:30:00+02:00"},
+		{"01.06.2026 08:15", "dmy", true, "2026-06-01T08:15:00+02:00"},
+	}
+	for _, tt := range tests {
+		got, err := parseImportDate(tt.value, berlin, tt.dateOrder, tt.endOfDay)
+		if err != nil {
+			t.Errorf("parseImportDate(%q, %s) failed: %v", tt.value, tt.dateOrder, err)
+			continue
+		}
+		if got.In(berlin).Format(time.RFC3339) != tt.want && got.Format(time.RFC3339) != tt.want {
+			t.Errorf("parseImportDate(%q, %s) = %v, want %s", tt.value, tt.dateOrder, got, tt.want)
+		}
+	}
+
+	if _, err := parseImportDate("next Tuesday", berlin, "mdy", true); err == nil {
+		t.Error("a date in words was read")
+	}
+	if got, err := parseImportDate("", berlin, "mdy", true); got != nil || err != nil {
+		t.Errorf("an empty date = %v, %v, want none", got, err)
+	}
+}
+
+func TestParseImportEstimate(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    int
+		wantErr bool
+	}{
+		{value: "45", want: 45},
+		{value: "1h 30m", want: 90},
+		{value: "0"},
+		{value: "-5", wantErr: true},
+		{value: "100001", wantErr: true},
+		{value: "a while", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseImportEstimate(tt.value)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseImportEstimate(%q) = %v, want an error", tt.value, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseImportEstimate(%q) failed: %v", tt.value, err)
+			continue
+		}
+		if (got == nil && tt.want != 0) || (got != nil && *got != tt.want) {
+			t.Errorf("parseImportEstimate(%q) = %v, want %d", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParseImportedTask(t *testing.T) {
+	imported, result := parseImportedTask(importRow{line: 3, values: map[string]string{
+		"title":    "Plan trip",
+		"status":   "Waiting on legal",
+		"priority": "asap",
+		"tags":     "#travel; Summer",
+	}}, time.UTC, "mdy")
+	if imported == nil {
+		t.Fatalf("a valid row wasn't imported: %v", result.Messages)
+	}
+	if imported.Category != "" || imported.Priority != 0 {
+		t.Errorf("got category %q and priority %d, want an unmatched status and no priority", imported.Category, imported.Priority)
+	}
+	if !slices.Equal(imported.TagNames, []string{"travel", "Summer"}) {
+		t.Errorf("tags = %v, want the names without their #", imported.TagNames)
+	}
+	if len(result.Messages) != 1 {
+		t.Errorf("messages = %v, want one about the priority", result.Messages)
+	}
+
+	imported, result = parseImportedTask(importRow{line: 4, values: map[string]string{
+		"dueDate":         "someday",
+		"estimateMinutes": "forever",
+	}}, time.UTC, "mdy")
+	if imported != nil || result.Status != importStatusFailed {
+		t.Errorf("an invalid row was imported as %v", imported)
+	}
+	if len(result.Messages) != 3 {
+		t.Errorf("messages = %v, want the title, due date and estimate reported", result.Messages)
+	}
+}
+
+// testArchive zips the given files, in order, into an archive to import
+func testArchive(t *testing.T, files [][2]string) *zip.Reader {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, file := range files {
+		f, err := w.Create(file[0])
+		if err != nil {
+			t.Fatalf("failed to add %s: %v", file[0], err)
+		}
+		if _, err := f.Write([]byte(file[1])); err != nil {
+			t.Fatalf("failed to write %s: %v", file[0], err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("failed to close the archive: %v", err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("failed to open the archive: %v", err)
+	}
+	return archive
+}
+
+func TestImportVault(t *testing.T) {
+	archive := testArchive(t, [][2]string{
+		{"Vault/Ideas.md", "Plain note linking [[Trip]]"},
+		{"Vault/Daily/2026-06-01.md", "---\r\ntitle: Packing\r\ntags: [travel, \"#Summer\"]\r\n---\r\nSocks\r\n"},
+		{"Vault/Broken.md", "---\ntitle: [unclosed\n---\nBody"},
+		{"Vault/photo.png", "\x89PNG"},
+		{"Vault/.obsidian/app.json", "{}"},
+		{"__MACOSX/Vault/._Ideas.md", "resource fork"},
+	})
+
+	files := importableFiles(archive)
+	paths := make([]string, 0, len(files))
+	for _, f := range files {
+		paths = append(paths, f.path)
+	}
+	wantPaths := []string{"Broken.md", "Daily/2026-06-01.md", "Ideas.md", "photo.png"}
+	if !slices.Equal(paths, wantPaths) {
+		t.Fatalf("importable files = %q, want %q without the vault folder and hidden files", paths, wantPaths)
+	}
+
+	tests := []struct {
+		path       string
+		title      string
+		collection string
+		tags       []string
+		content    string
+		message    bool
+	}{
+		{path: "Broken.md", title: "Broken", content: "Body", message: true},
+		{path: "Daily/2026-06-01.md", title: "Packing", collection: "Daily", tags: []string{"travel", "Summer"}, content: "Socks\r\n"},
+		{path: "Ideas.md", title: "Ideas", content: "Plain note linking [[Trip]]"},
+	}
+	for i, tt := range tests {
+		content, err := readImportFile(files[i].file, importMaxTotalBytes)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", tt.path, err)
+		}
+		imported, message := parseMarkdownNote(files[i].path, content)
+		if imported.Title != tt.title || imported.CollectionTitle != tt.collection {
+			t.Errorf("%s: got title %q in %q, want %q in %q", tt.path, imported.Title, imported.CollectionTitle, tt.title, tt.collection)
+		}
+		if !slices.Equal(imported.TagNames, tt.tags) {
+			t.Errorf("%s: got tags %q, want %q", tt.path, imported.TagNames, tt.tags)
+		}
+		if tt.message != (message != nil) {
+			t.Errorf("%s: got message %v, want one %v", tt.path, message, tt.message)
+		}
+		if !tt.message && imported.Content != tt.content {
+			t.Errorf("%s: got content %q, want %q", tt.path, imported.Content, tt.content)
+		}
+	}
+}
+
+func TestReadImportFileLimits(t *testing.T) {
+	archive := testArchive(t, [][2]string{
+		{"note.md", "Hello"},
+		{"binary.md", "\xff\xfe"},
+	})
+
+	if content, err := readImportFile(archive.File[0], importMaxTotalBytes); err != nil || content != "Hello" {
+		t.Errorf("got %q (%v), want the note", content, err)
+	}
+	if _, err := readImportFile(archive.File[0], 3); err == nil || !strings.Contains(err.Error(), "archive") {
+		t.Errorf("got %v, want the archive size error once the archive's budget is spent", err)
+	}
+	if _, err := readImportFile(archive.File[1], importMaxTotalBytes); err == nil || !strings.Contains(err.Error(), "UTF-8") {
+		t.Errorf("got %v, want invalid UTF-8 rejected", err)
+	}
+}
@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"app/internal/contract"
+	"app/internal/repository"
+	"context"
+	"math"
+	"time"
+)
+
+const (
+	insightsMaxDays         = 366
+	insightsBusiestProjects = 5
+)
+
+type InsightsUsecase struct {
+	userRepo     *repository.UserRepository
+	insightsRepo *repository.InsightsRepository
+}
+
+func NewInsightsUsecase(userRepo *repository.UserRepository, insightsRepo *repository.InsightsRepository) *InsightsUsecase {
+	return &InsightsUsecase{
+		userRepo:     userRepo,
+		insightsRepo: insightsRepo,
+	}
+}
+
+// GetInsights returns the user's productivity aggregates over a date range, split into days in their time zone
+func (u *InsightsUsecase) GetInsights(ctx context.Context, userID string, req *contract.InsightsReq) (*contract.InsightsRes, error) {
+	loc, err := resolveLocation(u.userRepo, userID, req.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	from, end, err := parseDateRange(req.From, req.To, loc, insightsMaxDays)
+	if err != nil {
+		return nil, err
+	}
+	period := repository.InsightsRange{From: from, To: end, Timezone: loc.String()}
+
+	dayCounts, err := u.insightsRepo.CountCompletionsByDay(ctx, userID, period)
+	if err != nil {
+		return nil, err
+	}
+	totals, err := u.insightsRepo.GetTaskTotals(ctx, userID, period)
+	if err != nil {
+		return nil, err
+	}
+	projects, err := u.insightsRepo.ListBusiestProjects(ctx, userID, period, insightsBusiestProjects)
+	if err != nil {
+		return nil, err
+	}
+	collections, err := u.insightsRepo.CountNotesByCollection(ctx, userID, period)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &contract.InsightsRes{
+		From:               req.From,
+		To:                 req.To,
+		Timezone:           loc.String(),
+		CompletedPerDay:    completionsPerDay(dayCounts, from, end),
+		Created:            totals.Created,
+		CompletedLate:      totals.CompletedLate,
+		Overdue:            totals.Overdue,
+		BusiestProjects:    make([]contract.InsightsProjectRes, 0, len(projects)),
+		NotesPerCollection: make([]contract.InsightsCollectionRes, 0, len(collections)),
+	}
+	for _, day := range res.CompletedPerDay {
+		res.Completed += day.Count
+	}
+	if totals.AverageCompletionSeconds != nil {
+		hours := math.Round(*totals.AverageCompletionSeconds/time.Hour.Seconds()*10) / 10
+		res.AverageCompletionHours = &hours
+	}
+	for _, project := range projects {
+		res.BusiestProjects = append(res.BusiestProjects, contract.InsightsProjectRes{
+			ProjectID: project.ProjectID,
+			Title:     project.Title,
+			Color:     project.Color,
+			Completed: project.Completed,
+		})
+	}
+	for _, collection := range collections {
+		res.NotesPerCollection = append(res.NotesPerCollection, contract.InsightsCollectionRes{
+			CollectionID: collection.CollectionID,
+			Title:        collection.Title,
+			Color:        collection.Color,
+			Notes:        collection.Notes,
+		})
+	}
+
+	return res, nil
+}
+
+// completionsPerDay fills in the days of [from, end) that had no completions
+func completionsPerDay(counts []repository.DayCount, from, end time.Time) []contract.InsightsDayRes {
+	byDay := map[string]int64{}
+	for _, count := range counts {
+		byDay[count.Day] = count.Count
+	}
+
+	days := []contract.InsightsDayRes{}
+	for day := from; day.Before(end); day = day.AddDate(0, 0, 1) {
+		date := day.Format(calendarDateLayout)
+		days = append(days, contract.InsightsDayRes{Date: date, Count: byDay[date]})
+	}
+	return days
+}
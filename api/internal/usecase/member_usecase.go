@@ -0,0 +1,423 @@
+package usecase
+
+import (
+	"app/internal/contract"
+	"app/internal/model"
+	"app/internal/repository"
+	"app/pkg/logger"
+	"app/pkg/util"
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+const (
+	MemberTargetProject    = "project"
+	MemberTargetCollection = "collection"
+	MemberTargetWorkspace  = "workspace"
+)
+
+type MemberUsecase struct {
+	membershipRepo *repository.MembershipRepository
+	userRepo       *repository.UserRepository
+	// emailUsecase is nil when SMTP isn't configured; invitations then only show up in the app
+	emailUsecase *EmailUsecase
+}
+
+func NewMemberUsecase(membershipRepo *repository.MembershipRepository, userRepo *repository.UserRepository, emailUsecase *EmailUsecase) *MemberUsecase {
+	return &MemberUsecase{
+		membershipRepo: membershipRepo,
+		userRepo:       userRepo,
+		emailUsecase:   emailUsecase,
+	}
+}
+
+// ListMembers returns everyone with access to a project, collection or workspace, its owner first.
+// Owners also get the pending invitations.
+func (u *MemberUsecase) ListMembers(ctx context.Context, userID, targetType, targetID string) (*contract.MembersRes, error) {
+	target := memberTargetOf(targetType, targetID)
+	access, err := u.access(ctx, userID, target, model.MemberRoleViewer)
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := u.userRepo.GetUserByID(access.OwnerID)
+	if err != nil {
+		logger.Log.Error("Failed to get owner", zap.Error(err), zap.String("userID", access.OwnerID))
+		return nil, err
+	}
+	members, err := u.membershipRepo.ListMembers(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &contract.MembersRes{
+		Role:        access.Role,
+		Members:     make([]contract.MemberRes, 0, len(members)+1),
+		Invitations: []contract.InvitationRes{},
+	}
+	if owner != nil {
+		res.Members = append(res.Members, contract.MemberRes{
+			UserID:      owner.ID,
+			Name:        owner.Name,
+			Email:       owner.Email,
+			GoogleImage: owner.GoogleImage,
+			Role:        model.MemberRoleOwner,
+			Creator:     true,
+			JoinedAt:    owner.CreatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	for i := range members {
+		res.Members = append(res.Members, toMemberRes(&members[i]))
+	}
+
+	if access.Role == model.MemberRoleOwner {
+		invitations, err := u.membershipRepo.ListInvitations(ctx, target)
+		if err != nil {
+			return nil, err
+		}
+		for i := range invitations {
+			res.Invitations = append(res.Invitations, toInvitationRes(&invitations[i]))
+		}
+	}
+
+	return res, nil
+}
+
+// Invite emails an invitation to join a project, collection or workspace. Only owners can invite.
+// A new invitation to the same address replaces the pending one.
+func (u *MemberUsecase) Invite(ctx context.Context, userID, targetType, targetID string, req *contract.InviteMemberReq) (*contract.InvitationRes, error) {
+	target := memberTargetOf(targetType, targetID)
+	access, err := u.access(ctx, userID, target, model.MemberRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+
+	invitee, err := u.userRepo.GetUserByEmail(email)
+	if err != nil {
+		logger.Log.Error("Failed to get invitee", zap.Error(err))
+		return nil, err
+	}
+	if invitee != nil {
+		if invitee.ID == access.OwnerID {
+			return nil, fiber.NewError(fiber.StatusConflict, repository.ErrAlreadyMember.Error())
+		}
+		members, err := u.membershipRepo.ListMembers(ctx, target)
+		if err != nil {
+			return nil, err
+		}
+		for _, member := range members {
+			if member.UserID == invitee.ID {
+				return nil, fiber.NewError(fiber.StatusConflict, repository.ErrAlreadyMember.Error())
+			}
+		}
+	}
+
+	inviter, err := u.userRepo.GetUserByID(userID)
+	if err != nil {
+		logger.Log.Error("Failed to get inviter", zap.Error(err), zap.String("userID", userID))
+		return nil, err
+	}
+	if inviter == nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, "User not found")
+	}
+
+	token, err := newSecretToken()
+	if err != nil {
+		logger.Log.Error("Failed to generate invitation token", zap.Error(err))
+		return nil, err
+	}
+	invitation := &model.Invitation{
+		InviterID:    userID,
+		ProjectID:    target.ProjectID,
+		CollectionID: target.CollectionID,
+		WorkspaceID:  target.WorkspaceID,
+		Email:        email,
+		Role:         req.Role,
+		TokenHash:    hashSecretToken(token),
+		ExpiresAt:    time.Now().Add(model.InvitationTTL),
+	}
+	if err := u.membershipRepo.CreateInvitation(ctx, invitation); err != nil {
+		return nil, err
+	}
+
+	// The invitation stands even if the email can't go out, since the invitee also sees it in the app
+	emailSent := false
+	if u.emailUsecase != nil {
+		title := util.ToValue(access.Title)
+		if title == "" {
+			title = "a shared " + memberTargetType(target)
+		}
+		if err := u.emailUsecase.SendInvitationEmail(email, inviter.Name, title, req.Role, token); err != nil {
+			logger.Log.Warn("Failed to send invitation email", zap.Error(err), zap.String("invitationID", invitation.ID))
+		} else {
+			emailSent = true
+		}
+	}
+
+	invitation.Inviter = inviter
+	res := toInvitationRes(invitation)
+	res.Title = access.Title
+	res.EmailSent = &emailSent
+	return &res, nil
+}
+
+// RevokeInvitation revokes a pending invitation. Only owners can revoke.
+func (u *MemberUsecase) RevokeInvitation(ctx context.Context, userID, targetType, targetID, invitationID string) error {
+	target := memberTargetOf(targetType, targetID)
+	if _, err := u.access(ctx, userID, target, model.MemberRoleOwner); err != nil {
+		return err
+	}
+
+	revoked, err := u.membershipRepo.RevokeInvitation(ctx, target, invitationID)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return fiber.NewError(fiber.StatusNotFound, "Invitation not found")
+	}
+	return nil
+}
+
+// UpdateMember changes a member's role. Only owners can change roles, and the user the
+// project, collection or workspace belongs to always stays its owner.
+func (u *MemberUsecase) UpdateMember(ctx context.Context, userID, targetType, targetID, memberUserID string, req *contract.UpdateMemberReq) error {
+	target := memberTargetOf(targetType, targetID)
+	access, err := u.access(ctx, userID, target, model.MemberRoleOwner)
+	if err != nil {
+		return err
+	}
+	if memberUserID == access.OwnerID {
+		return fiber.NewError(fiber.StatusBadRequest, "The creator's role can't change")
+	}
+
+	updated, err := u.membershipRepo.UpdateMemberRole(ctx, target, memberUserID, req.Role)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return fiber.NewError(fiber.StatusNotFound, repository.ErrMemberNotFound.Error())
+	}
+	return nil
+}
+
+// RemoveMember removes a member. Owners can remove anyone but the creator, and every member can leave.
+func (u *MemberUsecase) RemoveMember(ctx context.Context, userID, targetType, targetID, memberUserID string) error {
+	target := memberTargetOf(targetType, targetID)
+	minimum := model.MemberRoleOwner
+	if memberUserID == userID {
+		minimum = model.MemberRoleViewer
+	}
+	access, err := u.access(ctx, userID, target, minimum)
+	if err != nil {
+		return err
+	}
+	if memberUserID == access.OwnerID {
+		return fiber.NewError(fiber.StatusBadRequest, "The creator can't be removed")
+	}
+
+	removed, err := u.membershipRepo.RemoveMember(ctx, target, memberUserID)
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return fiber.NewError(fiber.StatusNotFound, repository.ErrMemberNotFound.Error())
+	}
+	return nil
+}
+
+// ListInvitations returns the pending invitations sent to the user's verified email address
+func (u *MemberUsecase) ListInvitations(ctx context.Context, userID string) (*contract.InvitationsRes, error) {
+	user, err := u.verifiedUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	invitations, err := u.membershipRepo.ListPendingInvitations(ctx, user.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &contract.InvitationsRes{Items: make([]contract.InvitationRes, 0, len(invitations))}
+	for i := range invitations {
+		res.Items = append(res.Items, toInvitationRes(&invitations[i]))
+	}
+	return res, nil
+}
+
+// AcceptInvitationByToken accepts the invitation an email link carries. Holding the token is
+// enough, so it can be accepted from an account with another address.
+func (u *MemberUsecase) AcceptInvitationByToken(ctx context.Context, userID string, req *contract.AcceptInvitationReq) (*contract.MembershipRes, error) {
+	invitation, err := u.membershipRepo.GetInvitationByToken(ctx, hashSecretToken(req.Token))
+	if err != nil {
+		return nil, err
+	}
+	if invitation == nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, repository.ErrInvitationNotFound.Error())
+	}
+
+	return u.accept(ctx, userID, invitation.ID)
+}
+
+// AcceptInvitation accepts one of the invitations sent to the user's verified email address
+func (u *MemberUsecase) AcceptInvitation(ctx context.Context, userID, invitationID string) (*contract.MembershipRes, error) {
+	invitation, err := u.pendingInvitation(ctx, userID, invitationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.accept(ctx, userID, invitation.ID)
+}
+
+// DeclineInvitation declines one of the invitations sent to the user's verified email address
+func (u *MemberUsecase) DeclineInvitation(ctx context.Context, userID, invitationID string) error {
+	invitation, err := u.pendingInvitation(ctx, userID, invitationID)
+	if err != nil {
+		return err
+	}
+
+	declined, err := u.membershipRepo.DeclineInvitation(ctx, invitation.ID)
+	if err != nil {
+		return err
+	}
+	if !declined {
+		return fiber.NewError(fiber.StatusNotFound, repository.ErrInvitationNotFound.Error())
+	}
+	return nil
+}
+
+func (u *MemberUsecase) accept(ctx context.Context, userID, invitationID string) (*contract.MembershipRes, error) {
+	membership, err := u.membershipRepo.AcceptInvitation(ctx, userID, invitationID)
+	switch {
+	case errors.Is(err, repository.ErrInvitationNotFound), errors.Is(err, repository.ErrMemberTargetNotFound):
+		return nil, fiber.NewError(fiber.StatusNotFound, err.Error())
+	case errors.Is(err, repository.ErrAlreadyMember):
+		return nil, fiber.NewError(fiber.StatusConflict, err.Error())
+	case err != nil:
+		return nil, err
+	}
+
+	target := repository.MemberTarget{ProjectID: membership.ProjectID, CollectionID: membership.CollectionID, WorkspaceID: membership.WorkspaceID}
+	return &contract.MembershipRes{
+		Type:         memberTargetType(target),
+		ProjectID:    membership.ProjectID,
+		CollectionID: membership.CollectionID,
+		WorkspaceID:  membership.WorkspaceID,
+		Role:         membership.Role,
+		JoinedAt:     membership.CreatedAt.UTC().Format(time.RFC3339),
+	}, nil
+}
+
+// pendingInvitation returns a pending invitation sent to the user's verified email address
+func (u *MemberUsecase) pendingInvitation(ctx context.Context, userID, invitationID string) (*model.Invitation, error) {
+	user, err := u.verifiedUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	invitation, err := u.membershipRepo.GetPendingInvitation(ctx, invitationID, user.Email)
+	if err != nil {
+		return nil, err
+	}
+	if invitation == nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, repository.ErrInvitationNotFound.Error())
+	}
+	return invitation, nil
+}
+
+// verifiedUser returns the user, who must have verified their email address to see invitations sent to it
+func (u *MemberUsecase) verifiedUser(userID string) (*model.User, error) {
+	user, err := u.userRepo.GetUserByID(userID)
+	if err != nil {
+		logger.Log.Error("Failed to get user", zap.Error(err), zap.String("userID", userID))
+		return nil, err
+	}
+	if user == nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, "User not found")
+	}
+	if !user.IsVerified {
+		return nil, fiber.NewError(fiber.StatusForbidden, "Verify your email address to see invitations sent to it")
+	}
+	return user, nil
+}
+
+// access returns the user's access to a project, collection or workspace, which must allow at least the minimum role
+func (u *MemberUsecase) access(ctx context.Context, userID string, target repository.MemberTarget, minimum string) (*repository.MemberAccess, error) {
+	access, err := u.membershipRepo.Access(ctx, userID, target)
+	if err != nil {
+		return nil, err
+	}
+	if access == nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, repository.ErrMemberTargetNotFound.Error())
+	}
+	if !model.MemberRoleAllows(access.Role, minimum) {
+		return nil, fiber.NewError(fiber.StatusForbidden, "Only owners can manage members")
+	}
+	return access, nil
+}
+
+func memberTargetOf(targetType, targetID string) repository.MemberTarget {
+	switch targetType {
+	case MemberTargetProject:
+		return repository.MemberTarget{ProjectID: &targetID}
+	case MemberTargetCollection:
+		return repository.MemberTarget{CollectionID: &targetID}
+	}
+	return repository.MemberTarget{WorkspaceID: &targetID}
+}
+
+func memberTargetType(target repository.MemberTarget) string {
+	switch {
+	case target.ProjectID != nil:
+		return MemberTargetProject
+	case target.CollectionID != nil:
+		return MemberTargetCollection
+	}
+	return MemberTargetWorkspace
+}
+
+func toMemberRes(membership *model.Membership) contract.MemberRes {
+	res := contract.MemberRes{
+		UserID:   membership.UserID,
+		Role:     membership.Role,
+		JoinedAt: membership.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if membership.User != nil {
+		res.Name = membership.User.Name
+		res.Email = membership.User.Email
+		res.GoogleImage = membership.User.GoogleImage
+	}
+	return res
+}
+
+func toInvitationRes(invitation *model.Invitation) contract.InvitationRes {
+	res := contract.InvitationRes{
+		ID:           invitation.ID,
+		Type:         memberTargetType(repository.MemberTarget{ProjectID: invitation.ProjectID, CollectionID: invitation.CollectionID, WorkspaceID: invitation.WorkspaceID}),
+		ProjectID:    invitation.ProjectID,
+		CollectionID: invitation.CollectionID,
+		WorkspaceID:  invitation.WorkspaceID,
+		Email:        invitation.Email,
+		Role:         invitation.Role,
+		ExpiresAt:    invitation.ExpiresAt.UTC().Format(time.RFC3339),
+		CreatedAt:    invitation.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if invitation.Inviter != nil {
+		res.InviterName = invitation.Inviter.Name
+	}
+	if invitation.Project != nil {
+		res.Title = invitation.Project.Title
+	}
+	if invitation.Collection != nil {
+		res.Title = util.ToPointer(invitation.Collection.Title)
+	}
+	if invitation.Workspace != nil {
+		res.Title = util.ToPointer(invitation.Workspace.Title)
+	}
+	return res
+}
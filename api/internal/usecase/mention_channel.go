@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"app/internal/model"
+	"app/internal/repository"
+	firebasepkg "app/pkg/firebase"
+	"app/pkg/logger"
+	"app/pkg/util"
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// mentionExcerptLength is how many characters of a comment a notification quotes
+const mentionExcerptLength = 280
+
+// EmailMentionChannel sends mention notifications to the user's email address over SMTP
+type EmailMentionChannel struct {
+	emailUsecase *EmailUsecase
+}
+
+func NewEmailMentionChannel(emailUsecase *EmailUsecase) *EmailMentionChannel {
+	return &EmailMentionChannel{emailUsecase: emailUsecase}
+}
+
+func (c *EmailMentionChannel) Name() string {
+	return model.ReminderChannelEmail
+}
+
+func (c *EmailMentionChannel) Send(ctx context.Context, recipient *model.User, comment *model.Comment) error {
+	if recipient.Email == "" {
+		return ErrNoMentionRecipient
+	}
+
+	subject, body := mentionMessage(comment)
+	return c.emailUsecase.SendEmail(recipient.Email, subject, body)
+}
+
+// PushMentionChannel sends mention notifications to the user's registered devices through Firebase Cloud Messaging
+type PushMentionChannel struct {
+	firebaseUsecase *firebasepkg.FirebaseUsecase
+	reminderRepo    *repository.ReminderRepository
+}
+
+func NewPushMentionChannel(firebaseUsecase *firebasepkg.FirebaseUsecase, reminderRepo *repository.ReminderRepository) *PushMentionChannel {
+	return &PushMentionChannel{
+		firebaseUsecase: firebaseUsecase,
+		reminderRepo:    reminderRepo,
+	}
+}
+
+func (c *PushMentionChannel) Name() string {
+	return model.ReminderChannelPush
+}
+
+func (c *PushMentionChannel) Send(ctx context.Context, recipient *model.User, comment *model.Comment) error {
+	tokens, err := c.reminderRepo.ListDeviceTokens(ctx, recipient.ID)
+	if err != nil {
+		return err
+	}
+	if len(tokens) == 0 {
+		return ErrNoMentionRecipient
+	}
+
+	title, body := mentionMessage(comment)
+	data := map[string]string{
+		"type":      "comment_mention",
+		"commentId": comment.ID,
+	}
+	if comment.TaskID != nil {
+		data["taskId"] = *comment.TaskID
+	}
+	if comment.NoteID != nil {
+		data["noteId"] = *comment.NoteID
+	}
+	delivered, staleTokens, err := c.firebaseUsecase.SendPush(ctx, tokens, title, body, data)
+
+	// Devices that uninstalled the app never come back, so stop sending to them
+	if len(staleTokens) > 0 {
+		if deleteErr := c.reminderRepo.DeleteDeviceTokens(ctx, "", staleTokens); deleteErr != nil {
+			logger.Log.Warn("Failed to delete stale device tokens", zap.Error(deleteErr), zap.String("userID", recipient.ID))
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+	if delivered == 0 {
+		return ErrNoMentionRecipient
+	}
+	return nil
+}
+
+// mentionMessage returns the title and body of a mention notification, quoting the start of the comment
+func mentionMessage(comment *model.Comment) (string, string) {
+	author := "Someone"
+	if comment.User != nil && comment.User.Name != "" {
+		author = comment.User.Name
+	}
+
+	on := "a note"
+	switch {
+	case comment.Task != nil && util.ToValue(comment.Task.Title) != "":
+		on = fmt.Sprintf("%q", *comment.Task.Title)
+	case comment.Task != nil:
+		on = "a task"
+	case comment.Note != nil && util.ToValue(comment.Note.Title) != "":
+		on = fmt.Sprintf("%q", *comment.Note.Title)
+	}
+
+	body := []rune(comment.Content)
+	if len(body) > mentionExcerptLength {
+		body = append(body[:mentionExcerptLength-1], '…')
+	}
+
+	return fmt.Sprintf("%s mentioned you on %s", author, on), string(body)
+}
@@ -0,0 +1,49 @@
+package usecase
+
+import (
+	"app/internal/model"
+	"app/pkg/util"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestMentionMessage(t *testing.T) {
+	tests := []struct {
+		name      string
+		comment   model.Comment
+		wantTitle string
+	}{
+		{
+			name:      "task",
+			comment:   model.Comment{User: &model.User{Name: "Ana"}, Task: &model.Task{Title: util.ToPointer("Ship it")}},
+			wantTitle: `Ana mentioned you on "Ship it"`,
+		},
+		{
+			name:      "untitled task",
+			comment:   model.Comment{User: &model.User{Name: "Ana"}, Task: &model.Task{}},
+			wantTitle: "Ana mentioned you on a task",
+		},
+		{
+			name:      "note by an unnamed author",
+			comment:   model.Comment{User: &model.User{}, Note: &model.Note{Title: util.ToPointer("Plans")}},
+			wantTitle: `Someone mentioned you on "Plans"`,
+		},
+		{
+			name:      "untitled note",
+			comment:   model.Comment{Note: &model.Note{}},
+			wantTitle: "Someone mentioned you on a note",
+		},
+	}
+	for _, tt := range tests {
+		tt.comment.Content = "Short"
+		if title, body := mentionMessage(&tt.comment); title != tt.wantTitle || body != "Short" {
+			t.Errorf("%s: got %q, %q, want %q, %q", tt.name, title, body, tt.wantTitle, "Short")
+		}
+	}
+
+	_, body := mentionMessage(&model.Comment{Content: strings.Repeat("ü", mentionExcerptLength+10)})
+	if utf8.RuneCountInString(body) != mentionExcerptLength || !strings.HasSuffix(body, "…") {
+		t.Errorf("a long comment is quoted as %d characters, want %d ending in …", utf8.RuneCountInString(body), mentionExcerptLength)
+	}
+}
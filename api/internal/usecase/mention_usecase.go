@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"app/internal/model"
+	"app/internal/repository"
+	"app/pkg/logger"
+	"app/pkg/util"
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// mentionStaleAfter is how long a notification may stay in sending before it is abandoned
+	mentionStaleAfter = 15 * time.Minute
+	mentionBatchSize  = 100
+)
+
+// ErrNoMentionRecipient means a channel had nowhere to deliver a mention notification, e.g. no registered devices
+var ErrNoMentionRecipient = errors.New("no mention recipient")
+
+// MentionChannel delivers @mention notifications through one medium
+type MentionChannel interface {
+	Name() string
+	Send(ctx context.Context, recipient *model.User, comment *model.Comment) error
+}
+
+type MentionUsecase struct {
+	userRepo    *repository.UserRepository
+	commentRepo *repository.CommentRepository
+	channels    []MentionChannel
+}
+
+func NewMentionUsecase(userRepo *repository.UserRepository, commentRepo *repository.CommentRepository, channels ...MentionChannel) *MentionUsecase {
+	return &MentionUsecase{
+		userRepo:    userRepo,
+		commentRepo: commentRepo,
+		channels:    channels,
+	}
+}
+
+// DispatchMentions attempts every mention notification that is ready. Returns the number sent.
+func (u *MentionUsecase) DispatchMentions(ctx context.Context) (int, error) {
+	if _, err := u.commentRepo.AbandonStaleMentions(ctx, mentionStaleAfter); err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for {
+		mentions, err := u.commentRepo.ClaimMentions(ctx, mentionBatchSize)
+		if err != nil {
+			return sent, err
+		}
+		for i := range mentions {
+			if u.deliver(ctx, &mentions[i]) {
+				sent++
+			}
+		}
+		if len(mentions) < mentionBatchSize {
+			return sent, nil
+		}
+	}
+}
+
+// deliver sends a claimed notification on every channel and records the outcome. It counts as sent
+// once any channel delivered it, so a failing channel never makes the others repeat it.
+// Reports whether the notification was sent.
+func (u *MentionUsecase) deliver(ctx context.Context, mention *model.CommentMention) bool {
+	comment, err := u.commentRepo.GetMentionComment(ctx, mention)
+	if err != nil {
+		u.retryMention(ctx, mention, err)
+		return false
+	}
+	if comment == nil {
+		_ = u.commentRepo.MarkMention(ctx, mention.ID, model.MentionStatusCancelled,
+			util.ToPointer("comment was deleted or edited, or the user no longer sees it"), nil)
+		return false
+	}
+
+	recipient, err := u.userRepo.GetUserByID(mention.UserID)
+	if err != nil {
+		u.retryMention(ctx, mention, err)
+		return false
+	}
+	if recipient == nil {
+		_ = u.commentRepo.MarkMention(ctx, mention.ID, model.MentionStatusCancelled, util.ToPointer("user not found"), nil)
+		return false
+	}
+
+	delivered := false
+	var sendErr error
+	for _, channel := range u.channels {
+		err := channel.Send(ctx, recipient, comment)
+		switch {
+		case err == nil:
+			delivered = true
+		case !errors.Is(err, ErrNoMentionRecipient):
+			logger.Log.Warn("Failed to send mention notification", zap.Error(err), zap.String("mentionID", mention.ID), zap.String("channel", channel.Name()))
+			sendErr = err
+		}
+	}
+
+	if delivered {
+		if err := u.commentRepo.MarkMention(ctx, mention.ID, model.MentionStatusSent, nil, nil); err != nil {
+			logger.Log.Error("Mention notification sent but not recorded", zap.Error(err), zap.String("mentionID", mention.ID))
+		}
+		return true
+	}
+	if sendErr != nil {
+		u.retryMention(ctx, mention, sendErr)
+		return false
+	}
+	_ = u.commentRepo.MarkMention(ctx, mention.ID, model.MentionStatusSkipped, util.ToPointer(ErrNoMentionRecipient.Error()), nil)
+	return false
+}
+
+// retryMention records a failed attempt and schedules the next one with exponential backoff
+func (u *MentionUsecase) retryMention(ctx context.Context, mention *model.CommentMention, cause error) {
+	logger.Log.Warn("Failed to deliver mention notification",
+		zap.Error(cause),
+		zap.String("mentionID", mention.ID),
+		zap.Int("attempts", mention.Attempts),
+	)
+
+	// 1, 4, 16, 64 minutes
+	backoff := time.Minute << (2 * (mention.Attempts - 1))
+	nextAttemptAt := time.Now().Add(backoff)
+	_ = u.commentRepo.MarkMention(ctx, mention.ID, model.MentionStatusFailed, util.ToPointer(cause.Error()), &nextAttemptAt)
+}
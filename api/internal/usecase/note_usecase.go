@@ -0,0 +1,156 @@
+package usecase
+
+import (
+	"app/internal/contract"
+	"app/internal/repository"
+	"app/pkg/logger"
+	"app/pkg/util"
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type NoteUsecase struct {
+	noteRepo *repository.NoteRepository
+}
+
+func NewNoteUsecase(noteRepo *repository.NoteRepository) *NoteUsecase {
+	return &NoteUsecase{noteRepo: noteRepo}
+}
+
+// GetRelatedNotes returns the notes most similar to the given note
+func (u *NoteUsecase) GetRelatedNotes(ctx context.Context, noteID, userID string, req *contract.RelatedNotesReq) (*contract.RelatedNotesRes, error) {
+	note, err := u.noteRepo.GetNoteByID(ctx, noteID, userID)
+	if err != nil {
+		logger.Log.Error("Failed to get note", zap.Error(err), zap.String("noteID", noteID))
+		return nil, err
+	}
+	if note == nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Note not found")
+	}
+
+	items := []contract.RelatedNoteRes{}
+
+	// Embeddings are generated on sync and may be missing if that failed
+	if note.Embedding == nil {
+		return &contract.RelatedNotesRes{Items: items}, nil
+	}
+
+	related, err := u.noteRepo.GetRelatedNotes(ctx, userID, note, repository.RelatedNoteFilters{
+		CollectionID: req.CollectionID,
+		MinScore:     req.MinScore,
+		Limit:        req.Limit,
+	})
+	if err != nil {
+		logger.Log.Error("Failed to get related notes", zap.Error(err), zap.String("noteID", noteID))
+		return nil, err
+	}
+
+	for _, r := range related {
+		items = append(items, contract.RelatedNoteRes{
+			ID:           r.ID,
+			CollectionID: r.CollectionID,
+			Title:        r.Title,
+			Score:        r.Score,
+			UpdatedAt:    r.UpdatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+
+	return &contract.RelatedNotesRes{Items: items}, nil
+}
+
+// GetBacklinks returns the notes linking to the given note
+func (u *NoteUsecase) GetBacklinks(ctx context.Context, noteID, userID string) (*contract.BacklinksRes, error) {
+	note, err := u.noteRepo.GetNoteByID(ctx, noteID, userID)
+	if err != nil {
+		logger.Log.Error("Failed to get note", zap.Error(err), zap.String("noteID", noteID))
+		return nil, err
+	}
+	if note == nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Note not found")
+	}
+
+	notes, err := u.noteRepo.GetBacklinks(ctx, noteID, userID)
+	if err != nil {
+		logger.Log.Error("Failed to get backlinks", zap.Error(err), zap.String("noteID", noteID))
+		return nil, err
+	}
+
+	items := make([]contract.NoteSummaryRes, 0, len(notes))
+	for _, n := range notes {
+		items = append(items, contract.NoteSummaryRes{
+			ID:           n.ID,
+			CollectionID: n.CollectionID,
+			Title:        n.Title,
+			UpdatedAt:    n.UpdatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+
+	return &contract.BacklinksRes{Items: items}, nil
+}
+
+// ListDanglingLinks returns links that don't point at a live note
+func (u *NoteUsecase) ListDanglingLinks(ctx context.Context, userID string) (*contract.DanglingLinksRes, error) {
+	links, err := u.noteRepo.ListDanglingLinks(ctx, userID)
+	if err != nil {
+		logger.Log.Error("Failed to list dangling links", zap.Error(err), zap.String("userID", userID))
+		return nil, err
+	}
+
+	items := make([]contract.DanglingLinkRes, 0, len(links))
+	for _, l := range links {
+		items = append(items, contract.DanglingLinkRes{
+			SourceNoteID: l.SourceNoteID,
+			SourceTitle:  l.SourceTitle,
+			TargetTitle:  l.TargetTitle,
+		})
+	}
+
+	return &contract.DanglingLinksRes{Items: items}, nil
+}
+
+// ResolveDanglingLinks retries title resolution for every dangling link
+func (u *NoteUsecase) ResolveDanglingLinks(ctx context.Context, userID string) (*contract.ResolveLinksRes, error) {
+	resolved, err := u.noteRepo.ResolveDanglingLinks(ctx, userID)
+	if err != nil {
+		logger.Log.Error("Failed to resolve dangling links", zap.Error(err), zap.String("userID", userID))
+		return nil, err
+	}
+
+	return &contract.ResolveLinksRes{Resolved: resolved}, nil
+}
+
+// GetNoteGraph returns the user's notes as graph nodes and their links as edges
+func (u *NoteUsecase) GetNoteGraph(ctx context.Context, userID string) (*contract.NoteGraphRes, error) {
+	notes, links, err := u.noteRepo.GetNoteGraph(ctx, userID)
+	if err != nil {
+		logger.Log.Error("Failed to get note graph", zap.Error(err), zap.String("userID", userID))
+		return nil, err
+	}
+
+	linkCounts := map[string]int{}
+	edges := make([]contract.NoteGraphEdgeRes, 0, len(links))
+	for _, l := range links {
+		target := util.ToValue(l.TargetNoteID)
+		linkCounts[l.SourceNoteID]++
+		linkCounts[target]++
+		edges = append(edges, contract.NoteGraphEdgeRes{
+			Source: l.SourceNoteID,
+			Target: target,
+		})
+	}
+
+	nodes := make([]contract.NoteGraphNodeRes, 0, len(notes))
+	for _, n := range notes {
+		nodes = append(nodes, contract.NoteGraphNodeRes{
+			ID:           n.ID,
+			CollectionID: n.CollectionID,
+			Title:        n.Title,
+			LinkCount:    linkCounts[n.ID],
+		})
+	}
+
+	return &contract.NoteGraphRes{Nodes: nodes, Edges: edges}, nil
+}
@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"app/internal/model"
+	"app/internal/repository"
+	firebasepkg "app/pkg/firebase"
+	"app/pkg/logger"
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// EmailReminderChannel sends reminders to the user's email address over SMTP
+type EmailReminderChannel struct {
+	emailUsecase *EmailUsecase
+}
+
+func NewEmailReminderChannel(emailUsecase *EmailUsecase) *EmailReminderChannel {
+	return &EmailReminderChannel{emailUsecase: emailUsecase}
+}
+
+func (c *EmailReminderChannel) Name() string {
+	return model.ReminderChannelEmail
+}
+
+func (c *EmailReminderChannel) Send(ctx context.Context, task *model.Task, delivery *model.ReminderDelivery) error {
+	if task.User == nil || task.User.Email == "" {
+		return ErrNoReminderRecipient
+	}
+
+	subject, body := reminderMessage(task, delivery)
+	return c.emailUsecase.SendEmail(task.User.Email, subject, body)
+}
+
+// PushReminderChannel sends reminders to the user's registered devices through Firebase Cloud Messaging
+type PushReminderChannel struct {
+	firebaseUsecase *firebasepkg.FirebaseUsecase
+	reminderRepo    *repository.ReminderRepository
+}
+
+func NewPushReminderChannel(firebaseUsecase *firebasepkg.FirebaseUsecase, reminderRepo *repository.ReminderRepository) *PushReminderChannel {
+	return &PushReminderChannel{
+		firebaseUsecase: firebaseUsecase,
+		reminderRepo:    reminderRepo,
+	}
+}
+
+func (c *PushReminderChannel) Name() string {
+	return model.ReminderChannelPush
+}
+
+func (c *PushReminderChannel) Send(ctx context.Context, task *model.Task, delivery *model.ReminderDelivery) error {
+	tokens, err := c.reminderRepo.ListDeviceTokens(ctx, task.UserID)
+	if err != nil {
+		return err
+	}
+	if len(tokens) == 0 {
+		return ErrNoReminderRecipient
+	}
+
+	title, body := reminderMessage(task, delivery)
+	delivered, staleTokens, err := c.firebaseUsecase.SendPush(ctx, tokens, title, body, map[string]string{
+		"type":    "task_reminder",
+		"taskId":  task.ID,
+		"dueDate": delivery.DueDate.UTC().Format(time.RFC3339),
+	})
+
+	// Devices that uninstalled the app never come back, so stop sending to them
+	if len(staleTokens) > 0 {
+		if deleteErr := c.reminderRepo.DeleteDeviceTokens(ctx, "", staleTokens); deleteErr != nil {
+			logger.Log.Warn("Failed to delete stale device tokens", zap.Error(deleteErr), zap.String("userID", task.UserID))
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+	if delivered == 0 {
+		return ErrNoReminderRecipient
+	}
+	return nil
+}
+
+// reminderMessage returns the title and body of a task reminder
+func reminderMessage(task *model.Task, delivery *model.ReminderDelivery) (string, string) {
+	title := "Untitled task"
+	if task.Title != nil && *task.Title != "" {
+		title = *task.Title
+	}
+
+	due := "Due now"
+	if delivery.OffsetMinutes > 0 {
+		due = "Due in " + formatReminderOffset(delivery.OffsetMinutes)
+	}
+	loc := time.UTC
+	if task.User != nil {
+		loc = task.User.Location()
+	}
+	body := fmt.Sprintf("%s (%s)", due, delivery.DueDate.In(loc).Format("Mon, 02 Jan 2006 15:04 MST"))
+	if task.Project != nil && task.Project.Title != nil {
+		body += " · " + *task.Project.Title
+	}
+
+	return "Reminder: " + title, body
+}
+
+// formatReminderOffset renders minutes in the largest whole unit, e.g. "2 hours"
+func formatReminderOffset(minutes int) string {
+	unit, n := "minute", minutes
+	switch {
+	case minutes%(24*60) == 0:
+		unit, n = "day", minutes/(24*60)
+	case minutes%60 == 0:
+		unit, n = "hour", minutes/60
+	}
+	if n != 1 {
+		unit += "s"
+	}
+	return fmt.Sprintf("%d %s", n, unit)
+}
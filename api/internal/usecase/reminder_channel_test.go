@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"app/internal/model"
+	"app/pkg/util"
+	"testing"
+	"time"
+)
+
+func TestFormatReminderOffset(t *testing.T) {
+	tests := []struct {
+		minutes int
+		want    string
+	}{
+		{1, "1 minute"},
+		{45, "45 minutes"},
+		{60, "1 hour"},
+		{90, "90 minutes"},
+		{120, "2 hours"},
+		{1440, "1 day"},
+		{10080, "7 days"},
+	}
+	for _, tt := range tests {
+		if got := formatReminderOffset(tt.minutes); got != tt.want {
+			t.Errorf("formatReminderOffset(%d) = %q, want %q", tt.minutes, got, tt.want)
+		}
+	}
+}
+
+func TestReminderMessage(t *testing.T) {
+	dueDate := time.Date(2026, 3, 2, 14, 30, 0, 0, time.UTC)
+	task := &model.Task{
+		Title:   util.ToPointer("File taxes"),
+		User:    &model.User{Timezone: "Asia/Jakarta"},
+		Project: &model.Project{Title: util.ToPointer("Home")},
+	}
+
+	title, body := reminderMessage(task, &model.ReminderDelivery{DueDate: dueDate, OffsetMinutes: 60})
+	if title != "Reminder: File taxes" {
+		t.Errorf("title = %q, want the task's title", title)
+	}
+	if want := "Due in 1 hour (Mon, 02 Mar 2026 21:30 WIB) · Home"; body != want {
+		t.Errorf("body = %q, want %q", body, want)
+	}
+
+	title, body = reminderMessage(&model.Task{}, &model.ReminderDelivery{DueDate: dueDate})
+	if title != "Reminder: Untitled task" {
+		t.Errorf("title = %q, want a placeholder for an untitled task", title)
+	}
+	if want := "Due now (Mon, 02 Mar 2026 14:30 UTC)"; body != want {
+		t.Errorf("body = %q, want %q", body, want)
+	}
+}
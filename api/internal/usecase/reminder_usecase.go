@@ -0,0 +1,213 @@
+package usecase
+
+import (
+	"app/internal/contract"
+	"app/internal/model"
+	"app/internal/repository"
+	"app/pkg/logger"
+	"app/pkg/util"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+const (
+	// reminderLookback is how late a reminder may still be sent, e.g. after downtime.
+	// Older reminders are never enqueued.
+	reminderLookback = 24 * time.Hour
+	// reminderStaleAfter is how long a delivery may stay in sending before it is abandoned
+	reminderStaleAfter = 15 * time.Minute
+	reminderBatchSize  = 100
+)
+
+// ErrNoReminderRecipient means a channel had nowhere to deliver a reminder, e.g. no registered devices
+var ErrNoReminderRecipient = errors.New("no reminder recipient")
+
+// ReminderChannel delivers task reminders through one medium
+type ReminderChannel interface {
+	Name() string
+	Send(ctx context.Context, task *model.Task, delivery *model.ReminderDelivery) error
+}
+
+type ReminderUsecase struct {
+	userRepo     *repository.UserRepository
+	reminderRepo *repository.ReminderRepository
+	channels     map[string]ReminderChannel
+}
+
+func NewReminderUsecase(userRepo *repository.UserRepository, reminderRepo *repository.ReminderRepository, channels ...ReminderChannel) *ReminderUsecase {
+	channelsByName := map[string]ReminderChannel{}
+	for _, channel := range channels {
+		channelsByName[channel.Name()] = channel
+	}
+	return &ReminderUsecase{
+		userRepo:     userRepo,
+		reminderRepo: reminderRepo,
+		channels:     channelsByName,
+	}
+}
+
+// DispatchDueReminders enqueues reminders that have fallen due and attempts every delivery that is ready.
+// Returns the number of reminders sent.
+func (u *ReminderUsecase) DispatchDueReminders(ctx context.Context) (int, error) {
+	if _, err := u.reminderRepo.AbandonStaleDeliveries(ctx, reminderStaleAfter); err != nil {
+		return 0, err
+	}
+
+	since := time.Now().Add(-reminderLookback)
+	for name := range u.channels {
+		if _, err := u.reminderRepo.EnqueueDueReminders(ctx, name, since); err != nil {
+			return 0, err
+		}
+	}
+
+	sent := 0
+	for {
+		deliveries, err := u.reminderRepo.ClaimDeliveries(ctx, reminderBatchSize)
+		if err != nil {
+			return sent, err
+		}
+		for i := range deliveries {
+			if u.deliver(ctx, &deliveries[i]) {
+				sent++
+			}
+		}
+		if len(deliveries) < reminderBatchSize {
+			return sent, nil
+		}
+	}
+}
+
+// deliver attempts a claimed delivery and records the outcome. Reports whether the reminder was sent.
+func (u *ReminderUsecase) deliver(ctx context.Context, delivery *model.ReminderDelivery) bool {
+	task, err := u.reminderRepo.GetDeliveryTask(ctx, delivery)
+	if err != nil {
+		u.retryDelivery(ctx, delivery, err)
+		return false
+	}
+	if task == nil {
+		_ = u.reminderRepo.MarkDelivery(ctx, delivery.ID, model.ReminderStatusCancelled,
+			util.ToPointer("task was completed, deleted or rescheduled"), nil)
+		return false
+	}
+
+	channel, ok := u.channels[delivery.Channel]
+	if !ok {
+		u.retryDelivery(ctx, delivery, fmt.Errorf("reminder channel %q is not configured", delivery.Channel))
+		return false
+	}
+
+	err = channel.Send(ctx, task, delivery)
+	if errors.Is(err, ErrNoReminderRecipient) {
+		_ = u.reminderRepo.MarkDelivery(ctx, delivery.ID, model.ReminderStatusSkipped, util.ToPointer(err.Error()), nil)
+		return false
+	}
+	if err != nil {
+		u.retryDelivery(ctx, delivery, err)
+		return false
+	}
+
+	if err := u.reminderRepo.MarkDelivery(ctx, delivery.ID, model.ReminderStatusSent, nil, nil); err != nil {
+		logger.Log.Error("Reminder sent but not recorded", zap.Error(err), zap.String("deliveryID", delivery.ID))
+	}
+	return true
+}
+
+// retryDelivery records a failed attempt and schedules the next one with exponential backoff
+func (u *ReminderUsecase) retryDelivery(ctx context.Context, delivery *model.ReminderDelivery, cause error) {
+	logger.Log.Warn("Failed to deliver reminder",
+		zap.Error(cause),
+		zap.String("deliveryID", delivery.ID),
+		zap.String("channel", delivery.Channel),
+		zap.Int("attempts", delivery.Attempts),
+	)
+
+	// 1, 4, 16, 64 minutes
+	backoff := time.Minute << (2 * (delivery.Attempts - 1))
+	nextAttemptAt := time.Now().Add(backoff)
+	_ = u.reminderRepo.MarkDelivery(ctx, delivery.ID, model.ReminderStatusFailed, util.ToPointer(cause.Error()), &nextAttemptAt)
+}
+
+// GetSettings returns the user's default reminder offsets and the channels reminders are sent through
+func (u *ReminderUsecase) GetSettings(ctx context.Context, userID string) (*contract.ReminderSettingsRes, error) {
+	user, err := u.userRepo.GetUserByID(userID)
+	if err != nil {
+		logger.Log.Error("Failed to get user", zap.Error(err), zap.String("userID", userID))
+		return nil, err
+	}
+	if user == nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, "User not found")
+	}
+
+	offsets := []int(user.ReminderOffsets)
+	if offsets == nil {
+		offsets = []int{}
+	}
+
+	channels := make([]string, 0, len(u.channels))
+	for _, name := range []string{model.ReminderChannelEmail, model.ReminderChannelPush} {
+		if _, ok := u.channels[name]; ok {
+			channels = append(channels, name)
+		}
+	}
+
+	return &contract.ReminderSettingsRes{Offsets: offsets, Channels: channels}, nil
+}
+
+// UpdateSettings replaces the user's default reminder offsets
+func (u *ReminderUsecase) UpdateSettings(ctx context.Context, userID string, req *contract.ReminderSettingsReq) (*contract.ReminderSettingsRes, error) {
+	offsets := req.Offsets
+	if offsets == nil {
+		offsets = []int{}
+	}
+	if err := u.reminderRepo.UpdateUserReminderOffsets(ctx, userID, offsets); err != nil {
+		return nil, err
+	}
+
+	return u.GetSettings(ctx, userID)
+}
+
+// RegisterDevice stores a push token so reminders reach the device
+func (u *ReminderUsecase) RegisterDevice(ctx context.Context, userID string, req *contract.RegisterDeviceReq) error {
+	return u.reminderRepo.RegisterDeviceToken(ctx, userID, req.Token, req.Platform)
+}
+
+// UnregisterDevice stops push reminders to a device, e.g. on sign-out
+func (u *ReminderUsecase) UnregisterDevice(ctx context.Context, userID string, req *contract.UnregisterDeviceReq) error {
+	return u.reminderRepo.DeleteDeviceTokens(ctx, userID, []string{req.Token})
+}
+
+// ListDeliveries returns the user's reminder delivery log
+func (u *ReminderUsecase) ListDeliveries(ctx context.Context, userID string, req *contract.ReminderDeliveriesReq) ([]contract.ReminderDeliveryRes, int64, error) {
+	deliveries, total, err := u.reminderRepo.ListDeliveries(ctx, userID, repository.ReminderDeliveryFilters{
+		TaskID: req.TaskID,
+		Status: req.Status,
+		Limit:  req.Limit,
+		Offset: (req.Page - 1) * req.Limit,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	items := make([]contract.ReminderDeliveryRes, 0, len(deliveries))
+	for _, d := range deliveries {
+		items = append(items, contract.ReminderDeliveryRes{
+			ID:            d.ID,
+			TaskID:        d.TaskID,
+			DueDate:       d.DueDate.UTC().Format(time.RFC3339),
+			OffsetMinutes: d.OffsetMinutes,
+			Channel:       d.Channel,
+			Status:        d.Status,
+			Attempts:      d.Attempts,
+			LastError:     d.LastError,
+			RemindAt:      d.RemindAt.UTC().Format(time.RFC3339),
+			SentAt:        util.TimePtrToStringPtr(d.SentAt, time.RFC3339),
+		})
+	}
+
+	return items, total, nil
+}
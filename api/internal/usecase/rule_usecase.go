@@ -0,0 +1,762 @@
+package usecase
+
+import (
+	"app/internal/contract"
+	"app/internal/model"
+	"app/internal/repository"
+	"app/pkg/logger"
+	"app/pkg/util"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+)
+
+const (
+	// ruleBatchSize is how many overdue tasks a rule fires for per tick
+	ruleBatchSize = 100
+	// ruleActionItemLimit is how many tasks extract_action_items creates from one note at most
+	ruleActionItemLimit = 50
+	// ruleEventStaleAfter is how long a worker has to run the rules of the events it took before
+	// another worker takes them again
+	ruleEventStaleAfter = 5 * time.Minute
+)
+
+// ruleActionItemPattern matches the unchecked items of a Markdown task list, e.g. "- [ ] Call Ana"
+var ruleActionItemPattern = regexp.MustCompile(`(?m)^\s*[-*+]\s+\[ \]\s+(.+?)\s*$`)
+
+type RuleUsecase struct {
+	ruleRepo *repository.RuleRepository
+	syncRepo *repository.SyncRepository
+}
+
+func NewRuleUsecase(ruleRepo *repository.RuleRepository, syncRepo *repository.SyncRepository) *RuleUsecase {
+	return &RuleUsecase{ruleRepo: ruleRepo, syncRepo: syncRepo}
+}
+
+// ruleFiring is a trigger firing for an entity
+type ruleFiring struct {
+	EntityType string
+	EntityID   string
+	ActivityID *string
+	TriggerKey string
+	// Changes is what the change that fired the rule did, which scheduled triggers have none of
+	Changes map[string]model.ActivityChange
+}
+
+// ListRules returns the user's rules of the personal space, for an empty workspaceID, or of a workspace
+func (u *RuleUsecase) ListRules(ctx context.Context, userID, workspaceID string) ([]contract.RuleRes, error) {
+	rules, err := u.ruleRepo.ListRules(ctx, userID, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]contract.RuleRes, 0, len(rules))
+	for i := range rules {
+		items = append(items, toRuleRes(&rules[i]))
+	}
+	return items, nil
+}
+
+// CreateRule creates a rule that runs in the personal space, for an empty workspaceID, or a workspace
+func (u *RuleUsecase) CreateRule(ctx context.Context, userID, workspaceID string, req *contract.CreateRuleReq) (*contract.RuleRes, error) {
+	if !model.IsRuleTrigger(req.Trigger) {
+		return nil, fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("unknown trigger: %s", req.Trigger))
+	}
+	if err := checkRuleConditions(req.Trigger, req.Conditions); err != nil {
+		return nil, err
+	}
+	if err := checkRuleActions(req.Trigger, req.Actions); err != nil {
+		return nil, err
+	}
+	if err := u.checkRuleTargets(ctx, userID, workspaceID, req.Actions); err != nil {
+		return nil, err
+	}
+
+	rule := &model.Rule{
+		UserID:     userID,
+		Name:       req.Name,
+		Trigger:    req.Trigger,
+		Conditions: toRuleConditions(req.Conditions),
+		Actions:    toRuleActions(req.Actions),
+		Active:     true,
+		DryRun:     req.DryRun,
+	}
+	if workspaceID != "" {
+		rule.WorkspaceID = &workspaceID
+	}
+	if err := u.ruleRepo.CreateRule(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	res := toRuleRes(rule)
+	return &res, nil
+}
+
+// UpdateRule changes the fields of a rule that are sent. The trigger stays, since the conditions
+// and actions are checked against it.
+func (u *RuleUsecase) UpdateRule(ctx context.Context, userID, ruleID string, req *contract.UpdateRuleReq) (*contract.RuleRes, error) {
+	rule, err := u.getRule(ctx, userID, ruleID)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]any{}
+	if req.Name != nil {
+		updates["name"] = *req.Name
+	}
+	if req.Conditions != nil {
+		if err := checkRuleConditions(rule.Trigger, *req.Conditions); err != nil {
+			return nil, err
+		}
+		updates["conditions"] = toRuleConditions(*req.Conditions)
+	}
+	if req.Actions != nil {
+		if err := checkRuleActions(rule.Trigger, *req.Actions); err != nil {
+			return nil, err
+		}
+		if err := u.checkRuleTargets(ctx, userID, util.ToValue(rule.WorkspaceID), *req.Actions); err != nil {
+			return nil, err
+		}
+		updates["actions"] = toRuleActions(*req.Actions)
+	}
+	if req.Active != nil {
+		updates["active"] = *req.Active
+	}
+	if req.DryRun != nil {
+		updates["dry_run"] = *req.DryRun
+	}
+
+	err = u.ruleRepo.UpdateRule(ctx, userID, ruleID, updates)
+	if errors.Is(err, repository.ErrRuleNotFound) {
+		return nil, fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rule, err = u.getRule(ctx, userID, ruleID)
+	if err != nil {
+		return nil, err
+	}
+	res := toRuleRes(rule)
+	return &res, nil
+}
+
+// DeleteRule deletes a rule of the user's
+func (u *RuleUsecase) DeleteRule(ctx context.Context, userID, ruleID string) error {
+	err := u.ruleRepo.DeleteRule(ctx, userID, ruleID)
+	if errors.Is(err, repository.ErrRuleNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+	return err
+}
+
+// ListExecutions returns the execution log of a rule of the user's
+func (u *RuleUsecase) ListExecutions(ctx context.Context, userID, ruleID string, req *contract.RuleExecutionsReq) ([]contract.RuleExecutionRes, int64, error) {
+	if _, err := u.getRule(ctx, userID, ruleID); err != nil {
+		return nil, 0, err
+	}
+
+	executions, total, err := u.ruleRepo.ListExecutions(ctx, ruleID, repository.RuleExecutionFilters{
+		Status: req.Status,
+		Limit:  req.Limit,
+		Offset: (req.Page - 1) * req.Limit,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	items := make([]contract.RuleExecutionRes, 0, len(executions))
+	for i := range executions {
+		items = append(items, toRuleExecutionRes(&executions[i]))
+	}
+	return items, total, nil
+}
+
+// RunEventRules runs the rules of the queued activity log entries, whatever made the changes, and
+// takes each entry off the queue once they ran. An entry whose rules fail is retried until
+// model.RuleEventMaxAttempts. Returns the number of entries done.
+func (u *RuleUsecase) RunEventRules(ctx context.Context) (int, error) {
+	events, err := u.ruleRepo.ClaimEvents(ctx, ruleBatchSize, ruleEventStaleAfter)
+	if err != nil {
+		return 0, err
+	}
+
+	done := 0
+	var errs []error
+	for _, event := range events {
+		// The entry is gone if the log was cleared since, and there's nothing left to run
+		if event.Activity != nil {
+			err := u.evaluateActivities(ctx, util.ToValue(event.WorkspaceID), []model.Activity{*event.Activity})
+			if err != nil {
+				errs = append(errs, err)
+				if event.Attempts < model.RuleEventMaxAttempts {
+					continue
+				}
+				logger.Log.Error("Gave up on rule event", zap.Error(err), zap.String("activityID", event.ActivityID))
+			}
+		}
+		if err := u.ruleRepo.DeleteEvent(ctx, event.ID); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		done++
+	}
+	return done, errors.Join(errs...)
+}
+
+// evaluateActivities fires the rules that changes trigger, in the personal space for an empty
+// workspaceID or in a workspace. Changes made by rules don't trigger rules.
+func (u *RuleUsecase) evaluateActivities(ctx context.Context, workspaceID string, activities []model.Activity) error {
+	type event struct {
+		activity *model.Activity
+		trigger  string
+	}
+	var events []event
+	var triggers, userIDs []string
+	for i := range activities {
+		activity := &activities[i]
+		if activity.Source == model.ActivitySourceRule {
+			continue
+		}
+		for _, eventType := range model.ActivityEventTypes(activity) {
+			if !model.IsRuleTrigger(eventType) {
+				continue
+			}
+			events = append(events, event{activity: activity, trigger: eventType})
+			if !slices.Contains(triggers, eventType) {
+				triggers = append(triggers, eventType)
+			}
+		}
+		for _, userID := range []string{activity.ActorID, activity.OwnerID} {
+			if !slices.Contains(userIDs, userID) {
+				userIDs = append(userIDs, userID)
+			}
+		}
+	}
+	if len(events) == 0 {
+		return nil
+	}
+
+	rules, err := u.ruleRepo.ListEventRules(ctx, workspaceID, userIDs, triggers)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, e := range events {
+		for i := range rules {
+			rule := &rules[i]
+			if rule.Trigger != e.trigger {
+				continue
+			}
+			// Personal rules see the changes their user made and the changes made to their items
+			if workspaceID == "" && rule.UserID != e.activity.ActorID && rule.UserID != e.activity.OwnerID {
+				continue
+			}
+			err := u.fire(ctx, rule, ruleFiring{
+				EntityType: e.activity.EntityType,
+				EntityID:   e.activity.EntityID,
+				ActivityID: &e.activity.ID,
+				TriggerKey: e.activity.ID,
+				Changes:    e.activity.Changes.Data(),
+			})
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RunScheduledRules fires the task.overdue rules for the tasks that went past their due date.
+// Returns the number of times rules fired.
+func (u *RuleUsecase) RunScheduledRules(ctx context.Context) (int, error) {
+	rules, err := u.ruleRepo.ListScheduledRules(ctx, model.RuleTriggerTaskOverdue)
+	if err != nil {
+		return 0, err
+	}
+
+	fired := 0
+	var errs []error
+	for i := range rules {
+		targets, err := u.ruleRepo.OverdueTasks(ctx, &rules[i], ruleBatchSize)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, target := range targets {
+			err := u.fire(ctx, &rules[i], ruleFiring{
+				EntityType: "task",
+				EntityID:   target.ID,
+				TriggerKey: target.TriggerKey,
+			})
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			fired++
+		}
+	}
+	return fired, errors.Join(errs...)
+}
+
+// fire runs a rule for an entity once per trigger key and logs the execution. Event rules whose
+// conditions don't hold aren't logged, so the log isn't filled with every edit; scheduled ones are
+// logged as skipped so the entity isn't picked up again. The execution of applied changes is logged
+// in their transaction, so running the rule again never applies them twice. A rule whose changes are
+// rejected is logged as failed without returning an error.
+func (u *RuleUsecase) fire(ctx context.Context, rule *model.Rule, firing ruleFiring) error {
+	snapshot, err := u.ruleRepo.EntitySnapshot(ctx, firing.EntityType, firing.EntityID)
+	if err != nil {
+		return err
+	}
+	if snapshot == nil || snapshot["deleted_at"] != nil {
+		return nil
+	}
+
+	fields, err := u.ruleFields(ctx, rule, firing, snapshot)
+	if err != nil {
+		return err
+	}
+	holds := ruleConditionsHold(rule.Conditions, fields, firing.Changes)
+	if !holds && firing.ActivityID != nil {
+		return nil
+	}
+
+	execution := &model.RuleExecution{
+		RuleID:     rule.ID,
+		Trigger:    rule.Trigger,
+		EntityType: firing.EntityType,
+		EntityID:   firing.EntityID,
+		ActivityID: firing.ActivityID,
+		TriggerKey: firing.TriggerKey,
+		Status:     model.RuleExecutionSkipped,
+		DryRun:     rule.DryRun,
+		Changes:    datatypes.JSON("[]"),
+	}
+	if !holds {
+		_, err := u.ruleRepo.CreateExecution(ctx, execution)
+		return err
+	}
+
+	changes, err := u.ruleChanges(ctx, rule, firing, fields)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		_, err := u.ruleRepo.CreateExecution(ctx, execution)
+		return err
+	}
+	if execution.Changes, err = json.Marshal(changes); err != nil {
+		return err
+	}
+	if rule.DryRun {
+		execution.Status = model.RuleExecutionDryRun
+		_, err := u.ruleRepo.CreateExecution(ctx, execution)
+		return err
+	}
+
+	execution.Status = model.RuleExecutionApplied
+	if _, err := u.syncRepo.ApplyRuleChanges(rule, execution, changes); err != nil {
+		logger.Log.Warn("Failed to apply rule changes", zap.Error(err), zap.String("ruleID", rule.ID), zap.String("entityID", firing.EntityID))
+		execution.ID = ""
+		execution.Status = model.RuleExecutionFailed
+		execution.Error = util.ToPointer(err.Error())
+		_, err := u.ruleRepo.CreateExecution(ctx, execution)
+		return err
+	}
+	return nil
+}
+
+// checkRuleTargets checks that the projects, collections and tags a rule's actions use exist and
+// that the user can use them where the rule runs
+func (u *RuleUsecase) checkRuleTargets(ctx context.Context, userID, workspaceID string, actions []contract.RuleAction) error {
+	for _, action := range actions {
+		if action.ProjectID != nil {
+			found, err := u.ruleRepo.ContainerInScope(ctx, userID, workspaceID, "project_id", *action.ProjectID)
+			if err != nil {
+				return err
+			}
+			if !found {
+				return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("project not found: %s", *action.ProjectID))
+			}
+		}
+		if action.CollectionID != nil {
+			found, err := u.ruleRepo.ContainerInScope(ctx, userID, workspaceID, "collection_id", *action.CollectionID)
+			if err != nil {
+				return err
+			}
+			if !found {
+				return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("collection not found: %s", *action.CollectionID))
+			}
+		}
+		if action.TagID != nil {
+			found, err := u.ruleRepo.HasTag(ctx, userID, *action.TagID)
+			if err != nil {
+				return err
+			}
+			if !found {
+				return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("tag not found: %s", *action.TagID))
+			}
+		}
+	}
+	return nil
+}
+
+// ruleFields returns the columns of the entity the rule's conditions and actions look at, with
+// tag_ids holding its tags
+func (u *RuleUsecase) ruleFields(ctx context.Context, rule *model.Rule, firing ruleFiring, snapshot map[string]any) (map[string]any, error) {
+	needsTags := slices.ContainsFunc(rule.Conditions, func(c model.RuleCondition) bool { return c.Field == "tag_ids" }) ||
+		slices.ContainsFunc(rule.Actions, func(a model.RuleAction) bool { return a.Type == model.RuleActionAddTag })
+	if !needsTags {
+		return snapshot, nil
+	}
+
+	tagIDs, err := u.ruleRepo.EntityTagIDs(ctx, firing.EntityType, firing.EntityID)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]any, 0, len(tagIDs))
+	for _, tagID := range tagIDs {
+		values = append(values, tagID)
+	}
+	fields := make(map[string]any, len(snapshot)+1)
+	for column, value := range snapshot {
+		fields[column] = value
+	}
+	fields["tag_ids"] = values
+	return fields, nil
+}
+
+// ruleChanges returns the sync changes a rule's actions make to an entity, leaving out those that
+// change nothing. A task or note change carries its current project or collection, since a sync
+// change always sets it.
+func (u *RuleUsecase) ruleChanges(ctx context.Context, rule *model.Rule, firing ruleFiring, fields map[string]any) ([]contract.Change, error) {
+	change := contract.Change{Type: firing.EntityType, EntityID: firing.EntityID}
+	if firing.EntityType == "task" {
+		change.ProjectID = ruleFieldString(fields, "project_id")
+	} else {
+		change.CollectionID = ruleFieldString(fields, "collection_id")
+	}
+	tagIDs := ruleFieldStrings(fields, "tag_ids")
+
+	modified := false
+	var extracted []contract.Change
+	for _, action := range rule.Actions {
+		switch action.Type {
+		case model.RuleActionMoveToProject:
+			if util.ToValue(change.ProjectID) != util.ToValue(action.ProjectID) {
+				change.ProjectID = action.ProjectID
+				modified = true
+			}
+		case model.RuleActionMoveToCollection:
+			if util.ToValue(change.CollectionID) != util.ToValue(action.CollectionID) {
+				change.CollectionID = action.CollectionID
+				modified = true
+			}
+		case model.RuleActionAddTag:
+			if !slices.Contains(tagIDs, util.ToValue(action.TagID)) {
+				tagIDs = append(tagIDs, util.ToValue(action.TagID))
+				change.TagIDs = &tagIDs
+				modified = true
+			}
+		case model.RuleActionSetStatus:
+			code, _ := model.StatusCategoryCode(util.ToValue(action.Status))
+			if ruleFieldInt(fields, "status") != code {
+				change.Status = &code
+				modified = true
+			}
+		case model.RuleActionSetPriority:
+			if ruleFieldInt(fields, "priority") != util.ToValue(action.Priority) {
+				change.Priority = action.Priority
+				modified = true
+			}
+		case model.RuleActionExtractActionItems:
+			items, err := u.actionItemChanges(ctx, rule, firing.EntityID, util.ToValue(ruleFieldString(fields, "content")), action.ProjectID)
+			if err != nil {
+				return nil, err
+			}
+			extracted = append(extracted, items...)
+		}
+	}
+
+	var changes []contract.Change
+	if modified {
+		changes = append(changes, change)
+	}
+	return append(changes, extracted...), nil
+}
+
+// actionItemChanges returns a new task for each unchecked item of a note's task list. The IDs are
+// derived from the rule, the note and the item, so an item makes its task once, and not again
+// after the task is deleted.
+func (u *RuleUsecase) actionItemChanges(ctx context.Context, rule *model.Rule, noteID, content string, projectID *string) ([]contract.Change, error) {
+	namespace, err := uuid.Parse(rule.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []contract.Change
+	var taskIDs []string
+	for _, match := range ruleActionItemPattern.FindAllStringSubmatch(content, -1) {
+		title := match[1]
+		taskID := uuid.NewSHA1(namespace, []byte(noteID+"\n"+title)).String()
+		if slices.Contains(taskIDs, taskID) {
+			continue
+		}
+		taskIDs = append(taskIDs, taskID)
+		changes = append(changes, contract.Change{
+			Type:      "task",
+			EntityID:  taskID,
+			Title:     &title,
+			ProjectID: projectID,
+		})
+		if len(changes) == ruleActionItemLimit {
+			break
+		}
+	}
+
+	existing, err := u.ruleRepo.ExistingTaskIDs(ctx, taskIDs)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(changes, func(c contract.Change) bool {
+		return slices.Contains(existing, c.EntityID)
+	}), nil
+}
+
+// getRule returns a live rule of the user's, or a 404
+func (u *RuleUsecase) getRule(ctx context.Context, userID, ruleID string) (*model.Rule, error) {
+	rule, err := u.ruleRepo.GetRule(ctx, userID, ruleID)
+	if err != nil {
+		return nil, err
+	}
+	if rule == nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, repository.ErrRuleNotFound.Error())
+	}
+	return rule, nil
+}
+
+// ruleConditionsHold reports whether every condition holds for an entity's fields and the change
+// that fired the rule
+func ruleConditionsHold(conditions []model.RuleCondition, fields map[string]any, changes map[string]model.ActivityChange) bool {
+	for _, condition := range conditions {
+		value := fields[condition.Field]
+		change, changed := changes[condition.Field]
+
+		var holds bool
+		switch condition.Op {
+		case model.RuleOpEq:
+			holds = ruleValuesEqual(value, condition.Value)
+		case model.RuleOpNeq:
+			holds = !ruleValuesEqual(value, condition.Value)
+		case model.RuleOpContains:
+			holds = ruleValueContains(value, condition.Value)
+		case model.RuleOpEmpty:
+			holds = ruleValueEmpty(value)
+		case model.RuleOpNotEmpty:
+			holds = !ruleValueEmpty(value)
+		case model.RuleOpChanged:
+			holds = changed
+		case model.RuleOpChangedTo:
+			holds = changed && ruleValuesEqual(change.To, condition.Value)
+		}
+		if !holds {
+			return false
+		}
+	}
+	return true
+}
+
+// ruleValuesEqual compares two JSON values, so 4 and 4.0 are equal
+func ruleValuesEqual(a, b any) bool {
+	aJSON, aErr := json.Marshal(a)
+	bJSON, bErr := json.Marshal(b)
+	return aErr == nil && bErr == nil && string(aJSON) == string(bJSON)
+}
+
+// ruleValueContains reports whether a text contains a string, ignoring case, or a list holds a value
+func ruleValueContains(value, part any) bool {
+	switch v := value.(type) {
+	case string:
+		s, ok := part.(string)
+		return ok && strings.Contains(strings.ToLower(v), strings.ToLower(s))
+	case []any:
+		return slices.ContainsFunc(v, func(item any) bool { return ruleValuesEqual(item, part) })
+	}
+	return false
+}
+
+func ruleValueEmpty(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(v) == ""
+	case []any:
+		return len(v) == 0
+	case map[string]any:
+		return len(v) == 0
+	}
+	return false
+}
+
+func ruleFieldString(fields map[string]any, field string) *string {
+	value, ok := fields[field].(string)
+	if !ok {
+		return nil
+	}
+	return &value
+}
+
+func ruleFieldInt(fields map[string]any, field string) int {
+	value, _ := fields[field].(float64)
+	return int(value)
+}
+
+func ruleFieldStrings(fields map[string]any, field string) []string {
+	values, _ := fields[field].([]any)
+	strs := make([]string, 0, len(values))
+	for _, value := range values {
+		if s, ok := value.(string); ok {
+			strs = append(strs, s)
+		}
+	}
+	return strs
+}
+
+// checkRuleConditions checks every condition has the value its operator needs, and that a
+// scheduled trigger isn't given conditions on a change
+func checkRuleConditions(trigger string, conditions []contract.RuleCondition) error {
+	for _, condition := range conditions {
+		switch condition.Op {
+		case model.RuleOpEq, model.RuleOpNeq, model.RuleOpContains, model.RuleOpChangedTo:
+			if condition.Value == nil {
+				return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("%s condition on %s needs a value", condition.Op, condition.Field))
+			}
+		}
+		switch condition.Op {
+		case model.RuleOpChanged, model.RuleOpChangedTo:
+			if trigger == model.RuleTriggerTaskOverdue {
+				return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("%s conditions don't apply to %s rules", condition.Op, trigger))
+			}
+		}
+	}
+	return nil
+}
+
+// checkRuleActions checks every action applies to the trigger's entities and has the parameter it needs
+func checkRuleActions(trigger string, actions []contract.RuleAction) error {
+	for _, action := range actions {
+		if !model.RuleActionFits(action.Type, trigger) {
+			return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("%s doesn't apply to %s rules", action.Type, trigger))
+		}
+
+		missing := ""
+		switch action.Type {
+		case model.RuleActionMoveToProject:
+			if action.ProjectID == nil {
+				missing = "projectId"
+			}
+		case model.RuleActionMoveToCollection:
+			if action.CollectionID == nil {
+				missing = "collectionId"
+			}
+		case model.RuleActionAddTag:
+			if action.TagID == nil {
+				missing = "tagId"
+			}
+		case model.RuleActionSetStatus:
+			if action.Status == nil {
+				missing = "status"
+			}
+		case model.RuleActionSetPriority:
+			if action.Priority == nil {
+				missing = "priority"
+			}
+		}
+		if missing != "" {
+			return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("%s needs %s", action.Type, missing))
+		}
+	}
+	return nil
+}
+
+func toRuleConditions(conditions []contract.RuleCondition) datatypes.JSONSlice[model.RuleCondition] {
+	items := make(datatypes.JSONSlice[model.RuleCondition], 0, len(conditions))
+	for _, condition := range conditions {
+		items = append(items, model.RuleCondition{Field: condition.Field, Op: condition.Op, Value: condition.Value})
+	}
+	return items
+}
+
+func toRuleActions(actions []contract.RuleAction) datatypes.JSONSlice[model.RuleAction] {
+	items := make(datatypes.JSONSlice[model.RuleAction], 0, len(actions))
+	for _, action := range actions {
+		items = append(items, model.RuleAction{
+			Type:         action.Type,
+			ProjectID:    action.ProjectID,
+			CollectionID: action.CollectionID,
+			TagID:        action.TagID,
+			Status:       action.Status,
+			Priority:     action.Priority,
+		})
+	}
+	return items
+}
+
+func toRuleRes(rule *model.Rule) contract.RuleRes {
+	conditions := make([]contract.RuleCondition, 0, len(rule.Conditions))
+	for _, condition := range rule.Conditions {
+		conditions = append(conditions, contract.RuleCondition{Field: condition.Field, Op: condition.Op, Value: condition.Value})
+	}
+	actions := make([]contract.RuleAction, 0, len(rule.Actions))
+	for _, action := range rule.Actions {
+		actions = append(actions, contract.RuleAction{
+			Type:         action.Type,
+			ProjectID:    action.ProjectID,
+			CollectionID: action.CollectionID,
+			TagID:        action.TagID,
+			Status:       action.Status,
+			Priority:     action.Priority,
+		})
+	}
+	return contract.RuleRes{
+		ID:          rule.ID,
+		Name:        rule.Name,
+		Trigger:     rule.Trigger,
+		Conditions:  conditions,
+		Actions:     actions,
+		WorkspaceID: rule.WorkspaceID,
+		Active:      rule.Active,
+		DryRun:      rule.DryRun,
+		CreatedAt:   rule.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:   rule.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+func toRuleExecutionRes(execution *model.RuleExecution) contract.RuleExecutionRes {
+	return contract.RuleExecutionRes{
+		ID:         execution.ID,
+		Trigger:    execution.Trigger,
+		EntityType: execution.EntityType,
+		EntityID:   execution.EntityID,
+		ActivityID: execution.ActivityID,
+		Status:     execution.Status,
+		DryRun:     execution.DryRun,
+		Changes:    json.RawMessage(execution.Changes),
+		Error:      execution.Error,
+		CreatedAt:  execution.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"app/internal/contract"
+	"app/internal/repository"
+	"app/pkg/logger"
+	"app/pkg/openai"
+	"app/pkg/util"
+	"context"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type SearchUsecase struct {
+	searchRepo   *repository.SearchRepository
+	openaiClient *openai.OpenAIClient
+}
+
+func NewSearchUsecase(searchRepo *repository.SearchRepository, openaiClient *openai.OpenAIClient) *SearchUsecase {
+	return &SearchUsecase{
+		searchRepo:   searchRepo,
+		openaiClient: openaiClient,
+	}
+}
+
+// Search runs a hybrid lexical + semantic search over the user's notes, tasks and comments
+func (u *SearchUsecase) Search(ctx context.Context, userID, workspaceID string, req *contract.SearchReq) (*contract.SearchRes, error) {
+	filters := repository.SearchFilters{
+		WorkspaceID: workspaceID,
+		Query:       req.Query,
+		Limit:       req.Limit,
+		Offset:      (req.Page - 1) * req.Limit,
+	}
+
+	if req.Types != "" {
+		for _, t := range strings.Split(req.Types, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				filters.Types = append(filters.Types, t)
+			}
+		}
+	}
+
+	if req.Tags != "" {
+		filters.Tags = util.NormalizeTagNames(strings.Split(req.Tags, ","))
+	}
+
+	// Semantic ranking is best-effort; lexical results are still useful without it
+	embedding, err := u.openaiClient.GenerateEmbedding(ctx, req.Query)
+	if err != nil {
+		logger.Log.Warn("Failed to generate embedding for search, falling back to lexical only", zap.Error(err))
+	} else {
+		filters.QueryEmbedding = embedding
+	}
+
+	results, err := u.searchRepo.Search(ctx, userID, filters)
+	if err != nil {
+		logger.Log.Error("Failed to search", zap.Error(err), zap.String("userID", userID))
+		return nil, err
+	}
+
+	items := make([]contract.SearchItemRes, 0, len(results))
+	for _, result := range results {
+		items = append(items, contract.SearchItemRes{
+			Type:      result.Type,
+			EntityID:  result.ID,
+			Title:     result.Title,
+			Snippet:   result.Snippet,
+			Score:     result.Score,
+			UpdatedAt: result.UpdatedAt.UTC().Format(time.RFC3339),
+			TaskID:    result.TaskID,
+			NoteID:    result.NoteID,
+		})
+	}
+
+	return &contract.SearchRes{Items: items}, nil
+}
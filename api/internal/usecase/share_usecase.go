@@ -0,0 +1,365 @@
+package usecase
+
+import (
+	"app/internal/config"
+	"app/internal/contract"
+	"app/internal/model"
+	"app/internal/repository"
+	"app/pkg/logger"
+	"app/pkg/markdown"
+	"app/pkg/util"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html/template"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+const (
+	shareTypeNote       = "note"
+	shareTypeCollection = "collection"
+
+	// shareCollectionNoteLimit caps how many notes a shared collection shows
+	shareCollectionNoteLimit = 500
+	// shareHTMLCacheBytes caps the rendered notes kept between views of shared pages
+	shareHTMLCacheBytes = 32 << 20
+)
+
+var (
+	errSharePasswordRequired = fiber.NewError(fiber.StatusUnauthorized, "This link needs a password")
+	errSharePasswordWrong    = fiber.NewError(fiber.StatusUnauthorized, "Wrong password")
+)
+
+type ShareUsecase struct {
+	shareRepo *repository.ShareRepository
+	htmlCache *markdown.Cache
+}
+
+func NewShareUsecase(shareRepo *repository.ShareRepository) *ShareUsecase {
+	return &ShareUsecase{shareRepo: shareRepo, htmlCache: markdown.NewCache(shareHTMLCacheBytes)}
+}
+
+// CreateLink creates a public link to one of the user's notes or collections. The URL is only
+// returned here, as only a hash of its token is kept.
+func (u *ShareUsecase) CreateLink(ctx context.Context, userID string, req *contract.CreateShareLinkReq) (*contract.ShareLinkRes, error) {
+	if (req.NoteID == nil) == (req.CollectionID == nil) {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "Either noteId or collectionId is required")
+	}
+
+	link := &model.ShareLink{UserID: userID, NoteID: req.NoteID, CollectionID: req.CollectionID}
+	if req.ExpiresAt != nil {
+		expiresAt, _ := time.Parse(time.RFC3339, *req.ExpiresAt)
+		if !expiresAt.After(time.Now()) {
+			return nil, fiber.NewError(fiber.StatusBadRequest, "expiresAt must be in the future")
+		}
+		link.ExpiresAt = &expiresAt
+	}
+	if req.Password != nil && *req.Password != "" {
+		passwordHash, err := util.HashPassword(*req.Password)
+		if err != nil {
+			logger.Log.Error("Failed to hash share link password", zap.Error(err))
+			return nil, err
+		}
+		link.PasswordHash = &passwordHash
+	}
+
+	token, err := newSecretToken()
+	if err != nil {
+		logger.Log.Error("Failed to generate share link token", zap.Error(err))
+		return nil, err
+	}
+	link.TokenHash = hashSecretToken(token)
+
+	if err := u.shareRepo.CreateLink(ctx, link); err != nil {
+		if errors.Is(err, repository.ErrShareTargetNotFound) {
+			return nil, fiber.NewError(fiber.StatusNotFound, "Note or collection not found")
+		}
+		return nil, err
+	}
+
+	created, err := u.shareRepo.GetLink(ctx, userID, link.ID)
+	if err != nil {
+		return nil, err
+	}
+	if created == nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Share link not found")
+	}
+
+	res := toShareLinkRes(created)
+	res.URL = util.ToPointer(fmt.Sprintf("%s/v1/shared/%s", strings.TrimRight(config.Env.App.BaseURL, "/"), token))
+	return &res, nil
+}
+
+// ListLinks returns the user's links that aren't revoked, including expired ones
+func (u *ShareUsecase) ListLinks(ctx context.Context, userID string, req *contract.ListShareLinksReq) (*contract.ShareLinksRes, error) {
+	links, err := u.shareRepo.ListLinks(ctx, userID, req.NoteID, req.CollectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &contract.ShareLinksRes{Items: make([]contract.ShareLinkRes, 0, len(links))}
+	for i := range links {
+		res.Items = append(res.Items, toShareLinkRes(&links[i]))
+	}
+	return res, nil
+}
+
+// RevokeLink makes a link stop working for good
+func (u *ShareUsecase) RevokeLink(ctx context.Context, userID, linkID string) error {
+	revoked, err := u.shareRepo.RevokeLink(ctx, userID, linkID)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return fiber.NewError(fiber.StatusNotFound, "Share link not found")
+	}
+	return nil
+}
+
+// ViewShare returns what a link shares. Links that are revoked, expired or whose note or collection
+// is gone are not found. The password is only checked for links that have one.
+func (u *ShareUsecase) ViewShare(ctx context.Context, token string, password *string) (*contract.SharedContentRes, error) {
+	link, err := u.shareRepo.GetLinkByToken(ctx, hashSecretToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if link == nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, "Share link not found")
+	}
+
+	if link.PasswordHash != nil {
+		if password == nil || *password == "" {
+			return nil, errSharePasswordRequired
+		}
+		if !util.CheckPasswordHash(*password, *link.PasswordHash) {
+			return nil, errSharePasswordWrong
+		}
+	}
+
+	res := &contract.SharedContentRes{ExpiresAt: util.TimePtrToStringPtr(link.ExpiresAt, time.RFC3339)}
+	if link.NoteID != nil {
+		note, err := u.shareRepo.GetSharedNote(ctx, link.UserID, *link.NoteID)
+		if err != nil {
+			return nil, err
+		}
+		if note == nil {
+			return nil, fiber.NewError(fiber.StatusNotFound, "Share link not found")
+		}
+		res.Type = shareTypeNote
+		res.Note = util.ToPointer(u.toSharedNoteRes(note, "", markdown.Options{}))
+	} else {
+		collection, err := u.shareRepo.GetSharedCollection(ctx, link.UserID, *link.CollectionID, shareCollectionNoteLimit)
+		if err != nil {
+			return nil, err
+		}
+		if collection == nil {
+			return nil, fiber.NewError(fiber.StatusNotFound, "Share link not found")
+		}
+		res.Type = shareTypeCollection
+		res.Collection = u.toSharedCollectionRes(collection)
+	}
+
+	if err := u.shareRepo.RecordView(ctx, link.ID); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// RenderSharePage renders shared content as a standalone web page
+func (u *ShareUsecase) RenderSharePage(content *contract.SharedContentRes) (string, error) {
+	var b strings.Builder
+	if err := sharePageTemplate.Execute(&b, content); err != nil {
+		logger.Log.Error("Failed to render share page", zap.Error(err))
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// RenderSharePasswordPage renders the form that asks for a link's password. message says why it is asked again.
+func (u *ShareUsecase) RenderSharePasswordPage(message string) (string, error) {
+	var b strings.Builder
+	if err := sharePasswordTemplate.Execute(&b, message); err != nil {
+		logger.Log.Error("Failed to render share password page", zap.Error(err))
+		return "", err
+	}
+	return b.String(), nil
+}
+
+func toShareLinkRes(link *model.ShareLink) contract.ShareLinkRes {
+	res := contract.ShareLinkRes{
+		ID:           link.ID,
+		Type:         shareTypeNote,
+		NoteID:       link.NoteID,
+		CollectionID: link.CollectionID,
+		HasPassword:  link.PasswordHash != nil,
+		ExpiresAt:    util.TimePtrToStringPtr(link.ExpiresAt, time.RFC3339),
+		Expired:      link.Expired(time.Now()),
+		ViewCount:    link.ViewCount,
+		LastViewedAt: util.TimePtrToStringPtr(link.LastViewedAt, time.RFC3339),
+		CreatedAt:    link.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if link.Note != nil {
+		res.Title = link.Note.Title
+	}
+	if link.CollectionID != nil {
+		res.Type = shareTypeCollection
+		if link.Collection != nil {
+			res.Title = &link.Collection.Title
+		}
+	}
+	return res
+}
+
+// toSharedNoteRes renders a note, reusing the HTML of the same version of it rendered with the
+// same wikilink targets, which linksKey identifies
+func (u *ShareUsecase) toSharedNoteRes(note *model.Note, linksKey string, opts markdown.Options) contract.SharedNoteRes {
+	tags := make([]string, 0, len(note.Tags))
+	for _, tag := range note.Tags {
+		tags = append(tags, tag.Name)
+	}
+	cacheKey := fmt.Sprintf("%s:%d:%s", note.ID, note.UpdatedAt.UnixNano(), linksKey)
+	return contract.SharedNoteRes{
+		Title:     note.Title,
+		Content:   note.Content,
+		HTML:      u.htmlCache.ToHTML(cacheKey, util.ToValue(note.Content), opts),
+		Tags:      tags,
+		CreatedAt: note.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt: note.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+// toSharedCollectionRes renders the collection's notes. Wikilinks between them point at the
+// linked note on the same page, as #note-<position>.
+func (u *ShareUsecase) toSharedCollectionRes(collection *model.Collection) *contract.SharedCollectionRes {
+	anchors := map[string]string{}
+	for i, note := range collection.Notes {
+		title := strings.ToLower(strings.TrimSpace(util.ToValue(note.Title)))
+		if _, ok := anchors[title]; title != "" && !ok {
+			anchors[title] = fmt.Sprintf("#note-%d", i+1)
+		}
+	}
+	opts := markdown.Options{
+		WikilinkHref: func(target string) string {
+			return anchors[strings.ToLower(target)]
+		},
+	}
+
+	res := &contract.SharedCollectionRes{
+		Title:       collection.Title,
+		Description: collection.Description,
+		Notes:       make([]contract.SharedNoteRes, 0, len(collection.Notes)),
+	}
+	linksKey := anchorsKey(anchors)
+	for i := range collection.Notes {
+		res.Notes = append(res.Notes, u.toSharedNoteRes(&collection.Notes[i], linksKey, opts))
+	}
+	return res
+}
+
+// anchorsKey identifies a set of wikilink anchors, so notes rendered with other anchors aren't reused
+func anchorsKey(anchors map[string]string) string {
+	hash := sha256.New()
+	for _, title := range slices.Sorted(maps.Keys(anchors)) {
+		fmt.Fprintf(hash, "%s\x00%s\x00", title, anchors[title])
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// shareStyle is the stylesheet of shared pages, inlined so they need nothing else
+const shareStyle = `
+body { margin: 0; background: #fafafa; color: #1f2328; font: 16px/1.6 -apple-system, BlinkMacSystemFont, "Segoe UI", Roboto, sans-serif; }
+main { max-width: 46rem; margin: 0 auto; padding: 2.5rem 1.25rem 4rem; }
+article { background: #fff; border: 1px solid #e5e7eb; border-radius: 8px; padding: 1.5rem 2rem; margin-bottom: 1.5rem; }
+h1, h2, h3, h4, h5, h6 { line-height: 1.25; }
+a { color: #0969da; }
+pre { background: #f6f8fa; padding: 1rem; border-radius: 6px; overflow-x: auto; }
+code { background: #f6f8fa; padding: .1em .3em; border-radius: 4px; font-size: .9em; }
+pre code { padding: 0; }
+blockquote { margin: 0; padding-left: 1rem; border-left: 4px solid #d0d7de; color: #57606a; }
+img { max-width: 100%; }
+mark { background: #fff3b0; }
+.wikilink { color: #57606a; border-bottom: 1px dashed #8c959f; }
+.meta { color: #57606a; font-size: .875rem; }
+.tag { display: inline-block; background: #eef1f4; border-radius: 999px; padding: 0 .6rem; margin-right: .3rem; }
+footer { color: #8c959f; font-size: .8rem; text-align: center; }
+form { display: flex; gap: .5rem; }
+input[type=password] { flex: 1; padding: .5rem; font-size: 1rem; border: 1px solid #d0d7de; border-radius: 6px; }
+button { padding: .5rem 1rem; font-size: 1rem; border: 0; border-radius: 6px; background: #1f2328; color: #fff; cursor: pointer; }
+.error { color: #cf222e; }
+`
+
+// shareTemplateFuncs are the helpers of the shared page templates
+var shareTemplateFuncs = template.FuncMap{
+	"position": func(i int) int { return i + 1 },
+	// Note HTML comes from the Markdown renderer, which escapes everything it doesn't write itself
+	"rendered": func(s string) template.HTML { return template.HTML(s) },
+	"date": func(s string) string {
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return s
+		}
+		return t.Format("Jan 2, 2006")
+	},
+}
+
+var sharePageTemplate = template.Must(template.New("share").Funcs(shareTemplateFuncs).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex, nofollow">
+<title>{{with .Note}}{{or .Title "Untitled note"}}{{end}}{{with .Collection}}{{.Title}}{{end}} · Memr</title>
+<style>` + shareStyle + `</style>
+</head>
+<body>
+<main>
+{{with .Note}}{{template "note" .}}{{end}}
+{{with .Collection}}
+<header>
+<h1>{{.Title}}</h1>
+{{with .Description}}<p class="meta">{{.}}</p>{{end}}
+{{if .Notes}}<ul>{{range $i, $note := .Notes}}<li><a href="#note-{{position $i}}">{{or $note.Title "Untitled note"}}</a></li>{{end}}</ul>{{else}}<p class="meta">This collection has no notes.</p>{{end}}
+</header>
+{{range $i, $note := .Notes}}<div id="note-{{position $i}}">{{template "note" $note}}</div>{{end}}
+{{end}}
+<footer>Shared from Memr</footer>
+</main>
+</body>
+</html>
+{{define "note"}}<article>
+<h1>{{or .Title "Untitled note"}}</h1>
+<p class="meta">{{range .Tags}}<span class="tag">#{{.}}</span>{{end}}Updated <time datetime="{{.UpdatedAt}}">{{date .UpdatedAt}}</time></p>
+{{rendered .HTML}}
+</article>{{end}}`))
+
+var sharePasswordTemplate = template.Must(template.New("share-password").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex, nofollow">
+<title>Password required · Memr</title>
+<style>` + shareStyle + `</style>
+</head>
+<body>
+<main>
+<article>
+<h1>Password required</h1>
+<p>Enter the password you were given to open this link.</p>
+{{with .}}<p class="error">{{.}}</p>{{end}}
+<form method="post">
+<input type="password" name="password" autocomplete="current-password" autofocus required>
+<button type="submit">Open</button>
+</form>
+</article>
+</main>
+</body>
+</html>`))
@@ -4,6 +4,7 @@ import (
 	"app/internal/contract"
 	"app/internal/repository"
 	"app/pkg/logger"
+	"errors"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -18,15 +19,42 @@ func NewSyncUsecase(syncRepo *repository.SyncRepository) *SyncUsecase {
 	return &SyncUsecase{syncRepo: syncRepo}
 }
 
-func (u *SyncUsecase) Sync(c *fiber.Ctx, userID string, req *contract.SyncReq) (res *contract.SyncRes, err error) {
-	logger.Log.Info("Syncing data", zap.String("userID", userID), zap.Any("req", req))
-	lastSyncTime, changes, err := u.syncRepo.Sync(userID, req)
+// Sync syncs the personal space, for an empty workspaceID, or a workspace the user is a member of.
+// The rules the changes trigger run in the background; what they change comes with a later sync.
+func (u *SyncUsecase) Sync(c *fiber.Ctx, userID, workspaceID string, req *contract.SyncReq) (res *contract.SyncRes, err error) {
+	logger.Log.Info("Syncing data", zap.String("userID", userID), zap.String("workspaceID", workspaceID), zap.Any("req", req))
+	lastSyncTime, changes, rejected, err := u.syncRepo.Sync(userID, workspaceID, req)
 	if err != nil {
 		logger.Log.Error("Failed to sync data", zap.Error(err))
+		if isSyncValidationError(err) {
+			return nil, fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		if errors.Is(err, repository.ErrWorkspaceAccess) {
+			return nil, fiber.NewError(fiber.StatusForbidden, err.Error())
+		}
 		return nil, err
 	}
+
 	return &contract.SyncRes{
 		Changes:      changes,
 		LastSyncTime: lastSyncTime.UTC().Format(time.RFC3339),
+		Rejected:     rejected,
 	}, nil
 }
+
+// isSyncValidationError reports whether a sync failed because of the client's changes rather than the server
+func isSyncValidationError(err error) bool {
+	return errors.Is(err, repository.ErrTaskParentNotFound) ||
+		errors.Is(err, repository.ErrTaskCycle) ||
+		errors.Is(err, repository.ErrTaskTooDeep) ||
+		errors.Is(err, repository.ErrTaskDependencyNotFound) ||
+		errors.Is(err, repository.ErrTaskDependencyCycle) ||
+		errors.Is(err, repository.ErrStatusNotFound) ||
+		errors.Is(err, repository.ErrStatusProjectNotFound) ||
+		errors.Is(err, repository.ErrStatusIncomplete) ||
+		errors.Is(err, repository.ErrInvalidRecurrenceRule) ||
+		errors.Is(err, repository.ErrWorkspaceItemOutside) ||
+		errors.Is(err, repository.ErrCommentIncomplete) ||
+		errors.Is(err, repository.ErrCommentParentNotFound) ||
+		errors.Is(err, repository.ErrTagNameRequired)
+}
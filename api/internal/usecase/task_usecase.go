@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"app/internal/contract"
+	"app/internal/repository"
+	"context"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type TaskUsecase struct {
+	taskRepo *repository.TaskRepository
+}
+
+func NewTaskUsecase(taskRepo *repository.TaskRepository) *TaskUsecase {
+	return &TaskUsecase{taskRepo: taskRepo}
+}
+
+// MoveTask places a task between two of its siblings. The new sort order, and any keys
+// rewritten to make room, reach other devices through sync.
+func (u *TaskUsecase) MoveTask(ctx context.Context, userID, taskID string, req *contract.MoveTaskReq) (*contract.MoveTaskRes, error) {
+	sortOrder, err := u.taskRepo.MoveTask(ctx, userID, taskID, req.AfterTaskID, req.BeforeTaskID)
+	switch {
+	case errors.Is(err, repository.ErrTaskNotFound):
+		return nil, fiber.NewError(fiber.StatusNotFound, err.Error())
+	case errors.Is(err, repository.ErrTaskMoveNotInList), errors.Is(err, repository.ErrTaskMoveOrder):
+		return nil, fiber.NewError(fiber.StatusBadRequest, err.Error())
+	case err != nil:
+		return nil, err
+	}
+
+	return &contract.MoveTaskRes{TaskID: taskID, SortOrder: sortOrder}, nil
+}
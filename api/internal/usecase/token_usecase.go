@@ -13,15 +13,16 @@ func NewTokenUsecase() *TokenUsecase {
 	return &TokenUsecase{}
 }
 
-func (u *TokenUsecase) GenerateTokenPair(userID string) (*contract.TokenRes, error) {
+// GenerateTokenPair issues tokens for the user with an active workspace, or an empty one for the personal space
+func (u *TokenUsecase) GenerateTokenPair(userID, workspaceID string) (*contract.TokenRes, error) {
 	accessExpiresAt := time.Now().Add(time.Duration(config.Env.JWT.AccessExpMinutes) * time.Minute)
-	accessToken, err := util.GenerateToken(userID, "", config.TokenTypeAccess, config.Env.JWT.Secret, accessExpiresAt)
+	accessToken, err := util.GenerateToken(userID, "", workspaceID, config.TokenTypeAccess, config.Env.JWT.Secret, accessExpiresAt)
 	if err != nil {
 		return nil, err
 	}
 
 	refreshExpiresAt := time.Now().Add(time.Duration(config.Env.JWT.RefreshExpDays) * 24 * time.Hour)
-	refreshToken, err := util.GenerateToken(userID, "", config.TokenTypeRefresh, config.Env.JWT.Secret, refreshExpiresAt)
+	refreshToken, err := util.GenerateToken(userID, "", workspaceID, config.TokenTypeRefresh, config.Env.JWT.Secret, refreshExpiresAt)
 	if err != nil {
 		return nil, err
 	}
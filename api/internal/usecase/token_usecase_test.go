@@ -0,0 +1,40 @@
+package usecase
+
+import (
+	"app/internal/config"
+	"app/pkg/util"
+	"testing"
+)
+
+func TestTokenPairKeepsTheWorkspace(t *testing.T) {
+	jwt := config.Env.JWT
+	t.Cleanup(func() { config.Env.JWT = jwt })
+	config.Env.JWT.Secret = "test-secret"
+	config.Env.JWT.AccessExpMinutes = 15
+	config.Env.JWT.RefreshExpDays = 30
+
+	u := NewTokenUsecase()
+	for _, workspaceID := range []string{"", "7f1c2f0e-4b8a-4f4e-9a59-3f0f6d3c2a11"} {
+		tokens, err := u.GenerateTokenPair("user-1", workspaceID)
+		if err != nil {
+			t.Fatalf("failed to generate tokens: %v", err)
+		}
+
+		// A refresh makes the new pair from the refresh token's claims
+		claims, err := util.VerifyToken(tokens.RefreshToken, config.Env.JWT.Secret)
+		if err != nil || claims.Type != config.TokenTypeRefresh || claims.WorkspaceID != workspaceID {
+			t.Fatalf("refresh token claims = %+v (%v), want a refresh token for workspace %q", claims, err, workspaceID)
+		}
+		refreshed, err := u.GenerateTokenPair(claims.ID, claims.WorkspaceID)
+		if err != nil {
+			t.Fatalf("failed to refresh tokens: %v", err)
+		}
+
+		for _, token := range []string{refreshed.AccessToken, refreshed.RefreshToken} {
+			claims, err := util.VerifyToken(token, config.Env.JWT.Secret)
+			if err != nil || claims.ID != "user-1" || claims.WorkspaceID != workspaceID {
+				t.Errorf("refreshed token claims = %+v (%v), want user-1 in workspace %q", claims, err, workspaceID)
+			}
+		}
+	}
+}
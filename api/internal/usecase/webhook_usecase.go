@@ -0,0 +1,471 @@
+package usecase
+
+import (
+	"app/internal/config"
+	"app/internal/contract"
+	"app/internal/model"
+	"app/internal/repository"
+	"app/pkg/logger"
+	"app/pkg/util"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+	"gorm.io/datatypes"
+)
+
+const (
+	// webhookStaleAfter is how long a delivery may stay in sending before it is abandoned
+	webhookStaleAfter = 15 * time.Minute
+	webhookBatchSize  = 100
+	webhookTimeout    = 10 * time.Second
+	// webhookResponseLimit is how much of an endpoint's response the delivery log keeps
+	webhookResponseLimit = 2048
+)
+
+var errWebhookPrivateAddress = errors.New("webhooks can't post to loopback, private, link-local or other non-public addresses")
+
+// webhookBlockedPrefixes are the ranges of the IANA special-purpose address registries that aren't
+// reachable on the public internet, or that translate to addresses which may not be
+var webhookBlockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // this network
+	netip.MustParsePrefix("10.0.0.0/8"),      // private
+	netip.MustParsePrefix("100.64.0.0/10"),   // shared address space (carrier-grade NAT)
+	netip.MustParsePrefix("127.0.0.0/8"),     // loopback
+	netip.MustParsePrefix("169.254.0.0/16"),  // link-local
+	netip.MustParsePrefix("172.16.0.0/12"),   // private
+	netip.MustParsePrefix("192.0.0.0/24"),    // IETF protocol assignments
+	netip.MustParsePrefix("192.0.2.0/24"),    // documentation
+	netip.MustParsePrefix("192.31.196.0/24"), // AS112
+	netip.MustParsePrefix("192.52.193.0/24"), // AMT
+	netip.MustParsePrefix("192.88.99.0/24"),  // 6to4 relay anycast
+	netip.MustParsePrefix("192.168.0.0/16"),  // private
+	netip.MustParsePrefix("192.175.48.0/24"), // AS112 direct delegation
+	netip.MustParsePrefix("198.18.0.0/15"),   // benchmarking
+	netip.MustParsePrefix("198.51.100.0/24"), // documentation
+	netip.MustParsePrefix("203.0.113.0/24"),  // documentation
+	netip.MustParsePrefix("224.0.0.0/4"),     // multicast
+	netip.MustParsePrefix("240.0.0.0/4"),     // reserved, and the limited broadcast address
+	netip.MustParsePrefix("::/128"),          // unspecified
+	netip.MustParsePrefix("::1/128"),         // loopback
+	netip.MustParsePrefix("64:ff9b::/96"),    // IPv4/IPv6 translation
+	netip.MustParsePrefix("64:ff9b:1::/48"),  // local IPv4/IPv6 translation
+	netip.MustParsePrefix("100::/64"),        // discard-only
+	netip.MustParsePrefix("2001::/23"),       // IETF protocol assignments, Teredo included
+	netip.MustParsePrefix("2001:db8::/32"),   // documentation
+	netip.MustParsePrefix("2002::/16"),       // 6to4
+	netip.MustParsePrefix("3fff::/20"),       // documentation
+	netip.MustParsePrefix("5f00::/16"),       // segment routing SIDs
+	netip.MustParsePrefix("fc00::/7"),        // unique local
+	netip.MustParsePrefix("fe80::/10"),       // link-local
+	netip.MustParsePrefix("fec0::/10"),       // site-local, deprecated
+	netip.MustParsePrefix("ff00::/8"),        // multicast
+}
+
+type WebhookUsecase struct {
+	webhookRepo    *repository.WebhookRepository
+	membershipRepo *repository.MembershipRepository
+	client         *http.Client
+}
+
+func NewWebhookUsecase(webhookRepo *repository.WebhookRepository, membershipRepo *repository.MembershipRepository) *WebhookUsecase {
+	return &WebhookUsecase{
+		webhookRepo:    webhookRepo,
+		membershipRepo: membershipRepo,
+		client:         newWebhookClient(config.Env.Webhook.AllowPrivateNetworks),
+	}
+}
+
+// ListWebhooks returns the user's webhooks
+func (u *WebhookUsecase) ListWebhooks(ctx context.Context, userID string) ([]contract.WebhookRes, error) {
+	webhooks, err := u.webhookRepo.ListWebhooks(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]contract.WebhookRes, 0, len(webhooks))
+	for i := range webhooks {
+		items = append(items, toWebhookRes(&webhooks[i]))
+	}
+	return items, nil
+}
+
+// CreateWebhook creates a webhook with a new secret, which is only returned now
+func (u *WebhookUsecase) CreateWebhook(ctx context.Context, userID string, req *contract.CreateWebhookReq) (*contract.WebhookRes, error) {
+	if err := checkWebhookURL(req.URL); err != nil {
+		return nil, err
+	}
+	if err := checkWebhookEventTypes(req.EventTypes); err != nil {
+		return nil, err
+	}
+	if req.ProjectID != nil {
+		access, err := u.membershipRepo.Access(ctx, userID, repository.MemberTarget{ProjectID: req.ProjectID})
+		if err != nil {
+			return nil, err
+		}
+		if access == nil {
+			return nil, fiber.NewError(fiber.StatusNotFound, repository.ErrProjectNotFound.Error())
+		}
+	}
+
+	token, err := newSecretToken()
+	if err != nil {
+		return nil, err
+	}
+	webhook := &model.Webhook{
+		UserID:      userID,
+		ProjectID:   req.ProjectID,
+		URL:         req.URL,
+		Description: req.Description,
+		Secret:      "whsec_" + token,
+		EventTypes:  req.EventTypes,
+		Active:      true,
+	}
+	if err := u.webhookRepo.CreateWebhook(ctx, webhook); err != nil {
+		return nil, err
+	}
+
+	res := toWebhookRes(webhook)
+	res.Secret = &webhook.Secret
+	return &res, nil
+}
+
+// UpdateWebhook changes the fields of a webhook that are sent
+func (u *WebhookUsecase) UpdateWebhook(ctx context.Context, userID, webhookID string, req *contract.UpdateWebhookReq) (*contract.WebhookRes, error) {
+	updates := map[string]any{}
+	if req.URL != nil {
+		if err := checkWebhookURL(*req.URL); err != nil {
+			return nil, err
+		}
+		updates["url"] = *req.URL
+	}
+	if req.Description != nil {
+		updates["description"] = *req.Description
+	}
+	if req.EventTypes != nil {
+		if err := checkWebhookEventTypes(*req.EventTypes); err != nil {
+			return nil, err
+		}
+		updates["event_types"] = datatypes.JSONSlice[string](*req.EventTypes)
+	}
+	if req.Active != nil {
+		updates["active"] = *req.Active
+	}
+
+	err := u.webhookRepo.UpdateWebhook(ctx, userID, webhookID, updates)
+	if errors.Is(err, repository.ErrWebhookNotFound) {
+		return nil, fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	webhook, err := u.webhookRepo.GetWebhook(ctx, userID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	if webhook == nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, repository.ErrWebhookNotFound.Error())
+	}
+	res := toWebhookRes(webhook)
+	return &res, nil
+}
+
+// DeleteWebhook deletes a webhook and cancels its pending deliveries
+func (u *WebhookUsecase) DeleteWebhook(ctx context.Context, userID, webhookID string) error {
+	err := u.webhookRepo.DeleteWebhook(ctx, userID, webhookID)
+	if errors.Is(err, repository.ErrWebhookNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+	return err
+}
+
+// ListDeliveries returns the delivery log of a webhook of the user's
+func (u *WebhookUsecase) ListDeliveries(ctx context.Context, userID, webhookID string, req *contract.WebhookDeliveriesReq) ([]contract.WebhookDeliveryRes, int64, error) {
+	if _, err := u.getWebhook(ctx, userID, webhookID); err != nil {
+		return nil, 0, err
+	}
+
+	deliveries, total, err := u.webhookRepo.ListDeliveries(ctx, webhookID, repository.WebhookDeliveryFilters{
+		Status: req.Status,
+		Limit:  req.Limit,
+		Offset: (req.Page - 1) * req.Limit,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	items := make([]contract.WebhookDeliveryRes, 0, len(deliveries))
+	for i := range deliveries {
+		items = append(items, toWebhookDeliveryRes(&deliveries[i]))
+	}
+	return items, total, nil
+}
+
+// Redeliver queues the event of a past delivery again. The event keeps its ID, so the endpoint can
+// tell it already got it.
+func (u *WebhookUsecase) Redeliver(ctx context.Context, userID, webhookID, deliveryID string) (*contract.WebhookDeliveryRes, error) {
+	webhook, err := u.getWebhook(ctx, userID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery, err := u.webhookRepo.Redeliver(ctx, webhook, deliveryID)
+	switch {
+	case errors.Is(err, repository.ErrWebhookDeliveryNotFound):
+		return nil, fiber.NewError(fiber.StatusNotFound, err.Error())
+	case errors.Is(err, repository.ErrWebhookInactive):
+		return nil, fiber.NewError(fiber.StatusBadRequest, err.Error())
+	case err != nil:
+		return nil, err
+	}
+
+	res := toWebhookDeliveryRes(delivery)
+	return &res, nil
+}
+
+// DispatchDeliveries posts every webhook delivery that is due. Returns the number sent.
+func (u *WebhookUsecase) DispatchDeliveries(ctx context.Context) (int, error) {
+	if _, err := u.webhookRepo.AbandonStaleDeliveries(ctx, webhookStaleAfter); err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for {
+		deliveries, err := u.webhookRepo.ClaimDeliveries(ctx, webhookBatchSize)
+		if err != nil {
+			return sent, err
+		}
+		for i := range deliveries {
+			if u.deliver(ctx, &deliveries[i]) {
+				sent++
+			}
+		}
+		if len(deliveries) < webhookBatchSize {
+			return sent, nil
+		}
+	}
+}
+
+// deliver posts a claimed delivery and records the outcome. Reports whether it was sent.
+func (u *WebhookUsecase) deliver(ctx context.Context, delivery *model.WebhookDelivery) bool {
+	webhook := delivery.Webhook
+	if webhook == nil || webhook.DeletedAt != nil || !webhook.Active {
+		_ = u.webhookRepo.MarkDelivery(ctx, delivery.ID, repository.WebhookAttempt{
+			Status:    model.WebhookStatusCancelled,
+			LastError: util.ToPointer("webhook was deleted or turned off"),
+		})
+		return false
+	}
+	if webhook.ProjectID != nil {
+		access, err := u.membershipRepo.Access(ctx, webhook.UserID, repository.MemberTarget{ProjectID: webhook.ProjectID})
+		if err != nil {
+			u.retryDelivery(ctx, delivery, repository.WebhookAttempt{LastError: util.ToPointer(err.Error())})
+			return false
+		}
+		if access == nil {
+			_ = u.webhookRepo.MarkDelivery(ctx, delivery.ID, repository.WebhookAttempt{
+				Status:    model.WebhookStatusCancelled,
+				LastError: util.ToPointer("the webhook's user no longer sees its project"),
+			})
+			return false
+		}
+	}
+
+	attempt := u.post(ctx, webhook, delivery)
+	if attempt.LastError != nil {
+		u.retryDelivery(ctx, delivery, attempt)
+		return false
+	}
+
+	attempt.Status = model.WebhookStatusSent
+	if err := u.webhookRepo.MarkDelivery(ctx, delivery.ID, attempt); err != nil {
+		logger.Log.Error("Webhook delivery sent but not recorded", zap.Error(err), zap.String("deliveryID", delivery.ID))
+	}
+	return true
+}
+
+// post sends a delivery's payload, signed with the webhook's secret. Any 2xx response counts as
+// received; the attempt has a LastError otherwise.
+func (u *WebhookUsecase) post(ctx context.Context, webhook *model.Webhook, delivery *model.WebhookDelivery) repository.WebhookAttempt {
+	var attempt repository.WebhookAttempt
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		attempt.LastError = util.ToPointer(err.Error())
+		return attempt
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "memr-webhooks/1.0")
+	req.Header.Set("X-Memr-Event", delivery.EventType)
+	req.Header.Set("X-Memr-Delivery", delivery.ID)
+	req.Header.Set("X-Memr-Timestamp", timestamp)
+	req.Header.Set("X-Memr-Signature", signWebhookPayload(webhook.Secret, timestamp, delivery.Payload))
+
+	start := time.Now()
+	resp, err := u.client.Do(req)
+	attempt.DurationMs = util.ToPointer(int(time.Since(start).Milliseconds()))
+	if err != nil {
+		attempt.LastError = util.ToPointer(err.Error())
+		return attempt
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, webhookResponseLimit))
+	attempt.ResponseStatus = &resp.StatusCode
+	attempt.ResponseBody = util.ToPointer(string(body))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		attempt.LastError = util.ToPointer(fmt.Sprintf("endpoint responded with status %d", resp.StatusCode))
+	}
+	return attempt
+}
+
+// retryDelivery records a failed attempt and schedules the next one with exponential backoff
+func (u *WebhookUsecase) retryDelivery(ctx context.Context, delivery *model.WebhookDelivery, attempt repository.WebhookAttempt) {
+	logger.Log.Warn("Failed to deliver webhook",
+		zap.String("error", util.ToValue(attempt.LastError)),
+		zap.String("deliveryID", delivery.ID),
+		zap.Int("attempts", delivery.Attempts),
+	)
+
+	// 1, 2, 4, 8, 16, 32 and 64 minutes
+	backoff := time.Minute << (delivery.Attempts - 1)
+	nextAttemptAt := time.Now().Add(backoff)
+	attempt.Status = model.WebhookStatusFailed
+	attempt.NextAttemptAt = &nextAttemptAt
+	_ = u.webhookRepo.MarkDelivery(ctx, delivery.ID, attempt)
+}
+
+// getWebhook returns a live webhook of the user's, or a 404
+func (u *WebhookUsecase) getWebhook(ctx context.Context, userID, webhookID string) (*model.Webhook, error) {
+	webhook, err := u.webhookRepo.GetWebhook(ctx, userID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	if webhook == nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, repository.ErrWebhookNotFound.Error())
+	}
+	return webhook, nil
+}
+
+// signWebhookPayload returns the X-Memr-Signature header of a payload: the hex HMAC-SHA256 of
+// the timestamp, a dot and the payload, keyed by the webhook's secret
+func signWebhookPayload(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// newWebhookClient returns the HTTP client webhooks are posted with. It doesn't follow redirects,
+// and unless allowPrivate is set it refuses to connect to addresses that aren't public, whatever
+// the URL's host resolves to.
+func newWebhookClient(allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: webhookTimeout}
+	if !allowPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			addr, err := netip.ParseAddr(host)
+			if err != nil || !webhookAddressAllowed(addr) {
+				return errWebhookPrivateAddress
+			}
+			return nil
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   webhookTimeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// webhookAddressAllowed reports whether an address is public, outside webhookBlockedPrefixes.
+// IPv4-mapped IPv6 addresses are checked as the IPv4 address they carry.
+func webhookAddressAllowed(addr netip.Addr) bool {
+	addr = addr.Unmap().WithZone("")
+	for _, prefix := range webhookBlockedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return addr.IsValid()
+}
+
+// checkWebhookURL checks a webhook URL is http or https with a host
+func checkWebhookURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Hostname() == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "url must be an http or https URL")
+	}
+	return nil
+}
+
+// checkWebhookEventTypes checks every event type is one webhooks can subscribe to
+func checkWebhookEventTypes(eventTypes []string) error {
+	for _, eventType := range eventTypes {
+		if !model.IsWebhookEventType(eventType) {
+			return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("unknown event type: %s", eventType))
+		}
+	}
+	return nil
+}
+
+func toWebhookRes(webhook *model.Webhook) contract.WebhookRes {
+	eventTypes := []string(webhook.EventTypes)
+	if eventTypes == nil {
+		eventTypes = []string{}
+	}
+	return contract.WebhookRes{
+		ID:          webhook.ID,
+		URL:         webhook.URL,
+		Description: webhook.Description,
+		EventTypes:  eventTypes,
+		ProjectID:   webhook.ProjectID,
+		Active:      webhook.Active,
+		CreatedAt:   webhook.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:   webhook.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+func toWebhookDeliveryRes(delivery *model.WebhookDelivery) contract.WebhookDeliveryRes {
+	return contract.WebhookDeliveryRes{
+		ID:             delivery.ID,
+		EventType:      delivery.EventType,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		LastError:      delivery.LastError,
+		ResponseStatus: delivery.ResponseStatus,
+		ResponseBody:   delivery.ResponseBody,
+		DurationMs:     delivery.DurationMs,
+		RedeliveryOf:   delivery.RedeliveryOf,
+		Payload:        json.RawMessage(delivery.Payload),
+		NextAttemptAt:  delivery.NextAttemptAt.UTC().Format(time.RFC3339),
+		SentAt:         util.TimePtrToStringPtr(delivery.SentAt, time.RFC3339),
+		CreatedAt:      delivery.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,48 @@
+package usecase
+
+import (
+	"net/netip"
+	"testing"
+)
+
+func TestWebhookAddressAllowed(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{addr: "93.184.215.14", want: true},
+		{addr: "2606:4700:4700::1111", want: true},
+		{addr: "127.0.0.1", want: false},
+		{addr: "10.1.2.3", want: false},
+		{addr: "172.20.0.1", want: false},
+		{addr: "192.168.1.1", want: false},
+		{addr: "169.254.169.254", want: false},
+		{addr: "100.64.0.1", want: false},
+		{addr: "100.127.255.254", want: false},
+		{addr: "192.0.0.8", want: false},
+		{addr: "198.18.0.1", want: false},
+		{addr: "198.19.255.255", want: false},
+		{addr: "203.0.113.7", want: false},
+		{addr: "240.0.0.1", want: false},
+		{addr: "255.255.255.255", want: false},
+		{addr: "0.0.0.0", want: false},
+		{addr: "::1", want: false},
+		{addr: "::", want: false},
+		{addr: "::ffff:127.0.0.1", want: false},
+		{addr: "::ffff:93.184.215.14", want: true},
+		{addr: "64:ff9b::a9fe:a9fe", want: false},
+		{addr: "2002:7f00:1::", want: false},
+		{addr: "2001::1", want: false},
+		{addr: "fd00::1", want: false},
+		{addr: "fe80::1%eth0", want: false},
+		{addr: "ff02::1", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			if got := webhookAddressAllowed(netip.MustParseAddr(tt.addr)); got != tt.want {
+				t.Errorf("webhookAddressAllowed(%s) = %v, want %v", tt.addr, got, tt.want)
+			}
+		})
+	}
+}
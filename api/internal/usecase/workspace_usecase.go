@@ -0,0 +1,152 @@
+package usecase
+
+import (
+	"app/internal/contract"
+	"app/internal/model"
+	"app/internal/repository"
+	"app/pkg/logger"
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type WorkspaceUsecase struct {
+	workspaceRepo  *repository.WorkspaceRepository
+	membershipRepo *repository.MembershipRepository
+	tokenUsecase   *TokenUsecase
+}
+
+func NewWorkspaceUsecase(workspaceRepo *repository.WorkspaceRepository, membershipRepo *repository.MembershipRepository, tokenUsecase *TokenUsecase) *WorkspaceUsecase {
+	return &WorkspaceUsecase{
+		workspaceRepo:  workspaceRepo,
+		membershipRepo: membershipRepo,
+		tokenUsecase:   tokenUsecase,
+	}
+}
+
+// ListWorkspaces returns the workspaces the user owns or is a member of
+func (u *WorkspaceUsecase) ListWorkspaces(ctx context.Context, userID, activeWorkspaceID string) (*contract.WorkspacesRes, error) {
+	workspaces, err := u.workspaceRepo.ListWorkspaces(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &contract.WorkspacesRes{
+		ActiveWorkspaceID: activeWorkspaceID,
+		Items:             make([]contract.WorkspaceRes, 0, len(workspaces)),
+	}
+	for i := range workspaces {
+		res.Items = append(res.Items, toWorkspaceRes(userID, &workspaces[i].Workspace, workspaces[i].Role))
+	}
+	return res, nil
+}
+
+// CreateWorkspace creates a team workspace owned by the user
+func (u *WorkspaceUsecase) CreateWorkspace(ctx context.Context, userID string, req *contract.CreateWorkspaceReq) (*contract.WorkspaceRes, error) {
+	workspace := &model.Workspace{
+		UserID: userID,
+		Title:  strings.TrimSpace(req.Title),
+	}
+	if workspace.Title == "" {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "title is required")
+	}
+	if err := u.workspaceRepo.CreateWorkspace(ctx, workspace); err != nil {
+		return nil, err
+	}
+
+	res := toWorkspaceRes(userID, workspace, model.MemberRoleOwner)
+	return &res, nil
+}
+
+// UpdateWorkspace renames a workspace. Only owners can rename it.
+func (u *WorkspaceUsecase) UpdateWorkspace(ctx context.Context, userID, workspaceID string, req *contract.UpdateWorkspaceReq) error {
+	if _, err := u.access(ctx, userID, workspaceID, model.MemberRoleOwner); err != nil {
+		return err
+	}
+
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "title is required")
+	}
+	err := u.workspaceRepo.RenameWorkspace(ctx, workspaceID, title)
+	if errors.Is(err, repository.ErrWorkspaceNotFound) {
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	}
+	return err
+}
+
+// DeleteWorkspace deletes an empty workspace. Only owners can delete it.
+func (u *WorkspaceUsecase) DeleteWorkspace(ctx context.Context, userID, workspaceID string) error {
+	if _, err := u.access(ctx, userID, workspaceID, model.MemberRoleOwner); err != nil {
+		return err
+	}
+
+	err := u.workspaceRepo.DeleteWorkspace(ctx, workspaceID)
+	switch {
+	case errors.Is(err, repository.ErrWorkspaceNotFound):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case errors.Is(err, repository.ErrWorkspaceNotEmpty):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	}
+	return err
+}
+
+// SwitchWorkspace issues tokens with another active workspace, or with none for the personal space
+func (u *WorkspaceUsecase) SwitchWorkspace(ctx context.Context, userID string, req *contract.SwitchWorkspaceReq) (*contract.SwitchWorkspaceRes, error) {
+	if req.WorkspaceID != "" {
+		if _, err := u.access(ctx, userID, req.WorkspaceID, model.MemberRoleViewer); err != nil {
+			return nil, err
+		}
+	}
+
+	tokens, err := u.tokenUsecase.GenerateTokenPair(userID, req.WorkspaceID)
+	if err != nil {
+		logger.Log.Error("Failed to generate token pair", zap.Error(err), zap.String("userID", userID))
+		return nil, err
+	}
+
+	return &contract.SwitchWorkspaceRes{
+		TokenRes:    *tokens,
+		WorkspaceID: req.WorkspaceID,
+	}, nil
+}
+
+// access returns the user's access to a workspace, which must allow at least the minimum role
+func (u *WorkspaceUsecase) access(ctx context.Context, userID, workspaceID string, minimum string) (*repository.MemberAccess, error) {
+	access, err := u.membershipRepo.Access(ctx, userID, repository.MemberTarget{WorkspaceID: &workspaceID})
+	if err != nil {
+		return nil, err
+	}
+	if err := checkWorkspaceRole(access, minimum); err != nil {
+		return nil, err
+	}
+	return access, nil
+}
+
+// checkWorkspaceRole fails with 404 without access to the workspace and 403 when the role is below the minimum
+func checkWorkspaceRole(access *repository.MemberAccess, minimum string) error {
+	if access == nil {
+		return fiber.NewError(fiber.StatusNotFound, repository.ErrWorkspaceNotFound.Error())
+	}
+	if !model.MemberRoleAllows(access.Role, minimum) {
+		if minimum == model.MemberRoleOwner {
+			return fiber.NewError(fiber.StatusForbidden, "Only owners can manage the workspace")
+		}
+		return fiber.NewError(fiber.StatusForbidden, "Not a member of the workspace")
+	}
+	return nil
+}
+
+func toWorkspaceRes(userID string, workspace *model.Workspace, role string) contract.WorkspaceRes {
+	return contract.WorkspaceRes{
+		ID:        workspace.ID,
+		Title:     workspace.Title,
+		Role:      role,
+		Creator:   workspace.UserID == userID,
+		CreatedAt: workspace.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
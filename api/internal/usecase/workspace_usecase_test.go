@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"app/internal/model"
+	"app/internal/repository"
+	"errors"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestCheckWorkspaceRole(t *testing.T) {
+	tests := []struct {
+		name    string
+		access  *repository.MemberAccess
+		minimum string
+		want    int
+	}{
+		{name: "no access", access: nil, minimum: model.MemberRoleViewer, want: fiber.StatusNotFound},
+		{name: "no role switching", access: &repository.MemberAccess{}, minimum: model.MemberRoleViewer, want: fiber.StatusForbidden},
+		{name: "unknown role switching", access: &repository.MemberAccess{Role: "guest"}, minimum: model.MemberRoleViewer, want: fiber.StatusForbidden},
+		{name: "viewer switching", access: &repository.MemberAccess{Role: model.MemberRoleViewer}, minimum: model.MemberRoleViewer},
+		{name: "editor switching", access: &repository.MemberAccess{Role: model.MemberRoleEditor}, minimum: model.MemberRoleViewer},
+		{name: "editor managing", access: &repository.MemberAccess{Role: model.MemberRoleEditor}, minimum: model.MemberRoleOwner, want: fiber.StatusForbidden},
+		{name: "owner managing", access: &repository.MemberAccess{Role: model.MemberRoleOwner}, minimum: model.MemberRoleOwner},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkWorkspaceRole(tt.access, tt.minimum)
+			if tt.want == 0 {
+				if err != nil {
+					t.Errorf("got %v, want access", err)
+				}
+				return
+			}
+			var fiberErr *fiber.Error
+			if !errors.As(err, &fiberErr) || fiberErr.Code != tt.want {
+				t.Errorf("got %v, want status %d", err, tt.want)
+			}
+		})
+	}
+}
@@ -2,15 +2,18 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	firebase "firebase.google.com/go"
 	"firebase.google.com/go/auth"
+	"firebase.google.com/go/messaging"
 	"google.golang.org/api/option"
 )
 
 type FirebaseUsecase struct {
-	auth *auth.Client
+	auth      *auth.Client
+	messaging *messaging.Client
 }
 
 func NewFirebaseUsecase(ctx context.Context, serviceAccountJSON string) (*FirebaseUsecase, error) {
@@ -31,8 +34,14 @@ func NewFirebaseUsecase(ctx context.Context, serviceAccountJSON string) (*Fireba
 		return nil, fmt.Errorf("failed to get firebase auth client: %w", err)
 	}
 
+	messagingClient, err := app.Messaging(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get firebase messaging client: %w", err)
+	}
+
 	return &FirebaseUsecase{
-		auth: authClient,
+		auth:      authClient,
+		messaging: messagingClient,
 	}, nil
 }
 
@@ -65,3 +74,34 @@ func (u *FirebaseUsecase) VerifyGoogleToken(ctx context.Context, idToken string)
 
 	return tokenInfo, nil
 }
+
+// SendPush sends a notification to each device token through FCM.
+// It returns how many devices accepted it and which tokens are no longer registered.
+// An error is returned only when no device accepted the notification.
+func (u *FirebaseUsecase) SendPush(ctx context.Context, tokens []string, title, body string, data map[string]string) (delivered int, staleTokens []string, err error) {
+	var errs []error
+	for _, token := range tokens {
+		_, sendErr := u.messaging.Send(ctx, &messaging.Message{
+			Token: token,
+			Notification: &messaging.Notification{
+				Title: title,
+				Body:  body,
+			},
+			Data: data,
+		})
+		if sendErr == nil {
+			delivered++
+			continue
+		}
+		if messaging.IsRegistrationTokenNotRegistered(sendErr) {
+			staleTokens = append(staleTokens, token)
+			continue
+		}
+		errs = append(errs, sendErr)
+	}
+
+	if delivered == 0 && len(errs) > 0 {
+		return 0, staleTokens, fmt.Errorf("failed to send push notification: %w", errors.Join(errs...))
+	}
+	return delivered, staleTokens, nil
+}
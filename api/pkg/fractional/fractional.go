@@ -0,0 +1,159 @@
+package fractional
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// digits are the key alphabet, in byte order so keys compare with plain string comparison
+// (and with COLLATE "C" in Postgres)
+const digits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// MaxKeyLength is the longest key Validate accepts. Keys grow as items are repeatedly
+// inserted at the same spot; rebalancing brings them back down.
+const MaxKeyLength = 64
+
+var ErrInvalidKey = errors.New("invalid sort order key")
+
+// Validate reports whether key is a well-formed key. A key may not end in the
+// smallest digit, otherwise there would be no room to insert before it.
+func Validate(key string) error {
+	if key == "" || len(key) > MaxKeyLength {
+		return fmt.Errorf("%w: must be 1 to %d characters", ErrInvalidKey, MaxKeyLength)
+	}
+	for i := 0; i < len(key); i++ {
+		if strings.IndexByte(digits, key[i]) < 0 {
+			return fmt.Errorf("%w: %q is not a base-62 digit", ErrInvalidKey, key[i])
+		}
+	}
+	if key[len(key)-1] == digits[0] {
+		return fmt.Errorf("%w: must not end in %q", ErrInvalidKey, digits[0])
+	}
+	return nil
+}
+
+// KeyBetween returns a key that sorts strictly between a and b.
+// An empty a means the start of the list, an empty b the end.
+func KeyBetween(a, b string) (string, error) {
+	if err := validateBounds(a, b); err != nil {
+		return "", err
+	}
+
+	// Appending and prepending are the common moves, so step to the nearest
+	// short key instead of halving, which would add a digit every few moves
+	switch {
+	case a != "" && b == "":
+		return keyAfter(a), nil
+	case a == "" && b != "":
+		return keyBefore(b), nil
+	}
+	return midpoint(a, b), nil
+}
+
+// NKeysBetween returns n ascending keys between a and b, spread so they stay short
+func NKeysBetween(a, b string, n int) ([]string, error) {
+	if err := validateBounds(a, b); err != nil {
+		return nil, err
+	}
+	return nKeysBetween(a, b, n), nil
+}
+
+func nKeysBetween(a, b string, n int) []string {
+	if n <= 0 {
+		return []string{}
+	}
+
+	mid := midpoint(a, b)
+	keys := make([]string, 0, n)
+	keys = append(keys, nKeysBetween(a, mid, n/2)...)
+	keys = append(keys, mid)
+	return append(keys, nKeysBetween(mid, b, n-n/2-1)...)
+}
+
+func validateBounds(a, b string) error {
+	if a != "" {
+		if err := Validate(a); err != nil {
+			return err
+		}
+	}
+	if b != "" {
+		if err := Validate(b); err != nil {
+			return err
+		}
+	}
+	if a != "" && b != "" && a >= b {
+		return fmt.Errorf("%w: %q is not before %q", ErrInvalidKey, a, b)
+	}
+	return nil
+}
+
+// keyAfter returns a short key greater than a by bumping its first digit that isn't the largest
+func keyAfter(a string) string {
+	for i := 0; i < len(a); i++ {
+		if d := strings.IndexByte(digits, a[i]); d < len(digits)-1 {
+			return a[:i] + string(digits[d+1])
+		}
+	}
+	return midpoint(a, "")
+}
+
+// keyBefore returns a short key smaller than b by lowering its first digit that can go down
+// without ending in the smallest digit
+func keyBefore(b string) string {
+	for i := 0; i < len(b); i++ {
+		if d := strings.IndexByte(digits, b[i]); d > 1 {
+			return b[:i] + string(digits[d-1])
+		}
+	}
+	return midpoint("", b)
+}
+
+// midpoint expects a < b, both valid or empty
+func midpoint(a, b string) string {
+	if b != "" {
+		// Keep the shared prefix and split the rest
+		n := 0
+		for n < len(b) && digitAt(a, n) == b[n] {
+			n++
+		}
+		if n > 0 {
+			rest := ""
+			if n < len(a) {
+				rest = a[n:]
+			}
+			return b[:n] + midpoint(rest, b[n:])
+		}
+	}
+
+	digitA := 0
+	if a != "" {
+		digitA = strings.IndexByte(digits, a[0])
+	}
+	digitB := len(digits)
+	if b != "" {
+		digitB = strings.IndexByte(digits, b[0])
+	}
+
+	if digitB-digitA > 1 {
+		return string(digits[(digitA+digitB+1)/2])
+	}
+
+	// The first digits are adjacent, so the key has to get longer
+	if len(b) > 1 {
+		return b[:1]
+	}
+	rest := ""
+	if len(a) > 1 {
+		rest = a[1:]
+	}
+	return string(digits[digitA]) + midpoint(rest, "")
+}
+
+// digitAt returns the digit at position i, treating missing digits as the smallest one
+func digitAt(key string, i int) byte {
+	if i < len(key) {
+		return key[i]
+	}
+	return digits[0]
+}
@@ -0,0 +1,183 @@
+package fractional
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// checkBetween fails the test unless key is valid and sorts strictly between a and b,
+// where an empty bound is open
+func checkBetween(t *testing.T, key, a, b string) {
+	t.Helper()
+
+	if err := Validate(key); err != nil {
+		t.Errorf("key %q between %q and %q is invalid: %v", key, a, b, err)
+	}
+	if a != "" && key <= a {
+		t.Errorf("key %q doesn't sort after %q", key, a)
+	}
+	if b != "" && key >= b {
+		t.Errorf("key %q doesn't sort before %q", key, b)
+	}
+}
+
+func TestKeyBetween(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+	}{
+		{name: "empty list", a: "", b: ""},
+		{name: "after", a: "V", b: ""},
+		{name: "after the largest digit", a: "zz", b: ""},
+		{name: "before", a: "", b: "V"},
+		{name: "before the second smallest digit", a: "", b: "1"},
+		{name: "before a key starting with the smallest digit", a: "", b: "01"},
+		{name: "wide gap", a: "1", b: "z"},
+		{name: "adjacent digits", a: "a", b: "b"},
+		{name: "adjacent longer keys", a: "a1", b: "a2"},
+		{name: "prefix of the upper bound", a: "a", b: "a1"},
+		{name: "prefix followed by the smallest digit", a: "a", b: "a01"},
+		{name: "carry into the next digit", a: "az", b: "b"},
+		{name: "lower bound longer", a: "azzz", b: "b1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := KeyBetween(tt.a, tt.b)
+			if err != nil {
+				t.Fatalf("KeyBetween(%q, %q) failed: %v", tt.a, tt.b, err)
+			}
+			checkBetween(t, key, tt.a, tt.b)
+		})
+	}
+}
+
+func TestKeyBetweenRejectsBadBounds(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+	}{
+		{name: "equal", a: "a", b: "a"},
+		{name: "reversed", a: "b", b: "a"},
+		{name: "ends in the smallest digit", a: "a0", b: ""},
+		{name: "not a digit", a: "", b: "a-b"},
+		{name: "too long", a: strings.Repeat("a", MaxKeyLength+1), b: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if key, err := KeyBetween(tt.a, tt.b); !errors.Is(err, ErrInvalidKey) {
+				t.Errorf("KeyBetween(%q, %q) = %q, %v, want ErrInvalidKey", tt.a, tt.b, key, err)
+			}
+			if keys, err := NKeysBetween(tt.a, tt.b, 3); !errors.Is(err, ErrInvalidKey) {
+				t.Errorf("NKeysBetween(%q, %q) = %q, %v, want ErrInvalidKey", tt.a, tt.b, keys, err)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{key: "a", want: true},
+		{key: "a0V", want: true},
+		{key: strings.Repeat("z", MaxKeyLength), want: true},
+		{key: "", want: false},
+		{key: "0", want: false},
+		{key: "a0", want: false},
+		{key: "a b", want: false},
+		{key: "é", want: false},
+		{key: strings.Repeat("z", MaxKeyLength+1), want: false},
+	}
+
+	for _, tt := range tests {
+		if err := Validate(tt.key); (err == nil) != tt.want {
+			t.Errorf("Validate(%q) = %v, want valid %v", tt.key, err, tt.want)
+		}
+	}
+}
+
+func TestNKeysBetween(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		n    int
+	}{
+		{name: "none", a: "a", b: "b", n: 0},
+		{name: "one", a: "a", b: "b", n: 1},
+		{name: "whole list", a: "", b: "", n: 100},
+		{name: "close bounds", a: "a1", b: "a2", n: 100},
+		{name: "adjacent digits", a: "a", b: "b", n: 500},
+		{name: "open start", a: "", b: "1", n: 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := NKeysBetween(tt.a, tt.b, tt.n)
+			if err != nil {
+				t.Fatalf("NKeysBetween failed: %v", err)
+			}
+			if len(keys) != tt.n {
+				t.Fatalf("got %d keys, want %d", len(keys), tt.n)
+			}
+			prev := tt.a
+			for _, key := range keys {
+				checkBetween(t, key, prev, tt.b)
+				prev = key
+			}
+		})
+	}
+}
+
+func TestRepeatedMovesStayShort(t *testing.T) {
+	tests := []struct {
+		name      string
+		next      func(last string) (string, error)
+		ascending bool
+		maxLength int
+	}{
+		{
+			name:      "append",
+			next:      func(last string) (string, error) { return KeyBetween(last, "") },
+			ascending: true,
+			maxLength: 5,
+		},
+		{
+			name:      "prepend",
+			next:      func(last string) (string, error) { return KeyBetween("", last) },
+			maxLength: 4,
+		},
+		{
+			name:      "insert after the same item",
+			next:      func(last string) (string, error) { return KeyBetween("a", last) },
+			maxLength: MaxKeyLength,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			last := "b"
+			for i := 0; i < 100; i++ {
+				key, err := tt.next(last)
+				if err != nil {
+					t.Fatalf("move %d after %q failed: %v", i, last, err)
+				}
+				if tt.ascending {
+					checkBetween(t, key, last, "")
+				} else {
+					checkBetween(t, key, "", last)
+				}
+				if len(key) > tt.maxLength {
+					t.Fatalf("move %d made key %q, want at most %d characters", i, key, tt.maxLength)
+				}
+				last = key
+			}
+		})
+	}
+}
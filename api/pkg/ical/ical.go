@@ -0,0 +1,88 @@
+package ical
+
+import (
+	"strings"
+	"time"
+)
+
+// maxLineOctets is the longest content line RFC 5545 allows before folding
+const maxLineOctets = 75
+
+// Writer builds an iCalendar (RFC 5545) document
+type Writer struct {
+	b strings.Builder
+}
+
+// NewCalendar starts a VCALENDAR with the given product identifier and display name
+func NewCalendar(prodID, name string) *Writer {
+	w := &Writer{}
+	w.Line("BEGIN", "VCALENDAR")
+	w.Line("VERSION", "2.0")
+	w.Line("PRODID", prodID)
+	w.Line("CALSCALE", "GREGORIAN")
+	w.Line("METHOD", "PUBLISH")
+	if name != "" {
+		w.Text("X-WR-CALNAME", name)
+	}
+	return w
+}
+
+// Line writes a property whose value is already in iCalendar form
+func (w *Writer) Line(name, value string) {
+	w.fold(name + ":" + value)
+}
+
+// Text writes a TEXT property, escaping the value
+func (w *Writer) Text(name, value string) {
+	w.Line(name, EscapeText(value))
+}
+
+// Time writes a DATE-TIME property in UTC
+func (w *Writer) Time(name string, t time.Time) {
+	w.Line(name, FormatTime(t))
+}
+
+// String ends the calendar and returns the document
+func (w *Writer) String() string {
+	w.Line("END", "VCALENDAR")
+	return w.b.String()
+}
+
+// fold splits a content line into lines of at most 75 octets, without breaking UTF-8 sequences
+func (w *Writer) fold(line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for !isRuneStart(line[cut]) {
+			cut--
+		}
+		w.b.WriteString(line[:cut])
+		w.b.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space, which counts towards their length
+		limit = maxLineOctets - 1
+	}
+	w.b.WriteString(line)
+	w.b.WriteString("\r\n")
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+// EscapeText escapes a TEXT value
+func EscapeText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(s)
+}
+
+// FormatTime formats a DATE-TIME in UTC, e.g. 20260102T150405Z
+func FormatTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
@@ -0,0 +1,58 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestEscapeText(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Plain", "Plain"},
+		{"Milk, eggs; bread", `Milk\, eggs\; bread`},
+		{`C:\temp`, `C:\\temp`},
+		{"One\r\nTwo\nThree\rFour", `One\nTwo\nThree\nFour`},
+	}
+	for _, tt := range tests {
+		if got := EscapeText(tt.in); got != tt.want {
+			t.Errorf("EscapeText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFormatTime(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	got := FormatTime(time.Date(2026, 1, 2, 22, 4, 5, 0, jakarta))
+	if got != "20260102T150405Z" {
+		t.Errorf("FormatTime() = %s, want it in UTC", got)
+	}
+}
+
+func TestFoldKeepsLinesShortAndRunesWhole(t *testing.T) {
+	summary := strings.Repeat("Überprüfung der Rechnungen ✓ ", 8)
+
+	w := NewCalendar("-//Test//EN", "")
+	w.Text("SUMMARY", summary)
+	doc := w.String()
+
+	if !strings.HasSuffix(doc, "END:VCALENDAR\r\n") {
+		t.Errorf("document doesn't end the calendar: %q", doc)
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(doc, "\r\n"), "\r\n") {
+		if len(line) > maxLineOctets {
+			t.Errorf("line is %d octets, want at most %d: %q", len(line), maxLineOctets, line)
+		}
+		if !utf8.ValidString(line) {
+			t.Errorf("line splits a character: %q", line)
+		}
+	}
+
+	unfolded := strings.ReplaceAll(doc, "\r\n ", "")
+	if !strings.Contains(unfolded, "SUMMARY:"+summary+"\r\n") {
+		t.Errorf("unfolding doesn't restore the summary: %q", unfolded)
+	}
+}
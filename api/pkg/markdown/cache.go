@@ -0,0 +1,56 @@
+package markdown
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Cache keeps rendered HTML by key, dropping the least recently used entries once they add up
+// to more than its size. A key must change whenever the source or options do, for example by
+// including the version of the document.
+type Cache struct {
+	mu       sync.Mutex
+	maxBytes int
+	size     int
+	entries  map[string]*list.Element
+	order    *list.List
+}
+
+type cacheEntry struct {
+	key  string
+	html string
+}
+
+// NewCache returns a cache holding up to maxBytes of HTML
+func NewCache(maxBytes int) *Cache {
+	return &Cache{maxBytes: maxBytes, entries: map[string]*list.Element{}, order: list.New()}
+}
+
+// ToHTML renders source as ToHTML does, or returns what was rendered for key before
+func (c *Cache) ToHTML(key, source string, opts Options) string {
+	c.mu.Lock()
+	if element, ok := c.entries[key]; ok {
+		c.order.MoveToFront(element)
+		c.mu.Unlock()
+		return element.Value.(*cacheEntry).html
+	}
+	c.mu.Unlock()
+
+	rendered := ToHTML(source, opts)
+	if len(rendered) > c.maxBytes {
+		return rendered
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.entries[key]; !ok {
+		c.entries[key] = c.order.PushFront(&cacheEntry{key: key, html: rendered})
+		c.size += len(rendered)
+	}
+	for c.size > c.maxBytes {
+		oldest := c.order.Remove(c.order.Back()).(*cacheEntry)
+		delete(c.entries, oldest.key)
+		c.size -= len(oldest.html)
+	}
+	return rendered
+}
@@ -0,0 +1,32 @@
+package markdown
+
+import "testing"
+
+func TestCache(t *testing.T) {
+	cache := NewCache(64)
+
+	first := cache.ToHTML("note:1", "*one*", Options{})
+	if first != "<p><em>one</em></p>\n" {
+		t.Fatalf("ToHTML = %q", first)
+	}
+	// The same key is served from the cache, whatever the source
+	if got := cache.ToHTML("note:1", "changed", Options{}); got != first {
+		t.Errorf("cached ToHTML = %q, want %q", got, first)
+	}
+	if got := cache.ToHTML("note:2", "changed", Options{}); got != "<p>changed</p>\n" {
+		t.Errorf("ToHTML for a new key = %q", got)
+	}
+
+	// Filling the cache drops the least recently used entry
+	cache.ToHTML("note:1", "", Options{})
+	cache.ToHTML("note:3", "just enough text to push it", Options{})
+	if _, ok := cache.entries["note:2"]; ok {
+		t.Error("the least recently used entry is still cached")
+	}
+	if _, ok := cache.entries["note:1"]; !ok {
+		t.Error("a recently used entry was dropped")
+	}
+	if cache.size > cache.maxBytes {
+		t.Errorf("cache holds %d bytes, more than its %d", cache.size, cache.maxBytes)
+	}
+}
@@ -0,0 +1,615 @@
+package markdown
+
+import (
+	"html"
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// MaxSourceLength is the longest source rendered as Markdown. Longer ones are shown as preformatted text.
+	MaxSourceLength = 1 << 20
+	// maxNestingDepth caps how deep block quotes and lists nest. Deeper markers are kept as text.
+	maxNestingDepth = 16
+)
+
+// Options change how a document is rendered
+type Options struct {
+	// WikilinkHref returns where a [[wikilink]] points, or "" to render it as plain text
+	WikilinkHref func(target string) string
+}
+
+var (
+	headingPattern  = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	listItemPattern = regexp.MustCompile(`^(\s*)([-*+]|\d{1,9}[.)])\s+(.*)$`)
+	taskItemPattern = regexp.MustCompile(`^\[([ xX])\]\s+`)
+	fencePattern    = regexp.MustCompile("^\\s{0,3}(```+|~~~+)\\s*([\\w+-]*)")
+)
+
+// ToHTML renders Markdown as HTML. All text is escaped and only the renderer's own tags are
+// written, so raw HTML in the source shows as text. Links and images only keep http, https
+// and mailto URLs, so the output is safe to serve to anyone.
+//
+// The usual Obsidian flavour is understood: headings, paragraphs with line breaks, lists and
+// task lists, block quotes, fenced code, rules, emphasis, ~~strikethrough~~, ==highlights==,
+// inline code, links, images, bare URLs and [[wikilinks]].
+//
+// Rendering takes time linear in the length of the source, which is capped at MaxSourceLength.
+func ToHTML(source string, opts Options) string {
+	if len(source) > MaxSourceLength {
+		return "<pre>" + html.EscapeString(source) + "</pre>\n"
+	}
+
+	r := &renderer{opts: opts}
+	lines := strings.Split(strings.ReplaceAll(source, "\r\n", "\n"), "\n")
+	r.blocks(lines)
+	return r.b.String()
+}
+
+type renderer struct {
+	b    strings.Builder
+	opts Options
+	// depth is how many block quotes and list items the current blocks are nested in
+	depth int
+}
+
+// blocks renders lines as a sequence of block elements
+func (r *renderer) blocks(lines []string) {
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		switch {
+		case strings.TrimSpace(line) == "":
+			i++
+		case fencePattern.MatchString(line):
+			i = r.codeBlock(lines, i)
+		case headingPattern.MatchString(line):
+			m := headingPattern.FindStringSubmatch(line)
+			level := string(rune('0' + len(m[1])))
+			r.b.WriteString("<h" + level + ">" + r.inline(m[2]) + "</h" + level + ">\n")
+			i++
+		case isRule(line):
+			r.b.WriteString("<hr>\n")
+			i++
+		case r.depth < maxNestingDepth && strings.HasPrefix(strings.TrimLeft(line, " "), ">"):
+			i = r.blockquote(lines, i)
+		case r.depth < maxNestingDepth && listItemPattern.MatchString(line):
+			i = r.list(lines, i)
+		default:
+			i = r.paragraph(lines, i)
+		}
+	}
+}
+
+// nested renders the blocks inside a block quote or list item
+func (r *renderer) nested(lines []string) {
+	r.depth++
+	r.blocks(lines)
+	r.depth--
+}
+
+func (r *renderer) codeBlock(lines []string, start int) int {
+	m := fencePattern.FindStringSubmatch(lines[start])
+	fence := m[1]
+
+	r.b.WriteString("<pre><code")
+	if m[2] != "" {
+		r.b.WriteString(` class="language-` + html.EscapeString(m[2]) + `"`)
+	}
+	r.b.WriteString(">")
+
+	// An unclosed fence runs to the end of the document
+	i := start + 1
+	for ; i < len(lines); i++ {
+		if strings.HasPrefix(strings.TrimSpace(lines[i]), fence) {
+			i++
+			break
+		}
+		r.b.WriteString(html.EscapeString(lines[i]) + "\n")
+	}
+	r.b.WriteString("</code></pre>\n")
+	return i
+}
+
+func (r *renderer) blockquote(lines []string, start int) int {
+	var quoted []string
+	i := start
+	for ; i < len(lines); i++ {
+		trimmed := strings.TrimLeft(lines[i], " ")
+		if !strings.HasPrefix(trimmed, ">") {
+			break
+		}
+		trimmed = strings.TrimPrefix(trimmed, ">")
+		quoted = append(quoted, strings.TrimPrefix(trimmed, " "))
+	}
+
+	r.b.WriteString("<blockquote>\n")
+	r.nested(quoted)
+	r.b.WriteString("</blockquote>\n")
+	return i
+}
+
+// list renders a list and returns the line after it. Lines indented past an item's marker
+// belong to that item and are rendered as its own blocks, which is how lists nest.
+func (r *renderer) list(lines []string, start int) int {
+	first := listItemPattern.FindStringSubmatch(lines[start])
+	indent := len(first[1])
+	ordered := !strings.ContainsAny(first[2], "-*+")
+	tag := "ul"
+	if ordered {
+		tag = "ol"
+	}
+	r.b.WriteString("<" + tag + ">\n")
+
+	i := start
+	for i < len(lines) {
+		m := listItemPattern.FindStringSubmatch(lines[i])
+		if m == nil || len(m[1]) != indent || strings.ContainsAny(m[2], "-*+") == ordered {
+			break
+		}
+
+		item := []string{m[3]}
+		i++
+		for i < len(lines) {
+			line := lines[i]
+			if strings.TrimSpace(line) == "" {
+				// A blank line only continues the item when indented content follows
+				if i+1 < len(lines) && leadingSpaces(lines[i+1]) > indent {
+					item = append(item, "")
+					i++
+					continue
+				}
+				break
+			}
+			if leadingSpaces(line) <= indent {
+				break
+			}
+			item = append(item, dedent(line, indent+2))
+			i++
+		}
+		r.listItem(item)
+	}
+
+	r.b.WriteString("</" + tag + ">\n")
+	return i
+}
+
+func (r *renderer) listItem(item []string) {
+	r.b.WriteString("<li>")
+	if m := taskItemPattern.FindStringSubmatch(item[0]); m != nil {
+		checked := ""
+		if m[1] != " " {
+			checked = " checked"
+		}
+		r.b.WriteString(`<input type="checkbox" disabled` + checked + `> `)
+		item[0] = item[0][len(m[0]):]
+	}
+
+	// The item's first paragraph stays inline so tight lists don't get spaced out
+	end := 1
+	for end < len(item) && strings.TrimSpace(item[end]) != "" && !listItemPattern.MatchString(item[end]) {
+		end++
+	}
+	r.b.WriteString(r.inlineLines(item[:end]))
+	if end < len(item) {
+		r.b.WriteString("\n")
+		r.nested(item[end:])
+	}
+	r.b.WriteString("</li>\n")
+}
+
+func (r *renderer) paragraph(lines []string, start int) int {
+	i := start
+	for i < len(lines) {
+		line := lines[i]
+		if strings.TrimSpace(line) == "" || (i > start && startsBlock(line)) {
+			break
+		}
+		i++
+	}
+
+	r.b.WriteString("<p>" + r.inlineLines(lines[start:i]) + "</p>\n")
+	return i
+}
+
+// inlineLines renders lines of one paragraph, keeping their line breaks as Obsidian does
+func (r *renderer) inlineLines(lines []string) string {
+	rendered := make([]string, len(lines))
+	for i, line := range lines {
+		rendered[i] = r.inline(strings.TrimSpace(line))
+	}
+	return strings.Join(rendered, "<br>\n")
+}
+
+func startsBlock(line string) bool {
+	return fencePattern.MatchString(line) ||
+		headingPattern.MatchString(line) ||
+		isRule(line) ||
+		strings.HasPrefix(strings.TrimLeft(line, " "), ">") ||
+		listItemPattern.MatchString(line)
+}
+
+// isRule reports whether a line is a thematic break: three or more of the same -, * or _
+func isRule(line string) bool {
+	marks := strings.ReplaceAll(strings.TrimSpace(line), " ", "")
+	return len(marks) >= 3 && strings.Trim(marks, marks[:1]) == "" && strings.ContainsAny(marks[:1], "-*_")
+}
+
+func leadingSpaces(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " \t"))
+}
+
+// dedent removes up to n leading spaces, or one tab
+func dedent(line string, n int) string {
+	if strings.HasPrefix(line, "\t") {
+		return line[1:]
+	}
+	for i := 0; i < n && strings.HasPrefix(line, " "); i++ {
+		line = line[1:]
+	}
+	return line
+}
+
+// inline renders the spans of a line of text
+func (r *renderer) inline(text string) string {
+	p := &inlineParser{r: r, text: text}
+	return p.render()
+}
+
+// inlineParser renders the spans of one line of text in a single pass. Searches ahead for closing
+// markers remember where they got to, so a line with many unclosed openers still takes linear time.
+type inlineParser struct {
+	r    *renderer
+	text string
+	// inLink is set while rendering a link's label, which can't hold other links
+	inLink bool
+
+	nodes  []inlineNode
+	delims []delimiter
+
+	// Where the next ]], ]( and > are, as found by find
+	wikilinkEnd, linkLabelEnd, autolinkEnd int
+	// noClosingTicks holds the lengths of backtick runs that close nowhere further on
+	noClosingTicks map[int]bool
+	// parenEnd is, for each index, where a link URL starting there ends. Built on first use.
+	parenEnd []int32
+}
+
+// inlineNode is a piece of output. Emphasis tags go around a delimiter run's unmatched markers:
+// closing tags before them, opening tags after.
+type inlineNode struct {
+	before string
+	html   string
+	after  string
+}
+
+// delimiter is a run of one emphasis marker, as in ** or ~~
+type delimiter struct {
+	node     int
+	marker   byte
+	count    int
+	canOpen  bool
+	canClose bool
+}
+
+func (p *inlineParser) render() string {
+	p.wikilinkEnd, p.linkLabelEnd, p.autolinkEnd = -1, -1, -1
+	text := p.text
+	plainStart := 0
+	flush := func(end int) {
+		if end > plainStart {
+			p.nodes = append(p.nodes, inlineNode{html: html.EscapeString(text[plainStart:end])})
+		}
+	}
+
+	for i := 0; i < len(text); {
+		if minRun(text[i]) > 0 {
+			n := len(text[i:]) - len(strings.TrimLeft(text[i:], text[i:i+1]))
+			flush(i)
+			p.addDelimiter(i, n)
+			i += n
+			plainStart = i
+			continue
+		}
+
+		rendered, next := p.span(i)
+		if next == i {
+			_, size := utf8.DecodeRuneInString(text[i:])
+			i += size
+			continue
+		}
+		flush(i)
+		p.nodes = append(p.nodes, inlineNode{html: rendered})
+		i = next
+		plainStart = i
+	}
+	flush(len(text))
+
+	p.matchEmphasis()
+	for _, d := range p.delims {
+		p.nodes[d.node].html = strings.Repeat(string(d.marker), d.count)
+	}
+
+	var b strings.Builder
+	for _, node := range p.nodes {
+		b.WriteString(node.before)
+		b.WriteString(node.html)
+		b.WriteString(node.after)
+	}
+	return b.String()
+}
+
+// addDelimiter records the run of n markers at text[i]. A run opens when text follows it and
+// closes when text comes before it. Underscores inside words, as in snake_case, do neither.
+func (p *inlineParser) addDelimiter(i, n int) {
+	text := p.text
+	marker := text[i]
+	before, after := byte(' '), byte(' ')
+	if i > 0 {
+		before = text[i-1]
+	}
+	if i+n < len(text) {
+		after = text[i+n]
+	}
+
+	d := delimiter{
+		node:     len(p.nodes),
+		marker:   marker,
+		count:    n,
+		canOpen:  !isSpaceByte(after),
+		canClose: !isSpaceByte(before),
+	}
+	if marker == '_' {
+		d.canOpen = d.canOpen && !isWordByte(before)
+		d.canClose = d.canClose && !isWordByte(after)
+	}
+	if n < minRun(marker) {
+		d.canOpen, d.canClose = false, false
+	}
+	p.nodes = append(p.nodes, inlineNode{})
+	p.delims = append(p.delims, d)
+}
+
+// matchEmphasis pairs delimiter runs into emphasis, each closer with the nearest opener of the
+// same marker. bottom keeps, per marker, how far down the openers were already searched in vain.
+func (p *inlineParser) matchEmphasis() {
+	var openers []int
+	bottom := map[byte]int{}
+
+	for c := range p.delims {
+		closer := &p.delims[c]
+		for closer.canClose && closer.count >= minRun(closer.marker) {
+			j := len(openers) - 1
+			for j >= bottom[closer.marker] && p.delims[openers[j]].marker != closer.marker {
+				j--
+			}
+			if j < bottom[closer.marker] {
+				bottom[closer.marker] = len(openers)
+				break
+			}
+
+			opener := &p.delims[openers[j]]
+			n := 1
+			if opener.count >= 2 && closer.count >= 2 {
+				n = 2
+			}
+			tag := emphasisTag(closer.marker, n)
+			opener.count -= n
+			closer.count -= n
+			p.nodes[opener.node].after = "<" + tag + ">" + p.nodes[opener.node].after
+			p.nodes[closer.node].before += "</" + tag + ">"
+
+			// Openers between the pair are left as text
+			openers = openers[:j+1]
+			if opener.count < minRun(opener.marker) {
+				openers = openers[:j]
+			}
+			for marker, b := range bottom {
+				bottom[marker] = min(b, len(openers))
+			}
+		}
+		if closer.canOpen && closer.count >= minRun(closer.marker) {
+			openers = append(openers, c)
+		}
+	}
+}
+
+// minRun is how many of an emphasis marker make a delimiter, or 0 for other characters
+func minRun(c byte) int {
+	switch c {
+	case '*', '_':
+		return 1
+	case '~', '=':
+		return 2
+	}
+	return 0
+}
+
+// emphasisTag is the tag n paired markers render as
+func emphasisTag(marker byte, n int) string {
+	switch {
+	case marker == '~':
+		return "del"
+	case marker == '=':
+		return "mark"
+	case n == 2:
+		return "strong"
+	}
+	return "em"
+}
+
+// span renders the span other than emphasis starting at text[i], if one does, and returns the
+// index after it. Returns i when no span starts there.
+func (p *inlineParser) span(i int) (string, int) {
+	text := p.text
+	rest := text[i:]
+	switch {
+	case rest[0] == '\\' && len(rest) > 1 && isASCIIPunct(rest[1]):
+		return html.EscapeString(rest[1:2]), i + 2
+
+	case rest[0] == '`':
+		ticks := len(rest) - len(strings.TrimLeft(rest, "`"))
+		if !p.noClosingTicks[ticks] {
+			if end := strings.Index(rest[ticks:], rest[:ticks]); end >= 0 {
+				code := strings.TrimSpace(rest[ticks : ticks+end])
+				return "<code>" + html.EscapeString(code) + "</code>", i + ticks + end + ticks
+			}
+			if p.noClosingTicks == nil {
+				p.noClosingTicks = map[int]bool{}
+			}
+			p.noClosingTicks[ticks] = true
+		}
+		// Unmatched backticks are text, all of the run at once
+		return rest[:ticks], i + ticks
+
+	case p.inLink:
+		// A link's label can't hold links
+
+	case strings.HasPrefix(rest, "[["), strings.HasPrefix(rest, "![["):
+		open := i + strings.Index(rest, "[[") + 2
+		if end := p.find(&p.wikilinkEnd, open, "]]"); end > open {
+			return p.r.wikilink(text[open:end]), end + 2
+		}
+
+	case rest[0] == '[', strings.HasPrefix(rest, "!["):
+		if rendered, next := p.link(i); next > i {
+			return rendered, next
+		}
+
+	case rest[0] == '<':
+		if end := p.find(&p.autolinkEnd, i, ">"); end > i+1 && isSafeURL(text[i+1:end]) {
+			return anchor(text[i+1:end], html.EscapeString(text[i+1:end])), end + 1
+		}
+
+	case strings.HasPrefix(rest, "http://") || strings.HasPrefix(rest, "https://"):
+		if i == 0 || !isWordByte(text[i-1]) {
+			url := bareURL(rest)
+			return anchor(url, html.EscapeString(url)), i + len(url)
+		}
+	}
+	return "", i
+}
+
+// find returns the index of the first sep at or after from, or -1 if there is none. *at holds
+// the last one found, so searching again from before it costs nothing.
+func (p *inlineParser) find(at *int, from int, sep string) int {
+	if *at < from {
+		*at = math.MaxInt
+		if j := strings.Index(p.text[from:], sep); j >= 0 {
+			*at = from + j
+		}
+	}
+	if *at == math.MaxInt {
+		return -1
+	}
+	return *at
+}
+
+// link renders [text](url) or ![alt](url) at text[i], returning the index after it, or i when it isn't one.
+// Links to unsafe URLs keep only their text.
+func (p *inlineParser) link(i int) (string, int) {
+	text := p.text
+	image := text[i] == '!'
+	open := i + 1
+	if image {
+		open = i + 2
+	}
+
+	closeBracket := p.find(&p.linkLabelEnd, open, "](")
+	if closeBracket < 0 {
+		return "", i
+	}
+	end := p.urlEnd(closeBracket + 2)
+	if end < 0 {
+		return "", i
+	}
+
+	label := text[open:closeBracket]
+	// A title after the URL, as in [text](url "title"), is dropped
+	url, _, _ := strings.Cut(strings.TrimSpace(text[closeBracket+2:end]), " ")
+	url = strings.Trim(url, "<>")
+
+	if !isSafeURL(url) {
+		return html.EscapeString(label), end + 1
+	}
+	if image {
+		return `<img src="` + html.EscapeString(url) + `" alt="` + html.EscapeString(label) + `">`, end + 1
+	}
+	labelParser := &inlineParser{r: p.r, text: label, inLink: true}
+	return anchor(url, labelParser.render()), end + 1
+}
+
+// urlEnd returns the index of the ) ending a link URL that starts at text[from], or -1 if there
+// is none. Parentheses inside the URL are balanced, as in Wikipedia links.
+func (p *inlineParser) urlEnd(from int) int {
+	if p.parenEnd == nil {
+		// Worked out from the end: an ( is skipped along with everything up to its matching )
+		text := p.text
+		p.parenEnd = make([]int32, len(text)+1)
+		p.parenEnd[len(text)] = -1
+		for k := len(text) - 1; k >= 0; k-- {
+			switch text[k] {
+			case ')':
+				p.parenEnd[k] = int32(k)
+			case '(':
+				p.parenEnd[k] = -1
+				if match := p.parenEnd[k+1]; match >= 0 {
+					p.parenEnd[k] = p.parenEnd[match+1]
+				}
+			default:
+				p.parenEnd[k] = p.parenEnd[k+1]
+			}
+		}
+	}
+	return int(p.parenEnd[from])
+}
+
+// wikilink renders [[target]], [[target|alias]] or [[target#heading]]
+func (r *renderer) wikilink(inner string) string {
+	target, alias, hasAlias := strings.Cut(inner, "|")
+	label := target
+	if hasAlias {
+		label = alias
+	}
+	target, _, _ = strings.Cut(target, "#")
+	target = strings.TrimSpace(target)
+
+	if r.opts.WikilinkHref != nil {
+		if href := r.opts.WikilinkHref(target); href != "" {
+			return `<a href="` + html.EscapeString(href) + `">` + html.EscapeString(strings.TrimSpace(label)) + "</a>"
+		}
+	}
+	return `<span class="wikilink">` + html.EscapeString(strings.TrimSpace(label)) + "</span>"
+}
+
+func anchor(url, label string) string {
+	return `<a href="` + html.EscapeString(url) + `" rel="nofollow noopener noreferrer">` + label + "</a>"
+}
+
+// bareURL returns the URL at the start of text, leaving out trailing punctuation
+func bareURL(text string) string {
+	end := strings.IndexFunc(text, func(r rune) bool { return unicode.IsSpace(r) || r == '<' || r == '>' })
+	if end < 0 {
+		end = len(text)
+	}
+	return strings.TrimRight(text[:end], ".,;:!?'\")]")
+}
+
+func isSafeURL(url string) bool {
+	lower := strings.ToLower(strings.TrimSpace(url))
+	return strings.HasPrefix(lower, "https://") || strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "mailto:")
+}
+
+func isASCIIPunct(c byte) bool {
+	return c < utf8.RuneSelf && (unicode.IsPunct(rune(c)) || unicode.IsSymbol(rune(c)))
+}
+
+func isSpaceByte(c byte) bool {
+	return c == ' ' || c == '\t'
+}
+
+func isWordByte(c byte) bool {
+	return c >= utf8.RuneSelf || c == '_' || unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c))
+}
@@ -0,0 +1,87 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestToHTMLInline(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{source: "*em* and **strong**", want: "<p><em>em</em> and <strong>strong</strong></p>\n"},
+		{source: "***both***", want: "<p><em><strong>both</strong></em></p>\n"},
+		{source: "**bold *and em* inside**", want: "<p><strong>bold <em>and em</em> inside</strong></p>\n"},
+		{source: "~~gone~~ ==marked==", want: "<p><del>gone</del> <mark>marked</mark></p>\n"},
+		{source: "snake_case_name", want: "<p>snake_case_name</p>\n"},
+		{source: "_em_ in*side*word", want: "<p><em>em</em> in<em>side</em>word</p>\n"},
+		{source: "a * b * c", want: "<p>a * b * c</p>\n"},
+		{source: "*unclosed **also", want: "<p>*unclosed **also</p>\n"},
+		{source: "a == b ~ c", want: "<p>a == b ~ c</p>\n"},
+		{source: "`*code*` and ``a`b``", want: "<p><code>*code*</code> and <code>a`b</code></p>\n"},
+		{source: "`unclosed *em*", want: "<p>`unclosed <em>em</em></p>\n"},
+		{source: `\*not em\*`, want: "<p>*not em*</p>\n"},
+		{source: "[**site**](https://example.com/a_(b))", want: `<p><a href="https://example.com/a_(b)" rel="nofollow noopener noreferrer"><strong>site</strong></a></p>` + "\n"},
+		{source: "[x](javascript:alert(1))", want: "<p>x</p>\n"},
+		{source: "![alt](https://example.com/i.png)", want: `<p><img src="https://example.com/i.png" alt="alt"></p>` + "\n"},
+		{source: "see [[Note|alias]]", want: `<p>see <span class="wikilink">alias</span></p>` + "\n"},
+		{source: "<https://example.com> and https://example.com/x.", want: `<p><a href="https://example.com" rel="nofollow noopener noreferrer">https://example.com</a> and <a href="https://example.com/x" rel="nofollow noopener noreferrer">https://example.com/x</a>.</p>` + "\n"},
+		{source: "<b>raw</b>", want: "<p>&lt;b&gt;raw&lt;/b&gt;</p>\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			if got := ToHTML(tt.source, Options{}); got != tt.want {
+				t.Errorf("ToHTML(%q)\n got %q\nwant %q", tt.source, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToHTMLNestingDepth(t *testing.T) {
+	got := ToHTML(strings.Repeat(">", maxNestingDepth+5)+" deep", Options{})
+	if n := strings.Count(got, "<blockquote>"); n != maxNestingDepth {
+		t.Errorf("got %d nested block quotes, want %d", n, maxNestingDepth)
+	}
+	if !strings.Contains(got, "&gt;&gt;&gt;&gt;&gt; deep") {
+		t.Errorf("markers past the nesting limit aren't kept as text: %q", got)
+	}
+}
+
+func TestToHTMLTooLong(t *testing.T) {
+	source := "# <title>\n" + strings.Repeat("x", MaxSourceLength)
+	got := ToHTML(source, Options{})
+	if !strings.HasPrefix(got, "<pre># &lt;title&gt;\n") {
+		t.Errorf("a source over MaxSourceLength isn't shown as escaped preformatted text: %.40q", got)
+	}
+}
+
+// Inputs that take quadratic time or worse in a naive renderer
+func TestToHTMLPathologicalInputs(t *testing.T) {
+	const size = 200_000
+	sources := map[string]string{
+		"unclosed emphasis":   strings.Repeat("*a ", size/3),
+		"unclosed underscore": strings.Repeat("_a ", size/3),
+		"mixed openers":       strings.Repeat("*_~~==", size/6) + "x",
+		"nested emphasis":     strings.Repeat("*a **", size/5) + strings.Repeat("b** a*", size/6),
+		"unclosed links":      strings.Repeat("[a", size/2),
+		"links without a URL": strings.Repeat("[a](", size/4),
+		"unclosed wikilinks":  strings.Repeat("[[a", size/3),
+		"unclosed autolinks":  strings.Repeat("<a", size/2),
+		"backtick runs":       strings.Repeat("`a``b```c", size/9),
+		"nested quotes":       strings.Repeat(strings.Repeat(">", 100)+" a\n", size/102),
+		"nested lists":        strings.Repeat("- a\n  ", size/6),
+	}
+
+	for name, source := range sources {
+		t.Run(name, func(t *testing.T) {
+			start := time.Now()
+			ToHTML(source, Options{})
+			if elapsed := time.Since(start); elapsed > 2*time.Second {
+				t.Errorf("rendering %d bytes took %v", len(source), elapsed)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/teambition/rrule-go"
+)
+
+// maxOccurrences caps expansion so an unbounded rule can't produce an unbounded result
+const maxOccurrences = 500
+
+// Parse parses an RFC 5545 RRULE (with or without the "RRULE:" prefix) anchored at dtstart
+func Parse(rule string, dtstart time.Time) (*rrule.RRule, error) {
+	rule = strings.TrimPrefix(strings.TrimSpace(rule), "RRULE:")
+	option, err := rrule.StrToROption(rule)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recurrence rule: %w", err)
+	}
+	option.Dtstart = dtstart
+
+	r, err := rrule.NewRRule(*option)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recurrence rule: %w", err)
+	}
+	return r, nil
+}
+
+// ErrTooFrequent is returned for rules that repeat more often than daily
+var ErrTooFrequent = errors.New("recurrence rule can't repeat more often than daily")
+
+// Validate reports whether rule is a well-formed RRULE that repeats at most daily
+func Validate(rule string) error {
+	r, err := Parse(rule, time.Now())
+	if err != nil {
+		return err
+	}
+	if r.OrigOptions.Freq > rrule.DAILY {
+		return ErrTooFrequent
+	}
+	return nil
+}
+
+// Next returns the first occurrence strictly after the given time, or nil once the rule is exhausted
+func Next(rule string, dtstart, after time.Time) (*time.Time, error) {
+	r, err := Parse(rule, dtstart)
+	if err != nil {
+		return nil, err
+	}
+
+	next := r.After(after, false)
+	if next.IsZero() {
+		return nil, nil
+	}
+	return &next, nil
+}
+
+// Between returns the occurrences within [from, to], capped at maxOccurrences.
+// Expansion stops at the cap rather than listing the whole range first.
+func Between(rule string, dtstart, from, to time.Time) ([]time.Time, error) {
+	r, err := Parse(rule, dtstart)
+	if err != nil {
+		return nil, err
+	}
+
+	occurrences := []time.Time{}
+	next := r.Iterator()
+	for len(occurrences) < maxOccurrences {
+		occurrence, ok := next()
+		if !ok || occurrence.After(to) {
+			break
+		}
+		if !occurrence.Before(from) {
+			occurrences = append(occurrences, occurrence)
+		}
+	}
+	return occurrences, nil
+}
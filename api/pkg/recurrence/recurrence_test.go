@@ -0,0 +1,54 @@
+package recurrence
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		rule    string
+		wantErr error
+	}{
+		{rule: "FREQ=DAILY"},
+		{rule: "RRULE:FREQ=WEEKLY;BYDAY=MO,WE"},
+		{rule: "FREQ=YEARLY;COUNT=3"},
+		{rule: "FREQ=HOURLY", wantErr: ErrTooFrequent},
+		{rule: "FREQ=MINUTELY;INTERVAL=30", wantErr: ErrTooFrequent},
+		{rule: "FREQ=SECONDLY", wantErr: ErrTooFrequent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			if err := Validate(tt.rule); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate(%q) = %v, want %v", tt.rule, err, tt.wantErr)
+			}
+		})
+	}
+
+	if err := Validate("FREQ=SOMETIMES"); err == nil {
+		t.Error("Validate accepted an unknown frequency")
+	}
+}
+
+func TestBetween(t *testing.T) {
+	dtstart := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	got, err := Between("FREQ=DAILY", dtstart, dtstart.AddDate(0, 0, 2), dtstart.AddDate(0, 0, 4))
+	if err != nil {
+		t.Fatalf("Between: %v", err)
+	}
+	if len(got) != 3 || !got[0].Equal(dtstart.AddDate(0, 0, 2)) || !got[2].Equal(dtstart.AddDate(0, 0, 4)) {
+		t.Errorf("Between = %v, want Jan 3 to Jan 5 inclusive", got)
+	}
+
+	// An unbounded range stops at the cap
+	got, err = Between("FREQ=DAILY", dtstart, dtstart, dtstart.AddDate(10, 0, 0))
+	if err != nil {
+		t.Fatalf("Between: %v", err)
+	}
+	if len(got) != maxOccurrences {
+		t.Errorf("got %d occurrences, want the cap of %d", len(got), maxOccurrences)
+	}
+}
@@ -0,0 +1,75 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FrontMatter holds the YAML front-matter fields a Markdown note can carry
+type FrontMatter struct {
+	Title string
+	Tags  []string
+}
+
+// ParseFrontMatter splits a leading "---" delimited YAML block off Markdown content.
+// Tags may be a list or a comma or space separated string, as Obsidian accepts both.
+// Content without front-matter is returned unchanged.
+func ParseFrontMatter(content string) (FrontMatter, string, error) {
+	var matter FrontMatter
+
+	lines := strings.SplitAfter(strings.TrimPrefix(content, "\ufeff"), "\n")
+	if strings.TrimRight(lines[0], " \t\r\n") != "---" {
+		return matter, content, nil
+	}
+
+	// The block ends at the next line that is just "---"
+	end := -1
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimRight(lines[i], " \t\r\n") == "---" {
+			end = i
+			break
+		}
+	}
+	if end < 0 {
+		return matter, content, nil
+	}
+	block := strings.Join(lines[1:end], "")
+	body := strings.Join(lines[end+1:], "")
+
+	var fields struct {
+		Title string `yaml:"title"`
+		Tags  any    `yaml:"tags"`
+		Tag   any    `yaml:"tag"`
+	}
+	if err := yaml.Unmarshal([]byte(block), &fields); err != nil {
+		return matter, body, err
+	}
+
+	matter.Title = strings.TrimSpace(fields.Title)
+	matter.Tags = append(frontMatterTags(fields.Tags), frontMatterTags(fields.Tag)...)
+	return matter, body, nil
+}
+
+func frontMatterTags(value any) []string {
+	var names []string
+	switch v := value.(type) {
+	case string:
+		names = strings.FieldsFunc(v, func(r rune) bool { return r == ',' || r == ' ' })
+	case []any:
+		for _, item := range v {
+			if item != nil {
+				names = append(names, fmt.Sprint(item))
+			}
+		}
+	}
+
+	tags := make([]string, 0, len(names))
+	for _, name := range names {
+		if name = NormalizeTagName(name); name != "" {
+			tags = append(tags, name)
+		}
+	}
+	return tags
+}
@@ -0,0 +1,100 @@
+package util
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseFrontMatter(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		title   string
+		tags    []string
+		body    string
+		wantErr bool
+	}{
+		{
+			name:    "none",
+			content: "# Heading\n\nText",
+			tags:    nil,
+			body:    "# Heading\n\nText",
+		},
+		{
+			name:    "title and tag list",
+			content: "---\ntitle: \" Trip \"\ntags:\n  - travel\n  - \"#Summer\"\n---\nPacking list",
+			title:   "Trip",
+			tags:    []string{"travel", "Summer"},
+			body:    "Packing list",
+		},
+		{
+			name:    "comma and space separated tag string",
+			content: "---\ntags: travel, summer plans\n---\nBody",
+			tags:    []string{"travel", "summer", "plans"},
+			body:    "Body",
+		},
+		{
+			name:    "numbers in a tag list",
+			content: "---\ntags: [2026, trip]\n---\nBody",
+			tags:    []string{"2026", "trip"},
+			body:    "Body",
+		},
+		{
+			name:    "number as tags",
+			content: "---\ntags: 2026\n---\nBody",
+			tags:    []string{},
+			body:    "Body",
+		},
+		{
+			name:    "tag and tags together",
+			content: "---\ntags: [a]\ntag: b\n---\nBody",
+			tags:    []string{"a", "b"},
+			body:    "Body",
+		},
+		{
+			name:    "CRLF line endings",
+			content: "---\r\ntitle: Trip\r\ntags: [travel]\r\n---\r\nBody\r\n",
+			title:   "Trip",
+			tags:    []string{"travel"},
+			body:    "Body\r\n",
+		},
+		{
+			name:    "byte order mark",
+			content: "\ufeff---\ntitle: Trip\n---\nBody",
+			title:   "Trip",
+			tags:    []string{},
+			body:    "Body",
+		},
+		{
+			name:    "missing closing delimiter",
+			content: "---\ntitle: Trip\nBody",
+			tags:    nil,
+			body:    "---\ntitle: Trip\nBody",
+		},
+		{
+			name:    "bad YAML",
+			content: "---\ntitle: [unclosed\n---\nBody",
+			tags:    nil,
+			body:    "Body",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matter, body, err := ParseFrontMatter(tt.content)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFrontMatter(%q) error = %v, want error %v", tt.content, err, tt.wantErr)
+			}
+			if matter.Title != tt.title {
+				t.Errorf("ParseFrontMatter(%q) title = %q, want %q", tt.content, matter.Title, tt.title)
+			}
+			if !slices.Equal(matter.Tags, tt.tags) {
+				t.Errorf("ParseFrontMatter(%q) tags = %q, want %q", tt.content, matter.Tags, tt.tags)
+			}
+			if body != tt.body {
+				t.Errorf("ParseFrontMatter(%q) body = %q, want %q", tt.content, body, tt.body)
+			}
+		})
+	}
+}
@@ -11,6 +11,8 @@ type JWTClaims struct {
 	ID   string `json:"sub"`
 	Role string `json:"role"`
 	Type string `json:"type"`
+	// WorkspaceID is the active workspace, or empty for the personal space
+	WorkspaceID string `json:"workspace"`
 }
 
 func VerifyToken(tokenStr, secret string) (JWTClaims, error) {
@@ -34,16 +36,21 @@ func VerifyToken(tokenStr, secret string) (JWTClaims, error) {
 
 	role, _ := claims["role"].(string)
 	tokenType, _ := claims["type"].(string)
+	workspaceID, _ := claims["workspace"].(string)
 
-	return JWTClaims{ID: id, Role: role, Type: tokenType}, nil
+	return JWTClaims{ID: id, Role: role, Type: tokenType, WorkspaceID: workspaceID}, nil
 }
 
-func GenerateToken(userID, role, tokenType, secret string, expiresAt time.Time) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+func GenerateToken(userID, role, workspaceID, tokenType, secret string, expiresAt time.Time) (string, error) {
+	claims := jwt.MapClaims{
 		"sub":  userID,
 		"role": role,
 		"type": tokenType,
 		"exp":  expiresAt.Unix(),
-	})
+	}
+	if workspaceID != "" {
+		claims["workspace"] = workspaceID
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString([]byte(secret))
 }
@@ -0,0 +1,44 @@
+package util
+
+import (
+	"regexp"
+	"strings"
+)
+
+// mentionPattern matches @handle and @name@example.com, but not the domain of a plain email address
+var mentionPattern = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_@.])@([\p{L}\p{N}_.+-]+(?:@[\p{L}\p{N}-]+(?:\.[\p{L}\p{N}-]+)+)?)`)
+
+// ParseMentions extracts unique lowercased handles from @mentions, preserving first-seen order.
+// A handle is an email address, its part before the @, or a name with the spaces left out.
+func ParseMentions(content string) []string {
+	seen := map[string]bool{}
+	handles := []string{}
+
+	for _, match := range mentionPattern.FindAllStringSubmatch(content, -1) {
+		// Punctuation ending a sentence isn't part of the handle
+		handle := strings.ToLower(strings.TrimRight(match[1], ".-"))
+		if handle == "" || seen[handle] {
+			continue
+		}
+		seen[handle] = true
+		handles = append(handles, handle)
+	}
+
+	return handles
+}
+
+// MentionHandles returns the handles that mention a user: their email address, its part before
+// the @ and their name with the spaces left out, all lowercased
+func MentionHandles(name, email string) []string {
+	handles := []string{}
+	if email = strings.ToLower(strings.TrimSpace(email)); email != "" {
+		handles = append(handles, email)
+		if local, _, ok := strings.Cut(email, "@"); ok && local != "" {
+			handles = append(handles, local)
+		}
+	}
+	if name = strings.ToLower(strings.Join(strings.Fields(name), "")); name != "" {
+		handles = append(handles, name)
+	}
+	return handles
+}
@@ -0,0 +1,50 @@
+package util
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseMentions(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{name: "handles", content: "@Ana and @bob.smith, take a look", want: []string{"ana", "bob.smith"}},
+		{name: "email address", content: "ping @Ana@Example.com please", want: []string{"ana@example.com"}},
+		{name: "plain email address", content: "mail ana@example.com instead", want: []string{}},
+		{name: "sentence punctuation", content: "Thanks @ana.", want: []string{"ana"}},
+		{name: "repeats", content: "@ana @ANA (@ana)", want: []string{"ana"}},
+		{name: "unicode", content: "@Zoë", want: []string{"zoë"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseMentions(tt.content); !slices.Equal(got, tt.want) {
+				t.Errorf("ParseMentions(%q) = %q, want %q", tt.content, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMentionHandles(t *testing.T) {
+	tests := []struct {
+		name      string
+		userName  string
+		userEmail string
+		want      []string
+	}{
+		{name: "email and name", userName: "Ana María", userEmail: " Ana@Example.com", want: []string{"ana@example.com", "ana", "anamaría"}},
+		{name: "no name", userEmail: "bob@example.com", want: []string{"bob@example.com", "bob"}},
+		{name: "nothing", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MentionHandles(tt.userName, tt.userEmail); !slices.Equal(got, tt.want) {
+				t.Errorf("MentionHandles(%q, %q) = %q, want %q", tt.userName, tt.userEmail, got, tt.want)
+			}
+		})
+	}
+}
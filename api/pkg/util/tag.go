@@ -0,0 +1,23 @@
+package util
+
+import (
+	"slices"
+	"strings"
+)
+
+// NormalizeTagName trims whitespace and a leading "#" so "#urgent" and "urgent" refer to the same tag
+func NormalizeTagName(name string) string {
+	return strings.TrimPrefix(strings.TrimSpace(name), "#")
+}
+
+// NormalizeTagNames normalizes and lowercases tag names for case-insensitive matching, dropping
+// empty ones and repeats, so "#a, A" is the single filter "a"
+func NormalizeTagNames(names []string) []string {
+	normalized := make([]string, 0, len(names))
+	for _, name := range names {
+		if name = strings.ToLower(NormalizeTagName(name)); name != "" && !slices.Contains(normalized, name) {
+			normalized = append(normalized, name)
+		}
+	}
+	return normalized
+}
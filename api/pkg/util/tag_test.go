@@ -0,0 +1,27 @@
+package util
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNormalizeTagNames(t *testing.T) {
+	tests := []struct {
+		name  string
+		names []string
+		want  []string
+	}{
+		{name: "trims and lowercases", names: []string{" #Work ", "Home"}, want: []string{"work", "home"}},
+		{name: "drops empty names", names: []string{"", " ", "#"}, want: []string{}},
+		{name: "drops repeats after normalizing", names: []string{"a", "a", "#A", " a "}, want: []string{"a"}},
+		{name: "keeps first-seen order", names: []string{"b", "a", "b"}, want: []string{"b", "a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeTagNames(tt.names); !slices.Equal(got, tt.want) {
+				t.Errorf("NormalizeTagNames(%q) = %q, want %q", tt.names, got, tt.want)
+			}
+		})
+	}
+}
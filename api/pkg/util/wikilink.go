@@ -0,0 +1,39 @@
+package util
+
+import (
+	"regexp"
+	"strings"
+)
+
+var wikiLinkPattern = regexp.MustCompile(`\[\[([^\[\]\n]+?)\]\]`)
+
+// codePattern matches fenced code blocks and inline code spans, whose brackets aren't links
+var codePattern = regexp.MustCompile("(?s)```.*?(?:```|$)|`[^`\n]*`")
+
+// ParseWikiLinks extracts unique link targets from [[Target]], [[Target|alias]]
+// and [[Target#heading]] references outside code, preserving first-seen order
+func ParseWikiLinks(content string) []string {
+	seen := map[string]bool{}
+	targets := []string{}
+
+	content = codePattern.ReplaceAllString(content, " ")
+	for _, match := range wikiLinkPattern.FindAllStringSubmatch(content, -1) {
+		target := match[1]
+		if i := strings.IndexAny(target, "|#"); i >= 0 {
+			target = target[:i]
+		}
+		target = strings.TrimSpace(target)
+		if target == "" {
+			continue
+		}
+
+		key := strings.ToLower(target)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		targets = append(targets, target)
+	}
+
+	return targets
+}
@@ -0,0 +1,34 @@
+package util
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseWikiLinks(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{name: "plain", content: "See [[Project Plan]] and [[Budget]]", want: []string{"Project Plan", "Budget"}},
+		{name: "alias", content: "[[Project Plan|the plan]]", want: []string{"Project Plan"}},
+		{name: "heading", content: "[[Project Plan#Goals]] and [[Budget#Q3|Q3 numbers]]", want: []string{"Project Plan", "Budget"}},
+		{name: "duplicates ignore case", content: "[[Budget]] [[budget]] [[Budget#Q3]]", want: []string{"Budget"}},
+		{name: "trims spaces", content: "[[  Budget  ]]", want: []string{"Budget"}},
+		{name: "inline code", content: "Write `[[Budget]]` to link, like [[Guide]]", want: []string{"Guide"}},
+		{name: "fenced code", content: "```\n[[Budget]]\n```\n[[Guide]]", want: []string{"Guide"}},
+		{name: "unclosed fence", content: "[[Guide]]\n```\n[[Budget]]", want: []string{"Guide"}},
+		{name: "unclosed brackets", content: "[[Budget and [[Guide]]", want: []string{"Guide"}},
+		{name: "across lines", content: "[[Bud\nget]]", want: []string{}},
+		{name: "empty", content: "[[]] [[ ]] [[|alias]] [[#heading]]", want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseWikiLinks(tt.content); !slices.Equal(got, tt.want) {
+				t.Errorf("ParseWikiLinks(%q) = %q, want %q", tt.content, got, tt.want)
+			}
+		})
+	}
+}